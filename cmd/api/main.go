@@ -3,12 +3,17 @@ package main
 import (
 	"assignment/internal/config"
 	"assignment/internal/controller"
+	"assignment/internal/domain/entities"
 	"assignment/internal/handler"
 	"assignment/internal/infrastructure/database/migration"
+	"assignment/internal/pubsub"
 	"assignment/internal/repository"
+	"assignment/internal/rpc"
+	"assignment/internal/worker"
 	"context"
 	"database/sql"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -23,6 +28,9 @@ import (
 func main() {
 	// Load config
 	cfg := config.Load()
+	if err := cfg.Validate(); err != nil {
+		log.Fatal("Invalid configuration: ", err)
+	}
 
 	// Initialize database
 	db, err := initDB(cfg)
@@ -41,11 +49,23 @@ func main() {
 
 	// Initialize layers with interfaces
 	repos := repository.NewRepositories(db)
-	controllers := controller.NewControllers(repos)
+	updateHub := pubsub.NewHub[*entities.Update](pubsub.DefaultBufferSize)
+	eventHub := pubsub.NewHub[*entities.UserEvent](pubsub.DefaultBufferSize)
+	controllers := controller.NewControllers(repos, updateHub, eventHub)
+
+	// Start the outbox worker that delivers published updates
+	deliverer := worker.EventRouter{
+		entities.EventUpdatePublished: worker.LogDeliverer{},
+		entities.EventWebhookDelivery: worker.NewWebhookDeliverer(repos.WebhookRepository(), worker.NewWebhookClient(cfg.Outbox.DeliveryTimeout)),
+	}
+	outboxWorker := worker.NewOutboxWorker(repos.OutboxRepository(), deliverer, cfg.Outbox)
+	outboxWorker.Start()
 
 	// Setup routes
 	r := gin.Default()
-	handler.SetupRoutes(r, controllers)
+	if err := handler.SetupRoutes(r, controllers); err != nil {
+		log.Fatal("Failed to setup routes:", err)
+	}
 
 	// Setup HTTP server
 	srv := &http.Server{
@@ -61,6 +81,20 @@ func main() {
 		}
 	}()
 
+	// Setup the gRPC server on its own port, sharing the controllers
+	grpcListener, err := net.Listen("tcp", ":"+cfg.Server.GRPCPort)
+	if err != nil {
+		log.Fatal("Failed to listen for gRPC:", err)
+	}
+	grpcServer := rpc.NewServer(controllers)
+
+	go func() {
+		log.Printf("gRPC server starting on port %s", cfg.Server.GRPCPort)
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			log.Fatalf("gRPC server failed to start: %v", err)
+		}
+	}()
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -71,6 +105,10 @@ func main() {
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer shutdownCancel()
 
+	// End open update streams and WebSocket connections first, the server waits for them otherwise
+	updateHub.Close()
+	eventHub.Close()
+
 	// Shutdown the server gracefully
 	if err := srv.Shutdown(shutdownCtx); err != nil {
 		log.Printf("Server forced to shutdown: %v", err)
@@ -78,6 +116,27 @@ func main() {
 		log.Println("Server exited gracefully")
 	}
 
+	// GracefulStop waits for pending RPCs, stop them when the timeout runs out
+	grpcStopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(grpcStopped)
+	}()
+	select {
+	case <-grpcStopped:
+		log.Println("gRPC server exited gracefully")
+	case <-shutdownCtx.Done():
+		grpcServer.Stop()
+		log.Println("gRPC server forced to shutdown")
+	}
+
+	// Stop the outbox worker once no more updates can be published
+	if err := outboxWorker.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Outbox worker forced to shutdown: %v", err)
+	} else {
+		log.Println("Outbox worker stopped")
+	}
+
 	// Close database connection
 	if err := db.Close(); err != nil {
 		log.Printf("Error closing database: %v", err)
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS block_snapshots;
\ No newline at end of file
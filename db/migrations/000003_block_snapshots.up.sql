@@ -0,0 +1,11 @@
+-- Block snapshots record the relationships removed when a block was created
+-- so that they can be restored when the block is lifted
+CREATE TABLE block_snapshots (
+    block_id INTEGER PRIMARY KEY,
+    had_friendship BOOLEAN NOT NULL DEFAULT FALSE,
+    blocker_subscribed BOOLEAN NOT NULL DEFAULT FALSE,
+    blocked_subscribed BOOLEAN NOT NULL DEFAULT FALSE,
+
+    -- Snapshot is removed together with its block
+    CONSTRAINT fk_block_snapshots_block FOREIGN KEY (block_id) REFERENCES blocks(id) ON DELETE CASCADE
+);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_friend_requests_addressee;
+DROP INDEX IF EXISTS idx_friend_requests_requester;
+DROP INDEX IF EXISTS unq_friend_request_pending;
+DROP TABLE IF EXISTS friend_requests;
\ No newline at end of file
@@ -0,0 +1,27 @@
+-- Friend requests table for consent based friendships
+-- requester asks addressee to become friends; a friends row is only created on acceptance
+CREATE TABLE friend_requests (
+    id SERIAL PRIMARY KEY,
+    requester_id INTEGER NOT NULL,
+    addressee_id INTEGER NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    -- Foreign key constraints
+    CONSTRAINT fk_friend_requests_requester FOREIGN KEY (requester_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_friend_requests_addressee FOREIGN KEY (addressee_id) REFERENCES users(id) ON DELETE CASCADE,
+
+    -- Prevent self requests
+    CONSTRAINT chk_no_self_friend_request CHECK (requester_id != addressee_id),
+
+    -- Restrict status to the supported workflow states
+    CONSTRAINT chk_friend_request_status CHECK (status IN ('pending', 'accepted', 'rejected', 'cancelled'))
+);
+
+-- Only one pending request per pair of users, regardless of direction
+CREATE UNIQUE INDEX unq_friend_request_pending ON friend_requests (LEAST(requester_id, addressee_id), GREATEST(requester_id, addressee_id)) WHERE status = 'pending';
+
+-- Indexes for faster incoming/outgoing lookups
+CREATE INDEX idx_friend_requests_requester ON friend_requests(requester_id);
+CREATE INDEX idx_friend_requests_addressee ON friend_requests(addressee_id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_inbox_user_update;
+DROP TABLE IF EXISTS inbox;
+DROP INDEX IF EXISTS idx_updates_sender;
+DROP TABLE IF EXISTS updates;
\ No newline at end of file
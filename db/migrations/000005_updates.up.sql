@@ -0,0 +1,31 @@
+-- Updates table stores the text published by a sender
+CREATE TABLE updates (
+    id SERIAL PRIMARY KEY,
+    sender_id INTEGER NOT NULL,
+    text TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    -- Foreign key constraints
+    CONSTRAINT fk_updates_sender FOREIGN KEY (sender_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Index for faster sender lookups
+CREATE INDEX idx_updates_sender ON updates(sender_id);
+
+-- Inbox table holds one row per recipient of an update
+-- recipients are resolved once at publish time
+CREATE TABLE inbox (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    update_id INTEGER NOT NULL,
+
+    -- Foreign key constraints
+    CONSTRAINT fk_inbox_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_inbox_update FOREIGN KEY (update_id) REFERENCES updates(id) ON DELETE CASCADE,
+
+    -- Unique constraint to prevent delivering the same update twice
+    CONSTRAINT unq_inbox_entry UNIQUE (user_id, update_id)
+);
+
+-- Index for paging through a user's timeline, newest first
+CREATE INDEX idx_inbox_user_update ON inbox(user_id, update_id DESC);
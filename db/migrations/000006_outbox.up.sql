@@ -0,0 +1,19 @@
+-- Outbox table holds events written in the same transaction as the data they describe
+-- a background worker delivers them and retries with backoff until they are delivered or dead
+CREATE TABLE outbox (
+    id SERIAL PRIMARY KEY,
+    event_type VARCHAR(64) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMP WITH TIME ZONE,
+
+    -- Restrict status to the supported delivery states
+    CONSTRAINT chk_outbox_status CHECK (status IN ('pending', 'delivered', 'dead'))
+);
+
+-- Index for the worker to find events that are due
+CREATE INDEX idx_outbox_pending ON outbox(next_attempt_at, id) WHERE status = 'pending';
@@ -0,0 +1,9 @@
+-- Usernames let updates mention users as @handle instead of by email
+ALTER TABLE users ADD COLUMN username VARCHAR(32);
+
+-- Handles are matched case-insensitively, so uniqueness must be too
+CREATE UNIQUE INDEX idx_users_username ON users (lower(username));
+
+-- Give the seed users a handle matching their email
+UPDATE users SET username = split_part(email, '@', 1)
+WHERE email IN ('andy@mail.com', 'alice@mail.com', 'bob@mail.com', 'jack@mail.com', 'lisa@mail.com');
@@ -0,0 +1,13 @@
+-- User settings table holds per-user privacy preferences
+-- users without a row use the defaults, so mentions from everyone are allowed
+CREATE TABLE user_settings (
+    user_id INTEGER PRIMARY KEY,
+    mention_policy VARCHAR(16) NOT NULL DEFAULT 'everyone',
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    -- Foreign key constraints
+    CONSTRAINT fk_user_settings_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+
+    -- Restrict mention policy to the supported audiences
+    CONSTRAINT chk_user_settings_mention_policy CHECK (mention_policy IN ('everyone', 'friends', 'nobody'))
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_mutes_muted;
+DROP TABLE IF EXISTS mutes;
\ No newline at end of file
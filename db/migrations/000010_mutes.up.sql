@@ -0,0 +1,22 @@
+-- muter stops receiving updates from muted, without touching friendships or subscriptions
+-- a mute with expires_at lapses on its own once that time has passed
+CREATE TABLE mutes (
+    id SERIAL PRIMARY KEY,
+    muter_id INTEGER NOT NULL,
+    muted_id INTEGER NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    -- Foreign key constraints
+    CONSTRAINT fk_mutes_muter FOREIGN KEY (muter_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_mutes_muted FOREIGN KEY (muted_id) REFERENCES users(id) ON DELETE CASCADE,
+
+    -- Prevent self-muting
+    CONSTRAINT chk_no_self_mute CHECK (muter_id != muted_id),
+
+    -- Unique constraint so muting again replaces the expiry
+    CONSTRAINT unq_mute UNIQUE (muter_id, muted_id)
+);
+
+-- Index for finding who mutes a sender when resolving recipients
+CREATE INDEX idx_mutes_muted ON mutes(muted_id);
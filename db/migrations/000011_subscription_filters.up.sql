@@ -0,0 +1,6 @@
+-- Keyword filters narrow a subscription down to the updates the subscriber cares about
+-- an update reaches the subscriber when it contains any include keyword (or there are none)
+-- and none of the exclude keywords; keywords are stored lowercase, hashtags keep their '#'
+ALTER TABLE subscriptions
+    ADD COLUMN include_keywords TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN exclude_keywords TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,8 @@
+-- Emails are compared case-insensitively: mentions are parsed lowercase and lookups
+-- lowercase their input, so every stored email is lowercase too. Two accounts whose
+-- emails differ only in case make this fail on the unique constraint and have to be
+-- merged by hand first
+UPDATE users SET email = lower(email) WHERE email <> lower(email);
+
+ALTER TABLE users
+    ADD CONSTRAINT chk_email_lowercase CHECK (email = lower(email));
@@ -3,11 +3,14 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 )
 
 type Config struct {
 	Database DatabaseConfig
 	Server   ServerConfig
+	Outbox   OutboxConfig
 }
 
 type DatabaseConfig struct {
@@ -20,7 +23,18 @@ type DatabaseConfig struct {
 }
 
 type ServerConfig struct {
-	Port string
+	Port     string
+	GRPCPort string
+}
+
+type OutboxConfig struct {
+	PollInterval    time.Duration
+	BatchSize       int
+	MaxAttempts     int
+	BaseBackoff     time.Duration
+	MaxBackoff      time.Duration
+	LeaseDuration   time.Duration
+	DeliveryTimeout time.Duration
 }
 
 func Load() *Config {
@@ -34,11 +48,64 @@ func Load() *Config {
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
 		},
 		Server: ServerConfig{
-			Port: getEnv("PORT", "8080"),
+			Port:     getEnv("PORT", "8080"),
+			GRPCPort: getEnv("GRPC_PORT", "9090"),
+		},
+		Outbox: OutboxConfig{
+			PollInterval:    getEnvDuration("OUTBOX_POLL_INTERVAL", time.Second),
+			BatchSize:       getEnvInt("OUTBOX_BATCH_SIZE", 50),
+			MaxAttempts:     getEnvInt("OUTBOX_MAX_ATTEMPTS", 8),
+			BaseBackoff:     getEnvDuration("OUTBOX_BASE_BACKOFF", time.Second),
+			MaxBackoff:      getEnvDuration("OUTBOX_MAX_BACKOFF", 5*time.Minute),
+			LeaseDuration:   getEnvDuration("OUTBOX_LEASE_DURATION", 10*time.Minute),
+			DeliveryTimeout: getEnvDuration("OUTBOX_DELIVERY_TIMEOUT", 10*time.Second),
 		},
 	}
 }
 
+// Validate reports the first setting the server cannot run with
+func (c *Config) Validate() error {
+	return c.Outbox.Validate()
+}
+
+// Validate checks that every outbox setting is positive, as a zero batch size
+// would keep the worker claiming nothing forever and a zero poll interval
+// panics, that the backoff bounds are in order, and that a lease lasts long
+// enough for every delivery of a batch to time out, since the events of a
+// batch are delivered one at a time under a single lease
+func (c OutboxConfig) Validate() error {
+	durations := []struct {
+		env   string
+		value time.Duration
+	}{
+		{"OUTBOX_POLL_INTERVAL", c.PollInterval},
+		{"OUTBOX_BASE_BACKOFF", c.BaseBackoff},
+		{"OUTBOX_MAX_BACKOFF", c.MaxBackoff},
+		{"OUTBOX_LEASE_DURATION", c.LeaseDuration},
+		{"OUTBOX_DELIVERY_TIMEOUT", c.DeliveryTimeout},
+	}
+	for _, d := range durations {
+		if d.value <= 0 {
+			return fmt.Errorf("%s must be positive, got %s", d.env, d.value)
+		}
+	}
+
+	if c.BatchSize <= 0 {
+		return fmt.Errorf("OUTBOX_BATCH_SIZE must be positive, got %d", c.BatchSize)
+	}
+	if c.MaxAttempts <= 0 {
+		return fmt.Errorf("OUTBOX_MAX_ATTEMPTS must be positive, got %d", c.MaxAttempts)
+	}
+	if c.MaxBackoff < c.BaseBackoff {
+		return fmt.Errorf("OUTBOX_MAX_BACKOFF (%s) must not be less than OUTBOX_BASE_BACKOFF (%s)", c.MaxBackoff, c.BaseBackoff)
+	}
+	if batchTime := time.Duration(c.BatchSize) * c.DeliveryTimeout; c.LeaseDuration < batchTime {
+		return fmt.Errorf("OUTBOX_LEASE_DURATION (%s) must cover OUTBOX_BATCH_SIZE deliveries of OUTBOX_DELIVERY_TIMEOUT (%s)", c.LeaseDuration, batchTime)
+	}
+
+	return nil
+}
+
 func (c *Config) DatabaseURL() string {
 	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		c.Database.Host,
@@ -55,3 +122,21 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad_OutboxDefaultsAreValid(t *testing.T) {
+	assert.NoError(t, Load().Validate())
+}
+
+func TestOutboxConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name          string
+		modify        func(cfg *OutboxConfig)
+		expectedError string
+	}{
+		{
+			name:   "valid",
+			modify: func(cfg *OutboxConfig) {},
+		},
+		{
+			name:          "zero batch size",
+			modify:        func(cfg *OutboxConfig) { cfg.BatchSize = 0 },
+			expectedError: "OUTBOX_BATCH_SIZE must be positive, got 0",
+		},
+		{
+			name:          "zero poll interval",
+			modify:        func(cfg *OutboxConfig) { cfg.PollInterval = 0 },
+			expectedError: "OUTBOX_POLL_INTERVAL must be positive, got 0s",
+		},
+		{
+			name:          "negative max attempts",
+			modify:        func(cfg *OutboxConfig) { cfg.MaxAttempts = -1 },
+			expectedError: "OUTBOX_MAX_ATTEMPTS must be positive, got -1",
+		},
+		{
+			name:          "negative delivery timeout",
+			modify:        func(cfg *OutboxConfig) { cfg.DeliveryTimeout = -time.Second },
+			expectedError: "OUTBOX_DELIVERY_TIMEOUT must be positive, got -1s",
+		},
+		{
+			name:          "max backoff below base backoff",
+			modify:        func(cfg *OutboxConfig) { cfg.MaxBackoff = 500 * time.Millisecond },
+			expectedError: "OUTBOX_MAX_BACKOFF (500ms) must not be less than OUTBOX_BASE_BACKOFF (1s)",
+		},
+		{
+			name:          "lease shorter than a batch of deliveries",
+			modify:        func(cfg *OutboxConfig) { cfg.LeaseDuration = time.Minute },
+			expectedError: "OUTBOX_LEASE_DURATION (1m0s) must cover OUTBOX_BATCH_SIZE deliveries of OUTBOX_DELIVERY_TIMEOUT (8m20s)",
+		},
+		{
+			name: "small batch within a short lease",
+			modify: func(cfg *OutboxConfig) {
+				cfg.BatchSize = 6
+				cfg.LeaseDuration = time.Minute
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := OutboxConfig{
+				PollInterval:    time.Second,
+				BatchSize:       50,
+				MaxAttempts:     8,
+				BaseBackoff:     time.Second,
+				MaxBackoff:      5 * time.Minute,
+				LeaseDuration:   10 * time.Minute,
+				DeliveryTimeout: 10 * time.Second,
+			}
+			tt.modify(&cfg)
+
+			err := cfg.Validate()
+
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
@@ -3,15 +3,29 @@ package controller
 import "assignment/internal/domain/interfaces"
 
 type controllers struct {
-    userController interfaces.UserControllerInterface
+    userController    interfaces.UserControllerInterface
+    updateController  interfaces.UpdateControllerInterface
+    webhookController interfaces.WebhookControllerInterface
 }
 
-func NewControllers(repos interfaces.Repositories) interfaces.Controllers {
+func NewControllers(repos interfaces.Repositories, updateHub interfaces.UpdateHubInterface, eventHub interfaces.UserEventHubInterface) interfaces.Controllers {
+    userController := NewUserController(repos.UserRepository(), eventHub)
+
     return &controllers{
-        userController: NewUserController(repos.UserRepository()),
+        userController:    userController,
+        updateController:  NewUpdateController(repos.UpdateRepository(), userController, updateHub),
+        webhookController: NewWebhookController(repos.WebhookRepository(), userController),
     }
 }
 
 func (c *controllers) UserController() interfaces.UserControllerInterface {
     return c.userController
+}
+
+func (c *controllers) UpdateController() interfaces.UpdateControllerInterface {
+    return c.updateController
+}
+
+func (c *controllers) WebhookController() interfaces.WebhookControllerInterface {
+    return c.webhookController
 }
\ No newline at end of file
@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"assignment/internal/domain/entities"
+	"assignment/internal/domain/interfaces"
+)
+
+type updateController struct {
+	updateRepo     interfaces.UpdateRepositoryInterface
+	userController interfaces.UserControllerInterface
+	hub            interfaces.UpdateHubInterface
+}
+
+func NewUpdateController(updateRepo interfaces.UpdateRepositoryInterface, userController interfaces.UserControllerInterface, hub interfaces.UpdateHubInterface) interfaces.UpdateControllerInterface {
+	return &updateController{
+		updateRepo:     updateRepo,
+		userController: userController,
+		hub:            hub,
+	}
+}
+
+// PublishUpdate stores the update, delivers it to the recipients resolved by
+// GetRecipients and pushes it to the ones that are streaming
+func (c *updateController) PublishUpdate(senderEmail, text string) (*entities.Update, error) {
+	sender, err := c.userController.GetUser(senderEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	// A zero limit resolves every recipient at once
+	recipients, err := c.userController.GetRecipients(senderEmail, text, "", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	update, err := c.updateRepo.CreateUpdateTx(sender, text, recipients.Users)
+	if err != nil {
+		return nil, err
+	}
+
+	recipientIDs := make([]int, len(recipients.Users))
+	for i, recipient := range recipients.Users {
+		recipientIDs[i] = recipient.ID
+	}
+	c.hub.Publish(recipientIDs, update)
+
+	return update, nil
+}
+
+func (c *updateController) GetTimeline(email string, limit, offset int) (*entities.TimelinePage, error) {
+	user, err := c.userController.GetUser(email)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch one extra update to know whether another page follows
+	updates, err := c.updateRepo.GetTimeline(user, limit+1, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	hasMore := len(updates) > limit
+	if hasMore {
+		updates = updates[:limit]
+	}
+
+	return &entities.TimelinePage{
+		Updates: updates,
+		HasMore: hasMore,
+	}, nil
+}
+
+// SubscribeUpdates starts a live stream of the user's incoming updates. With a
+// lastEventID the stream's Replay reads everything the user received after that
+// update from the inbox, backlogPageSize updates at a time, so the backlog is
+// never held in memory at once. The subscription is taken before the backlog is
+// read so nothing published in between is lost; the caller skips live updates
+// already replayed
+func (c *updateController) SubscribeUpdates(email string, lastEventID, backlogPageSize int) (*entities.UpdateStream, error) {
+	user, err := c.userController.GetUser(email)
+	if err != nil {
+		return nil, err
+	}
+
+	updates, cancel := c.hub.Subscribe(user.ID)
+
+	replay := func(send func(page []*entities.Update) error) error {
+		for afterID := lastEventID; afterID > 0; {
+			page, err := c.updateRepo.GetInboxSince(user, afterID, backlogPageSize)
+			if err != nil {
+				return err
+			}
+			if len(page) == 0 {
+				return nil
+			}
+
+			if err := send(page); err != nil {
+				return err
+			}
+			if len(page) < backlogPageSize {
+				return nil
+			}
+			afterID = page[len(page)-1].ID
+		}
+		return nil
+	}
+
+	return &entities.UpdateStream{
+		Replay:  replay,
+		Updates: updates,
+		Close:   cancel,
+	}, nil
+}
@@ -0,0 +1,363 @@
+package controller
+
+import (
+	"assignment/internal/domain/entities"
+	"assignment/mocks"
+	"assignment/pkg/errors"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPublishUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sender := &entities.User{ID: 1, Email: "sender@example.com"}
+	recipients := []*entities.User{
+		{ID: 2, Email: "friend@example.com"},
+		{ID: 3, Email: "mentioned@example.com"},
+	}
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		senderEmail    string
+		text           string
+		setupMock      func(mockRepo *mocks.MockUpdateRepositoryInterface, mockUser *mocks.MockUserControllerInterface, mockHub *mocks.MockUpdateHubInterface)
+		wantErr        bool
+		wantErrType    errors.ErrorType
+		wantErrMsg     string
+		expectedUpdate *entities.Update
+	}{
+		{
+			name:        "successful publish",
+			senderEmail: "sender@example.com",
+			text:        "Hello mentioned@example.com",
+			setupMock: func(mockRepo *mocks.MockUpdateRepositoryInterface, mockUser *mocks.MockUserControllerInterface, mockHub *mocks.MockUpdateHubInterface) {
+				update := &entities.Update{
+					ID:         10,
+					Sender:     sender,
+					Text:       "Hello mentioned@example.com",
+					Recipients: recipients,
+					CreatedAt:  createdAt,
+				}
+				mockUser.EXPECT().GetUser("sender@example.com").Return(sender, nil)
+				mockUser.EXPECT().GetRecipients("sender@example.com", "Hello mentioned@example.com", "", 0).Return(&entities.UserPage{Users: recipients, Total: len(recipients)}, nil)
+				mockRepo.EXPECT().CreateUpdateTx(sender, "Hello mentioned@example.com", recipients).Return(update, nil)
+				mockHub.EXPECT().Publish([]int{2, 3}, update)
+			},
+			wantErr: false,
+			expectedUpdate: &entities.Update{
+				ID:         10,
+				Sender:     sender,
+				Text:       "Hello mentioned@example.com",
+				Recipients: recipients,
+				CreatedAt:  createdAt,
+			},
+		},
+		{
+			name:        "sender not found",
+			senderEmail: "nonexistent@example.com",
+			text:        "Hello",
+			setupMock: func(mockRepo *mocks.MockUpdateRepositoryInterface, mockUser *mocks.MockUserControllerInterface, mockHub *mocks.MockUpdateHubInterface) {
+				mockUser.EXPECT().GetUser("nonexistent@example.com").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", "nonexistent@example.com"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeNotFound,
+			wantErrMsg:  "User not found: nonexistent@example.com",
+		},
+		{
+			name:        "error resolving recipients",
+			senderEmail: "sender@example.com",
+			text:        "Hello",
+			setupMock: func(mockRepo *mocks.MockUpdateRepositoryInterface, mockUser *mocks.MockUserControllerInterface, mockHub *mocks.MockUpdateHubInterface) {
+				mockUser.EXPECT().GetUser("sender@example.com").Return(sender, nil)
+				mockUser.EXPECT().GetRecipients("sender@example.com", "Hello", "", 0).Return(nil, errors.New(errors.ErrorTypeDatabase, "Failed to fetch recipients"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeDatabase,
+			wantErrMsg:  "Failed to fetch recipients",
+		},
+		{
+			name:        "error storing update",
+			senderEmail: "sender@example.com",
+			text:        "Hello",
+			setupMock: func(mockRepo *mocks.MockUpdateRepositoryInterface, mockUser *mocks.MockUserControllerInterface, mockHub *mocks.MockUpdateHubInterface) {
+				mockUser.EXPECT().GetUser("sender@example.com").Return(sender, nil)
+				mockUser.EXPECT().GetRecipients("sender@example.com", "Hello", "", 0).Return(&entities.UserPage{Users: recipients, Total: len(recipients)}, nil)
+				mockRepo.EXPECT().CreateUpdateTx(sender, "Hello", recipients).Return(nil, errors.New(errors.ErrorTypeDatabase, "Failed to commit transaction"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeDatabase,
+			wantErrMsg:  "Failed to commit transaction",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockUpdateRepositoryInterface(ctrl)
+			mockUser := mocks.NewMockUserControllerInterface(ctrl)
+			mockHub := mocks.NewMockUpdateHubInterface(ctrl)
+			tt.setupMock(mockRepo, mockUser, mockHub)
+
+			controller := NewUpdateController(mockRepo, mockUser, mockHub)
+			update, err := controller.PublishUpdate(tt.senderEmail, tt.text)
+
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedUpdate, update)
+				return
+			}
+
+			assert.Error(t, err)
+			var appErr *errors.AppError
+			assert.True(t, stderrors.As(err, &appErr))
+			assert.Equal(t, tt.wantErrType, appErr.Type)
+			assert.Equal(t, tt.wantErrMsg, appErr.Message)
+		})
+	}
+}
+
+func TestGetTimeline(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := &entities.User{ID: 2, Email: "reader@example.com"}
+	sender := &entities.User{ID: 1, Email: "sender@example.com"}
+	updates := []*entities.Update{
+		{ID: 3, Sender: sender, Text: "third"},
+		{ID: 2, Sender: sender, Text: "second"},
+		{ID: 1, Sender: sender, Text: "first"},
+	}
+
+	tests := []struct {
+		name         string
+		email        string
+		limit        int
+		offset       int
+		setupMock    func(mockRepo *mocks.MockUpdateRepositoryInterface, mockUser *mocks.MockUserControllerInterface)
+		wantErr      bool
+		wantErrType  errors.ErrorType
+		wantErrMsg   string
+		expectedPage *entities.TimelinePage
+	}{
+		{
+			name:  "page with more updates after it",
+			email: "reader@example.com",
+			limit: 2,
+			setupMock: func(mockRepo *mocks.MockUpdateRepositoryInterface, mockUser *mocks.MockUserControllerInterface) {
+				mockUser.EXPECT().GetUser("reader@example.com").Return(user, nil)
+				mockRepo.EXPECT().GetTimeline(user, 3, 0).Return(updates, nil)
+			},
+			wantErr:      false,
+			expectedPage: &entities.TimelinePage{Updates: updates[:2], HasMore: true},
+		},
+		{
+			name:   "last page",
+			email:  "reader@example.com",
+			limit:  2,
+			offset: 2,
+			setupMock: func(mockRepo *mocks.MockUpdateRepositoryInterface, mockUser *mocks.MockUserControllerInterface) {
+				mockUser.EXPECT().GetUser("reader@example.com").Return(user, nil)
+				mockRepo.EXPECT().GetTimeline(user, 3, 2).Return(updates[2:], nil)
+			},
+			wantErr:      false,
+			expectedPage: &entities.TimelinePage{Updates: updates[2:], HasMore: false},
+		},
+		{
+			name:  "user not found",
+			email: "nonexistent@example.com",
+			limit: 20,
+			setupMock: func(mockRepo *mocks.MockUpdateRepositoryInterface, mockUser *mocks.MockUserControllerInterface) {
+				mockUser.EXPECT().GetUser("nonexistent@example.com").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", "nonexistent@example.com"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeNotFound,
+			wantErrMsg:  "User not found: nonexistent@example.com",
+		},
+		{
+			name:  "repository error",
+			email: "reader@example.com",
+			limit: 20,
+			setupMock: func(mockRepo *mocks.MockUpdateRepositoryInterface, mockUser *mocks.MockUserControllerInterface) {
+				mockUser.EXPECT().GetUser("reader@example.com").Return(user, nil)
+				mockRepo.EXPECT().GetTimeline(user, 21, 0).Return(nil, errors.New(errors.ErrorTypeDatabase, "Failed to fetch timeline"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeDatabase,
+			wantErrMsg:  "Failed to fetch timeline",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockUpdateRepositoryInterface(ctrl)
+			mockUser := mocks.NewMockUserControllerInterface(ctrl)
+			tt.setupMock(mockRepo, mockUser)
+
+			controller := NewUpdateController(mockRepo, mockUser, mocks.NewMockUpdateHubInterface(ctrl))
+			page, err := controller.GetTimeline(tt.email, tt.limit, tt.offset)
+
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedPage, page)
+				return
+			}
+
+			assert.Error(t, err)
+			var appErr *errors.AppError
+			assert.True(t, stderrors.As(err, &appErr))
+			assert.Equal(t, tt.wantErrType, appErr.Type)
+			assert.Equal(t, tt.wantErrMsg, appErr.Message)
+		})
+	}
+}
+
+func TestSubscribeUpdates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := &entities.User{ID: 2, Email: "reader@example.com"}
+	sender := &entities.User{ID: 1, Email: "sender@example.com"}
+	fourth := &entities.Update{ID: 4, Sender: sender, Text: "fourth"}
+	fifth := &entities.Update{ID: 5, Sender: sender, Text: "fifth"}
+	sixth := &entities.Update{ID: 6, Sender: sender, Text: "sixth"}
+
+	tests := []struct {
+		name          string
+		email         string
+		lastEventID   int
+		setupMock     func(mockRepo *mocks.MockUpdateRepositoryInterface, mockUser *mocks.MockUserControllerInterface, mockHub *mocks.MockUpdateHubInterface, cancel func())
+		wantErr       bool
+		wantErrType   errors.ErrorType
+		wantErrMsg    string
+		wantCancelled bool
+		expectedPages [][]*entities.Update
+		wantReplayErr string
+	}{
+		{
+			name:  "new stream has no backlog",
+			email: "reader@example.com",
+			setupMock: func(mockRepo *mocks.MockUpdateRepositoryInterface, mockUser *mocks.MockUserControllerInterface, mockHub *mocks.MockUpdateHubInterface, cancel func()) {
+				mockUser.EXPECT().GetUser("reader@example.com").Return(user, nil)
+				mockHub.EXPECT().Subscribe(2).Return(make(chan *entities.Update), cancel)
+			},
+			wantErr: false,
+		},
+		{
+			name:        "resumed stream replays the inbox",
+			email:       "reader@example.com",
+			lastEventID: 3,
+			setupMock: func(mockRepo *mocks.MockUpdateRepositoryInterface, mockUser *mocks.MockUserControllerInterface, mockHub *mocks.MockUpdateHubInterface, cancel func()) {
+				mockUser.EXPECT().GetUser("reader@example.com").Return(user, nil)
+				mockHub.EXPECT().Subscribe(2).Return(make(chan *entities.Update), cancel)
+				mockRepo.EXPECT().GetInboxSince(user, 3, 2).Return([]*entities.Update{fourth}, nil)
+			},
+			wantErr:       false,
+			expectedPages: [][]*entities.Update{{fourth}},
+		},
+		{
+			name:        "backlog is read page by page until exhausted",
+			email:       "reader@example.com",
+			lastEventID: 3,
+			setupMock: func(mockRepo *mocks.MockUpdateRepositoryInterface, mockUser *mocks.MockUserControllerInterface, mockHub *mocks.MockUpdateHubInterface, cancel func()) {
+				mockUser.EXPECT().GetUser("reader@example.com").Return(user, nil)
+				mockHub.EXPECT().Subscribe(2).Return(make(chan *entities.Update), cancel)
+				gomock.InOrder(
+					mockRepo.EXPECT().GetInboxSince(user, 3, 2).Return([]*entities.Update{fourth, fifth}, nil),
+					mockRepo.EXPECT().GetInboxSince(user, 5, 2).Return([]*entities.Update{sixth}, nil),
+				)
+			},
+			wantErr:       false,
+			expectedPages: [][]*entities.Update{{fourth, fifth}, {sixth}},
+		},
+		{
+			name:        "backlog ending on a full page stops at the empty one",
+			email:       "reader@example.com",
+			lastEventID: 3,
+			setupMock: func(mockRepo *mocks.MockUpdateRepositoryInterface, mockUser *mocks.MockUserControllerInterface, mockHub *mocks.MockUpdateHubInterface, cancel func()) {
+				mockUser.EXPECT().GetUser("reader@example.com").Return(user, nil)
+				mockHub.EXPECT().Subscribe(2).Return(make(chan *entities.Update), cancel)
+				gomock.InOrder(
+					mockRepo.EXPECT().GetInboxSince(user, 3, 2).Return([]*entities.Update{fourth, fifth}, nil),
+					mockRepo.EXPECT().GetInboxSince(user, 5, 2).Return([]*entities.Update{}, nil),
+				)
+			},
+			wantErr:       false,
+			expectedPages: [][]*entities.Update{{fourth, fifth}},
+		},
+		{
+			name:  "user not found",
+			email: "nonexistent@example.com",
+			setupMock: func(mockRepo *mocks.MockUpdateRepositoryInterface, mockUser *mocks.MockUserControllerInterface, mockHub *mocks.MockUpdateHubInterface, cancel func()) {
+				mockUser.EXPECT().GetUser("nonexistent@example.com").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", "nonexistent@example.com"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeNotFound,
+			wantErrMsg:  "User not found: nonexistent@example.com",
+		},
+		{
+			name:        "backlog error ends the replay",
+			email:       "reader@example.com",
+			lastEventID: 3,
+			setupMock: func(mockRepo *mocks.MockUpdateRepositoryInterface, mockUser *mocks.MockUserControllerInterface, mockHub *mocks.MockUpdateHubInterface, cancel func()) {
+				mockUser.EXPECT().GetUser("reader@example.com").Return(user, nil)
+				mockHub.EXPECT().Subscribe(2).Return(make(chan *entities.Update), cancel)
+				gomock.InOrder(
+					mockRepo.EXPECT().GetInboxSince(user, 3, 2).Return([]*entities.Update{fourth, fifth}, nil),
+					mockRepo.EXPECT().GetInboxSince(user, 5, 2).Return(nil, errors.New(errors.ErrorTypeDatabase, "Failed to fetch inbox")),
+				)
+			},
+			wantErr:       false,
+			expectedPages: [][]*entities.Update{{fourth, fifth}},
+			wantReplayErr: "Failed to fetch inbox",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockUpdateRepositoryInterface(ctrl)
+			mockUser := mocks.NewMockUserControllerInterface(ctrl)
+			mockHub := mocks.NewMockUpdateHubInterface(ctrl)
+			cancelled := false
+			tt.setupMock(mockRepo, mockUser, mockHub, func() { cancelled = true })
+
+			controller := NewUpdateController(mockRepo, mockUser, mockHub)
+			stream, err := controller.SubscribeUpdates(tt.email, tt.lastEventID, 2)
+
+			assert.Equal(t, tt.wantCancelled, cancelled)
+
+			if !tt.wantErr {
+				assert.NoError(t, err)
+
+				var pages [][]*entities.Update
+				err = stream.Replay(func(page []*entities.Update) error {
+					pages = append(pages, page)
+					return nil
+				})
+				if tt.wantReplayErr != "" {
+					var appErr *errors.AppError
+					assert.True(t, stderrors.As(err, &appErr))
+					assert.Equal(t, tt.wantReplayErr, appErr.Message)
+				} else {
+					assert.NoError(t, err)
+				}
+				assert.Equal(t, tt.expectedPages, pages)
+				assert.NotNil(t, stream.Updates)
+				stream.Close()
+				assert.True(t, cancelled)
+				return
+			}
+
+			assert.Error(t, err)
+			var appErr *errors.AppError
+			assert.True(t, stderrors.As(err, &appErr))
+			assert.Equal(t, tt.wantErrType, appErr.Type)
+			assert.Equal(t, tt.wantErrMsg, appErr.Message)
+		})
+	}
+}
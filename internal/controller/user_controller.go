@@ -5,24 +5,27 @@ import (
 	"assignment/internal/domain/interfaces"
 	"assignment/pkg/errors"
 	"assignment/pkg/utils"
-	"maps"
 	"slices"
+	"strings"
+	"time"
 )
 
 type userController struct {
 	userRepo interfaces.UserRepositoryInterface
+	events   interfaces.UserEventHubInterface
 }
 
-func NewUserController(userRepo interfaces.UserRepositoryInterface) interfaces.UserControllerInterface {
+func NewUserController(userRepo interfaces.UserRepositoryInterface, events interfaces.UserEventHubInterface) interfaces.UserControllerInterface {
 	return &userController{
 		userRepo: userRepo,
+		events:   events,
 	}
 }
 
-func (c *userController) CreateFriendship(user1Email, user2Email string) error {
-	// Check for self-friendship
-	if user1Email == user2Email {
-		return errors.ErrCannotFriendSelf
+func (c *userController) DeleteFriendship(user1Email, user2Email string) error {
+	// Check for self-unfriend
+	if strings.EqualFold(user1Email, user2Email) {
+		return errors.ErrCannotUnfriendSelf
 	}
 
 	// Get users from repository
@@ -36,45 +39,346 @@ func (c *userController) CreateFriendship(user1Email, user2Email string) error {
 		return err
 	}
 
+	if err := c.userRepo.DeleteFriendship(user1, user2); err != nil {
+		return err
+	}
+
+	c.publishEvent(entities.UserEventFriendRemoved, user1, user2)
+	return nil
+}
+
+func (c *userController) SendFriendRequest(requestorEmail, targetEmail string) (*entities.FriendRequest, error) {
+	// Check for self-friendship
+	if strings.EqualFold(requestorEmail, targetEmail) {
+		return nil, errors.ErrCannotFriendSelf
+	}
+
+	requestor, err := c.userRepo.GetUserByEmail(requestorEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := c.userRepo.GetUserByEmail(targetEmail)
+	if err != nil {
+		return nil, err
+	}
+
 	// Check if either user has blocked the other
-	isBlocked, err := c.userRepo.CheckBidirectionalBlock(user1.ID, user2.ID)
+	isBlocked, err := c.userRepo.CheckBidirectionalBlock(requestor.ID, target.ID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if isBlocked {
-		return errors.ErrUserBlocked
+		return nil, errors.ErrUserBlocked
+	}
+
+	alreadyFriends, err := c.userRepo.CheckFriendshipExists(requestor.ID, target.ID)
+	if err != nil {
+		return nil, err
+	}
+	if alreadyFriends {
+		return nil, errors.ErrAlreadyFriends
+	}
+
+	request, err := c.userRepo.CreateFriendRequest(requestor, target)
+	if err != nil {
+		return nil, err
+	}
+
+	c.publishEvent(entities.UserEventFriendRequested, requestor, target)
+	return request, nil
+}
+
+func (c *userController) GetIncomingFriendRequests(email string) ([]*entities.FriendRequest, error) {
+	user, err := c.userRepo.GetUserByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.userRepo.GetIncomingFriendRequests(user)
+}
+
+func (c *userController) GetOutgoingFriendRequests(email string) ([]*entities.FriendRequest, error) {
+	user, err := c.userRepo.GetUserByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.userRepo.GetOutgoingFriendRequests(user)
+}
+
+// AcceptFriendRequest accepts the pending request that target sent to requestor
+func (c *userController) AcceptFriendRequest(requestorEmail, targetEmail string) error {
+	requestor, err := c.userRepo.GetUserByEmail(requestorEmail)
+	if err != nil {
+		return err
+	}
+
+	target, err := c.userRepo.GetUserByEmail(targetEmail)
+	if err != nil {
+		return err
+	}
+
+	// A block created after the request was sent still prevents the friendship;
+	// the repository checks it in the same transaction as the insert
+	if err := c.userRepo.AcceptFriendRequestTx(target, requestor); err != nil {
+		return err
+	}
+
+	c.publishEvent(entities.UserEventFriendAdded, requestor, target)
+	return nil
+}
+
+// RejectFriendRequest rejects the pending request that target sent to requestor
+func (c *userController) RejectFriendRequest(requestorEmail, targetEmail string) error {
+	requestor, err := c.userRepo.GetUserByEmail(requestorEmail)
+	if err != nil {
+		return err
+	}
+
+	target, err := c.userRepo.GetUserByEmail(targetEmail)
+	if err != nil {
+		return err
+	}
+
+	return c.userRepo.UpdateFriendRequestStatus(target, requestor, entities.FriendRequestRejected)
+}
+
+// CancelFriendRequest withdraws the pending request that requestor sent to target
+func (c *userController) CancelFriendRequest(requestorEmail, targetEmail string) error {
+	requestor, err := c.userRepo.GetUserByEmail(requestorEmail)
+	if err != nil {
+		return err
+	}
+
+	target, err := c.userRepo.GetUserByEmail(targetEmail)
+	if err != nil {
+		return err
 	}
 
-	return c.userRepo.CreateFriendship(user1, user2)
+	return c.userRepo.UpdateFriendRequestStatus(requestor, target, entities.FriendRequestCancelled)
 }
 
-func (c *userController) GetFriendList(email string) ([]*entities.User, error) {
+func (c *userController) GetFriendList(email, cursor string, limit int) (*entities.UserPage, error) {
+	page, err := newPageRequest(cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
 	user, err := c.userRepo.GetUserByEmail(email)
 	if err != nil {
 		return nil, err
 	}
 
-	return c.userRepo.GetFriendList(user)
+	friends, err := c.userRepo.GetFriendList(user, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return trimUserPage(friends, limit), nil
 }
 
-func (c *userController) GetCommonFriends(email1, email2 string) ([]*entities.User, error) {
+// GetFriendListsBatch returns the same page of the friend list of each of the
+// given users, by user ID
+func (c *userController) GetFriendListsBatch(users []*entities.User, cursor string, limit int) (map[int]*entities.UserPage, error) {
+	page, err := newPageRequest(cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := c.userRepo.GetFriendListsBatch(userIDsOf(users), page)
+	if err != nil {
+		return nil, err
+	}
+
+	return trimUserPages(pages, limit), nil
+}
+
+func (c *userController) GetCommonFriends(emails []string, cursor string, limit int) (*entities.UserPage, error) {
+	page, err := newPageRequest(cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	emails = lowerEmails(emails)
+
+	// Check for same user listed more than once
+	seen := make(map[string]bool, len(emails))
+	for _, email := range emails {
+		if seen[email] {
+			return nil, errors.ErrCannotGetCommonFriendsWithSelf
+		}
+		seen[email] = true
+	}
+
+	users, err := c.getAllUsersByEmails(emails)
+	if err != nil {
+		return nil, err
+	}
+
+	commonFriends, err := c.userRepo.GetCommonFriends(users, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return trimUserPage(commonFriends, limit), nil
+}
+
+// GetCommonFriendsBatch returns the same page of the friends each of the given
+// users has in common with all of the users with the given emails, by user ID
+func (c *userController) GetCommonFriendsBatch(users []*entities.User, emails []string, cursor string, limit int) (map[int]*entities.UserPage, error) {
+	page, err := newPageRequest(cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	emails = lowerEmails(emails)
+
+	// Check for same user listed more than once, or listed with itself
+	seen := make(map[string]bool, len(emails))
+	for _, email := range emails {
+		if seen[email] {
+			return nil, errors.ErrCannotGetCommonFriendsWithSelf
+		}
+		seen[email] = true
+	}
+	for _, user := range users {
+		if seen[user.Email] {
+			return nil, errors.ErrCannotGetCommonFriendsWithSelf
+		}
+	}
+
+	others, err := c.getAllUsersByEmails(emails)
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := c.userRepo.GetCommonFriendsBatch(userIDsOf(users), others, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return trimUserPages(pages, limit), nil
+}
+
+// getAllUsersByEmails returns the users with the given emails, or a not found
+// error for the first email nobody has
+func (c *userController) getAllUsersByEmails(emails []string) ([]*entities.User, error) {
+	emails = lowerEmails(emails)
+
+	users, err := c.userRepo.GetUsersByEmails(emails)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(users) != len(emails) {
+		found := make(map[string]bool, len(users))
+		for _, user := range users {
+			found[user.Email] = true
+		}
+		for _, email := range emails {
+			if !found[email] {
+				return nil, errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", email)
+			}
+		}
+	}
+
+	return users, nil
+}
+
+// GetFriendshipPath finds the shortest chain of friends from requestor to target
+// using a bidirectional breadth-first search that loads one level of friends per query
+func (c *userController) GetFriendshipPath(requestorEmail, targetEmail string, maxDepth int) ([]*entities.User, error) {
 	// Check for same user
-	if email1 == email2 {
-		return nil, errors.ErrCannotGetCommonFriendsWithSelf
+	if strings.EqualFold(requestorEmail, targetEmail) {
+		return nil, errors.ErrCannotGetPathToSelf
 	}
 
-	// Get users from repository
-	user1, err := c.userRepo.GetUserByEmail(email1)
+	requestor, err := c.userRepo.GetUserByEmail(requestorEmail)
 	if err != nil {
 		return nil, err
 	}
 
-	user2, err := c.userRepo.GetUserByEmail(email2)
+	target, err := c.userRepo.GetUserByEmail(targetEmail)
 	if err != nil {
 		return nil, err
 	}
 
-	return c.userRepo.GetCommonFriends(user1, user2)
+	// Each side maps a visited user ID to the user it was reached from
+	forward := map[int]*entities.User{requestor.ID: nil}
+	backward := map[int]*entities.User{target.ID: nil}
+	forwardFrontier := []*entities.User{requestor}
+	backwardFrontier := []*entities.User{target}
+
+	for hops := 0; hops < maxDepth; hops++ {
+		if len(forwardFrontier) == 0 || len(backwardFrontier) == 0 {
+			break
+		}
+
+		// Expand the smaller frontier to keep the number of loaded friends low
+		expandForward := len(forwardFrontier) <= len(backwardFrontier)
+		frontier, visited, other := forwardFrontier, forward, backward
+		if !expandForward {
+			frontier, visited, other = backwardFrontier, backward, forward
+		}
+
+		frontierIDs := make([]int, len(frontier))
+		for i, user := range frontier {
+			frontierIDs[i] = user.ID
+		}
+
+		friends, err := c.userRepo.GetUnblockedFriendsBatch(frontierIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		var next []*entities.User
+		for _, user := range frontier {
+			for _, friend := range friends[user.ID] {
+				if _, seen := visited[friend.ID]; seen {
+					continue
+				}
+				visited[friend.ID] = user
+
+				// Both searches advance a full level at a time, so the first
+				// meeting point is on a shortest path
+				if _, reached := other[friend.ID]; reached {
+					return buildFriendshipPath(friend, forward, backward), nil
+				}
+				next = append(next, friend)
+			}
+		}
+
+		if expandForward {
+			forwardFrontier = next
+		} else {
+			backwardFrontier = next
+		}
+	}
+
+	return nil, errors.ErrFriendshipPathNotFound
+}
+
+// buildFriendshipPath joins the forward and backward search trees at the meeting user
+func buildFriendshipPath(meeting *entities.User, forward, backward map[int]*entities.User) []*entities.User {
+	var path []*entities.User
+	for user := meeting; user != nil; user = forward[user.ID] {
+		path = append(path, user)
+	}
+	slices.Reverse(path)
+
+	for user := backward[meeting.ID]; user != nil; user = backward[user.ID] {
+		path = append(path, user)
+	}
+
+	return path
+}
+
+func (c *userController) GetFriendSuggestions(email string, limit int) ([]*entities.FriendSuggestion, error) {
+	user, err := c.userRepo.GetUserByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.userRepo.GetFriendSuggestions(user, limit)
 }
 
 func (c *userController) CreateSubscription(requestorEmail, targetEmail string) error {
@@ -97,7 +401,164 @@ func (c *userController) CreateSubscription(requestorEmail, targetEmail string)
 		return errors.ErrUserBlocked
 	}
 
-	return c.userRepo.CreateSubscription(requestor, target)
+	if err := c.userRepo.CreateSubscription(requestor, target); err != nil {
+		return err
+	}
+
+	c.publishEvent(entities.UserEventSubscriptionAdded, requestor, target)
+	return nil
+}
+
+func (c *userController) DeleteSubscription(requestorEmail, targetEmail string) error {
+	requestor, err := c.userRepo.GetUserByEmail(requestorEmail)
+	if err != nil {
+		return err
+	}
+
+	target, err := c.userRepo.GetUserByEmail(targetEmail)
+	if err != nil {
+		return err
+	}
+
+	if err := c.userRepo.DeleteSubscription(requestor, target); err != nil {
+		return err
+	}
+
+	c.publishEvent(entities.UserEventSubscriptionRemoved, requestor, target)
+	return nil
+}
+
+// GetSubscribers lists the users who subscribe to the user's updates
+func (c *userController) GetSubscribers(email, cursor string, limit int) (*entities.UserPage, error) {
+	page, err := newPageRequest(cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := c.userRepo.GetUserByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := c.userRepo.GetSubscribersByUserID(user.ID, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return trimUserPage(users, limit), nil
+}
+
+// GetSubscribersBatch returns the same page of the subscribers of each of the
+// given users, by user ID
+func (c *userController) GetSubscribersBatch(users []*entities.User, cursor string, limit int) (map[int]*entities.UserPage, error) {
+	page, err := newPageRequest(cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := c.userRepo.GetSubscribersBatch(userIDsOf(users), page)
+	if err != nil {
+		return nil, err
+	}
+
+	return trimUserPages(pages, limit), nil
+}
+
+// GetSubscriptions lists the users whose updates the user subscribes to
+func (c *userController) GetSubscriptions(email, cursor string, limit int) (*entities.UserPage, error) {
+	page, err := newPageRequest(cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := c.userRepo.GetUserByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := c.userRepo.GetSubscriptionsByUserID(user.ID, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return trimUserPage(users, limit), nil
+}
+
+// CountSubscribers returns the number of subscribers of each of the users with
+// the given emails. Emails nobody has are left out
+func (c *userController) CountSubscribers(emails []string) (map[string]int, error) {
+	users, err := c.userRepo.GetUsersByEmails(emails)
+	if err != nil {
+		return nil, err
+	}
+
+	subscribers, err := c.userRepo.CountSubscribersBatch(userIDsOf(users))
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int, len(users))
+	for _, user := range users {
+		counts[user.Email] = subscribers[user.ID]
+	}
+
+	return counts, nil
+}
+
+// CountSubscribersBatch returns the number of subscribers of each of the given
+// users, by user ID. Users without subscribers are left out
+func (c *userController) CountSubscribersBatch(users []*entities.User) (map[int]int, error) {
+	return c.userRepo.CountSubscribersBatch(userIDsOf(users))
+}
+
+func (c *userController) GetSubscriptionFilter(requestorEmail, targetEmail string) (*entities.SubscriptionFilter, error) {
+	requestor, err := c.userRepo.GetUserByEmail(requestorEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := c.userRepo.GetUserByEmail(targetEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.userRepo.GetSubscriptionFilter(requestor, target)
+}
+
+// UpdateSubscriptionFilter replaces the keyword filters on the requestor's
+// subscription to the target. Keywords are lowercased and deduplicated, and an
+// empty filter makes the subscription receive every update again
+func (c *userController) UpdateSubscriptionFilter(requestorEmail, targetEmail string, filter *entities.SubscriptionFilter) (*entities.SubscriptionFilter, error) {
+	requestor, err := c.userRepo.GetUserByEmail(requestorEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := c.userRepo.GetUserByEmail(targetEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := &entities.SubscriptionFilter{
+		Include: normalizeKeywords(filter.Include),
+		Exclude: normalizeKeywords(filter.Exclude),
+	}
+	if err := c.userRepo.UpdateSubscriptionFilter(requestor, target, normalized); err != nil {
+		return nil, err
+	}
+
+	return normalized, nil
+}
+
+func normalizeKeywords(keywords []string) []string {
+	normalized := make([]string, 0, len(keywords))
+	for _, keyword := range keywords {
+		keyword = strings.ToLower(keyword)
+		if !slices.Contains(normalized, keyword) {
+			normalized = append(normalized, keyword)
+		}
+	}
+	return normalized
 }
 
 func (c *userController) CreateBlock(requestorEmail, targetEmail string) error {
@@ -111,65 +572,414 @@ func (c *userController) CreateBlock(requestorEmail, targetEmail string) error {
 		return err
 	}
 
-	return c.userRepo.CreateBlockTx(requestor, target)
+	if err := c.userRepo.CreateBlockTx(requestor, target); err != nil {
+		return err
+	}
+
+	c.publishEvent(entities.UserEventBlockAdded, requestor, target)
+	return nil
+}
+
+func (c *userController) DeleteBlock(requestorEmail, targetEmail string, restore bool) error {
+	requestor, err := c.userRepo.GetUserByEmail(requestorEmail)
+	if err != nil {
+		return err
+	}
+
+	target, err := c.userRepo.GetUserByEmail(targetEmail)
+	if err != nil {
+		return err
+	}
+
+	// Restoring relationships is not allowed while the target still blocks the
+	// requestor; the repository checks it in the same transaction as the restore
+	if err := c.userRepo.DeleteBlockTx(requestor, target, restore); err != nil {
+		return err
+	}
+
+	c.publishEvent(entities.UserEventBlockRemoved, requestor, target)
+	return nil
+}
+
+func (c *userController) GetBlockedUsers(email, cursor string, limit int) (*entities.UserPage, error) {
+	page, err := newPageRequest(cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := c.userRepo.GetUserByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := c.userRepo.GetBlockedUsers(user, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return trimUserPage(users, limit), nil
+}
+
+// GetBlockers lists the users who block the user
+func (c *userController) GetBlockers(email, cursor string, limit int) (*entities.UserPage, error) {
+	page, err := newPageRequest(cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := c.userRepo.GetUserByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := c.userRepo.GetBlockers(user, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return trimUserPage(users, limit), nil
+}
+
+// GetBlockersBatch returns the same page of the users who block each of the
+// given users, by user ID
+func (c *userController) GetBlockersBatch(users []*entities.User, cursor string, limit int) (map[int]*entities.UserPage, error) {
+	page, err := newPageRequest(cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := c.userRepo.GetBlockersBatch(userIDsOf(users), page)
+	if err != nil {
+		return nil, err
+	}
+
+	return trimUserPages(pages, limit), nil
+}
+
+// CreateMute hides the target's updates from the requestor until expiresAt, or
+// until unmuted when expiresAt is nil. Friendships and subscriptions are kept
+func (c *userController) CreateMute(requestorEmail, targetEmail string, expiresAt *time.Time) (*entities.Mute, error) {
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, errors.ErrMuteExpiryInPast
+	}
+
+	requestor, err := c.userRepo.GetUserByEmail(requestorEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := c.userRepo.GetUserByEmail(targetEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.userRepo.UpsertMute(requestor, target, expiresAt)
+}
+
+func (c *userController) DeleteMute(requestorEmail, targetEmail string) error {
+	requestor, err := c.userRepo.GetUserByEmail(requestorEmail)
+	if err != nil {
+		return err
+	}
+
+	target, err := c.userRepo.GetUserByEmail(targetEmail)
+	if err != nil {
+		return err
+	}
+
+	return c.userRepo.DeleteMute(requestor, target)
+}
+
+func (c *userController) GetMutes(email string) ([]*entities.Mute, error) {
+	user, err := c.userRepo.GetUserByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.userRepo.GetMutes(user)
+}
+
+// SubscribeEvents starts a live stream of the relationship events that concern the user
+func (c *userController) SubscribeEvents(email string) (*entities.UserEventStream, error) {
+	user, err := c.userRepo.GetUserByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	events, cancel := c.events.Subscribe(user.ID)
+
+	return &entities.UserEventStream{
+		Events: events,
+		Close:  cancel,
+	}, nil
+}
+
+// publishEvent pushes a relationship change to both users. Blocks are only
+// pushed to the blocker so that being blocked is not disclosed
+func (c *userController) publishEvent(eventType entities.UserEventType, actor, target *entities.User) {
+	userIDs := []int{actor.ID, target.ID}
+	if eventType == entities.UserEventBlockAdded || eventType == entities.UserEventBlockRemoved {
+		userIDs = []int{actor.ID}
+	}
+
+	c.events.Publish(userIDs, &entities.UserEvent{
+		Type:      eventType,
+		Actor:     actor,
+		Target:    target,
+		CreatedAt: time.Now(),
+	})
+}
+
+func (c *userController) GetRelationship(emailA, emailB string) (*entities.Relationship, error) {
+	// Check for same user
+	if strings.EqualFold(emailA, emailB) {
+		return nil, errors.ErrCannotGetRelationshipWithSelf
+	}
+
+	userA, err := c.userRepo.GetUserByEmail(emailA)
+	if err != nil {
+		return nil, err
+	}
+
+	userB, err := c.userRepo.GetUserByEmail(emailB)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.userRepo.GetRelationship(userA, userB)
 }
 
-func (c *userController) GetRecipients(senderEmail, text string) ([]*entities.User, error) {
+// GetRecipients returns a page of the users who receive an update from the
+// sender, or all of them when limit is 0
+func (c *userController) GetRecipients(senderEmail, text, cursor string, limit int) (*entities.UserPage, error) {
+	page, err := newPageRequest(cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
 	sender, err := c.userRepo.GetUserByEmail(senderEmail)
 	if err != nil {
 		return nil, err
 	}
 
-	mentionedEmails := utils.ExtractEmailsFromText(text)
-	var mentionedUsers []*entities.User
+	mentionedEmails, _, err := c.resolveMentions(text)
+	if err != nil {
+		return nil, err
+	}
 
-	if len(mentionedEmails) > 0 {
-		mentionedUsers, err = c.userRepo.GetUsersByEmails(mentionedEmails)
-		if err != nil {
-			return nil, err
-		}
+	recipients, err := c.userRepo.GetRecipients(sender, mentionedEmails, utils.ExtractKeywords(text), page)
+	if err != nil {
+		return nil, err
 	}
 
-	senderFriends, err := c.userRepo.GetFriendList(sender)
+	users := make([]*entities.User, len(recipients.Recipients))
+	for i, recipient := range recipients.Recipients {
+		users[i] = recipient.User
+	}
+
+	return trimUserPage(&entities.UserPage{Users: users, Total: recipients.Total}, limit), nil
+}
+
+// ExplainRecipients resolves the same recipients as GetRecipients, keeping the
+// reasons each one receives the update and the mentions that were dropped
+func (c *userController) ExplainRecipients(senderEmail, text, cursor string, limit int) (*entities.RecipientExplanation, error) {
+	page, err := newPageRequest(cursor, limit)
 	if err != nil {
 		return nil, err
 	}
 
-	subscribers, err := c.userRepo.GetSubscribersByUserID(sender.ID)
+	sender, err := c.userRepo.GetUserByEmail(senderEmail)
 	if err != nil {
 		return nil, err
 	}
 
-	recipients := make(map[int]*entities.User)
+	mentionedEmails, unknownHandles, err := c.resolveMentions(text)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, friend := range senderFriends {
-		recipients[friend.ID] = friend
+	keywords := utils.ExtractKeywords(text)
+
+	recipients, err := c.userRepo.GetRecipients(sender, mentionedEmails, keywords, page)
+	if err != nil {
+		return nil, err
 	}
+	recipients.Recipients, recipients.NextCursor = trimPage(recipients.Recipients, limit, func(recipient *entities.Recipient) string {
+		return recipient.User.Email
+	})
 
-	for _, subscriber := range subscribers {
-		recipients[subscriber.ID] = subscriber
+	droppedMentions, err := c.userRepo.GetDroppedMentions(sender, mentionedEmails, keywords)
+	if err != nil {
+		return nil, err
 	}
 
-	// Batch check bidirectional blocks for all mentioned users
-	if len(mentionedUsers) > 0 {
-		mentionedUserIDs := make([]int, len(mentionedUsers))
-		for i, user := range mentionedUsers {
-			if user.Email != senderEmail {
-				mentionedUserIDs[i] = user.ID
-			}
-		}
+	for _, handle := range unknownHandles {
+		droppedMentions = append(droppedMentions, &entities.DroppedMention{
+			Handle: handle,
+			Reason: entities.DroppedMentionUnknown,
+		})
+	}
 
-		blockedUsers, err := c.userRepo.CheckBidirectionalBlocksBatch(sender.ID, mentionedUserIDs)
+	return &entities.RecipientExplanation{
+		RecipientPage:   *recipients,
+		DroppedMentions: droppedMentions,
+	}, nil
+}
+
+// newPageRequest decodes the cursor of a list into the email the page starts
+// after and asks for one extra item to know whether another page follows. A
+// zero limit asks for the whole list
+func newPageRequest(cursor string, limit int) (entities.PageRequest, error) {
+	var page entities.PageRequest
+	if cursor != "" {
+		after, err := utils.DecodeCursor(cursor)
 		if err != nil {
-			return nil, err
+			return page, errors.ErrInvalidCursor
 		}
+		page.After = after
+	}
+	if limit > 0 {
+		page.Limit = limit + 1
+	}
+	return page, nil
+}
 
-		for _, mentioned := range mentionedUsers {
-			if !blockedUsers[mentioned.ID] {
-				recipients[mentioned.ID] = mentioned
-			}
+// trimPage drops the extra item asked for by newPageRequest and returns the
+// cursor of the next page, or "" on the last page
+func trimPage[T any](items []T, limit int, email func(T) string) ([]T, string) {
+	if limit <= 0 || len(items) <= limit {
+		return items, ""
+	}
+	items = items[:limit]
+	return items, utils.EncodeCursor(email(items[limit-1]))
+}
+
+func trimUserPage(page *entities.UserPage, limit int) *entities.UserPage {
+	page.Users, page.NextCursor = trimPage(page.Users, limit, func(user *entities.User) string {
+		return user.Email
+	})
+	return page
+}
+
+// lowerEmails returns emails in lower case, the way they are stored, so they
+// compare with each other and with stored emails regardless of case
+func lowerEmails(emails []string) []string {
+	lowered := make([]string, len(emails))
+	for i, email := range emails {
+		lowered[i] = strings.ToLower(email)
+	}
+	return lowered
+}
+
+func trimUserPages(pages map[int]*entities.UserPage, limit int) map[int]*entities.UserPage {
+	for _, page := range pages {
+		trimUserPage(page, limit)
+	}
+	return pages
+}
+
+func userIDsOf(users []*entities.User) []int {
+	ids := make([]int, len(users))
+	for i, user := range users {
+		ids[i] = user.ID
+	}
+	return ids
+}
+
+// resolveMentions parses the mentions in text and returns the mentioned emails,
+// with @handles replaced by their owners' emails, and the handles nobody owns
+func (c *userController) resolveMentions(text string) ([]string, []string, error) {
+	mentions := utils.ParseMentions(text)
+	if len(mentions.Handles) == 0 {
+		return mentions.Emails, nil, nil
+	}
+
+	owners, err := c.userRepo.GetEmailsByUsernames(mentions.Handles)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	emails := mentions.Emails
+	seen := make(map[string]bool, len(emails))
+	for _, email := range emails {
+		seen[email] = true
+	}
+
+	var unknownHandles []string
+	for _, handle := range mentions.Handles {
+		email, ok := owners[handle]
+		if !ok {
+			unknownHandles = append(unknownHandles, handle)
+			continue
+		}
+		if !seen[email] {
+			seen[email] = true
+			emails = append(emails, email)
 		}
 	}
 
-	return slices.Collect(maps.Values(recipients)), nil
+	return emails, unknownHandles, nil
+}
+
+func (c *userController) CreateUser(email string) (*entities.User, error) {
+	return c.userRepo.CreateUser(email)
+}
+
+func (c *userController) GetUser(email string) (*entities.User, error) {
+	return c.userRepo.GetUserByEmail(email)
+}
+
+// GetUsersByEmails returns the users with the given emails, skipping the emails
+// nobody has
+func (c *userController) GetUsersByEmails(emails []string) ([]*entities.User, error) {
+	return c.userRepo.GetUsersByEmails(emails)
+}
+
+// SetUsername gives the user the handle others can mention them by as @username
+func (c *userController) SetUsername(email, username string) error {
+	user, err := c.userRepo.GetUserByEmail(email)
+	if err != nil {
+		return err
+	}
+
+	return c.userRepo.SetUsername(user, strings.ToLower(username))
+}
+
+func (c *userController) GetSettings(email string) (*entities.UserSettings, error) {
+	user, err := c.userRepo.GetUserByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.userRepo.GetUserSettings(user)
+}
+
+func (c *userController) UpdateSettings(email string, mentionPolicy entities.MentionPolicy) (*entities.UserSettings, error) {
+	user, err := c.userRepo.GetUserByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := &entities.UserSettings{
+		User:          user,
+		MentionPolicy: mentionPolicy,
+	}
+	if err := c.userRepo.UpdateUserSettings(settings); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+func (c *userController) GetUsers() ([]*entities.User, error) {
+	return c.userRepo.GetAllUsers()
+}
+
+func (c *userController) DeleteUser(email string) error {
+	user, err := c.userRepo.GetUserByEmail(email)
+	if err != nil {
+		return err
+	}
+
+	return c.userRepo.DeleteUser(user)
 }
@@ -2,124 +2,22 @@ package controller
 
 import (
 	"assignment/internal/domain/entities"
+	"assignment/internal/domain/interfaces"
+	"assignment/internal/pubsub"
 	"assignment/mocks"
 	"assignment/pkg/errors"
+	"assignment/pkg/utils"
 	stderrors "errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
 
-func TestCreateFriendships(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	tests := []struct {
-		name        string
-		user1Email  string
-		user2Email  string
-		setupMock   func(mockRepo *mocks.MockUserRepositoryInterface)
-		wantErr     bool
-		wantErrType errors.ErrorType
-		wantErrMsg  string
-	}{
-		{
-			name:       "successful friendship creation",
-			user1Email: "a@example.com",
-			user2Email: "b@example.com",
-			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
-				user1 := &entities.User{ID: 1, Email: "a@example.com"}
-				user2 := &entities.User{ID: 2, Email: "b@example.com"}
-				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(user1, nil)
-				mockRepo.EXPECT().GetUserByEmail("b@example.com").Return(user2, nil)
-				mockRepo.EXPECT().CheckBidirectionalBlock(1, 2).Return(false, nil)
-				mockRepo.EXPECT().CreateFriendship(user1, user2).Return(nil)
-			},
-			wantErr: false,
-		},
-		{
-			name:       "same email friendship should fail",
-			user1Email: "a@example.com",
-			user2Email: "a@example.com",
-			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
-				// No mock expectations needed as validation happens before repository calls
-			},
-			wantErr:     true,
-			wantErrType: errors.ErrorTypeBusiness,
-			wantErrMsg:  "Cannot add yourself as a friend",
-		},
-		{
-			name:       "repo error",
-			user1Email: "a@example.com",
-			user2Email: "b@example.com",
-			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
-				user1 := &entities.User{ID: 1, Email: "a@example.com"}
-				user2 := &entities.User{ID: 2, Email: "b@example.com"}
-
-				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(user1, nil)
-				mockRepo.EXPECT().GetUserByEmail("b@example.com").Return(user2, nil)
-				mockRepo.EXPECT().CheckBidirectionalBlock(1, 2).Return(false, nil)
-				mockRepo.EXPECT().CreateFriendship(user1, user2).Return(errors.New(errors.ErrorTypeDatabase, "database connection failed"))
-			},
-			wantErr:     true,
-			wantErrType: errors.ErrorTypeDatabase,
-			wantErrMsg:  "database connection failed",
-		},
-		{
-			name:       "friendship blocked - user1 blocks user2",
-			user1Email: "a@example.com",
-			user2Email: "b@example.com",
-			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
-				user1 := &entities.User{ID: 1, Email: "a@example.com"}
-				user2 := &entities.User{ID: 2, Email: "b@example.com"}
-
-				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(user1, nil)
-				mockRepo.EXPECT().GetUserByEmail("b@example.com").Return(user2, nil)
-				mockRepo.EXPECT().CheckBidirectionalBlock(1, 2).Return(true, nil)
-			},
-			wantErr:     true,
-			wantErrType: errors.ErrorTypeForbidden,
-			wantErrMsg:  "Cannot perform action on blocked user",
-		},
-		{
-			name:       "block check error",
-			user1Email: "a@example.com",
-			user2Email: "b@example.com",
-			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
-				user1 := &entities.User{ID: 1, Email: "a@example.com"}
-				user2 := &entities.User{ID: 2, Email: "b@example.com"}
-
-				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(user1, nil)
-				mockRepo.EXPECT().GetUserByEmail("b@example.com").Return(user2, nil)
-				mockRepo.EXPECT().CheckBidirectionalBlock(1, 2).Return(false, errors.New(errors.ErrorTypeDatabase, "Failed to check block existence"))
-			},
-			wantErr:     true,
-			wantErrType: errors.ErrorTypeDatabase,
-			wantErrMsg:  "Failed to check block existence",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
-			tt.setupMock(mockRepo)
-
-			controller := NewUserController(mockRepo)
-			err := controller.CreateFriendship(tt.user1Email, tt.user2Email)
-
-			if !tt.wantErr {
-				assert.NoError(t, err)
-				return
-			}
-
-			assert.Error(t, err)
-			var appErr *errors.AppError
-			assert.True(t, stderrors.As(err, &appErr))
-			assert.Equal(t, tt.wantErrType, appErr.Type)
-			assert.Equal(t, tt.wantErrMsg, appErr.Message)
-		})
-	}
+// newTestEventHub returns a hub without subscribers, so published events go nowhere
+func newTestEventHub() *pubsub.Hub[*entities.UserEvent] {
+	return pubsub.NewHub[*entities.UserEvent](pubsub.DefaultBufferSize)
 }
 
 func TestGetFriendList(t *testing.T) {
@@ -145,7 +43,7 @@ func TestGetFriendList(t *testing.T) {
 					{ID: 3, Email: "jane@example.com"},
 				}
 				mockRepo.EXPECT().GetUserByEmail("andy@example.com").Return(user, nil)
-				mockRepo.EXPECT().GetFriendList(user).Return(friends, nil)
+				mockRepo.EXPECT().GetFriendList(user, entities.PageRequest{}).Return(&entities.UserPage{Users: friends}, nil)
 			},
 			wantErr: false,
 			expectedFriends: []*entities.User{
@@ -159,7 +57,7 @@ func TestGetFriendList(t *testing.T) {
 			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
 				user := &entities.User{ID: 1, Email: "andy@example.com"}
 				mockRepo.EXPECT().GetUserByEmail("andy@example.com").Return(user, nil)
-				mockRepo.EXPECT().GetFriendList(user).Return([]*entities.User{}, nil)
+				mockRepo.EXPECT().GetFriendList(user, entities.PageRequest{}).Return(&entities.UserPage{Users: []*entities.User{}}, nil)
 			},
 			wantErr:         false,
 			expectedFriends: []*entities.User{},
@@ -180,7 +78,7 @@ func TestGetFriendList(t *testing.T) {
 			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
 				user := &entities.User{ID: 1, Email: "andy@example.com"}
 				mockRepo.EXPECT().GetUserByEmail("andy@example.com").Return(user, nil)
-				mockRepo.EXPECT().GetFriendList(user).Return(nil, errors.New(errors.ErrorTypeDatabase, "database connection failed"))
+				mockRepo.EXPECT().GetFriendList(user, entities.PageRequest{}).Return(nil, errors.New(errors.ErrorTypeDatabase, "database connection failed"))
 			},
 			wantErr:     true,
 			wantErrType: errors.ErrorTypeDatabase,
@@ -193,11 +91,12 @@ func TestGetFriendList(t *testing.T) {
 			mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
 			tt.setupMock(mockRepo)
 
-			controller := NewUserController(mockRepo)
-			friends, err := controller.GetFriendList(tt.email)
+			controller := NewUserController(mockRepo, newTestEventHub())
+			page, err := controller.GetFriendList(tt.email, "", 0)
 
 			if !tt.wantErr {
 				assert.NoError(t, err)
+				friends := page.Users
 				assert.Equal(t, len(tt.expectedFriends), len(friends))
 				for i, expectedFriend := range tt.expectedFriends {
 					assert.Equal(t, expectedFriend.ID, friends[i].ID)
@@ -215,14 +114,61 @@ func TestGetFriendList(t *testing.T) {
 	}
 }
 
+func TestGetFriendListPagination(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := &entities.User{ID: 1, Email: "andy@example.com"}
+	friends := []*entities.User{
+		{ID: 2, Email: "bob@example.com"},
+		{ID: 3, Email: "jane@example.com"},
+		{ID: 4, Email: "john@example.com"},
+	}
+
+	t.Run("first page asks for one extra friend", func(t *testing.T) {
+		mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+		mockRepo.EXPECT().GetUserByEmail("andy@example.com").Return(user, nil)
+		mockRepo.EXPECT().GetFriendList(user, entities.PageRequest{Limit: 3}).Return(&entities.UserPage{Users: friends, Total: 5}, nil)
+
+		controller := NewUserController(mockRepo, newTestEventHub())
+		page, err := controller.GetFriendList("andy@example.com", "", 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, friends[:2], page.Users)
+		assert.Equal(t, 5, page.Total)
+		assert.Equal(t, utils.EncodeCursor("jane@example.com"), page.NextCursor)
+	})
+
+	t.Run("last page has no next cursor", func(t *testing.T) {
+		mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+		mockRepo.EXPECT().GetUserByEmail("andy@example.com").Return(user, nil)
+		mockRepo.EXPECT().GetFriendList(user, entities.PageRequest{After: "jane@example.com", Limit: 3}).Return(&entities.UserPage{Users: friends[2:], Total: 5}, nil)
+
+		controller := NewUserController(mockRepo, newTestEventHub())
+		page, err := controller.GetFriendList("andy@example.com", utils.EncodeCursor("jane@example.com"), 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, friends[2:], page.Users)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+
+		controller := NewUserController(mockRepo, newTestEventHub())
+		_, err := controller.GetFriendList("andy@example.com", "not a cursor", 2)
+
+		assert.Equal(t, errors.ErrInvalidCursor, err)
+	})
+}
+
 func TestGetCommonFriends(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	tests := []struct {
 		name                  string
-		email1                string
-		email2                string
+		emails                []string
 		setupMock             func(mockRepo *mocks.MockUserRepositoryInterface)
 		wantErr               bool
 		wantErrType           errors.ErrorType
@@ -231,18 +177,18 @@ func TestGetCommonFriends(t *testing.T) {
 	}{
 		{
 			name:   "successful common friends retrieval with common friends",
-			email1: "andy@example.com",
-			email2: "john@example.com",
+			emails: []string{"andy@example.com", "john@example.com"},
 			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
-				user1 := &entities.User{ID: 1, Email: "andy@example.com"}
-				user2 := &entities.User{ID: 2, Email: "john@example.com"}
+				users := []*entities.User{
+					{ID: 1, Email: "andy@example.com"},
+					{ID: 2, Email: "john@example.com"},
+				}
 				commonFriends := []*entities.User{
 					{ID: 3, Email: "jane@example.com"},
 					{ID: 4, Email: "bob@example.com"},
 				}
-				mockRepo.EXPECT().GetUserByEmail("andy@example.com").Return(user1, nil)
-				mockRepo.EXPECT().GetUserByEmail("john@example.com").Return(user2, nil)
-				mockRepo.EXPECT().GetCommonFriends(user1, user2).Return(commonFriends, nil)
+				mockRepo.EXPECT().GetUsersByEmails([]string{"andy@example.com", "john@example.com"}).Return(users, nil)
+				mockRepo.EXPECT().GetCommonFriends(users, entities.PageRequest{}).Return(&entities.UserPage{Users: commonFriends}, nil)
 			},
 			wantErr: false,
 			expectedCommonFriends: []*entities.User{
@@ -250,24 +196,38 @@ func TestGetCommonFriends(t *testing.T) {
 				{ID: 4, Email: "bob@example.com"},
 			},
 		},
+		{
+			name:   "successful common friends retrieval across three users",
+			emails: []string{"andy@example.com", "john@example.com", "kate@example.com"},
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				users := []*entities.User{
+					{ID: 1, Email: "andy@example.com"},
+					{ID: 2, Email: "john@example.com"},
+					{ID: 5, Email: "kate@example.com"},
+				}
+				mockRepo.EXPECT().GetUsersByEmails([]string{"andy@example.com", "john@example.com", "kate@example.com"}).Return(users, nil)
+				mockRepo.EXPECT().GetCommonFriends(users, entities.PageRequest{}).Return(&entities.UserPage{Users: []*entities.User{{ID: 3, Email: "jane@example.com"}}}, nil)
+			},
+			wantErr:               false,
+			expectedCommonFriends: []*entities.User{{ID: 3, Email: "jane@example.com"}},
+		},
 		{
 			name:   "successful common friends retrieval with no common friends",
-			email1: "andy@example.com",
-			email2: "john@example.com",
+			emails: []string{"andy@example.com", "john@example.com"},
 			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
-				user1 := &entities.User{ID: 1, Email: "andy@example.com"}
-				user2 := &entities.User{ID: 2, Email: "john@example.com"}
-				mockRepo.EXPECT().GetUserByEmail("andy@example.com").Return(user1, nil)
-				mockRepo.EXPECT().GetUserByEmail("john@example.com").Return(user2, nil)
-				mockRepo.EXPECT().GetCommonFriends(user1, user2).Return([]*entities.User{}, nil)
+				users := []*entities.User{
+					{ID: 1, Email: "andy@example.com"},
+					{ID: 2, Email: "john@example.com"},
+				}
+				mockRepo.EXPECT().GetUsersByEmails([]string{"andy@example.com", "john@example.com"}).Return(users, nil)
+				mockRepo.EXPECT().GetCommonFriends(users, entities.PageRequest{}).Return(&entities.UserPage{Users: []*entities.User{}}, nil)
 			},
 			wantErr:               false,
 			expectedCommonFriends: []*entities.User{},
 		},
 		{
 			name:   "cannot get common friends with self",
-			email1: "andy@example.com",
-			email2: "andy@example.com",
+			emails: []string{"andy@example.com", "andy@example.com"},
 			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
 				// No mock expectations needed as validation happens before repository calls
 			},
@@ -276,39 +236,83 @@ func TestGetCommonFriends(t *testing.T) {
 			wantErrMsg:  "Cannot get common friends with yourself",
 		},
 		{
-			name:   "first user not found",
-			email1: "nonexistent@example.com",
-			email2: "john@example.com",
+			name:   "duplicate email among several users",
+			emails: []string{"andy@example.com", "john@example.com", "andy@example.com"},
 			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
-				mockRepo.EXPECT().GetUserByEmail("nonexistent@example.com").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User with email '%s' not found", "nonexistent@example.com"))
+				// No mock expectations needed as validation happens before repository calls
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeBusiness,
+			wantErrMsg:  "Cannot get common friends with yourself",
+		},
+		{
+			name:   "emails in mixed case are looked up in lower case",
+			emails: []string{"Andy@Example.com", "JOHN@example.com"},
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				users := []*entities.User{
+					{ID: 1, Email: "andy@example.com"},
+					{ID: 2, Email: "john@example.com"},
+				}
+				mockRepo.EXPECT().GetUsersByEmails([]string{"andy@example.com", "john@example.com"}).Return(users, nil)
+				mockRepo.EXPECT().GetCommonFriends(users, entities.PageRequest{}).Return(&entities.UserPage{Users: []*entities.User{{ID: 3, Email: "jane@example.com"}}}, nil)
+			},
+			wantErr:               false,
+			expectedCommonFriends: []*entities.User{{ID: 3, Email: "jane@example.com"}},
+		},
+		{
+			name:   "same user in another case",
+			emails: []string{"Andy@example.com", "andy@example.com"},
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				// No mock expectations needed as validation happens before repository calls
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeBusiness,
+			wantErrMsg:  "Cannot get common friends with yourself",
+		},
+		{
+			name:   "mixed case email of an existing user is not reported missing",
+			emails: []string{"Andy@example.com", "Nonexistent@example.com"},
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetUsersByEmails([]string{"andy@example.com", "nonexistent@example.com"}).Return([]*entities.User{
+					{ID: 1, Email: "andy@example.com"},
+				}, nil)
 			},
 			wantErr:     true,
 			wantErrType: errors.ErrorTypeNotFound,
-			wantErrMsg:  "User with email 'nonexistent@example.com' not found",
+			wantErrMsg:  "User not found: nonexistent@example.com",
 		},
 		{
-			name:   "second user not found",
-			email1: "andy@example.com",
-			email2: "nonexistent@example.com",
+			name:   "user not found",
+			emails: []string{"andy@example.com", "nonexistent@example.com"},
 			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
-				user1 := &entities.User{ID: 1, Email: "andy@example.com"}
-				mockRepo.EXPECT().GetUserByEmail("andy@example.com").Return(user1, nil)
-				mockRepo.EXPECT().GetUserByEmail("nonexistent@example.com").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User with email '%s' not found", "nonexistent@example.com"))
+				mockRepo.EXPECT().GetUsersByEmails([]string{"andy@example.com", "nonexistent@example.com"}).Return([]*entities.User{
+					{ID: 1, Email: "andy@example.com"},
+				}, nil)
 			},
 			wantErr:     true,
 			wantErrType: errors.ErrorTypeNotFound,
-			wantErrMsg:  "User with email 'nonexistent@example.com' not found",
+			wantErrMsg:  "User not found: nonexistent@example.com",
+		},
+		{
+			name:   "repository error when getting users",
+			emails: []string{"andy@example.com", "john@example.com"},
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetUsersByEmails([]string{"andy@example.com", "john@example.com"}).Return(nil, errors.New(errors.ErrorTypeDatabase, "Failed to fetch users by emails"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeDatabase,
+			wantErrMsg:  "Failed to fetch users by emails",
 		},
 		{
 			name:   "repository error when getting common friends",
-			email1: "andy@example.com",
-			email2: "john@example.com",
+			emails: []string{"andy@example.com", "john@example.com"},
 			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
-				user1 := &entities.User{ID: 1, Email: "andy@example.com"}
-				user2 := &entities.User{ID: 2, Email: "john@example.com"}
-				mockRepo.EXPECT().GetUserByEmail("andy@example.com").Return(user1, nil)
-				mockRepo.EXPECT().GetUserByEmail("john@example.com").Return(user2, nil)
-				mockRepo.EXPECT().GetCommonFriends(user1, user2).Return(nil, errors.New(errors.ErrorTypeDatabase, "database connection failed"))
+				users := []*entities.User{
+					{ID: 1, Email: "andy@example.com"},
+					{ID: 2, Email: "john@example.com"},
+				}
+				mockRepo.EXPECT().GetUsersByEmails([]string{"andy@example.com", "john@example.com"}).Return(users, nil)
+				mockRepo.EXPECT().GetCommonFriends(users, entities.PageRequest{}).Return(nil, errors.New(errors.ErrorTypeDatabase, "database connection failed"))
 			},
 			wantErr:     true,
 			wantErrType: errors.ErrorTypeDatabase,
@@ -321,11 +325,12 @@ func TestGetCommonFriends(t *testing.T) {
 			mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
 			tt.setupMock(mockRepo)
 
-			controller := NewUserController(mockRepo)
-			commonFriends, err := controller.GetCommonFriends(tt.email1, tt.email2)
+			controller := NewUserController(mockRepo, newTestEventHub())
+			page, err := controller.GetCommonFriends(tt.emails, "", 0)
 
 			if !tt.wantErr {
 				assert.NoError(t, err)
+				commonFriends := page.Users
 				assert.Equal(t, len(tt.expectedCommonFriends), len(commonFriends))
 				for i, expectedFriend := range tt.expectedCommonFriends {
 					assert.Equal(t, expectedFriend.ID, commonFriends[i].ID)
@@ -478,7 +483,7 @@ func TestCreateSubscription(t *testing.T) {
 			mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
 			tt.setupMock(mockRepo)
 
-			controller := NewUserController(mockRepo)
+			controller := NewUserController(mockRepo, newTestEventHub())
 			err := controller.CreateSubscription(tt.requestorEmail, tt.targetEmail)
 
 			if !tt.wantErr {
@@ -611,7 +616,7 @@ func TestCreateBlock(t *testing.T) {
 			mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
 			tt.setupMock(mockRepo)
 
-			controller := NewUserController(mockRepo)
+			controller := NewUserController(mockRepo, newTestEventHub())
 			err := controller.CreateBlock(tt.requestorEmail, tt.targetEmail)
 
 			if !tt.wantErr {
@@ -628,6 +633,15 @@ func TestCreateBlock(t *testing.T) {
 	}
 }
 
+// asRecipients wraps users as recipients for repository mocks
+func asRecipientPage(users []*entities.User) *entities.RecipientPage {
+	recipients := make([]*entities.Recipient, len(users))
+	for i, user := range users {
+		recipients[i] = &entities.Recipient{User: user}
+	}
+	return &entities.RecipientPage{Recipients: recipients, Total: len(recipients)}
+}
+
 func TestGetRecipients(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -648,25 +662,20 @@ func TestGetRecipients(t *testing.T) {
 			text:        "Hello @mentioned@example.com how are you?",
 			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
 				sender := &entities.User{ID: 1, Email: "sender@example.com"}
-				mentioned := &entities.User{ID: 4, Email: "mentioned@example.com"}
-				friends := []*entities.User{
+				recipients := []*entities.User{
 					{ID: 2, Email: "friend@example.com"},
-				}
-				subscribers := []*entities.User{
+					{ID: 4, Email: "mentioned@example.com"},
 					{ID: 3, Email: "subscriber@example.com"},
 				}
-				
+
 				mockRepo.EXPECT().GetUserByEmail("sender@example.com").Return(sender, nil)
-				mockRepo.EXPECT().GetUsersByEmails([]string{"mentioned@example.com"}).Return([]*entities.User{mentioned}, nil)
-				mockRepo.EXPECT().GetFriendList(sender).Return(friends, nil)
-				mockRepo.EXPECT().GetSubscribersByUserID(1).Return(subscribers, nil)
-				mockRepo.EXPECT().CheckBidirectionalBlocksBatch(1, []int{4}).Return(map[int]bool{4: false}, nil)
+				mockRepo.EXPECT().GetRecipients(sender, []string{"mentioned@example.com"}, gomock.Any(), entities.PageRequest{}).Return(asRecipientPage(recipients), nil)
 			},
 			wantErr: false,
 			expectedRecipients: []*entities.User{
 				{ID: 2, Email: "friend@example.com"},
-				{ID: 3, Email: "subscriber@example.com"},
 				{ID: 4, Email: "mentioned@example.com"},
+				{ID: 3, Email: "subscriber@example.com"},
 			},
 		},
 		{
@@ -675,114 +684,126 @@ func TestGetRecipients(t *testing.T) {
 			text:        "Hello everyone!",
 			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
 				sender := &entities.User{ID: 1, Email: "sender@example.com"}
-				friends := []*entities.User{
+				recipients := []*entities.User{
 					{ID: 2, Email: "friend1@example.com"},
-					{ID: 3, Email: "friend2@example.com"},
+					{ID: 3, Email: "subscriber1@example.com"},
 				}
-				subscribers := []*entities.User{
-					{ID: 4, Email: "subscriber@example.com"},
-				}
-				
+
 				mockRepo.EXPECT().GetUserByEmail("sender@example.com").Return(sender, nil)
-				mockRepo.EXPECT().GetFriendList(sender).Return(friends, nil)
-				mockRepo.EXPECT().GetSubscribersByUserID(1).Return(subscribers, nil)
+				mockRepo.EXPECT().GetRecipients(sender, []string(nil), gomock.Any(), entities.PageRequest{}).Return(asRecipientPage(recipients), nil)
 			},
 			wantErr: false,
 			expectedRecipients: []*entities.User{
 				{ID: 2, Email: "friend1@example.com"},
-				{ID: 3, Email: "friend2@example.com"},
-				{ID: 4, Email: "subscriber@example.com"},
+				{ID: 3, Email: "subscriber1@example.com"},
 			},
 		},
 		{
-			name:        "mentioned user is blocked - should be excluded",
+			name:        "all mentioned emails are passed to the repository",
 			senderEmail: "sender@example.com",
-			text:        "Hello @blocked@example.com",
+			text:        "Hi a@example.com and b@example.com",
 			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
 				sender := &entities.User{ID: 1, Email: "sender@example.com"}
-				mentioned := &entities.User{ID: 4, Email: "blocked@example.com"}
-				
+
 				mockRepo.EXPECT().GetUserByEmail("sender@example.com").Return(sender, nil)
-				mockRepo.EXPECT().GetUsersByEmails([]string{"blocked@example.com"}).Return([]*entities.User{mentioned}, nil)
-				mockRepo.EXPECT().GetFriendList(sender).Return([]*entities.User{}, nil)
-				mockRepo.EXPECT().GetSubscribersByUserID(1).Return([]*entities.User{}, nil)
-				mockRepo.EXPECT().CheckBidirectionalBlocksBatch(1, []int{4}).Return(map[int]bool{4: true}, nil)
+				mockRepo.EXPECT().GetRecipients(sender, []string{"a@example.com", "b@example.com"}, gomock.Any(), entities.PageRequest{}).Return(asRecipientPage([]*entities.User{
+					{ID: 5, Email: "a@example.com"},
+				}), nil)
 			},
 			wantErr: false,
-			expectedRecipients: []*entities.User{},
+			expectedRecipients: []*entities.User{
+				{ID: 5, Email: "a@example.com"},
+			},
 		},
 		{
-			name:        "sender not found",
-			senderEmail: "nonexistent@example.com",
-			text:        "Hello world!",
+			name:        "update keywords are passed for subscription filters",
+			senderEmail: "sender@example.com",
+			text:        "Shipping #Go today",
 			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
-				mockRepo.EXPECT().GetUserByEmail("nonexistent@example.com").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User with email '%s' not found", "nonexistent@example.com"))
+				sender := &entities.User{ID: 1, Email: "sender@example.com"}
+
+				mockRepo.EXPECT().GetUserByEmail("sender@example.com").Return(sender, nil)
+				mockRepo.EXPECT().GetRecipients(sender, []string(nil), []string{"shipping", "#go", "go", "today"}, entities.PageRequest{}).Return(&entities.RecipientPage{Recipients: []*entities.Recipient{}}, nil)
 			},
-			wantErr:     true,
-			wantErrType: errors.ErrorTypeNotFound,
-			wantErrMsg:  "User with email 'nonexistent@example.com' not found",
+			wantErr:            false,
+			expectedRecipients: []*entities.User{},
 		},
 		{
-			name:        "error getting mentioned users",
+			name:        "mentions are normalised and handles resolved to emails",
 			senderEmail: "sender@example.com",
-			text:        "Hello @mentioned@example.com",
+			text:        "Hi A@Example.com. and (a@example.com), @Bob @bob @ghost",
 			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
 				sender := &entities.User{ID: 1, Email: "sender@example.com"}
-				
+
 				mockRepo.EXPECT().GetUserByEmail("sender@example.com").Return(sender, nil)
-				mockRepo.EXPECT().GetUsersByEmails([]string{"mentioned@example.com"}).Return(nil, errors.New(errors.ErrorTypeDatabase, "database connection failed"))
+				mockRepo.EXPECT().GetEmailsByUsernames([]string{"bob", "ghost"}).Return(map[string]string{"bob": "bob@example.com"}, nil)
+				mockRepo.EXPECT().GetRecipients(sender, []string{"a@example.com", "bob@example.com"}, gomock.Any(), entities.PageRequest{}).Return(asRecipientPage([]*entities.User{
+					{ID: 5, Email: "a@example.com"},
+					{ID: 6, Email: "bob@example.com"},
+				}), nil)
+			},
+			wantErr: false,
+			expectedRecipients: []*entities.User{
+				{ID: 5, Email: "a@example.com"},
+				{ID: 6, Email: "bob@example.com"},
 			},
-			wantErr:     true,
-			wantErrType: errors.ErrorTypeDatabase,
-			wantErrMsg:  "database connection failed",
 		},
 		{
-			name:        "error getting friend list",
+			name:        "handle and email of the same user are passed once",
 			senderEmail: "sender@example.com",
-			text:        "Hello world!",
+			text:        "Hi @bob (bob@example.com)",
 			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
 				sender := &entities.User{ID: 1, Email: "sender@example.com"}
-				
+
 				mockRepo.EXPECT().GetUserByEmail("sender@example.com").Return(sender, nil)
-				mockRepo.EXPECT().GetFriendList(sender).Return(nil, errors.New(errors.ErrorTypeDatabase, "failed to get friends"))
+				mockRepo.EXPECT().GetEmailsByUsernames([]string{"bob"}).Return(map[string]string{"bob": "bob@example.com"}, nil)
+				mockRepo.EXPECT().GetRecipients(sender, []string{"bob@example.com"}, gomock.Any(), entities.PageRequest{}).Return(asRecipientPage([]*entities.User{
+					{ID: 6, Email: "bob@example.com"},
+				}), nil)
+			},
+			wantErr: false,
+			expectedRecipients: []*entities.User{
+				{ID: 6, Email: "bob@example.com"},
 			},
-			wantErr:     true,
-			wantErrType: errors.ErrorTypeDatabase,
-			wantErrMsg:  "failed to get friends",
 		},
 		{
-			name:        "error getting subscribers",
+			name:        "error resolving handles",
 			senderEmail: "sender@example.com",
-			text:        "Hello world!",
+			text:        "Hi @bob",
 			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
 				sender := &entities.User{ID: 1, Email: "sender@example.com"}
-				friends := []*entities.User{}
-				
+
 				mockRepo.EXPECT().GetUserByEmail("sender@example.com").Return(sender, nil)
-				mockRepo.EXPECT().GetFriendList(sender).Return(friends, nil)
-				mockRepo.EXPECT().GetSubscribersByUserID(1).Return(nil, errors.New(errors.ErrorTypeDatabase, "failed to get subscribers"))
+				mockRepo.EXPECT().GetEmailsByUsernames([]string{"bob"}).Return(nil, errors.New(errors.ErrorTypeDatabase, "Failed to fetch users by usernames"))
 			},
 			wantErr:     true,
 			wantErrType: errors.ErrorTypeDatabase,
-			wantErrMsg:  "failed to get subscribers",
+			wantErrMsg:  "Failed to fetch users by usernames",
+		},
+		{
+			name:        "sender not found",
+			senderEmail: "nonexistent@example.com",
+			text:        "Hello world!",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetUserByEmail("nonexistent@example.com").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User with email '%s' not found", "nonexistent@example.com"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeNotFound,
+			wantErrMsg:  "User with email 'nonexistent@example.com' not found",
 		},
 		{
-			name:        "error checking bidirectional blocks",
+			name:        "error resolving recipients",
 			senderEmail: "sender@example.com",
 			text:        "Hello @mentioned@example.com",
 			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
 				sender := &entities.User{ID: 1, Email: "sender@example.com"}
-				mentioned := &entities.User{ID: 4, Email: "mentioned@example.com"}
-				
+
 				mockRepo.EXPECT().GetUserByEmail("sender@example.com").Return(sender, nil)
-				mockRepo.EXPECT().GetUsersByEmails([]string{"mentioned@example.com"}).Return([]*entities.User{mentioned}, nil)
-				mockRepo.EXPECT().GetFriendList(sender).Return([]*entities.User{}, nil)
-				mockRepo.EXPECT().GetSubscribersByUserID(1).Return([]*entities.User{}, nil)
-				mockRepo.EXPECT().CheckBidirectionalBlocksBatch(1, []int{4}).Return(nil, errors.New(errors.ErrorTypeDatabase, "failed to check blocks"))
+				mockRepo.EXPECT().GetRecipients(sender, []string{"mentioned@example.com"}, gomock.Any(), entities.PageRequest{}).Return(nil, errors.New(errors.ErrorTypeDatabase, "Failed to fetch recipients"))
 			},
 			wantErr:     true,
 			wantErrType: errors.ErrorTypeDatabase,
-			wantErrMsg:  "failed to check blocks",
+			wantErrMsg:  "Failed to fetch recipients",
 		},
 		{
 			name:        "no recipients - empty friends, subscribers, and no mentions",
@@ -790,32 +811,13 @@ func TestGetRecipients(t *testing.T) {
 			text:        "Hello world!",
 			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
 				sender := &entities.User{ID: 1, Email: "sender@example.com"}
-				
+
 				mockRepo.EXPECT().GetUserByEmail("sender@example.com").Return(sender, nil)
-				mockRepo.EXPECT().GetFriendList(sender).Return([]*entities.User{}, nil)
-				mockRepo.EXPECT().GetSubscribersByUserID(1).Return([]*entities.User{}, nil)
+				mockRepo.EXPECT().GetRecipients(sender, []string(nil), gomock.Any(), entities.PageRequest{}).Return(&entities.RecipientPage{Recipients: []*entities.Recipient{}}, nil)
 			},
 			wantErr: false,
 			expectedRecipients: []*entities.User{},
 		},
-		{
-			name:        "mentioned user is same as sender - should be excluded from batch check",
-			senderEmail: "sender@example.com",
-			text:        "Hello @sender@example.com world!",
-			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
-				sender := &entities.User{ID: 1, Email: "sender@example.com"}
-				
-				mockRepo.EXPECT().GetUserByEmail("sender@example.com").Return(sender, nil)
-				mockRepo.EXPECT().GetUsersByEmails([]string{"sender@example.com"}).Return([]*entities.User{sender}, nil)
-				mockRepo.EXPECT().GetFriendList(sender).Return([]*entities.User{}, nil)
-				mockRepo.EXPECT().GetSubscribersByUserID(1).Return([]*entities.User{}, nil)
-				mockRepo.EXPECT().CheckBidirectionalBlocksBatch(1, []int{0}).Return(map[int]bool{}, nil)
-			},
-			wantErr: false,
-			expectedRecipients: []*entities.User{
-				{ID: 1, Email: "sender@example.com"},
-			},
-		},
 	}
 
 	for _, tt := range tests {
@@ -823,11 +825,12 @@ func TestGetRecipients(t *testing.T) {
 			mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
 			tt.setupMock(mockRepo)
 
-			controller := NewUserController(mockRepo)
-			recipients, err := controller.GetRecipients(tt.senderEmail, tt.text)
+			controller := NewUserController(mockRepo, newTestEventHub())
+			page, err := controller.GetRecipients(tt.senderEmail, tt.text, "", 0)
 
 			if !tt.wantErr {
 				assert.NoError(t, err)
+				recipients := page.Users
 				assert.Equal(t, len(tt.expectedRecipients), len(recipients))
 				
 				// Convert to maps for easier comparison since order might vary
@@ -853,3 +856,2130 @@ func TestGetRecipients(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name         string
+		email        string
+		setupMock    func(mockRepo *mocks.MockUserRepositoryInterface)
+		wantErr      bool
+		wantErrType  errors.ErrorType
+		wantErrMsg   string
+		expectedUser *entities.User
+	}{
+		{
+			name:  "successful user creation",
+			email: "new@example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().CreateUser("new@example.com").Return(&entities.User{ID: 6, Email: "new@example.com"}, nil)
+			},
+			wantErr:      false,
+			expectedUser: &entities.User{ID: 6, Email: "new@example.com"},
+		},
+		{
+			name:  "duplicate email",
+			email: "andy@example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().CreateUser("andy@example.com").Return(nil, errors.New(errors.ErrorTypeConflict, "Resource already exists").WithDetails("Email address already exists"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeConflict,
+			wantErrMsg:  "Resource already exists",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+			tt.setupMock(mockRepo)
+
+			controller := NewUserController(mockRepo, newTestEventHub())
+			user, err := controller.CreateUser(tt.email)
+
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedUser, user)
+				return
+			}
+
+			assert.Error(t, err)
+			var appErr *errors.AppError
+			assert.True(t, stderrors.As(err, &appErr))
+			assert.Equal(t, tt.wantErrType, appErr.Type)
+			assert.Equal(t, tt.wantErrMsg, appErr.Message)
+		})
+	}
+}
+
+func TestGetUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name         string
+		email        string
+		setupMock    func(mockRepo *mocks.MockUserRepositoryInterface)
+		wantErr      bool
+		wantErrType  errors.ErrorType
+		wantErrMsg   string
+		expectedUser *entities.User
+	}{
+		{
+			name:  "existing user",
+			email: "andy@example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetUserByEmail("andy@example.com").Return(&entities.User{ID: 1, Email: "andy@example.com"}, nil)
+			},
+			wantErr:      false,
+			expectedUser: &entities.User{ID: 1, Email: "andy@example.com"},
+		},
+		{
+			name:  "user not found",
+			email: "nonexistent@example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetUserByEmail("nonexistent@example.com").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", "nonexistent@example.com"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeNotFound,
+			wantErrMsg:  "User not found: nonexistent@example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+			tt.setupMock(mockRepo)
+
+			controller := NewUserController(mockRepo, newTestEventHub())
+			user, err := controller.GetUser(tt.email)
+
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedUser, user)
+				return
+			}
+
+			assert.Error(t, err)
+			var appErr *errors.AppError
+			assert.True(t, stderrors.As(err, &appErr))
+			assert.Equal(t, tt.wantErrType, appErr.Type)
+			assert.Equal(t, tt.wantErrMsg, appErr.Message)
+		})
+	}
+}
+
+func TestSetUsername(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := &entities.User{ID: 1, Email: "andy@example.com"}
+
+	tests := []struct {
+		name        string
+		email       string
+		username    string
+		setupMock   func(mockRepo *mocks.MockUserRepositoryInterface)
+		wantErr     bool
+		wantErrType errors.ErrorType
+		wantErrMsg  string
+	}{
+		{
+			name:     "username is stored lowercase",
+			email:    "andy@example.com",
+			username: "Andy_B",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetUserByEmail("andy@example.com").Return(user, nil)
+				mockRepo.EXPECT().SetUsername(user, "andy_b").Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:     "user not found",
+			email:    "nonexistent@example.com",
+			username: "ghost",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetUserByEmail("nonexistent@example.com").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", "nonexistent@example.com"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeNotFound,
+			wantErrMsg:  "User not found: nonexistent@example.com",
+		},
+		{
+			name:     "username taken",
+			email:    "andy@example.com",
+			username: "bob",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetUserByEmail("andy@example.com").Return(user, nil)
+				mockRepo.EXPECT().SetUsername(user, "bob").Return(errors.New(errors.ErrorTypeConflict, "Resource already exists"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeConflict,
+			wantErrMsg:  "Resource already exists",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+			tt.setupMock(mockRepo)
+
+			controller := NewUserController(mockRepo, newTestEventHub())
+			err := controller.SetUsername(tt.email, tt.username)
+
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.Error(t, err)
+			var appErr *errors.AppError
+			assert.True(t, stderrors.As(err, &appErr))
+			assert.Equal(t, tt.wantErrType, appErr.Type)
+			assert.Equal(t, tt.wantErrMsg, appErr.Message)
+		})
+	}
+}
+
+func TestGetSettings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := &entities.User{ID: 1, Email: "andy@example.com"}
+
+	tests := []struct {
+		name             string
+		email            string
+		setupMock        func(mockRepo *mocks.MockUserRepositoryInterface)
+		wantErr          bool
+		wantErrType      errors.ErrorType
+		wantErrMsg       string
+		expectedSettings *entities.UserSettings
+	}{
+		{
+			name:  "existing user",
+			email: "andy@example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetUserByEmail("andy@example.com").Return(user, nil)
+				mockRepo.EXPECT().GetUserSettings(user).Return(&entities.UserSettings{User: user, MentionPolicy: entities.MentionPolicyFriends}, nil)
+			},
+			wantErr:          false,
+			expectedSettings: &entities.UserSettings{User: user, MentionPolicy: entities.MentionPolicyFriends},
+		},
+		{
+			name:  "user not found",
+			email: "nonexistent@example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetUserByEmail("nonexistent@example.com").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", "nonexistent@example.com"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeNotFound,
+			wantErrMsg:  "User not found: nonexistent@example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+			tt.setupMock(mockRepo)
+
+			controller := NewUserController(mockRepo, newTestEventHub())
+			settings, err := controller.GetSettings(tt.email)
+
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedSettings, settings)
+				return
+			}
+
+			assert.Error(t, err)
+			var appErr *errors.AppError
+			assert.True(t, stderrors.As(err, &appErr))
+			assert.Equal(t, tt.wantErrType, appErr.Type)
+			assert.Equal(t, tt.wantErrMsg, appErr.Message)
+		})
+	}
+}
+
+func TestUpdateSettings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := &entities.User{ID: 1, Email: "andy@example.com"}
+
+	tests := []struct {
+		name             string
+		email            string
+		mentionPolicy    entities.MentionPolicy
+		setupMock        func(mockRepo *mocks.MockUserRepositoryInterface)
+		wantErr          bool
+		wantErrType      errors.ErrorType
+		wantErrMsg       string
+		expectedSettings *entities.UserSettings
+	}{
+		{
+			name:          "policy is stored",
+			email:         "andy@example.com",
+			mentionPolicy: entities.MentionPolicyNobody,
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetUserByEmail("andy@example.com").Return(user, nil)
+				mockRepo.EXPECT().UpdateUserSettings(&entities.UserSettings{User: user, MentionPolicy: entities.MentionPolicyNobody}).Return(nil)
+			},
+			wantErr:          false,
+			expectedSettings: &entities.UserSettings{User: user, MentionPolicy: entities.MentionPolicyNobody},
+		},
+		{
+			name:          "user not found",
+			email:         "nonexistent@example.com",
+			mentionPolicy: entities.MentionPolicyFriends,
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetUserByEmail("nonexistent@example.com").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", "nonexistent@example.com"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeNotFound,
+			wantErrMsg:  "User not found: nonexistent@example.com",
+		},
+		{
+			name:          "database error",
+			email:         "andy@example.com",
+			mentionPolicy: entities.MentionPolicyFriends,
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetUserByEmail("andy@example.com").Return(user, nil)
+				mockRepo.EXPECT().UpdateUserSettings(&entities.UserSettings{User: user, MentionPolicy: entities.MentionPolicyFriends}).Return(errors.New(errors.ErrorTypeDatabase, "Failed to update user settings"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeDatabase,
+			wantErrMsg:  "Failed to update user settings",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+			tt.setupMock(mockRepo)
+
+			controller := NewUserController(mockRepo, newTestEventHub())
+			settings, err := controller.UpdateSettings(tt.email, tt.mentionPolicy)
+
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedSettings, settings)
+				return
+			}
+
+			assert.Error(t, err)
+			var appErr *errors.AppError
+			assert.True(t, stderrors.As(err, &appErr))
+			assert.Equal(t, tt.wantErrType, appErr.Type)
+			assert.Equal(t, tt.wantErrMsg, appErr.Message)
+		})
+	}
+}
+
+func TestGetUsers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name          string
+		setupMock     func(mockRepo *mocks.MockUserRepositoryInterface)
+		wantErr       bool
+		wantErrType   errors.ErrorType
+		wantErrMsg    string
+		expectedUsers []*entities.User
+	}{
+		{
+			name: "successful users retrieval",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetAllUsers().Return([]*entities.User{
+					{ID: 2, Email: "alice@example.com"},
+					{ID: 1, Email: "andy@example.com"},
+				}, nil)
+			},
+			wantErr: false,
+			expectedUsers: []*entities.User{
+				{ID: 2, Email: "alice@example.com"},
+				{ID: 1, Email: "andy@example.com"},
+			},
+		},
+		{
+			name: "repository error",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetAllUsers().Return(nil, errors.New(errors.ErrorTypeDatabase, "Failed to fetch users"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeDatabase,
+			wantErrMsg:  "Failed to fetch users",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+			tt.setupMock(mockRepo)
+
+			controller := NewUserController(mockRepo, newTestEventHub())
+			users, err := controller.GetUsers()
+
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedUsers, users)
+				return
+			}
+
+			assert.Error(t, err)
+			var appErr *errors.AppError
+			assert.True(t, stderrors.As(err, &appErr))
+			assert.Equal(t, tt.wantErrType, appErr.Type)
+			assert.Equal(t, tt.wantErrMsg, appErr.Message)
+		})
+	}
+}
+
+func TestDeleteUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name        string
+		email       string
+		setupMock   func(mockRepo *mocks.MockUserRepositoryInterface)
+		wantErr     bool
+		wantErrType errors.ErrorType
+		wantErrMsg  string
+	}{
+		{
+			name:  "successful user deletion",
+			email: "andy@example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				user := &entities.User{ID: 1, Email: "andy@example.com"}
+				mockRepo.EXPECT().GetUserByEmail("andy@example.com").Return(user, nil)
+				mockRepo.EXPECT().DeleteUser(user).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:  "user not found",
+			email: "nonexistent@example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetUserByEmail("nonexistent@example.com").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", "nonexistent@example.com"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeNotFound,
+			wantErrMsg:  "User not found: nonexistent@example.com",
+		},
+		{
+			name:  "repository error",
+			email: "andy@example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				user := &entities.User{ID: 1, Email: "andy@example.com"}
+				mockRepo.EXPECT().GetUserByEmail("andy@example.com").Return(user, nil)
+				mockRepo.EXPECT().DeleteUser(user).Return(errors.New(errors.ErrorTypeDatabase, "Failed to delete user"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeDatabase,
+			wantErrMsg:  "Failed to delete user",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+			tt.setupMock(mockRepo)
+
+			controller := NewUserController(mockRepo, newTestEventHub())
+			err := controller.DeleteUser(tt.email)
+
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.Error(t, err)
+			var appErr *errors.AppError
+			assert.True(t, stderrors.As(err, &appErr))
+			assert.Equal(t, tt.wantErrType, appErr.Type)
+			assert.Equal(t, tt.wantErrMsg, appErr.Message)
+		})
+	}
+}
+
+func TestDeleteFriendship(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name        string
+		user1Email  string
+		user2Email  string
+		setupMock   func(mockRepo *mocks.MockUserRepositoryInterface)
+		wantErr     bool
+		wantErrType errors.ErrorType
+		wantErrMsg  string
+	}{
+		{
+			name:       "successful friendship removal",
+			user1Email: "a@example.com",
+			user2Email: "b@example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				user1 := &entities.User{ID: 1, Email: "a@example.com"}
+				user2 := &entities.User{ID: 2, Email: "b@example.com"}
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(user1, nil)
+				mockRepo.EXPECT().GetUserByEmail("b@example.com").Return(user2, nil)
+				mockRepo.EXPECT().DeleteFriendship(user1, user2).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:       "same email should fail",
+			user1Email: "a@example.com",
+			user2Email: "a@example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				// No mock expectations needed as validation happens before repository calls
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeBusiness,
+			wantErrMsg:  "Cannot remove yourself as a friend",
+		},
+		{
+			name:       "user not found",
+			user1Email: "a@example.com",
+			user2Email: "nonexistent@example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				user1 := &entities.User{ID: 1, Email: "a@example.com"}
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(user1, nil)
+				mockRepo.EXPECT().GetUserByEmail("nonexistent@example.com").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", "nonexistent@example.com"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeNotFound,
+			wantErrMsg:  "User not found: nonexistent@example.com",
+		},
+		{
+			name:       "friendship does not exist",
+			user1Email: "a@example.com",
+			user2Email: "b@example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				user1 := &entities.User{ID: 1, Email: "a@example.com"}
+				user2 := &entities.User{ID: 2, Email: "b@example.com"}
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(user1, nil)
+				mockRepo.EXPECT().GetUserByEmail("b@example.com").Return(user2, nil)
+				mockRepo.EXPECT().DeleteFriendship(user1, user2).Return(errors.ErrFriendshipNotFound)
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeNotFound,
+			wantErrMsg:  "Friendship not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+			tt.setupMock(mockRepo)
+
+			controller := NewUserController(mockRepo, newTestEventHub())
+			err := controller.DeleteFriendship(tt.user1Email, tt.user2Email)
+
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.Error(t, err)
+			var appErr *errors.AppError
+			assert.True(t, stderrors.As(err, &appErr))
+			assert.Equal(t, tt.wantErrType, appErr.Type)
+			assert.Equal(t, tt.wantErrMsg, appErr.Message)
+		})
+	}
+}
+
+func TestDeleteSubscription(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name           string
+		requestorEmail string
+		targetEmail    string
+		setupMock      func(mockRepo *mocks.MockUserRepositoryInterface)
+		wantErr        bool
+		wantErrType    errors.ErrorType
+		wantErrMsg     string
+	}{
+		{
+			name:           "successful unsubscribe",
+			requestorEmail: "a@example.com",
+			targetEmail:    "b@example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				requestor := &entities.User{ID: 1, Email: "a@example.com"}
+				target := &entities.User{ID: 2, Email: "b@example.com"}
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(requestor, nil)
+				mockRepo.EXPECT().GetUserByEmail("b@example.com").Return(target, nil)
+				mockRepo.EXPECT().DeleteSubscription(requestor, target).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:           "requestor not found",
+			requestorEmail: "nonexistent@example.com",
+			targetEmail:    "b@example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetUserByEmail("nonexistent@example.com").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", "nonexistent@example.com"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeNotFound,
+			wantErrMsg:  "User not found: nonexistent@example.com",
+		},
+		{
+			name:           "subscription does not exist",
+			requestorEmail: "a@example.com",
+			targetEmail:    "b@example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				requestor := &entities.User{ID: 1, Email: "a@example.com"}
+				target := &entities.User{ID: 2, Email: "b@example.com"}
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(requestor, nil)
+				mockRepo.EXPECT().GetUserByEmail("b@example.com").Return(target, nil)
+				mockRepo.EXPECT().DeleteSubscription(requestor, target).Return(errors.ErrSubscriptionNotFound)
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeNotFound,
+			wantErrMsg:  "Subscription not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+			tt.setupMock(mockRepo)
+
+			controller := NewUserController(mockRepo, newTestEventHub())
+			err := controller.DeleteSubscription(tt.requestorEmail, tt.targetEmail)
+
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.Error(t, err)
+			var appErr *errors.AppError
+			assert.True(t, stderrors.As(err, &appErr))
+			assert.Equal(t, tt.wantErrType, appErr.Type)
+			assert.Equal(t, tt.wantErrMsg, appErr.Message)
+		})
+	}
+}
+
+func TestDeleteBlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name           string
+		requestorEmail string
+		targetEmail    string
+		restore        bool
+		setupMock      func(mockRepo *mocks.MockUserRepositoryInterface)
+		wantErr        bool
+		wantErrType    errors.ErrorType
+		wantErrMsg     string
+	}{
+		{
+			name:           "successful unblock without restore",
+			requestorEmail: "a@example.com",
+			targetEmail:    "b@example.com",
+			restore:        false,
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				requestor := &entities.User{ID: 1, Email: "a@example.com"}
+				target := &entities.User{ID: 2, Email: "b@example.com"}
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(requestor, nil)
+				mockRepo.EXPECT().GetUserByEmail("b@example.com").Return(target, nil)
+				mockRepo.EXPECT().DeleteBlockTx(requestor, target, false).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:           "successful unblock with restore",
+			requestorEmail: "a@example.com",
+			targetEmail:    "b@example.com",
+			restore:        true,
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				requestor := &entities.User{ID: 1, Email: "a@example.com"}
+				target := &entities.User{ID: 2, Email: "b@example.com"}
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(requestor, nil)
+				mockRepo.EXPECT().GetUserByEmail("b@example.com").Return(target, nil)
+				mockRepo.EXPECT().DeleteBlockTx(requestor, target, true).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:           "restore rejected while target still blocks requestor",
+			requestorEmail: "a@example.com",
+			targetEmail:    "b@example.com",
+			restore:        true,
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				requestor := &entities.User{ID: 1, Email: "a@example.com"}
+				target := &entities.User{ID: 2, Email: "b@example.com"}
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(requestor, nil)
+				mockRepo.EXPECT().GetUserByEmail("b@example.com").Return(target, nil)
+				mockRepo.EXPECT().DeleteBlockTx(requestor, target, true).Return(errors.ErrUserBlocked)
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeForbidden,
+			wantErrMsg:  "Cannot perform action on blocked user",
+		},
+		{
+			name:           "block does not exist",
+			requestorEmail: "a@example.com",
+			targetEmail:    "b@example.com",
+			restore:        false,
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				requestor := &entities.User{ID: 1, Email: "a@example.com"}
+				target := &entities.User{ID: 2, Email: "b@example.com"}
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(requestor, nil)
+				mockRepo.EXPECT().GetUserByEmail("b@example.com").Return(target, nil)
+				mockRepo.EXPECT().DeleteBlockTx(requestor, target, false).Return(errors.ErrBlockNotFound)
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeNotFound,
+			wantErrMsg:  "Block not found",
+		},
+		{
+			name:           "target not found",
+			requestorEmail: "a@example.com",
+			targetEmail:    "nonexistent@example.com",
+			restore:        true,
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				requestor := &entities.User{ID: 1, Email: "a@example.com"}
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(requestor, nil)
+				mockRepo.EXPECT().GetUserByEmail("nonexistent@example.com").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", "nonexistent@example.com"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeNotFound,
+			wantErrMsg:  "User not found: nonexistent@example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+			tt.setupMock(mockRepo)
+
+			controller := NewUserController(mockRepo, newTestEventHub())
+			err := controller.DeleteBlock(tt.requestorEmail, tt.targetEmail, tt.restore)
+
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.Error(t, err)
+			var appErr *errors.AppError
+			assert.True(t, stderrors.As(err, &appErr))
+			assert.Equal(t, tt.wantErrType, appErr.Type)
+			assert.Equal(t, tt.wantErrMsg, appErr.Message)
+		})
+	}
+}
+
+func TestUpdateSubscriptionFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	requestor := &entities.User{ID: 1, Email: "a@example.com"}
+	target := &entities.User{ID: 2, Email: "b@example.com"}
+
+	tests := []struct {
+		name           string
+		requestorEmail string
+		targetEmail    string
+		filter         *entities.SubscriptionFilter
+		setupMock      func(mockRepo *mocks.MockUserRepositoryInterface)
+		wantErr        bool
+		wantErrType    errors.ErrorType
+		wantErrMsg     string
+		expectedFilter *entities.SubscriptionFilter
+	}{
+		{
+			name:           "keywords are lowercased and deduplicated",
+			requestorEmail: "a@example.com",
+			targetEmail:    "b@example.com",
+			filter:         &entities.SubscriptionFilter{Include: []string{"Go", "#Rust", "go"}, Exclude: []string{"Spam"}},
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(requestor, nil)
+				mockRepo.EXPECT().GetUserByEmail("b@example.com").Return(target, nil)
+				mockRepo.EXPECT().UpdateSubscriptionFilter(requestor, target, &entities.SubscriptionFilter{
+					Include: []string{"go", "#rust"},
+					Exclude: []string{"spam"},
+				}).Return(nil)
+			},
+			wantErr:        false,
+			expectedFilter: &entities.SubscriptionFilter{Include: []string{"go", "#rust"}, Exclude: []string{"spam"}},
+		},
+		{
+			name:           "empty filter clears the keywords",
+			requestorEmail: "a@example.com",
+			targetEmail:    "b@example.com",
+			filter:         &entities.SubscriptionFilter{},
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(requestor, nil)
+				mockRepo.EXPECT().GetUserByEmail("b@example.com").Return(target, nil)
+				mockRepo.EXPECT().UpdateSubscriptionFilter(requestor, target, &entities.SubscriptionFilter{
+					Include: []string{},
+					Exclude: []string{},
+				}).Return(nil)
+			},
+			wantErr:        false,
+			expectedFilter: &entities.SubscriptionFilter{Include: []string{}, Exclude: []string{}},
+		},
+		{
+			name:           "subscription not found",
+			requestorEmail: "a@example.com",
+			targetEmail:    "b@example.com",
+			filter:         &entities.SubscriptionFilter{Include: []string{"go"}},
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(requestor, nil)
+				mockRepo.EXPECT().GetUserByEmail("b@example.com").Return(target, nil)
+				mockRepo.EXPECT().UpdateSubscriptionFilter(requestor, target, gomock.Any()).Return(errors.ErrSubscriptionNotFound)
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeNotFound,
+			wantErrMsg:  "Subscription not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+			tt.setupMock(mockRepo)
+
+			controller := NewUserController(mockRepo, newTestEventHub())
+			filter, err := controller.UpdateSubscriptionFilter(tt.requestorEmail, tt.targetEmail, tt.filter)
+
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedFilter, filter)
+				return
+			}
+
+			assert.Error(t, err)
+			var appErr *errors.AppError
+			assert.True(t, stderrors.As(err, &appErr))
+			assert.Equal(t, tt.wantErrType, appErr.Type)
+			assert.Equal(t, tt.wantErrMsg, appErr.Message)
+		})
+	}
+}
+
+func TestCreateMute(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	requestor := &entities.User{ID: 1, Email: "a@example.com"}
+	target := &entities.User{ID: 2, Email: "b@example.com"}
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name           string
+		requestorEmail string
+		targetEmail    string
+		expiresAt      *time.Time
+		setupMock      func(mockRepo *mocks.MockUserRepositoryInterface)
+		wantErr        bool
+		wantErrType    errors.ErrorType
+		wantErrMsg     string
+		expectedMute   *entities.Mute
+	}{
+		{
+			name:           "mute without expiry",
+			requestorEmail: "a@example.com",
+			targetEmail:    "b@example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(requestor, nil)
+				mockRepo.EXPECT().GetUserByEmail("b@example.com").Return(target, nil)
+				mockRepo.EXPECT().UpsertMute(requestor, target, nil).Return(&entities.Mute{Muter: requestor, Muted: target}, nil)
+			},
+			wantErr:      false,
+			expectedMute: &entities.Mute{Muter: requestor, Muted: target},
+		},
+		{
+			name:           "mute with expiry",
+			requestorEmail: "a@example.com",
+			targetEmail:    "b@example.com",
+			expiresAt:      &future,
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(requestor, nil)
+				mockRepo.EXPECT().GetUserByEmail("b@example.com").Return(target, nil)
+				mockRepo.EXPECT().UpsertMute(requestor, target, &future).Return(&entities.Mute{Muter: requestor, Muted: target, ExpiresAt: &future}, nil)
+			},
+			wantErr:      false,
+			expectedMute: &entities.Mute{Muter: requestor, Muted: target, ExpiresAt: &future},
+		},
+		{
+			name:           "expiry in the past",
+			requestorEmail: "a@example.com",
+			targetEmail:    "b@example.com",
+			expiresAt:      &past,
+			setupMock:      func(mockRepo *mocks.MockUserRepositoryInterface) {},
+			wantErr:        true,
+			wantErrType:    errors.ErrorTypeValidation,
+			wantErrMsg:     "Mute expiry must be in the future",
+		},
+		{
+			name:           "target not found",
+			requestorEmail: "a@example.com",
+			targetEmail:    "nonexistent@example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(requestor, nil)
+				mockRepo.EXPECT().GetUserByEmail("nonexistent@example.com").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", "nonexistent@example.com"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeNotFound,
+			wantErrMsg:  "User not found: nonexistent@example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+			tt.setupMock(mockRepo)
+
+			controller := NewUserController(mockRepo, newTestEventHub())
+			mute, err := controller.CreateMute(tt.requestorEmail, tt.targetEmail, tt.expiresAt)
+
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedMute, mute)
+				return
+			}
+
+			assert.Error(t, err)
+			var appErr *errors.AppError
+			assert.True(t, stderrors.As(err, &appErr))
+			assert.Equal(t, tt.wantErrType, appErr.Type)
+			assert.Equal(t, tt.wantErrMsg, appErr.Message)
+		})
+	}
+}
+
+func TestDeleteMute(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	requestor := &entities.User{ID: 1, Email: "a@example.com"}
+	target := &entities.User{ID: 2, Email: "b@example.com"}
+
+	tests := []struct {
+		name           string
+		requestorEmail string
+		targetEmail    string
+		setupMock      func(mockRepo *mocks.MockUserRepositoryInterface)
+		wantErr        bool
+		wantErrType    errors.ErrorType
+		wantErrMsg     string
+	}{
+		{
+			name:           "successful unmute",
+			requestorEmail: "a@example.com",
+			targetEmail:    "b@example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(requestor, nil)
+				mockRepo.EXPECT().GetUserByEmail("b@example.com").Return(target, nil)
+				mockRepo.EXPECT().DeleteMute(requestor, target).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:           "mute not found",
+			requestorEmail: "a@example.com",
+			targetEmail:    "b@example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(requestor, nil)
+				mockRepo.EXPECT().GetUserByEmail("b@example.com").Return(target, nil)
+				mockRepo.EXPECT().DeleteMute(requestor, target).Return(errors.ErrMuteNotFound)
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeNotFound,
+			wantErrMsg:  "Mute not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+			tt.setupMock(mockRepo)
+
+			controller := NewUserController(mockRepo, newTestEventHub())
+			err := controller.DeleteMute(tt.requestorEmail, tt.targetEmail)
+
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.Error(t, err)
+			var appErr *errors.AppError
+			assert.True(t, stderrors.As(err, &appErr))
+			assert.Equal(t, tt.wantErrType, appErr.Type)
+			assert.Equal(t, tt.wantErrMsg, appErr.Message)
+		})
+	}
+}
+
+func TestSendFriendRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name           string
+		requestorEmail string
+		targetEmail    string
+		setupMock      func(mockRepo *mocks.MockUserRepositoryInterface)
+		wantErr        bool
+		wantErrType    errors.ErrorType
+		wantErrMsg     string
+	}{
+		{
+			name:           "successful friend request",
+			requestorEmail: "a@example.com",
+			targetEmail:    "b@example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				requestor := &entities.User{ID: 1, Email: "a@example.com"}
+				target := &entities.User{ID: 2, Email: "b@example.com"}
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(requestor, nil)
+				mockRepo.EXPECT().GetUserByEmail("b@example.com").Return(target, nil)
+				mockRepo.EXPECT().CheckBidirectionalBlock(1, 2).Return(false, nil)
+				mockRepo.EXPECT().CheckFriendshipExists(1, 2).Return(false, nil)
+				mockRepo.EXPECT().CreateFriendRequest(requestor, target).Return(&entities.FriendRequest{
+					ID:        1,
+					Requester: requestor,
+					Addressee: target,
+					Status:    entities.FriendRequestPending,
+				}, nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:           "request to self should fail",
+			requestorEmail: "a@example.com",
+			targetEmail:    "a@example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				// No mock expectations needed as validation happens before repository calls
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeBusiness,
+			wantErrMsg:  "Cannot add yourself as a friend",
+		},
+		{
+			name:           "request to self in another case should fail",
+			requestorEmail: "a@example.com",
+			targetEmail:    "A@Example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				// No mock expectations needed as validation happens before repository calls
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeBusiness,
+			wantErrMsg:  "Cannot add yourself as a friend",
+		},
+		{
+			name:           "blocked users cannot send requests",
+			requestorEmail: "a@example.com",
+			targetEmail:    "b@example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				requestor := &entities.User{ID: 1, Email: "a@example.com"}
+				target := &entities.User{ID: 2, Email: "b@example.com"}
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(requestor, nil)
+				mockRepo.EXPECT().GetUserByEmail("b@example.com").Return(target, nil)
+				mockRepo.EXPECT().CheckBidirectionalBlock(1, 2).Return(true, nil)
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeForbidden,
+			wantErrMsg:  "Cannot perform action on blocked user",
+		},
+		{
+			name:           "already friends",
+			requestorEmail: "a@example.com",
+			targetEmail:    "b@example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				requestor := &entities.User{ID: 1, Email: "a@example.com"}
+				target := &entities.User{ID: 2, Email: "b@example.com"}
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(requestor, nil)
+				mockRepo.EXPECT().GetUserByEmail("b@example.com").Return(target, nil)
+				mockRepo.EXPECT().CheckBidirectionalBlock(1, 2).Return(false, nil)
+				mockRepo.EXPECT().CheckFriendshipExists(1, 2).Return(true, nil)
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeConflict,
+			wantErrMsg:  "Users are already friends",
+		},
+		{
+			name:           "pending request already exists",
+			requestorEmail: "a@example.com",
+			targetEmail:    "b@example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				requestor := &entities.User{ID: 1, Email: "a@example.com"}
+				target := &entities.User{ID: 2, Email: "b@example.com"}
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(requestor, nil)
+				mockRepo.EXPECT().GetUserByEmail("b@example.com").Return(target, nil)
+				mockRepo.EXPECT().CheckBidirectionalBlock(1, 2).Return(false, nil)
+				mockRepo.EXPECT().CheckFriendshipExists(1, 2).Return(false, nil)
+				mockRepo.EXPECT().CreateFriendRequest(requestor, target).Return(nil, errors.New(errors.ErrorTypeConflict, "Resource already exists").WithDetails("Friend request already pending"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeConflict,
+			wantErrMsg:  "Resource already exists",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+			tt.setupMock(mockRepo)
+
+			controller := NewUserController(mockRepo, newTestEventHub())
+			request, err := controller.SendFriendRequest(tt.requestorEmail, tt.targetEmail)
+
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.requestorEmail, request.Requester.Email)
+				assert.Equal(t, tt.targetEmail, request.Addressee.Email)
+				assert.Equal(t, entities.FriendRequestPending, request.Status)
+				return
+			}
+
+			assert.Error(t, err)
+			var appErr *errors.AppError
+			assert.True(t, stderrors.As(err, &appErr))
+			assert.Equal(t, tt.wantErrType, appErr.Type)
+			assert.Equal(t, tt.wantErrMsg, appErr.Message)
+		})
+	}
+}
+
+func TestGetIncomingFriendRequests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := &entities.User{ID: 1, Email: "a@example.com"}
+	requests := []*entities.FriendRequest{
+		{ID: 1, Requester: &entities.User{ID: 2, Email: "b@example.com"}, Addressee: user, Status: entities.FriendRequestPending},
+	}
+
+	t.Run("incoming requests", func(t *testing.T) {
+		mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+		mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(user, nil)
+		mockRepo.EXPECT().GetIncomingFriendRequests(user).Return(requests, nil)
+
+		controller := NewUserController(mockRepo, newTestEventHub())
+		result, err := controller.GetIncomingFriendRequests("a@example.com")
+
+		assert.NoError(t, err)
+		assert.Equal(t, requests, result)
+	})
+
+	t.Run("outgoing requests", func(t *testing.T) {
+		mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+		mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(user, nil)
+		mockRepo.EXPECT().GetOutgoingFriendRequests(user).Return([]*entities.FriendRequest{}, nil)
+
+		controller := NewUserController(mockRepo, newTestEventHub())
+		result, err := controller.GetOutgoingFriendRequests("a@example.com")
+
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+		mockRepo.EXPECT().GetUserByEmail("nonexistent@example.com").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", "nonexistent@example.com"))
+
+		controller := NewUserController(mockRepo, newTestEventHub())
+		_, err := controller.GetIncomingFriendRequests("nonexistent@example.com")
+
+		var appErr *errors.AppError
+		assert.True(t, stderrors.As(err, &appErr))
+		assert.Equal(t, errors.ErrorTypeNotFound, appErr.Type)
+	})
+}
+
+func TestGetSubscribersSubscriptionsAndBlocks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := &entities.User{ID: 1, Email: "a@example.com"}
+	others := []*entities.User{{ID: 2, Email: "b@example.com"}}
+
+	t.Run("subscribers", func(t *testing.T) {
+		mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+		mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(user, nil)
+		mockRepo.EXPECT().GetSubscribersByUserID(1, entities.PageRequest{}).Return(&entities.UserPage{Users: others, Total: 1}, nil)
+
+		controller := NewUserController(mockRepo, newTestEventHub())
+		result, err := controller.GetSubscribers("a@example.com", "", 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, others, result.Users)
+	})
+
+	t.Run("subscriptions", func(t *testing.T) {
+		mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+		mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(user, nil)
+		mockRepo.EXPECT().GetSubscriptionsByUserID(1, entities.PageRequest{}).Return(&entities.UserPage{Users: others, Total: 1}, nil)
+
+		controller := NewUserController(mockRepo, newTestEventHub())
+		result, err := controller.GetSubscriptions("a@example.com", "", 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, others, result.Users)
+	})
+
+	t.Run("blocked users", func(t *testing.T) {
+		mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+		mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(user, nil)
+		mockRepo.EXPECT().GetBlockedUsers(user, entities.PageRequest{}).Return(&entities.UserPage{Users: others, Total: 1}, nil)
+
+		controller := NewUserController(mockRepo, newTestEventHub())
+		result, err := controller.GetBlockedUsers("a@example.com", "", 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, others, result.Users)
+	})
+
+	t.Run("blockers", func(t *testing.T) {
+		mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+		mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(user, nil)
+		mockRepo.EXPECT().GetBlockers(user, entities.PageRequest{}).Return(&entities.UserPage{Users: others, Total: 1}, nil)
+
+		controller := NewUserController(mockRepo, newTestEventHub())
+		result, err := controller.GetBlockers("a@example.com", "", 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, others, result.Users)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+		mockRepo.EXPECT().GetUserByEmail("nonexistent@example.com").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", "nonexistent@example.com"))
+
+		controller := NewUserController(mockRepo, newTestEventHub())
+		_, err := controller.GetSubscriptions("nonexistent@example.com", "", 0)
+
+		var appErr *errors.AppError
+		assert.True(t, stderrors.As(err, &appErr))
+		assert.Equal(t, errors.ErrorTypeNotFound, appErr.Type)
+	})
+}
+
+func TestCountSubscribers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	users := []*entities.User{{ID: 1, Email: "a@example.com"}, {ID: 2, Email: "b@example.com"}}
+
+	t.Run("counts by email", func(t *testing.T) {
+		mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+		mockRepo.EXPECT().GetUsersByEmails([]string{"a@example.com", "b@example.com", "nobody@example.com"}).Return(users, nil)
+		mockRepo.EXPECT().CountSubscribersBatch([]int{1, 2}).Return(map[int]int{1: 3}, nil)
+
+		controller := NewUserController(mockRepo, newTestEventHub())
+		counts, err := controller.CountSubscribers([]string{"a@example.com", "b@example.com", "nobody@example.com"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int{"a@example.com": 3, "b@example.com": 0}, counts)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+		mockRepo.EXPECT().GetUsersByEmails([]string{"a@example.com"}).Return(users[:1], nil)
+		mockRepo.EXPECT().CountSubscribersBatch([]int{1}).Return(nil, errors.New(errors.ErrorTypeDatabase, "Failed to count subscribers"))
+
+		controller := NewUserController(mockRepo, newTestEventHub())
+		_, err := controller.CountSubscribers([]string{"a@example.com"})
+
+		var appErr *errors.AppError
+		assert.True(t, stderrors.As(err, &appErr))
+		assert.Equal(t, errors.ErrorTypeDatabase, appErr.Type)
+	})
+}
+
+func TestUserListsBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	andy := &entities.User{ID: 1, Email: "andy@example.com"}
+	john := &entities.User{ID: 2, Email: "john@example.com"}
+	jane := &entities.User{ID: 3, Email: "jane@example.com"}
+	kate := &entities.User{ID: 4, Email: "kate@example.com"}
+	users := []*entities.User{andy, john}
+
+	t.Run("friend lists are trimmed to the page", func(t *testing.T) {
+		mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+		mockRepo.EXPECT().GetFriendListsBatch([]int{1, 2}, entities.PageRequest{After: "bob@example.com", Limit: 2}).Return(map[int]*entities.UserPage{
+			1: {Users: []*entities.User{jane, kate}, Total: 4},
+			2: {Users: []*entities.User{kate}, Total: 2},
+		}, nil)
+
+		controller := NewUserController(mockRepo, newTestEventHub())
+		pages, err := controller.GetFriendListsBatch(users, utils.EncodeCursor("bob@example.com"), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &entities.UserPage{Users: []*entities.User{jane}, Total: 4, NextCursor: utils.EncodeCursor("jane@example.com")}, pages[1])
+		assert.Equal(t, &entities.UserPage{Users: []*entities.User{kate}, Total: 2}, pages[2])
+	})
+
+	t.Run("subscribers", func(t *testing.T) {
+		mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+		mockRepo.EXPECT().GetSubscribersBatch([]int{1, 2}, entities.PageRequest{}).Return(map[int]*entities.UserPage{
+			1: {Users: []*entities.User{jane}, Total: 1},
+			2: {Users: []*entities.User{}},
+		}, nil)
+
+		controller := NewUserController(mockRepo, newTestEventHub())
+		pages, err := controller.GetSubscribersBatch(users, "", 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []*entities.User{jane}, pages[1].Users)
+		assert.Empty(t, pages[2].Users)
+	})
+
+	t.Run("blockers", func(t *testing.T) {
+		mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+		mockRepo.EXPECT().GetBlockersBatch([]int{1, 2}, entities.PageRequest{Limit: 51}).Return(nil, errors.New(errors.ErrorTypeDatabase, "Failed to fetch blockers"))
+
+		controller := NewUserController(mockRepo, newTestEventHub())
+		_, err := controller.GetBlockersBatch(users, "", 50)
+
+		var appErr *errors.AppError
+		assert.True(t, stderrors.As(err, &appErr))
+		assert.Equal(t, errors.ErrorTypeDatabase, appErr.Type)
+	})
+
+	t.Run("subscriber counts", func(t *testing.T) {
+		mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+		mockRepo.EXPECT().CountSubscribersBatch([]int{1, 2}).Return(map[int]int{2: 5}, nil)
+
+		controller := NewUserController(mockRepo, newTestEventHub())
+		counts, err := controller.CountSubscribersBatch(users)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[int]int{2: 5}, counts)
+	})
+
+	t.Run("common friends with other users", func(t *testing.T) {
+		mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+		mockRepo.EXPECT().GetUsersByEmails([]string{"jane@example.com"}).Return([]*entities.User{jane}, nil)
+		mockRepo.EXPECT().GetCommonFriendsBatch([]int{1, 2}, []*entities.User{jane}, entities.PageRequest{}).Return(map[int]*entities.UserPage{
+			1: {Users: []*entities.User{kate}, Total: 1},
+			2: {Users: []*entities.User{}},
+		}, nil)
+
+		controller := NewUserController(mockRepo, newTestEventHub())
+		pages, err := controller.GetCommonFriendsBatch(users, []string{"jane@example.com"}, "", 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []*entities.User{kate}, pages[1].Users)
+		assert.Empty(t, pages[2].Users)
+	})
+
+	t.Run("common friends with one of the users", func(t *testing.T) {
+		mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+
+		controller := NewUserController(mockRepo, newTestEventHub())
+		_, err := controller.GetCommonFriendsBatch(users, []string{"John@example.com"}, "", 0)
+
+		assert.Equal(t, errors.ErrCannotGetCommonFriendsWithSelf, err)
+	})
+
+	t.Run("common friends with the same user in another case", func(t *testing.T) {
+		mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+
+		controller := NewUserController(mockRepo, newTestEventHub())
+		_, err := controller.GetCommonFriendsBatch(users, []string{"Jane@example.com", "jane@example.com"}, "", 0)
+
+		assert.Equal(t, errors.ErrCannotGetCommonFriendsWithSelf, err)
+	})
+
+	t.Run("common friends with nobody's email", func(t *testing.T) {
+		mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+		mockRepo.EXPECT().GetUsersByEmails([]string{"nobody@example.com"}).Return([]*entities.User{}, nil)
+
+		controller := NewUserController(mockRepo, newTestEventHub())
+		_, err := controller.GetCommonFriendsBatch(users, []string{"nobody@example.com"}, "", 0)
+
+		var appErr *errors.AppError
+		assert.True(t, stderrors.As(err, &appErr))
+		assert.Equal(t, errors.ErrorTypeNotFound, appErr.Type)
+		assert.Equal(t, "User not found: nobody@example.com", appErr.Message)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+
+		controller := NewUserController(mockRepo, newTestEventHub())
+		_, err := controller.GetFriendListsBatch(users, "not a cursor", 1)
+
+		assert.Equal(t, errors.ErrInvalidCursor, err)
+	})
+}
+
+func TestAcceptFriendRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name           string
+		requestorEmail string
+		targetEmail    string
+		setupMock      func(mockRepo *mocks.MockUserRepositoryInterface)
+		wantErr        bool
+		wantErrType    errors.ErrorType
+		wantErrMsg     string
+	}{
+		{
+			name:           "successful acceptance",
+			requestorEmail: "b@example.com",
+			targetEmail:    "a@example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				addressee := &entities.User{ID: 2, Email: "b@example.com"}
+				requester := &entities.User{ID: 1, Email: "a@example.com"}
+				mockRepo.EXPECT().GetUserByEmail("b@example.com").Return(addressee, nil)
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(requester, nil)
+				mockRepo.EXPECT().AcceptFriendRequestTx(requester, addressee).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:           "block created after request prevents acceptance",
+			requestorEmail: "b@example.com",
+			targetEmail:    "a@example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				addressee := &entities.User{ID: 2, Email: "b@example.com"}
+				requester := &entities.User{ID: 1, Email: "a@example.com"}
+				mockRepo.EXPECT().GetUserByEmail("b@example.com").Return(addressee, nil)
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(requester, nil)
+				mockRepo.EXPECT().AcceptFriendRequestTx(requester, addressee).Return(errors.ErrUserBlocked)
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeForbidden,
+			wantErrMsg:  "Cannot perform action on blocked user",
+		},
+		{
+			name:           "no pending request",
+			requestorEmail: "b@example.com",
+			targetEmail:    "a@example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				addressee := &entities.User{ID: 2, Email: "b@example.com"}
+				requester := &entities.User{ID: 1, Email: "a@example.com"}
+				mockRepo.EXPECT().GetUserByEmail("b@example.com").Return(addressee, nil)
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(requester, nil)
+				mockRepo.EXPECT().AcceptFriendRequestTx(requester, addressee).Return(errors.ErrFriendRequestNotFound)
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeNotFound,
+			wantErrMsg:  "Pending friend request not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+			tt.setupMock(mockRepo)
+
+			controller := NewUserController(mockRepo, newTestEventHub())
+			err := controller.AcceptFriendRequest(tt.requestorEmail, tt.targetEmail)
+
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.Error(t, err)
+			var appErr *errors.AppError
+			assert.True(t, stderrors.As(err, &appErr))
+			assert.Equal(t, tt.wantErrType, appErr.Type)
+			assert.Equal(t, tt.wantErrMsg, appErr.Message)
+		})
+	}
+}
+
+func TestRejectAndCancelFriendRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	requester := &entities.User{ID: 1, Email: "a@example.com"}
+	addressee := &entities.User{ID: 2, Email: "b@example.com"}
+
+	t.Run("addressee rejects request", func(t *testing.T) {
+		mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+		mockRepo.EXPECT().GetUserByEmail("b@example.com").Return(addressee, nil)
+		mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(requester, nil)
+		mockRepo.EXPECT().UpdateFriendRequestStatus(requester, addressee, entities.FriendRequestRejected).Return(nil)
+
+		controller := NewUserController(mockRepo, newTestEventHub())
+		assert.NoError(t, controller.RejectFriendRequest("b@example.com", "a@example.com"))
+	})
+
+	t.Run("requester cancels request", func(t *testing.T) {
+		mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+		mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(requester, nil)
+		mockRepo.EXPECT().GetUserByEmail("b@example.com").Return(addressee, nil)
+		mockRepo.EXPECT().UpdateFriendRequestStatus(requester, addressee, entities.FriendRequestCancelled).Return(nil)
+
+		controller := NewUserController(mockRepo, newTestEventHub())
+		assert.NoError(t, controller.CancelFriendRequest("a@example.com", "b@example.com"))
+	})
+
+	t.Run("no pending request to reject", func(t *testing.T) {
+		mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+		mockRepo.EXPECT().GetUserByEmail("b@example.com").Return(addressee, nil)
+		mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(requester, nil)
+		mockRepo.EXPECT().UpdateFriendRequestStatus(requester, addressee, entities.FriendRequestRejected).Return(errors.ErrFriendRequestNotFound)
+
+		controller := NewUserController(mockRepo, newTestEventHub())
+		err := controller.RejectFriendRequest("b@example.com", "a@example.com")
+
+		var appErr *errors.AppError
+		assert.True(t, stderrors.As(err, &appErr))
+		assert.Equal(t, errors.ErrorTypeNotFound, appErr.Type)
+		assert.Equal(t, "Pending friend request not found", appErr.Message)
+	})
+}
+
+func TestGetRelationship(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name                 string
+		emailA               string
+		emailB               string
+		setupMock            func(mockRepo *mocks.MockUserRepositoryInterface)
+		wantErr              bool
+		wantErrType          errors.ErrorType
+		wantErrMsg           string
+		expectedRelationship *entities.Relationship
+	}{
+		{
+			name:   "successful relationship retrieval",
+			emailA: "a@example.com",
+			emailB: "b@example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				userA := &entities.User{ID: 1, Email: "a@example.com"}
+				userB := &entities.User{ID: 2, Email: "b@example.com"}
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(userA, nil)
+				mockRepo.EXPECT().GetUserByEmail("b@example.com").Return(userB, nil)
+				mockRepo.EXPECT().GetRelationship(userA, userB).Return(&entities.Relationship{AreFriends: true, BSubscribesToA: true}, nil)
+			},
+			wantErr:              false,
+			expectedRelationship: &entities.Relationship{AreFriends: true, BSubscribesToA: true},
+		},
+		{
+			name:   "same user should fail",
+			emailA: "a@example.com",
+			emailB: "a@example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				// No mock expectations needed as validation happens before repository calls
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeBusiness,
+			wantErrMsg:  "Cannot get relationship with yourself",
+		},
+		{
+			name:   "user not found",
+			emailA: "a@example.com",
+			emailB: "nonexistent@example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				userA := &entities.User{ID: 1, Email: "a@example.com"}
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(userA, nil)
+				mockRepo.EXPECT().GetUserByEmail("nonexistent@example.com").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", "nonexistent@example.com"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeNotFound,
+			wantErrMsg:  "User not found: nonexistent@example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+			tt.setupMock(mockRepo)
+
+			controller := NewUserController(mockRepo, newTestEventHub())
+			relationship, err := controller.GetRelationship(tt.emailA, tt.emailB)
+
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedRelationship, relationship)
+				return
+			}
+
+			assert.Error(t, err)
+			var appErr *errors.AppError
+			assert.True(t, stderrors.As(err, &appErr))
+			assert.Equal(t, tt.wantErrType, appErr.Type)
+			assert.Equal(t, tt.wantErrMsg, appErr.Message)
+		})
+	}
+}
+
+func TestGetFriendSuggestions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name                string
+		email               string
+		limit               int
+		setupMock           func(mockRepo *mocks.MockUserRepositoryInterface)
+		wantErr             bool
+		wantErrType         errors.ErrorType
+		wantErrMsg          string
+		expectedSuggestions []*entities.FriendSuggestion
+	}{
+		{
+			name:  "successful suggestions retrieval",
+			email: "a@example.com",
+			limit: 10,
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				user := &entities.User{ID: 1, Email: "a@example.com"}
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(user, nil)
+				mockRepo.EXPECT().GetFriendSuggestions(user, 10).Return([]*entities.FriendSuggestion{
+					{User: &entities.User{ID: 4, Email: "d@example.com"}, MutualFriends: 2},
+					{User: &entities.User{ID: 5, Email: "e@example.com"}, MutualFriends: 1},
+				}, nil)
+			},
+			wantErr: false,
+			expectedSuggestions: []*entities.FriendSuggestion{
+				{User: &entities.User{ID: 4, Email: "d@example.com"}, MutualFriends: 2},
+				{User: &entities.User{ID: 5, Email: "e@example.com"}, MutualFriends: 1},
+			},
+		},
+		{
+			name:  "user not found",
+			email: "nonexistent@example.com",
+			limit: 10,
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetUserByEmail("nonexistent@example.com").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", "nonexistent@example.com"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeNotFound,
+			wantErrMsg:  "User not found: nonexistent@example.com",
+		},
+		{
+			name:  "repository error",
+			email: "a@example.com",
+			limit: 5,
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				user := &entities.User{ID: 1, Email: "a@example.com"}
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(user, nil)
+				mockRepo.EXPECT().GetFriendSuggestions(user, 5).Return(nil, errors.New(errors.ErrorTypeDatabase, "Failed to fetch friend suggestions"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeDatabase,
+			wantErrMsg:  "Failed to fetch friend suggestions",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+			tt.setupMock(mockRepo)
+
+			controller := NewUserController(mockRepo, newTestEventHub())
+			suggestions, err := controller.GetFriendSuggestions(tt.email, tt.limit)
+
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedSuggestions, suggestions)
+				return
+			}
+
+			assert.Error(t, err)
+			var appErr *errors.AppError
+			assert.True(t, stderrors.As(err, &appErr))
+			assert.Equal(t, tt.wantErrType, appErr.Type)
+			assert.Equal(t, tt.wantErrMsg, appErr.Message)
+		})
+	}
+}
+
+func TestGetFriendshipPath(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	a := &entities.User{ID: 1, Email: "a@example.com"}
+	b := &entities.User{ID: 2, Email: "b@example.com"}
+	c := &entities.User{ID: 3, Email: "c@example.com"}
+	d := &entities.User{ID: 4, Email: "d@example.com"}
+	e := &entities.User{ID: 5, Email: "e@example.com"}
+
+	// chain is a - b - c - d; withShortcut also links a and d through e
+	chain := map[int][]*entities.User{
+		1: {b},
+		2: {a, c},
+		3: {b, d},
+		4: {c},
+	}
+	withShortcut := map[int][]*entities.User{
+		1: {b, e},
+		2: {a, c},
+		3: {b, d},
+		4: {c, e},
+		5: {a, d},
+	}
+	friendsOf := func(graph map[int][]*entities.User) func(userIDs []int) (map[int][]*entities.User, error) {
+		return func(userIDs []int) (map[int][]*entities.User, error) {
+			result := make(map[int][]*entities.User)
+			for _, id := range userIDs {
+				result[id] = graph[id]
+			}
+			return result, nil
+		}
+	}
+
+	tests := []struct {
+		name         string
+		fromEmail    string
+		toEmail      string
+		maxDepth     int
+		setupMock    func(mockRepo *mocks.MockUserRepositoryInterface)
+		wantErr      bool
+		wantErrType  errors.ErrorType
+		wantErrMsg   string
+		expectedPath []*entities.User
+	}{
+		{
+			name:      "direct friends",
+			fromEmail: "a@example.com",
+			toEmail:   "b@example.com",
+			maxDepth:  6,
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(a, nil)
+				mockRepo.EXPECT().GetUserByEmail("b@example.com").Return(b, nil)
+				mockRepo.EXPECT().GetUnblockedFriendsBatch(gomock.Any()).DoAndReturn(friendsOf(chain)).AnyTimes()
+			},
+			wantErr:      false,
+			expectedPath: []*entities.User{a, b},
+		},
+		{
+			name:      "path through several friends",
+			fromEmail: "a@example.com",
+			toEmail:   "d@example.com",
+			maxDepth:  6,
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(a, nil)
+				mockRepo.EXPECT().GetUserByEmail("d@example.com").Return(d, nil)
+				mockRepo.EXPECT().GetUnblockedFriendsBatch(gomock.Any()).DoAndReturn(friendsOf(chain)).AnyTimes()
+			},
+			wantErr:      false,
+			expectedPath: []*entities.User{a, b, c, d},
+		},
+		{
+			name:      "shortest path is preferred",
+			fromEmail: "a@example.com",
+			toEmail:   "d@example.com",
+			maxDepth:  6,
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(a, nil)
+				mockRepo.EXPECT().GetUserByEmail("d@example.com").Return(d, nil)
+				mockRepo.EXPECT().GetUnblockedFriendsBatch(gomock.Any()).DoAndReturn(friendsOf(withShortcut)).AnyTimes()
+			},
+			wantErr:      false,
+			expectedPath: []*entities.User{a, e, d},
+		},
+		{
+			name:      "path longer than max depth",
+			fromEmail: "a@example.com",
+			toEmail:   "d@example.com",
+			maxDepth:  2,
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(a, nil)
+				mockRepo.EXPECT().GetUserByEmail("d@example.com").Return(d, nil)
+				mockRepo.EXPECT().GetUnblockedFriendsBatch(gomock.Any()).DoAndReturn(friendsOf(chain)).Times(2)
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeNotFound,
+			wantErrMsg:  "No friendship path found within max depth",
+		},
+		{
+			name:      "disconnected users",
+			fromEmail: "a@example.com",
+			toEmail:   "e@example.com",
+			maxDepth:  6,
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(a, nil)
+				mockRepo.EXPECT().GetUserByEmail("e@example.com").Return(e, nil)
+				mockRepo.EXPECT().GetUnblockedFriendsBatch(gomock.Any()).DoAndReturn(friendsOf(chain)).AnyTimes()
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeNotFound,
+			wantErrMsg:  "No friendship path found within max depth",
+		},
+		{
+			name:      "same user",
+			fromEmail: "a@example.com",
+			toEmail:   "a@example.com",
+			maxDepth:  6,
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				// No mock expectations needed as validation happens before repository calls
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeBusiness,
+			wantErrMsg:  "Cannot get friendship path to yourself",
+		},
+		{
+			name:      "target not found",
+			fromEmail: "a@example.com",
+			toEmail:   "nonexistent@example.com",
+			maxDepth:  6,
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(a, nil)
+				mockRepo.EXPECT().GetUserByEmail("nonexistent@example.com").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", "nonexistent@example.com"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeNotFound,
+			wantErrMsg:  "User not found: nonexistent@example.com",
+		},
+		{
+			name:      "repository error",
+			fromEmail: "a@example.com",
+			toEmail:   "d@example.com",
+			maxDepth:  6,
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(a, nil)
+				mockRepo.EXPECT().GetUserByEmail("d@example.com").Return(d, nil)
+				mockRepo.EXPECT().GetUnblockedFriendsBatch([]int{1}).Return(nil, errors.New(errors.ErrorTypeDatabase, "Failed to fetch friends batch"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeDatabase,
+			wantErrMsg:  "Failed to fetch friends batch",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+			tt.setupMock(mockRepo)
+
+			controller := NewUserController(mockRepo, newTestEventHub())
+			path, err := controller.GetFriendshipPath(tt.fromEmail, tt.toEmail, tt.maxDepth)
+
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedPath, path)
+				return
+			}
+
+			assert.Error(t, err)
+			var appErr *errors.AppError
+			assert.True(t, stderrors.As(err, &appErr))
+			assert.Equal(t, tt.wantErrType, appErr.Type)
+			assert.Equal(t, tt.wantErrMsg, appErr.Message)
+		})
+	}
+}
+
+func TestExplainRecipients(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sender := &entities.User{ID: 1, Email: "sender@example.com"}
+	recipients := []*entities.Recipient{
+		{
+			User:    &entities.User{ID: 2, Email: "friend@example.com"},
+			Reasons: []entities.RecipientReason{entities.RecipientReasonFriend, entities.RecipientReasonMentioned},
+		},
+		{
+			User:    &entities.User{ID: 3, Email: "subscriber@example.com"},
+			Reasons: []entities.RecipientReason{entities.RecipientReasonSubscriber},
+		},
+	}
+	dropped := []*entities.DroppedMention{
+		{Email: "blocked@example.com", Reason: entities.DroppedMentionBlocked},
+		{Email: "ghost@example.com", Reason: entities.DroppedMentionUnknown},
+	}
+
+	tests := []struct {
+		name                string
+		senderEmail         string
+		text                string
+		setupMock           func(mockRepo *mocks.MockUserRepositoryInterface)
+		wantErr             bool
+		wantErrType         errors.ErrorType
+		wantErrMsg          string
+		expectedExplanation *entities.RecipientExplanation
+	}{
+		{
+			name:        "recipients with reasons and dropped mentions",
+			senderEmail: "sender@example.com",
+			text:        "Hi friend@example.com blocked@example.com ghost@example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mentioned := []string{"friend@example.com", "blocked@example.com", "ghost@example.com"}
+				mockRepo.EXPECT().GetUserByEmail("sender@example.com").Return(sender, nil)
+				mockRepo.EXPECT().GetRecipients(sender, mentioned, gomock.Any(), entities.PageRequest{}).Return(&entities.RecipientPage{Recipients: recipients, Total: len(recipients)}, nil)
+				mockRepo.EXPECT().GetDroppedMentions(sender, mentioned, gomock.Any()).Return(dropped, nil)
+			},
+			wantErr: false,
+			expectedExplanation: &entities.RecipientExplanation{
+				RecipientPage:   entities.RecipientPage{Recipients: recipients, Total: len(recipients)},
+				DroppedMentions: dropped,
+			},
+		},
+		{
+			name:        "unknown handles are dropped after the emails",
+			senderEmail: "sender@example.com",
+			text:        "Hi @friend ghost@example.com @nobody",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mentioned := []string{"ghost@example.com", "friend@example.com"}
+				mockRepo.EXPECT().GetUserByEmail("sender@example.com").Return(sender, nil)
+				mockRepo.EXPECT().GetEmailsByUsernames([]string{"friend", "nobody"}).Return(map[string]string{"friend": "friend@example.com"}, nil)
+				mockRepo.EXPECT().GetRecipients(sender, mentioned, gomock.Any(), entities.PageRequest{}).Return(&entities.RecipientPage{Recipients: recipients[:1], Total: 1}, nil)
+				mockRepo.EXPECT().GetDroppedMentions(sender, mentioned, gomock.Any()).Return([]*entities.DroppedMention{
+					{Email: "ghost@example.com", Reason: entities.DroppedMentionUnknown},
+				}, nil)
+			},
+			wantErr: false,
+			expectedExplanation: &entities.RecipientExplanation{
+				RecipientPage: entities.RecipientPage{Recipients: recipients[:1], Total: 1},
+				DroppedMentions: []*entities.DroppedMention{
+					{Email: "ghost@example.com", Reason: entities.DroppedMentionUnknown},
+					{Handle: "nobody", Reason: entities.DroppedMentionUnknown},
+				},
+			},
+		},
+		{
+			name:        "sender not found",
+			senderEmail: "nonexistent@example.com",
+			text:        "Hello",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetUserByEmail("nonexistent@example.com").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", "nonexistent@example.com"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeNotFound,
+			wantErrMsg:  "User not found: nonexistent@example.com",
+		},
+		{
+			name:        "error fetching dropped mentions",
+			senderEmail: "sender@example.com",
+			text:        "Hi ghost@example.com",
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				mockRepo.EXPECT().GetUserByEmail("sender@example.com").Return(sender, nil)
+				mockRepo.EXPECT().GetRecipients(sender, []string{"ghost@example.com"}, gomock.Any(), entities.PageRequest{}).Return(&entities.RecipientPage{Recipients: recipients, Total: len(recipients)}, nil)
+				mockRepo.EXPECT().GetDroppedMentions(sender, []string{"ghost@example.com"}, gomock.Any()).Return(nil, errors.New(errors.ErrorTypeDatabase, "Failed to fetch dropped mentions"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeDatabase,
+			wantErrMsg:  "Failed to fetch dropped mentions",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+			tt.setupMock(mockRepo)
+
+			controller := NewUserController(mockRepo, newTestEventHub())
+			explanation, err := controller.ExplainRecipients(tt.senderEmail, tt.text, "", 0)
+
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedExplanation, explanation)
+				return
+			}
+
+			assert.Error(t, err)
+			var appErr *errors.AppError
+			assert.True(t, stderrors.As(err, &appErr))
+			assert.Equal(t, tt.wantErrType, appErr.Type)
+			assert.Equal(t, tt.wantErrMsg, appErr.Message)
+		})
+	}
+}
+
+func TestUserEventsArePublished(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userA := &entities.User{ID: 1, Email: "a@example.com"}
+	userB := &entities.User{ID: 2, Email: "b@example.com"}
+
+	expectUsers := func(mockRepo *mocks.MockUserRepositoryInterface) {
+		mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(userA, nil)
+		mockRepo.EXPECT().GetUserByEmail("b@example.com").Return(userB, nil)
+	}
+
+	tests := []struct {
+		name          string
+		action        func(controller interfaces.UserControllerInterface) error
+		setupMock     func(mockRepo *mocks.MockUserRepositoryInterface)
+		wantEventType entities.UserEventType
+		wantUserIDs   []int
+	}{
+		{
+			name: "friendship removed",
+			action: func(controller interfaces.UserControllerInterface) error {
+				return controller.DeleteFriendship("a@example.com", "b@example.com")
+			},
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				expectUsers(mockRepo)
+				mockRepo.EXPECT().DeleteFriendship(userA, userB).Return(nil)
+			},
+			wantEventType: entities.UserEventFriendRemoved,
+			wantUserIDs:   []int{1, 2},
+		},
+		{
+			name: "friend request sent",
+			action: func(controller interfaces.UserControllerInterface) error {
+				_, err := controller.SendFriendRequest("a@example.com", "b@example.com")
+				return err
+			},
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				expectUsers(mockRepo)
+				mockRepo.EXPECT().CheckBidirectionalBlock(1, 2).Return(false, nil)
+				mockRepo.EXPECT().CheckFriendshipExists(1, 2).Return(false, nil)
+				mockRepo.EXPECT().CreateFriendRequest(userA, userB).Return(&entities.FriendRequest{ID: 1, Requester: userA, Addressee: userB}, nil)
+			},
+			wantEventType: entities.UserEventFriendRequested,
+			wantUserIDs:   []int{1, 2},
+		},
+		{
+			name: "friend request accepted",
+			action: func(controller interfaces.UserControllerInterface) error {
+				return controller.AcceptFriendRequest("a@example.com", "b@example.com")
+			},
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				expectUsers(mockRepo)
+				mockRepo.EXPECT().AcceptFriendRequestTx(userB, userA).Return(nil)
+			},
+			wantEventType: entities.UserEventFriendAdded,
+			wantUserIDs:   []int{1, 2},
+		},
+		{
+			name: "subscription created",
+			action: func(controller interfaces.UserControllerInterface) error {
+				return controller.CreateSubscription("a@example.com", "b@example.com")
+			},
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				expectUsers(mockRepo)
+				mockRepo.EXPECT().CheckBidirectionalBlock(1, 2).Return(false, nil)
+				mockRepo.EXPECT().CreateSubscription(userA, userB).Return(nil)
+			},
+			wantEventType: entities.UserEventSubscriptionAdded,
+			wantUserIDs:   []int{1, 2},
+		},
+		{
+			name: "subscription removed",
+			action: func(controller interfaces.UserControllerInterface) error {
+				return controller.DeleteSubscription("a@example.com", "b@example.com")
+			},
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				expectUsers(mockRepo)
+				mockRepo.EXPECT().DeleteSubscription(userA, userB).Return(nil)
+			},
+			wantEventType: entities.UserEventSubscriptionRemoved,
+			wantUserIDs:   []int{1, 2},
+		},
+		{
+			name: "block is only pushed to the blocker",
+			action: func(controller interfaces.UserControllerInterface) error {
+				return controller.CreateBlock("a@example.com", "b@example.com")
+			},
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				expectUsers(mockRepo)
+				mockRepo.EXPECT().CreateBlockTx(userA, userB).Return(nil)
+			},
+			wantEventType: entities.UserEventBlockAdded,
+			wantUserIDs:   []int{1},
+		},
+		{
+			name: "unblock is only pushed to the blocker",
+			action: func(controller interfaces.UserControllerInterface) error {
+				return controller.DeleteBlock("a@example.com", "b@example.com", false)
+			},
+			setupMock: func(mockRepo *mocks.MockUserRepositoryInterface) {
+				expectUsers(mockRepo)
+				mockRepo.EXPECT().DeleteBlockTx(userA, userB, false).Return(nil)
+			},
+			wantEventType: entities.UserEventBlockRemoved,
+			wantUserIDs:   []int{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+			mockEvents := mocks.NewMockUserEventHubInterface(ctrl)
+			tt.setupMock(mockRepo)
+
+			mockEvents.EXPECT().Publish(tt.wantUserIDs, gomock.Any()).Do(func(userIDs []int, event *entities.UserEvent) {
+				assert.Equal(t, tt.wantEventType, event.Type)
+				assert.Equal(t, userA, event.Actor)
+				assert.Equal(t, userB, event.Target)
+			})
+
+			controller := NewUserController(mockRepo, mockEvents)
+			assert.NoError(t, tt.action(controller))
+		})
+	}
+}
+
+func TestUserEventsAreNotPublishedOnFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userA := &entities.User{ID: 1, Email: "a@example.com"}
+	userB := &entities.User{ID: 2, Email: "b@example.com"}
+
+	mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(userA, nil)
+	mockRepo.EXPECT().GetUserByEmail("b@example.com").Return(userB, nil)
+	mockRepo.EXPECT().CreateSubscription(userA, userB).Return(errors.ErrAlreadySubscribed)
+	mockRepo.EXPECT().CheckBidirectionalBlock(1, 2).Return(false, nil)
+
+	// No Publish expectation: the mock fails the test if an event is published
+	mockEvents := mocks.NewMockUserEventHubInterface(ctrl)
+
+	controller := NewUserController(mockRepo, mockEvents)
+	assert.Equal(t, errors.ErrAlreadySubscribed, controller.CreateSubscription("a@example.com", "b@example.com"))
+}
+
+func TestSubscribeEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := &entities.User{ID: 1, Email: "a@example.com"}
+
+	t.Run("subscribes to the user's events", func(t *testing.T) {
+		mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+		mockEvents := mocks.NewMockUserEventHubInterface(ctrl)
+
+		events := make(chan *entities.UserEvent)
+		cancelled := false
+		mockRepo.EXPECT().GetUserByEmail("a@example.com").Return(user, nil)
+		mockEvents.EXPECT().Subscribe(1).Return(events, func() { cancelled = true })
+
+		controller := NewUserController(mockRepo, mockEvents)
+		stream, err := controller.SubscribeEvents("a@example.com")
+
+		assert.NoError(t, err)
+		assert.Equal(t, (<-chan *entities.UserEvent)(events), stream.Events)
+		stream.Close()
+		assert.True(t, cancelled)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+		mockEvents := mocks.NewMockUserEventHubInterface(ctrl)
+
+		mockRepo.EXPECT().GetUserByEmail("nonexistent@example.com").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", "nonexistent@example.com"))
+
+		controller := NewUserController(mockRepo, mockEvents)
+		_, err := controller.SubscribeEvents("nonexistent@example.com")
+
+		var appErr *errors.AppError
+		assert.True(t, stderrors.As(err, &appErr))
+		assert.Equal(t, errors.ErrorTypeNotFound, appErr.Type)
+	})
+}
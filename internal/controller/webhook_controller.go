@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"assignment/internal/domain/entities"
+	"assignment/internal/domain/interfaces"
+	"assignment/pkg/errors"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+type webhookController struct {
+	webhookRepo    interfaces.WebhookRepositoryInterface
+	userController interfaces.UserControllerInterface
+}
+
+func NewWebhookController(webhookRepo interfaces.WebhookRepositoryInterface, userController interfaces.UserControllerInterface) interfaces.WebhookControllerInterface {
+	return &webhookController{
+		webhookRepo:    webhookRepo,
+		userController: userController,
+	}
+}
+
+// RegisterWebhook sets the URL the user's updates are delivered to. Every call
+// issues a new signing secret, so registering again also rotates the secret
+func (c *webhookController) RegisterWebhook(email, url string) (*entities.Webhook, error) {
+	user, err := c.userController.GetUser(email)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.webhookRepo.UpsertWebhook(user, url, secret)
+}
+
+func (c *webhookController) RemoveWebhook(email string) error {
+	user, err := c.userController.GetUser(email)
+	if err != nil {
+		return err
+	}
+
+	return c.webhookRepo.DeleteWebhook(user)
+}
+
+func (c *webhookController) GetWebhookDeliveries(email string, limit int) ([]*entities.WebhookDelivery, error) {
+	user, err := c.userController.GetUser(email)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook, err := c.webhookRepo.GetWebhookByUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.webhookRepo.GetWebhookDeliveries(webhook, limit)
+}
+
+// generateWebhookSecret returns 32 random bytes, hex encoded
+func generateWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", errors.Wrap(err, errors.ErrorTypeInternal, "Failed to generate webhook secret")
+	}
+
+	return hex.EncodeToString(secret), nil
+}
@@ -0,0 +1,268 @@
+package controller
+
+import (
+	"assignment/internal/domain/entities"
+	"assignment/mocks"
+	"assignment/pkg/errors"
+	stderrors "errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRegisterWebhook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := &entities.User{ID: 1, Email: "user@example.com"}
+
+	tests := []struct {
+		name        string
+		email       string
+		url         string
+		setupMock   func(mockRepo *mocks.MockWebhookRepositoryInterface, mockUser *mocks.MockUserControllerInterface)
+		wantErr     bool
+		wantErrType errors.ErrorType
+		wantErrMsg  string
+	}{
+		{
+			name:  "successful registration",
+			email: "user@example.com",
+			url:   "https://example.com/hook",
+			setupMock: func(mockRepo *mocks.MockWebhookRepositoryInterface, mockUser *mocks.MockUserControllerInterface) {
+				mockUser.EXPECT().GetUser("user@example.com").Return(user, nil)
+				mockRepo.EXPECT().UpsertWebhook(user, "https://example.com/hook", gomock.Any()).DoAndReturn(
+					func(user *entities.User, url, secret string) (*entities.Webhook, error) {
+						return &entities.Webhook{ID: 1, User: user, URL: url, Secret: secret}, nil
+					})
+			},
+			wantErr: false,
+		},
+		{
+			name:  "user not found",
+			email: "nonexistent@example.com",
+			url:   "https://example.com/hook",
+			setupMock: func(mockRepo *mocks.MockWebhookRepositoryInterface, mockUser *mocks.MockUserControllerInterface) {
+				mockUser.EXPECT().GetUser("nonexistent@example.com").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", "nonexistent@example.com"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeNotFound,
+			wantErrMsg:  "User not found: nonexistent@example.com",
+		},
+		{
+			name:  "repository error",
+			email: "user@example.com",
+			url:   "https://example.com/hook",
+			setupMock: func(mockRepo *mocks.MockWebhookRepositoryInterface, mockUser *mocks.MockUserControllerInterface) {
+				mockUser.EXPECT().GetUser("user@example.com").Return(user, nil)
+				mockRepo.EXPECT().UpsertWebhook(user, "https://example.com/hook", gomock.Any()).Return(nil, errors.New(errors.ErrorTypeDatabase, "Database operation failed"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeDatabase,
+			wantErrMsg:  "Database operation failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockWebhookRepositoryInterface(ctrl)
+			mockUser := mocks.NewMockUserControllerInterface(ctrl)
+			tt.setupMock(mockRepo, mockUser)
+
+			controller := NewWebhookController(mockRepo, mockUser)
+			webhook, err := controller.RegisterWebhook(tt.email, tt.url)
+
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.url, webhook.URL)
+				assert.Len(t, webhook.Secret, 64)
+				return
+			}
+
+			assert.Error(t, err)
+			var appErr *errors.AppError
+			assert.True(t, stderrors.As(err, &appErr))
+			assert.Equal(t, tt.wantErrType, appErr.Type)
+			assert.Equal(t, tt.wantErrMsg, appErr.Message)
+		})
+	}
+}
+
+func TestRegisterWebhook_RotatesSecret(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := &entities.User{ID: 1, Email: "user@example.com"}
+
+	mockRepo := mocks.NewMockWebhookRepositoryInterface(ctrl)
+	mockUser := mocks.NewMockUserControllerInterface(ctrl)
+	mockUser.EXPECT().GetUser("user@example.com").Return(user, nil).Times(2)
+
+	var secrets []string
+	mockRepo.EXPECT().UpsertWebhook(user, "https://example.com/hook", gomock.Any()).DoAndReturn(
+		func(user *entities.User, url, secret string) (*entities.Webhook, error) {
+			secrets = append(secrets, secret)
+			return &entities.Webhook{ID: 1, User: user, URL: url, Secret: secret}, nil
+		}).Times(2)
+
+	controller := NewWebhookController(mockRepo, mockUser)
+	_, err := controller.RegisterWebhook("user@example.com", "https://example.com/hook")
+	assert.NoError(t, err)
+	_, err = controller.RegisterWebhook("user@example.com", "https://example.com/hook")
+	assert.NoError(t, err)
+
+	assert.Len(t, secrets, 2)
+	assert.NotEqual(t, secrets[0], secrets[1])
+}
+
+func TestRemoveWebhook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := &entities.User{ID: 1, Email: "user@example.com"}
+
+	tests := []struct {
+		name        string
+		email       string
+		setupMock   func(mockRepo *mocks.MockWebhookRepositoryInterface, mockUser *mocks.MockUserControllerInterface)
+		wantErr     bool
+		wantErrType errors.ErrorType
+		wantErrMsg  string
+	}{
+		{
+			name:  "successful removal",
+			email: "user@example.com",
+			setupMock: func(mockRepo *mocks.MockWebhookRepositoryInterface, mockUser *mocks.MockUserControllerInterface) {
+				mockUser.EXPECT().GetUser("user@example.com").Return(user, nil)
+				mockRepo.EXPECT().DeleteWebhook(user).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:  "no webhook registered",
+			email: "user@example.com",
+			setupMock: func(mockRepo *mocks.MockWebhookRepositoryInterface, mockUser *mocks.MockUserControllerInterface) {
+				mockUser.EXPECT().GetUser("user@example.com").Return(user, nil)
+				mockRepo.EXPECT().DeleteWebhook(user).Return(errors.ErrWebhookNotFound)
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeNotFound,
+			wantErrMsg:  "Webhook not found",
+		},
+		{
+			name:  "user not found",
+			email: "nonexistent@example.com",
+			setupMock: func(mockRepo *mocks.MockWebhookRepositoryInterface, mockUser *mocks.MockUserControllerInterface) {
+				mockUser.EXPECT().GetUser("nonexistent@example.com").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", "nonexistent@example.com"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeNotFound,
+			wantErrMsg:  "User not found: nonexistent@example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockWebhookRepositoryInterface(ctrl)
+			mockUser := mocks.NewMockUserControllerInterface(ctrl)
+			tt.setupMock(mockRepo, mockUser)
+
+			controller := NewWebhookController(mockRepo, mockUser)
+			err := controller.RemoveWebhook(tt.email)
+
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.Error(t, err)
+			var appErr *errors.AppError
+			assert.True(t, stderrors.As(err, &appErr))
+			assert.Equal(t, tt.wantErrType, appErr.Type)
+			assert.Equal(t, tt.wantErrMsg, appErr.Message)
+		})
+	}
+}
+
+func TestGetWebhookDeliveries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := &entities.User{ID: 1, Email: "user@example.com"}
+	webhook := &entities.Webhook{ID: 7, User: user, URL: "https://example.com/hook"}
+	deliveries := []*entities.WebhookDelivery{
+		{ID: 2, WebhookID: 7, UpdateID: 5, Attempt: 2, StatusCode: 200, Success: true},
+		{ID: 1, WebhookID: 7, UpdateID: 5, Attempt: 1, StatusCode: 500, Error: "EXTERNAL_ERROR: Webhook responded with status 500"},
+	}
+
+	tests := []struct {
+		name               string
+		email              string
+		limit              int
+		setupMock          func(mockRepo *mocks.MockWebhookRepositoryInterface, mockUser *mocks.MockUserControllerInterface)
+		wantErr            bool
+		wantErrType        errors.ErrorType
+		wantErrMsg         string
+		expectedDeliveries []*entities.WebhookDelivery
+	}{
+		{
+			name:  "deliveries of the user's webhook",
+			email: "user@example.com",
+			limit: 20,
+			setupMock: func(mockRepo *mocks.MockWebhookRepositoryInterface, mockUser *mocks.MockUserControllerInterface) {
+				mockUser.EXPECT().GetUser("user@example.com").Return(user, nil)
+				mockRepo.EXPECT().GetWebhookByUser(user).Return(webhook, nil)
+				mockRepo.EXPECT().GetWebhookDeliveries(webhook, 20).Return(deliveries, nil)
+			},
+			wantErr:            false,
+			expectedDeliveries: deliveries,
+		},
+		{
+			name:  "no webhook registered",
+			email: "user@example.com",
+			limit: 20,
+			setupMock: func(mockRepo *mocks.MockWebhookRepositoryInterface, mockUser *mocks.MockUserControllerInterface) {
+				mockUser.EXPECT().GetUser("user@example.com").Return(user, nil)
+				mockRepo.EXPECT().GetWebhookByUser(user).Return(nil, errors.ErrWebhookNotFound)
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeNotFound,
+			wantErrMsg:  "Webhook not found",
+		},
+		{
+			name:  "user not found",
+			email: "nonexistent@example.com",
+			limit: 20,
+			setupMock: func(mockRepo *mocks.MockWebhookRepositoryInterface, mockUser *mocks.MockUserControllerInterface) {
+				mockUser.EXPECT().GetUser("nonexistent@example.com").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", "nonexistent@example.com"))
+			},
+			wantErr:     true,
+			wantErrType: errors.ErrorTypeNotFound,
+			wantErrMsg:  "User not found: nonexistent@example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockWebhookRepositoryInterface(ctrl)
+			mockUser := mocks.NewMockUserControllerInterface(ctrl)
+			tt.setupMock(mockRepo, mockUser)
+
+			controller := NewWebhookController(mockRepo, mockUser)
+			result, err := controller.GetWebhookDeliveries(tt.email, tt.limit)
+
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedDeliveries, result)
+				return
+			}
+
+			assert.Error(t, err)
+			var appErr *errors.AppError
+			assert.True(t, stderrors.As(err, &appErr))
+			assert.Equal(t, tt.wantErrType, appErr.Type)
+			assert.Equal(t, tt.wantErrMsg, appErr.Message)
+		})
+	}
+}
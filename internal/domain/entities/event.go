@@ -0,0 +1,40 @@
+package entities
+
+import (
+	"strings"
+	"time"
+)
+
+type UserEventType string
+
+const (
+	UserEventFriendAdded         UserEventType = "friend.added"
+	UserEventFriendRemoved       UserEventType = "friend.removed"
+	UserEventFriendRequested     UserEventType = "friend.requested"
+	UserEventSubscriptionAdded   UserEventType = "subscription.added"
+	UserEventSubscriptionRemoved UserEventType = "subscription.removed"
+	UserEventBlockAdded          UserEventType = "block.added"
+	UserEventBlockRemoved        UserEventType = "block.removed"
+	UserEventUpdateReceived      UserEventType = "update.received"
+)
+
+// Topic is the part of the event type before the dot, e.g. "friend"
+func (t UserEventType) Topic() string {
+	topic, _, _ := strings.Cut(string(t), ".")
+	return topic
+}
+
+// UserEvent is a relationship change pushed to the live connections of the users
+// it concerns. Actor made the change and Target is the other user
+type UserEvent struct {
+	Type      UserEventType
+	Actor     *User
+	Target    *User
+	CreatedAt time.Time
+}
+
+// UserEventStream is a live subscription to the events that concern a user
+type UserEventStream struct {
+	Events <-chan *UserEvent
+	Close  func()
+}
@@ -0,0 +1,20 @@
+package entities
+
+import "time"
+
+type FriendRequestStatus string
+
+const (
+	FriendRequestPending   FriendRequestStatus = "pending"
+	FriendRequestAccepted  FriendRequestStatus = "accepted"
+	FriendRequestRejected  FriendRequestStatus = "rejected"
+	FriendRequestCancelled FriendRequestStatus = "cancelled"
+)
+
+type FriendRequest struct {
+	ID        int
+	Requester *User
+	Addressee *User
+	Status    FriendRequestStatus
+	CreatedAt time.Time
+}
@@ -0,0 +1,11 @@
+package entities
+
+import "time"
+
+// Mute hides Muted's updates from Muter until ExpiresAt, or for good when it is nil
+type Mute struct {
+	Muter     *User
+	Muted     *User
+	ExpiresAt *time.Time
+	CreatedAt time.Time
+}
@@ -0,0 +1,45 @@
+package entities
+
+import "time"
+
+type OutboxStatus string
+
+const (
+	OutboxPending   OutboxStatus = "pending"
+	OutboxDelivered OutboxStatus = "delivered"
+	OutboxDead      OutboxStatus = "dead"
+)
+
+const (
+	EventUpdatePublished = "update.published"
+	EventWebhookDelivery = "webhook.delivery"
+)
+
+// OutboxEvent is an event waiting to be delivered by the outbox worker
+type OutboxEvent struct {
+	ID        int
+	EventType string
+	Payload   []byte
+	Attempts  int
+	CreatedAt time.Time
+}
+
+// UpdatePublishedPayload is the JSON payload of an update.published event
+type UpdatePublishedPayload struct {
+	UpdateID   int       `json:"update_id"`
+	Sender     string    `json:"sender"`
+	Text       string    `json:"text"`
+	Recipients []string  `json:"recipients"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// WebhookDeliveryPayload is the JSON payload of a webhook.delivery event, one per
+// recipient with a webhook. It leaves out the other recipients on purpose
+type WebhookDeliveryPayload struct {
+	WebhookID int       `json:"webhook_id"`
+	Recipient string    `json:"recipient"`
+	UpdateID  int       `json:"update_id"`
+	Sender    string    `json:"sender"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
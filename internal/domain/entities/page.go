@@ -0,0 +1,16 @@
+package entities
+
+// PageRequest selects up to Limit items that sort after the After key, or
+// every remaining item when Limit is zero
+type PageRequest struct {
+	After string
+	Limit int
+}
+
+// UserPage is one page of a list of users ordered by email. Total counts the
+// users on every page and NextCursor, empty on the last page, fetches the next one
+type UserPage struct {
+	Users      []*User
+	Total      int
+	NextCursor string
+}
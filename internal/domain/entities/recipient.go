@@ -0,0 +1,47 @@
+package entities
+
+type RecipientReason string
+
+const (
+	RecipientReasonFriend     RecipientReason = "friend"
+	RecipientReasonSubscriber RecipientReason = "subscriber"
+	RecipientReasonMentioned  RecipientReason = "mentioned"
+)
+
+// Recipient is a user who receives an update, with every reason that applies
+type Recipient struct {
+	User    *User
+	Reasons []RecipientReason
+}
+
+// RecipientPage is one page of the recipients of an update, ordered by email
+type RecipientPage struct {
+	Recipients []*Recipient
+	Total      int
+	NextCursor string
+}
+
+type DroppedMentionReason string
+
+const (
+	DroppedMentionUnknown    DroppedMentionReason = "unknown"
+	DroppedMentionBlocked    DroppedMentionReason = "blocked"
+	DroppedMentionSelf       DroppedMentionReason = "self"
+	DroppedMentionRestricted DroppedMentionReason = "restricted"
+	DroppedMentionMuted      DroppedMentionReason = "muted"
+)
+
+// DroppedMention is a mentioned email, or an @handle nobody owns, that did not
+// become a recipient
+type DroppedMention struct {
+	Email  string
+	Handle string
+	Reason DroppedMentionReason
+}
+
+// RecipientExplanation is a page of recipients with the reasons they receive
+// the update, and every mention that was dropped
+type RecipientExplanation struct {
+	RecipientPage
+	DroppedMentions []*DroppedMention
+}
@@ -0,0 +1,15 @@
+package entities
+
+// MentionPolicy decides who may make a user a recipient by mentioning them
+type MentionPolicy string
+
+const (
+	MentionPolicyEveryone MentionPolicy = "everyone"
+	MentionPolicyFriends  MentionPolicy = "friends"
+	MentionPolicyNobody   MentionPolicy = "nobody"
+)
+
+type UserSettings struct {
+	User          *User
+	MentionPolicy MentionPolicy
+}
@@ -0,0 +1,9 @@
+package entities
+
+// SubscriptionFilter narrows a subscription to the updates that contain any of
+// the Include keywords, or all updates when there are none, and none of the
+// Exclude keywords. Keywords are lowercase; hashtags keep their '#'
+type SubscriptionFilter struct {
+	Include []string
+	Exclude []string
+}
@@ -0,0 +1,26 @@
+package entities
+
+import "time"
+
+type Update struct {
+	ID         int
+	Sender     *User
+	Text       string
+	Recipients []*User
+	CreatedAt  time.Time
+}
+
+// TimelinePage is one page of the updates a user received, newest first
+type TimelinePage struct {
+	Updates []*Update
+	HasMore bool
+}
+
+// UpdateStream is a live subscription to a user's incoming updates. Replay
+// passes the updates missed since the last one the client saw to send a page at
+// a time, oldest first, and stops at the first error send returns
+type UpdateStream struct {
+	Replay  func(send func(page []*Update) error) error
+	Updates <-chan *Update
+	Close   func()
+}
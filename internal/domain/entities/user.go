@@ -4,3 +4,17 @@ type User struct {
 	ID    int
 	Email string
 }
+
+// Relationship describes how user A relates to user B
+type Relationship struct {
+	AreFriends     bool
+	ASubscribesToB bool
+	BSubscribesToA bool
+	ABlocksB       bool
+	BBlocksA       bool
+}
+
+type FriendSuggestion struct {
+	User          *User
+	MutualFriends int
+}
@@ -0,0 +1,27 @@
+package entities
+
+import "time"
+
+// Webhook is the endpoint a user receives their updates on. Every delivery is
+// signed with the secret
+type Webhook struct {
+	ID        int
+	User      *User
+	URL       string
+	Secret    string
+	CreatedAt time.Time
+}
+
+// WebhookDelivery records one attempt to deliver an update to a webhook
+type WebhookDelivery struct {
+	ID            int
+	WebhookID     int
+	OutboxEventID int
+	UpdateID      int
+	Attempt       int
+	StatusCode    int
+	Success       bool
+	Error         string
+	Duration      time.Duration
+	CreatedAt     time.Time
+}
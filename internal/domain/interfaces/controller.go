@@ -1,16 +1,69 @@
 package interfaces
 
-import "assignment/internal/domain/entities"
+import (
+    "assignment/internal/domain/entities"
+    "time"
+)
 
 type UserControllerInterface interface {
-    CreateFriendship(user1Email, user2Email string) error
-    GetFriendList(email string) ([]*entities.User, error)
-    GetCommonFriends(email1, email2 string) ([]*entities.User, error)
+    DeleteFriendship(user1Email, user2Email string) error
+    SendFriendRequest(requestorEmail, targetEmail string) (*entities.FriendRequest, error)
+    GetIncomingFriendRequests(email string) ([]*entities.FriendRequest, error)
+    GetOutgoingFriendRequests(email string) ([]*entities.FriendRequest, error)
+    AcceptFriendRequest(requestorEmail, targetEmail string) error
+    RejectFriendRequest(requestorEmail, targetEmail string) error
+    CancelFriendRequest(requestorEmail, targetEmail string) error
+    GetFriendList(email, cursor string, limit int) (*entities.UserPage, error)
+    GetFriendListsBatch(users []*entities.User, cursor string, limit int) (map[int]*entities.UserPage, error)
+    GetCommonFriends(emails []string, cursor string, limit int) (*entities.UserPage, error)
+    GetCommonFriendsBatch(users []*entities.User, emails []string, cursor string, limit int) (map[int]*entities.UserPage, error)
+    GetFriendshipPath(requestorEmail, targetEmail string, maxDepth int) ([]*entities.User, error)
+    GetFriendSuggestions(email string, limit int) ([]*entities.FriendSuggestion, error)
     CreateSubscription(requestorEmail, targetEmail string) error
+    DeleteSubscription(requestorEmail, targetEmail string) error
+    GetSubscribers(email, cursor string, limit int) (*entities.UserPage, error)
+    GetSubscribersBatch(users []*entities.User, cursor string, limit int) (map[int]*entities.UserPage, error)
+    GetSubscriptions(email, cursor string, limit int) (*entities.UserPage, error)
+    CountSubscribers(emails []string) (map[string]int, error)
+    CountSubscribersBatch(users []*entities.User) (map[int]int, error)
+    GetSubscriptionFilter(requestorEmail, targetEmail string) (*entities.SubscriptionFilter, error)
+    UpdateSubscriptionFilter(requestorEmail, targetEmail string, filter *entities.SubscriptionFilter) (*entities.SubscriptionFilter, error)
     CreateBlock(requestorEmail, targetEmail string) error
-    GetRecipients(senderEmail, text string) ([]*entities.User, error)
+    DeleteBlock(requestorEmail, targetEmail string, restore bool) error
+    GetBlockedUsers(email, cursor string, limit int) (*entities.UserPage, error)
+    GetBlockers(email, cursor string, limit int) (*entities.UserPage, error)
+    GetBlockersBatch(users []*entities.User, cursor string, limit int) (map[int]*entities.UserPage, error)
+    CreateMute(requestorEmail, targetEmail string, expiresAt *time.Time) (*entities.Mute, error)
+    DeleteMute(requestorEmail, targetEmail string) error
+    GetMutes(email string) ([]*entities.Mute, error)
+    GetRelationship(emailA, emailB string) (*entities.Relationship, error)
+    SubscribeEvents(email string) (*entities.UserEventStream, error)
+    GetRecipients(senderEmail, text, cursor string, limit int) (*entities.UserPage, error)
+    ExplainRecipients(senderEmail, text, cursor string, limit int) (*entities.RecipientExplanation, error)
+    CreateUser(email string) (*entities.User, error)
+    GetUser(email string) (*entities.User, error)
+    GetUsersByEmails(emails []string) ([]*entities.User, error)
+    SetUsername(email, username string) error
+    GetSettings(email string) (*entities.UserSettings, error)
+    UpdateSettings(email string, mentionPolicy entities.MentionPolicy) (*entities.UserSettings, error)
+    GetUsers() ([]*entities.User, error)
+    DeleteUser(email string) error
+}
+
+type UpdateControllerInterface interface {
+    PublishUpdate(senderEmail, text string) (*entities.Update, error)
+    GetTimeline(email string, limit, offset int) (*entities.TimelinePage, error)
+    SubscribeUpdates(email string, lastEventID, backlogPageSize int) (*entities.UpdateStream, error)
+}
+
+type WebhookControllerInterface interface {
+    RegisterWebhook(email, url string) (*entities.Webhook, error)
+    RemoveWebhook(email string) error
+    GetWebhookDeliveries(email string, limit int) ([]*entities.WebhookDelivery, error)
 }
 
 type Controllers interface {
     UserController() UserControllerInterface
+    UpdateController() UpdateControllerInterface
+    WebhookController() WebhookControllerInterface
 }
\ No newline at end of file
@@ -0,0 +1,17 @@
+package interfaces
+
+import "assignment/internal/domain/entities"
+
+// UpdateHubInterface fans published updates out to the recipients connected to
+// this process
+type UpdateHubInterface interface {
+	Publish(recipientIDs []int, update *entities.Update)
+	Subscribe(userID int) (<-chan *entities.Update, func())
+}
+
+// UserEventHubInterface fans relationship events out to the users connected to
+// this process
+type UserEventHubInterface interface {
+	Publish(userIDs []int, event *entities.UserEvent)
+	Subscribe(userID int) (<-chan *entities.UserEvent, func())
+}
@@ -1,21 +1,82 @@
 package interfaces
 
-import "assignment/internal/domain/entities"
+import (
+	"assignment/internal/domain/entities"
+	"time"
+)
 
 type UserRepositoryInterface interface {
-	CreateFriendship(user1, user2 *entities.User) error
-	GetFriendList(user *entities.User) ([]*entities.User, error)
-	GetCommonFriends(user1, user2 *entities.User) ([]*entities.User, error)
+	DeleteFriendship(user1, user2 *entities.User) error
+	CheckFriendshipExists(user1ID, user2ID int) (bool, error)
+	CreateFriendRequest(requester, addressee *entities.User) (*entities.FriendRequest, error)
+	GetIncomingFriendRequests(user *entities.User) ([]*entities.FriendRequest, error)
+	GetOutgoingFriendRequests(user *entities.User) ([]*entities.FriendRequest, error)
+	AcceptFriendRequestTx(requester, addressee *entities.User) error
+	UpdateFriendRequestStatus(requester, addressee *entities.User, status entities.FriendRequestStatus) error
+	GetFriendList(user *entities.User, page entities.PageRequest) (*entities.UserPage, error)
+	GetFriendListsBatch(userIDs []int, page entities.PageRequest) (map[int]*entities.UserPage, error)
+	GetCommonFriends(users []*entities.User, page entities.PageRequest) (*entities.UserPage, error)
+	GetCommonFriendsBatch(userIDs []int, others []*entities.User, page entities.PageRequest) (map[int]*entities.UserPage, error)
+	GetUnblockedFriendsBatch(userIDs []int) (map[int][]*entities.User, error)
+	GetFriendSuggestions(user *entities.User, limit int) ([]*entities.FriendSuggestion, error)
 	CreateSubscription(requestor, target *entities.User) error
+	DeleteSubscription(requestor, target *entities.User) error
+	GetSubscriptionFilter(subscriber, target *entities.User) (*entities.SubscriptionFilter, error)
+	UpdateSubscriptionFilter(subscriber, target *entities.User, filter *entities.SubscriptionFilter) error
 	CreateBlockTx(requestor, target *entities.User) error
+	DeleteBlockTx(requestor, target *entities.User, restore bool) error
+	GetBlockedUsers(blocker *entities.User, page entities.PageRequest) (*entities.UserPage, error)
+	GetBlockers(blocked *entities.User, page entities.PageRequest) (*entities.UserPage, error)
+	GetBlockersBatch(userIDs []int, page entities.PageRequest) (map[int]*entities.UserPage, error)
 	CheckBlockExists(requestorID, targetID int) (bool, error)
 	CheckBidirectionalBlock(user1ID, user2ID int) (bool, error)
 	CheckBidirectionalBlocksBatch(senderID int, userIDs []int) (map[int]bool, error)
+	UpsertMute(muter, muted *entities.User, expiresAt *time.Time) (*entities.Mute, error)
+	DeleteMute(muter, muted *entities.User) error
+	GetMutes(muter *entities.User) ([]*entities.Mute, error)
+	GetRelationship(userA, userB *entities.User) (*entities.Relationship, error)
 	GetUserByEmail(email string) (*entities.User, error)
 	GetUsersByEmails(emails []string) ([]*entities.User, error)
-	GetSubscribersByUserID(userID int) ([]*entities.User, error)
+	GetEmailsByUsernames(usernames []string) (map[string]string, error)
+	SetUsername(user *entities.User, username string) error
+	GetSubscribersByUserID(userID int, page entities.PageRequest) (*entities.UserPage, error)
+	GetSubscriptionsByUserID(userID int, page entities.PageRequest) (*entities.UserPage, error)
+	GetSubscribersBatch(userIDs []int, page entities.PageRequest) (map[int]*entities.UserPage, error)
+	CountSubscribersBatch(userIDs []int) (map[int]int, error)
+	GetRecipients(sender *entities.User, mentionedEmails, keywords []string, page entities.PageRequest) (*entities.RecipientPage, error)
+	GetDroppedMentions(sender *entities.User, mentionedEmails, keywords []string) ([]*entities.DroppedMention, error)
+	GetUserSettings(user *entities.User) (*entities.UserSettings, error)
+	UpdateUserSettings(settings *entities.UserSettings) error
+	CreateUser(email string) (*entities.User, error)
+	GetAllUsers() ([]*entities.User, error)
+	DeleteUser(user *entities.User) error
+}
+
+type UpdateRepositoryInterface interface {
+	CreateUpdateTx(sender *entities.User, text string, recipients []*entities.User) (*entities.Update, error)
+	GetTimeline(user *entities.User, limit, offset int) ([]*entities.Update, error)
+	GetInboxSince(user *entities.User, afterUpdateID, limit int) ([]*entities.Update, error)
+}
+
+type OutboxRepositoryInterface interface {
+	ClaimOutboxEvents(limit int, lease time.Duration) ([]*entities.OutboxEvent, error)
+	MarkOutboxEventDelivered(id, attempts int) error
+	RescheduleOutboxEvent(id, attempts int, nextAttemptAt time.Time, lastError string) error
+	DeadLetterOutboxEvent(id, attempts int, lastError string) error
+}
+
+type WebhookRepositoryInterface interface {
+	UpsertWebhook(user *entities.User, url, secret string) (*entities.Webhook, error)
+	GetWebhookByID(id int) (*entities.Webhook, error)
+	GetWebhookByUser(user *entities.User) (*entities.Webhook, error)
+	DeleteWebhook(user *entities.User) error
+	RecordWebhookDelivery(delivery *entities.WebhookDelivery) error
+	GetWebhookDeliveries(webhook *entities.Webhook, limit int) ([]*entities.WebhookDelivery, error)
 }
 
 type Repositories interface {
 	UserRepository() UserRepositoryInterface
+	UpdateRepository() UpdateRepositoryInterface
+	OutboxRepository() OutboxRepositoryInterface
+	WebhookRepository() WebhookRepositoryInterface
 }
@@ -0,0 +1,102 @@
+package gql
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// batchWait is how long a loader waits for more keys after the first key
+	// of a batch, long enough for the resolvers of a list to all ask
+	batchWait = 5 * time.Millisecond
+	// maxBatchSize is the most keys fetched at once, a full page of users
+	maxBatchSize = maxPageSize
+)
+
+// loader batches the keys asked for by resolvers running concurrently into a
+// single fetch, so a field of every user in a list costs one query instead of
+// one per user. A batch is fetched once it holds maxBatch keys, or wait after
+// its first key. Values are cached by key for the life of the loader, which
+// lasts one request
+type loader[K comparable, V any] struct {
+	fetch    func(keys []K) (map[K]V, error)
+	wait     time.Duration
+	maxBatch int
+
+	mu     sync.Mutex
+	batch  *loaderBatch[K, V]
+	loaded map[K]*loaderBatch[K, V]
+}
+
+type loaderBatch[K comparable, V any] struct {
+	keys   []K
+	values map[K]V
+	err    error
+	done   chan struct{}
+}
+
+// newLoader returns a loader that fetches values with fetch. Keys missing from
+// the map fetch returns load as the zero value
+func newLoader[K comparable, V any](fetch func(keys []K) (map[K]V, error), wait time.Duration, maxBatch int) *loader[K, V] {
+	return &loader[K, V]{
+		fetch:    fetch,
+		wait:     wait,
+		maxBatch: maxBatch,
+		loaded:   make(map[K]*loaderBatch[K, V]),
+	}
+}
+
+// Load returns the value for key once the batch it was added to is fetched
+func (l *loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	b, ok := l.loaded[key]
+	if !ok {
+		b = l.batch
+		if b == nil {
+			b = &loaderBatch[K, V]{done: make(chan struct{})}
+			l.batch = b
+			time.AfterFunc(l.wait, func() { l.dispatch(b) })
+		}
+		b.keys = append(b.keys, key)
+		l.loaded[key] = b
+
+		if len(b.keys) >= l.maxBatch {
+			l.batch = nil
+			go l.run(b)
+		}
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-b.done:
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+
+	if b.err != nil {
+		var zero V
+		return zero, b.err
+	}
+	return b.values[key], nil
+}
+
+// dispatch fetches the batch once its wait is over, unless it filled up and
+// was fetched already
+func (l *loader[K, V]) dispatch(b *loaderBatch[K, V]) {
+	l.mu.Lock()
+	if l.batch != b {
+		l.mu.Unlock()
+		return
+	}
+	l.batch = nil
+	l.mu.Unlock()
+
+	l.run(b)
+}
+
+func (l *loader[K, V]) run(b *loaderBatch[K, V]) {
+	b.values, b.err = l.fetch(b.keys)
+	close(b.done)
+}
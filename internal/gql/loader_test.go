@@ -0,0 +1,114 @@
+package gql
+
+import (
+	"context"
+	stderrors "errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingFetch returns each key's length and records the batches asked for
+type recordingFetch struct {
+	mu      sync.Mutex
+	batches [][]string
+	err     error
+}
+
+func (f *recordingFetch) fetch(keys []string) (map[string]int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	batch := append([]string(nil), keys...)
+	sort.Strings(batch)
+	f.batches = append(f.batches, batch)
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	values := make(map[string]int, len(keys))
+	for _, key := range keys {
+		if key != "missing" {
+			values[key] = len(key)
+		}
+	}
+	return values, nil
+}
+
+// loadAll loads every key concurrently and returns the values in key order
+func loadAll(l *loader[string, int], keys []string) ([]int, []error) {
+	values := make([]int, len(keys))
+	errs := make([]error, len(keys))
+
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			values[i], errs[i] = l.Load(context.Background(), key)
+		}()
+	}
+	wg.Wait()
+
+	return values, errs
+}
+
+func TestLoader(t *testing.T) {
+	t.Run("concurrent loads are fetched in one batch", func(t *testing.T) {
+		f := &recordingFetch{}
+		l := newLoader(f.fetch, 20*time.Millisecond, 10)
+
+		values, errs := loadAll(l, []string{"a", "bb", "ccc", "missing"})
+
+		assert.Equal(t, []int{1, 2, 3, 0}, values)
+		assert.Equal(t, []error{nil, nil, nil, nil}, errs)
+		assert.Equal(t, [][]string{{"a", "bb", "ccc", "missing"}}, f.batches)
+	})
+
+	t.Run("full batch is fetched without waiting", func(t *testing.T) {
+		f := &recordingFetch{}
+		l := newLoader(f.fetch, time.Hour, 2)
+
+		values, _ := loadAll(l, []string{"a", "bb", "ccc", "dddd"})
+
+		assert.Equal(t, []int{1, 2, 3, 4}, values)
+		assert.Len(t, f.batches, 2)
+	})
+
+	t.Run("values are cached and keys deduplicated", func(t *testing.T) {
+		f := &recordingFetch{}
+		l := newLoader(f.fetch, 20*time.Millisecond, 10)
+
+		values, _ := loadAll(l, []string{"a", "a", "bb"})
+		assert.Equal(t, []int{1, 1, 2}, values)
+
+		value, err := l.Load(context.Background(), "bb")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, value)
+		assert.Equal(t, [][]string{{"a", "bb"}}, f.batches)
+	})
+
+	t.Run("fetch error is returned to every load of the batch", func(t *testing.T) {
+		fetchErr := stderrors.New("connection refused")
+		f := &recordingFetch{err: fetchErr}
+		l := newLoader(f.fetch, 20*time.Millisecond, 10)
+
+		_, errs := loadAll(l, []string{"a", "bb"})
+
+		assert.Equal(t, []error{fetchErr, fetchErr}, errs)
+	})
+
+	t.Run("load gives up when the context is done", func(t *testing.T) {
+		f := &recordingFetch{}
+		l := newLoader(f.fetch, time.Hour, 10)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := l.Load(ctx, "a")
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,155 @@
+package gql
+
+import (
+	"assignment/internal/domain/entities"
+	"assignment/pkg/errors"
+	"assignment/pkg/validator"
+	"context"
+	"strings"
+)
+
+// maxCommonFriendsWith is the most other users commonFriendsWith takes, 20
+// users in all as for the common friends endpoint
+const maxCommonFriendsWith = 19
+
+type queryResolver struct{}
+
+func (r *queryResolver) User(ctx context.Context, args struct{ Email string }) (*userResolver, error) {
+	v := validator.New()
+	if validator.ValidateEmail(v, args.Email); !v.Valid() {
+		return nil, errors.ToGraphQLError(errors.NewValidationError(v.Errors))
+	}
+
+	// Emails are stored in lower case, which is how the loader keys the users
+	// it finds
+	user, err := loadersFrom(ctx).users.Load(ctx, strings.ToLower(args.Email))
+	if err != nil {
+		return nil, errors.ToGraphQLError(err)
+	}
+	if user == nil {
+		return nil, nil
+	}
+
+	return &userResolver{user: user, scale: 1}, nil
+}
+
+// userResolver resolves the fields of a user. scale is the product of the page
+// sizes of the lists the user was found in, which every list of the user
+// multiplies by its own page size to stay under maxQueryCost
+type userResolver struct {
+	user  *entities.User
+	scale int
+}
+
+type pageArgs struct {
+	First *int32
+	After *string
+}
+
+type commonFriendsArgs struct {
+	Emails []string
+	First  *int32
+	After  *string
+}
+
+func (r *userResolver) Email() string {
+	return r.user.Email
+}
+
+func (r *userResolver) Friends(ctx context.Context, args pageArgs) (*userConnectionResolver, error) {
+	return r.list(ctx, loadersFrom(ctx).friends, "", args.First, args.After)
+}
+
+func (r *userResolver) Subscribers(ctx context.Context, args pageArgs) (*userConnectionResolver, error) {
+	return r.list(ctx, loadersFrom(ctx).subscribers, "", args.First, args.After)
+}
+
+func (r *userResolver) SubscriberCount(ctx context.Context) (int32, error) {
+	count, err := loadersFrom(ctx).subscriberCounts.Load(ctx, r.user)
+	if err != nil {
+		return 0, errors.ToGraphQLError(err)
+	}
+	return int32(count), nil
+}
+
+func (r *userResolver) BlockedBy(ctx context.Context, args pageArgs) (*userConnectionResolver, error) {
+	return r.list(ctx, loadersFrom(ctx).blockers, "", args.First, args.After)
+}
+
+func (r *userResolver) CommonFriendsWith(ctx context.Context, args commonFriendsArgs) (*userConnectionResolver, error) {
+	v := validator.New()
+	v.Check(len(args.Emails) >= 1 && len(args.Emails) <= maxCommonFriendsWith, "emails", "between 1 and 19 emails required")
+	for _, email := range args.Emails {
+		validator.ValidateEmail(v, email)
+	}
+	if !v.Valid() {
+		return nil, errors.ToGraphQLError(errors.NewValidationError(v.Errors))
+	}
+
+	// Lowercased so the same emails in another case share a batch. Checked
+	// here rather than by the batch, which fails as a whole, so only the user
+	// listed among the emails gets the error
+	emails := make([]string, len(args.Emails))
+	for i, email := range args.Emails {
+		emails[i] = strings.ToLower(email)
+		if emails[i] == r.user.Email {
+			return nil, errors.ToGraphQLError(errors.ErrCannotGetCommonFriendsWithSelf)
+		}
+	}
+
+	return r.list(ctx, loadersFrom(ctx).commonFriendsWith, strings.Join(emails, " "), args.First, args.After)
+}
+
+// list resolves a page of a list of users related to the user, loaded together
+// with the same page of the list of the other users of a batch
+func (r *userResolver) list(ctx context.Context, pages *loader[pageKey, *entities.UserPage], with string, first *int32, after *string) (*userConnectionResolver, error) {
+	limit := defaultPageSize
+	if first != nil {
+		limit = int(*first)
+	}
+	var cursor string
+	if after != nil {
+		cursor = *after
+	}
+
+	v := validator.New()
+	v.Check(limit >= 1, "first", "must be at least 1")
+	v.Check(limit <= maxPageSize, "first", "must not exceed 100")
+	v.Check(r.scale*limit <= maxQueryCost, "first", "nested lists must not ask for more than 10000 users in all")
+	if !v.Valid() {
+		return nil, errors.ToGraphQLError(errors.NewValidationError(v.Errors))
+	}
+
+	page, err := pages.Load(ctx, pageKey{user: r.user, with: with, cursor: cursor, limit: limit})
+	if err != nil {
+		return nil, errors.ToGraphQLError(err)
+	}
+
+	return &userConnectionResolver{page: page, scale: r.scale * limit}, nil
+}
+
+// userConnectionResolver resolves a page of users, scale being the product of
+// the page sizes of the lists down to and including this one
+type userConnectionResolver struct {
+	page  *entities.UserPage
+	scale int
+}
+
+func (r *userConnectionResolver) Nodes() []*userResolver {
+	nodes := make([]*userResolver, len(r.page.Users))
+	for i, user := range r.page.Users {
+		nodes[i] = &userResolver{user: user, scale: r.scale}
+	}
+	return nodes
+}
+
+func (r *userConnectionResolver) TotalCount() int32 {
+	return int32(r.page.Total)
+}
+
+func (r *userConnectionResolver) NextCursor() *string {
+	if r.page.NextCursor == "" {
+		return nil
+	}
+	return &r.page.NextCursor
+}
@@ -0,0 +1,152 @@
+package gql
+
+import (
+	"assignment/internal/domain/entities"
+	"assignment/internal/domain/interfaces"
+	"context"
+	_ "embed"
+	"strings"
+
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+const (
+	// defaultPageSize and maxPageSize bound the lists as in the REST API
+	defaultPageSize = 50
+	maxPageSize     = 100
+
+	// maxQueryDepth allows friends of friends of friends, lists of users
+	// nested deeper cost too many queries
+	maxQueryDepth = 8
+
+	// maxQueryCost is the most users a list may ask for counting the lists it
+	// is nested in, the product of their page sizes: 100 friends of 100
+	// friends, but not 50 friends of 50 friends of 50 friends
+	maxQueryCost = 10000
+)
+
+// Schema runs GraphQL queries on the user controller, so a client can fetch a
+// user and their relationships in one round trip
+type Schema struct {
+	schema         *graphql.Schema
+	userController interfaces.UserControllerInterface
+}
+
+// NewSchema parses schema.graphql against the resolvers. Every user of a list
+// is resolved concurrently, up to a full page at once, so their fields can be
+// batched by the loaders
+func NewSchema(userController interfaces.UserControllerInterface) *Schema {
+	return &Schema{
+		schema: graphql.MustParseSchema(schemaSDL, &queryResolver{},
+			graphql.UseStringDescriptions(),
+			graphql.MaxDepth(maxQueryDepth),
+			graphql.MaxParallelism(maxPageSize),
+		),
+		userController: userController,
+	}
+}
+
+// Exec runs a query with loaders of its own, users and lists are only cached
+// for the request
+func (s *Schema) Exec(ctx context.Context, query, operationName string, variables map[string]interface{}) *graphql.Response {
+	ctx = context.WithValue(ctx, loadersKey{}, newLoaders(s.userController))
+	return s.schema.Exec(ctx, query, operationName, variables)
+}
+
+type loadersKey struct{}
+
+// loaders batch the lookups made by the resolvers of one request. Users are
+// looked up by email once, then their fields are loaded by user
+type loaders struct {
+	users             *loader[string, *entities.User]
+	subscriberCounts  *loader[*entities.User, int]
+	friends           *loader[pageKey, *entities.UserPage]
+	subscribers       *loader[pageKey, *entities.UserPage]
+	blockers          *loader[pageKey, *entities.UserPage]
+	commonFriendsWith *loader[pageKey, *entities.UserPage]
+}
+
+func newLoaders(userController interfaces.UserControllerInterface) *loaders {
+	return &loaders{
+		users: newLoader(func(emails []string) (map[string]*entities.User, error) {
+			users, err := userController.GetUsersByEmails(emails)
+			if err != nil {
+				return nil, err
+			}
+
+			byEmail := make(map[string]*entities.User, len(users))
+			for _, user := range users {
+				byEmail[user.Email] = user
+			}
+			return byEmail, nil
+		}, batchWait, maxBatchSize),
+		subscriberCounts: newLoader(func(users []*entities.User) (map[*entities.User]int, error) {
+			counts, err := userController.CountSubscribersBatch(users)
+			if err != nil {
+				return nil, err
+			}
+
+			byUser := make(map[*entities.User]int, len(users))
+			for _, user := range users {
+				byUser[user] = counts[user.ID]
+			}
+			return byUser, nil
+		}, batchWait, maxBatchSize),
+		friends: newPageLoader(func(users []*entities.User, _ []string, cursor string, limit int) (map[int]*entities.UserPage, error) {
+			return userController.GetFriendListsBatch(users, cursor, limit)
+		}),
+		subscribers: newPageLoader(func(users []*entities.User, _ []string, cursor string, limit int) (map[int]*entities.UserPage, error) {
+			return userController.GetSubscribersBatch(users, cursor, limit)
+		}),
+		blockers: newPageLoader(func(users []*entities.User, _ []string, cursor string, limit int) (map[int]*entities.UserPage, error) {
+			return userController.GetBlockersBatch(users, cursor, limit)
+		}),
+		commonFriendsWith: newPageLoader(userController.GetCommonFriendsBatch),
+	}
+}
+
+// pageKey asks for a page of a list of users related to user. with holds the
+// other users of commonFriendsWith, space separated
+type pageKey struct {
+	user   *entities.User
+	with   string
+	cursor string
+	limit  int
+}
+
+// newPageLoader returns a loader of the pages of a list, fetched with list.
+// The keys of a batch mostly come from the same field of every user of a list,
+// so they are fetched with one call per distinct set of arguments
+func newPageLoader(list func(users []*entities.User, with []string, cursor string, limit int) (map[int]*entities.UserPage, error)) *loader[pageKey, *entities.UserPage] {
+	return newLoader(func(keys []pageKey) (map[pageKey]*entities.UserPage, error) {
+		groups := make(map[pageKey][]pageKey)
+		for _, key := range keys {
+			args := pageKey{with: key.with, cursor: key.cursor, limit: key.limit}
+			groups[args] = append(groups[args], key)
+		}
+
+		pages := make(map[pageKey]*entities.UserPage, len(keys))
+		for args, group := range groups {
+			users := make([]*entities.User, len(group))
+			for i, key := range group {
+				users[i] = key.user
+			}
+
+			byUser, err := list(users, strings.Fields(args.with), args.cursor, args.limit)
+			if err != nil {
+				return nil, err
+			}
+			for _, key := range group {
+				pages[key] = byUser[key.user.ID]
+			}
+		}
+		return pages, nil
+	}, batchWait, maxBatchSize)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
@@ -0,0 +1,253 @@
+package gql
+
+import (
+	"assignment/internal/domain/entities"
+	"assignment/mocks"
+	"assignment/pkg/errors"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSchema(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	andy := &entities.User{ID: 1, Email: "andy@example.com"}
+	john := &entities.User{ID: 2, Email: "john@example.com"}
+	lisa := &entities.User{ID: 3, Email: "lisa@example.com"}
+	kate := &entities.User{ID: 4, Email: "kate@example.com"}
+
+	tests := []struct {
+		name           string
+		query          string
+		variables      map[string]interface{}
+		setupMock      func(mockController *mocks.MockUserControllerInterface)
+		expectedData   string
+		expectedErrors string
+	}{
+		{
+			name:  "subscriber counts of every friend are batched",
+			query: `query($email: String!) { user(email: $email) { email friends { nodes { email subscriberCount } totalCount nextCursor } } }`,
+			variables: map[string]interface{}{
+				"email": "andy@example.com",
+			},
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetUsersByEmails([]string{"andy@example.com"}).Return([]*entities.User{andy}, nil)
+				mockController.EXPECT().GetFriendListsBatch([]*entities.User{andy}, "", 50).Return(map[int]*entities.UserPage{andy.ID: {Users: []*entities.User{john, kate, lisa}, Total: 3}}, nil)
+				mockController.EXPECT().CountSubscribersBatch(gomock.InAnyOrder([]*entities.User{john, kate, lisa})).Return(map[int]int{john.ID: 2, lisa.ID: 1}, nil)
+			},
+			expectedData: `{"user":{"email":"andy@example.com","friends":{"nodes":[
+				{"email":"john@example.com","subscriberCount":2},
+				{"email":"kate@example.com","subscriberCount":0},
+				{"email":"lisa@example.com","subscriberCount":1}
+			],"totalCount":3,"nextCursor":null}}}`,
+		},
+		{
+			name:  "users looked up together are batched",
+			query: `{ andy: user(email: "andy@example.com") { email } john: user(email: "john@example.com") { email } ghost: user(email: "ghost@example.com") { email } }`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetUsersByEmails(gomock.InAnyOrder([]string{"andy@example.com", "john@example.com", "ghost@example.com"})).Return([]*entities.User{andy, john}, nil)
+			},
+			expectedData: `{"andy":{"email":"andy@example.com"},"john":{"email":"john@example.com"},"ghost":null}`,
+		},
+		{
+			name:  "emails in another case find the user",
+			query: `{ andy: user(email: "Andy@Example.com") { email } again: user(email: "andy@example.com") { commonFriendsWith(emails: ["John@example.com"]) { totalCount } } }`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetUsersByEmails([]string{"andy@example.com"}).Return([]*entities.User{andy}, nil)
+				mockController.EXPECT().GetCommonFriendsBatch([]*entities.User{andy}, []string{"john@example.com"}, "", 50).Return(map[int]*entities.UserPage{andy.ID: {Users: []*entities.User{kate}, Total: 1}}, nil)
+			},
+			expectedData: `{"andy":{"email":"andy@example.com"},"again":{"commonFriendsWith":{"totalCount":1}}}`,
+		},
+		{
+			name:  "subscribers and blockers pages",
+			query: `{ user(email: "andy@example.com") { subscribers(first: 1) { nodes { email } nextCursor } blockedBy(first: 2, after: "a2F0ZQ") { nodes { email } totalCount } } }`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetUsersByEmails([]string{"andy@example.com"}).Return([]*entities.User{andy}, nil)
+				mockController.EXPECT().GetSubscribersBatch([]*entities.User{andy}, "", 1).Return(map[int]*entities.UserPage{andy.ID: {Users: []*entities.User{john}, Total: 2, NextCursor: "am9obg"}}, nil)
+				mockController.EXPECT().GetBlockersBatch([]*entities.User{andy}, "a2F0ZQ", 2).Return(map[int]*entities.UserPage{andy.ID: {Users: []*entities.User{lisa}, Total: 3}}, nil)
+			},
+			expectedData: `{"user":{"subscribers":{"nodes":[{"email":"john@example.com"}],"nextCursor":"am9obg"},"blockedBy":{"nodes":[{"email":"lisa@example.com"}],"totalCount":3}}}`,
+		},
+		{
+			name:  "common friends with other users",
+			query: `{ user(email: "andy@example.com") { commonFriendsWith(emails: ["john@example.com", "lisa@example.com"]) { nodes { email } } } }`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetUsersByEmails([]string{"andy@example.com"}).Return([]*entities.User{andy}, nil)
+				mockController.EXPECT().GetCommonFriendsBatch([]*entities.User{andy}, []string{"john@example.com", "lisa@example.com"}, "", 50).Return(map[int]*entities.UserPage{andy.ID: {Users: []*entities.User{kate}, Total: 1}}, nil)
+			},
+			expectedData: `{"user":{"commonFriendsWith":{"nodes":[{"email":"kate@example.com"}]}}}`,
+		},
+		{
+			name:  "lists nested under every friend are batched",
+			query: `{ user(email: "andy@example.com") { friends(first: 2) { nodes { email friends(first: 1) { totalCount } subscribers { totalCount } blockedBy { totalCount } commonFriendsWith(emails: ["andy@example.com"]) { nodes { email } } } } } }`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetUsersByEmails([]string{"andy@example.com"}).Return([]*entities.User{andy}, nil)
+				mockController.EXPECT().GetFriendListsBatch([]*entities.User{andy}, "", 2).Return(map[int]*entities.UserPage{andy.ID: {Users: []*entities.User{john, lisa}, Total: 2}}, nil)
+				mockController.EXPECT().GetFriendListsBatch(gomock.InAnyOrder([]*entities.User{john, lisa}), "", 1).Return(map[int]*entities.UserPage{
+					john.ID: {Users: []*entities.User{andy}, Total: 3, NextCursor: "YW5keQ"},
+					lisa.ID: {Users: []*entities.User{andy}, Total: 1},
+				}, nil)
+				mockController.EXPECT().GetSubscribersBatch(gomock.InAnyOrder([]*entities.User{john, lisa}), "", 50).Return(map[int]*entities.UserPage{
+					john.ID: {Users: []*entities.User{}},
+					lisa.ID: {Users: []*entities.User{kate}, Total: 1},
+				}, nil)
+				mockController.EXPECT().GetBlockersBatch(gomock.InAnyOrder([]*entities.User{john, lisa}), "", 50).Return(map[int]*entities.UserPage{
+					john.ID: {Users: []*entities.User{kate}, Total: 1},
+					lisa.ID: {Users: []*entities.User{}},
+				}, nil)
+				mockController.EXPECT().GetCommonFriendsBatch(gomock.InAnyOrder([]*entities.User{john, lisa}), []string{"andy@example.com"}, "", 50).Return(map[int]*entities.UserPage{
+					john.ID: {Users: []*entities.User{kate}, Total: 1},
+					lisa.ID: {Users: []*entities.User{}},
+				}, nil)
+			},
+			expectedData: `{"user":{"friends":{"nodes":[
+				{"email":"john@example.com","friends":{"totalCount":3},"subscribers":{"totalCount":0},"blockedBy":{"totalCount":1},"commonFriendsWith":{"nodes":[{"email":"kate@example.com"}]}},
+				{"email":"lisa@example.com","friends":{"totalCount":1},"subscribers":{"totalCount":1},"blockedBy":{"totalCount":0},"commonFriendsWith":{"nodes":[]}}
+			]}}}`,
+		},
+		{
+			name:  "invalid email",
+			query: `{ user(email: "andy") { email } }`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedData:   `{"user":null}`,
+			expectedErrors: `[{"message":"Validation failed: email: must be valid email address","path":["user"],"extensions":{"type":"VALIDATION_ERROR"}}]`,
+		},
+		{
+			name:  "page size out of range",
+			query: `{ user(email: "andy@example.com") { friends(first: 101) { totalCount } } }`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetUsersByEmails([]string{"andy@example.com"}).Return([]*entities.User{andy}, nil)
+			},
+			expectedData:   `{"user":null}`,
+			expectedErrors: `[{"message":"Validation failed: first: must not exceed 100","path":["user","friends"],"extensions":{"type":"VALIDATION_ERROR"}}]`,
+		},
+		{
+			name:  "nested lists asking for too many users",
+			query: `{ user(email: "andy@example.com") { friends(first: 100) { nodes { subscribers(first: 100) { nodes { blockedBy(first: 2) { totalCount } } } } } } }`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetUsersByEmails([]string{"andy@example.com"}).Return([]*entities.User{andy}, nil)
+				mockController.EXPECT().GetFriendListsBatch([]*entities.User{andy}, "", 100).Return(map[int]*entities.UserPage{andy.ID: {Users: []*entities.User{john}, Total: 1}}, nil)
+				mockController.EXPECT().GetSubscribersBatch([]*entities.User{john}, "", 100).Return(map[int]*entities.UserPage{john.ID: {Users: []*entities.User{lisa}, Total: 1}}, nil)
+			},
+			expectedData:   `{"user":null}`,
+			expectedErrors: `[{"message":"Validation failed: first: nested lists must not ask for more than 10000 users in all","path":["user","friends","nodes",0,"subscribers","nodes",0,"blockedBy"],"extensions":{"type":"VALIDATION_ERROR"}}]`,
+		},
+		{
+			name:  "common friends with nobody",
+			query: `{ user(email: "andy@example.com") { commonFriendsWith(emails: []) { totalCount } } }`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetUsersByEmails([]string{"andy@example.com"}).Return([]*entities.User{andy}, nil)
+			},
+			expectedData:   `{"user":null}`,
+			expectedErrors: `[{"message":"Validation failed: emails: between 1 and 19 emails required","path":["user","commonFriendsWith"],"extensions":{"type":"VALIDATION_ERROR"}}]`,
+		},
+		{
+			name:  "common friends with self",
+			query: `{ user(email: "andy@example.com") { commonFriendsWith(emails: ["Andy@example.com"]) { totalCount } } }`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetUsersByEmails([]string{"andy@example.com"}).Return([]*entities.User{andy}, nil)
+			},
+			expectedData:   `{"user":null}`,
+			expectedErrors: `[{"message":"` + errors.ErrCannotGetCommonFriendsWithSelf.Message + `","path":["user","commonFriendsWith"],"extensions":{"type":"BUSINESS_ERROR"}}]`,
+		},
+		{
+			name:  "controller error",
+			query: `{ user(email: "andy@example.com") { commonFriendsWith(emails: ["ghost@example.com"]) { totalCount } } }`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetUsersByEmails([]string{"andy@example.com"}).Return([]*entities.User{andy}, nil)
+				mockController.EXPECT().GetCommonFriendsBatch([]*entities.User{andy}, []string{"ghost@example.com"}, "", 50).Return(nil, errors.New(errors.ErrorTypeNotFound, "User not found: ghost@example.com"))
+			},
+			expectedData:   `{"user":null}`,
+			expectedErrors: `[{"message":"User not found: ghost@example.com","path":["user","commonFriendsWith"],"extensions":{"type":"NOT_FOUND"}}]`,
+		},
+		{
+			name:  "query nested too deep",
+			query: `{ user(email: "andy@example.com") { friends { nodes { friends { nodes { friends { nodes { friends { nodes { email } } } } } } } } } }`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as the query is rejected before it runs
+			},
+			expectedErrors: `[{"message":"Field \"nodes\" has depth 9 that exceeds max depth 8","locations":[{"line":1,"column":101}]}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := mocks.NewMockUserControllerInterface(ctrl)
+			tt.setupMock(mockController)
+
+			schema := NewSchema(mockController)
+			response := schema.Exec(context.Background(), tt.query, "", tt.variables)
+
+			if tt.expectedData == "" {
+				assert.Empty(t, response.Data)
+			} else {
+				assert.JSONEq(t, tt.expectedData, string(response.Data))
+			}
+
+			if tt.expectedErrors == "" {
+				assert.Empty(t, response.Errors)
+			} else {
+				errs, err := json.Marshal(response.Errors)
+				assert.NoError(t, err)
+				assert.JSONEq(t, tt.expectedErrors, string(errs))
+			}
+		})
+	}
+}
+
+func TestSchema_FullPageIsBatched(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	andy := &entities.User{ID: 1, Email: "andy@example.com"}
+	friends := make([]*entities.User, maxPageSize)
+	for i := range friends {
+		friends[i] = &entities.User{ID: i + 2, Email: fmt.Sprintf("friend%03d@example.com", i)}
+	}
+
+	// The loaders fetch what they have after batchWait, a slow run may split
+	// the page, but never into one fetch per friend
+	var mu sync.Mutex
+	var counted, listed []*entities.User
+	countFetches, listFetches := 0, 0
+
+	mockController := mocks.NewMockUserControllerInterface(ctrl)
+	mockController.EXPECT().GetUsersByEmails([]string{"andy@example.com"}).Return([]*entities.User{andy}, nil)
+	mockController.EXPECT().GetFriendListsBatch([]*entities.User{andy}, "", maxPageSize).Return(map[int]*entities.UserPage{andy.ID: {Users: friends, Total: maxPageSize}}, nil)
+	mockController.EXPECT().CountSubscribersBatch(gomock.Any()).DoAndReturn(func(users []*entities.User) (map[int]int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		counted = append(counted, users...)
+		countFetches++
+		return map[int]int{}, nil
+	}).AnyTimes()
+	mockController.EXPECT().GetFriendListsBatch(gomock.Any(), "", 1).DoAndReturn(func(users []*entities.User, cursor string, limit int) (map[int]*entities.UserPage, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		listed = append(listed, users...)
+		listFetches++
+		pages := make(map[int]*entities.UserPage, len(users))
+		for _, user := range users {
+			pages[user.ID] = &entities.UserPage{Users: []*entities.User{andy}, Total: 1}
+		}
+		return pages, nil
+	}).AnyTimes()
+
+	schema := NewSchema(mockController)
+	response := schema.Exec(context.Background(), `{ user(email: "andy@example.com") { friends(first: 100) { nodes { subscriberCount friends(first: 1) { totalCount } } } } }`, "", nil)
+
+	assert.Empty(t, response.Errors)
+	assert.ElementsMatch(t, friends, counted)
+	assert.Less(t, countFetches, 10, "expected the subscriber counts to be fetched in a few batches")
+	assert.ElementsMatch(t, friends, listed)
+	assert.Less(t, listFetches, 10, "expected the friend lists to be fetched in a few batches")
+}
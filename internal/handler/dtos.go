@@ -1,39 +1,115 @@
 package handler
 
-import "assignment/pkg/validator"
+import (
+	"assignment/pkg/validator"
+	"time"
+)
 
 type CreateFriendshipRequest struct {
 	Friends []string `json:"friends"`
 }
 
 func ValidateCreateFriendshipRequest(v *validator.Validator, r *CreateFriendshipRequest) {
-	v.Check(len(r.Friends) == 2, "emails count", "exactly 2 emails required")
+	validator.ValidateEmailPair(v, r.Friends)
+}
 
-	for _, email := range r.Friends {
-		v.Check(len(email) > 0, "email", "email cannot be empty")
-		validator.ValidateEmail(v, email)
-	}
+type DeleteFriendshipRequest struct {
+	Friends []string `json:"friends"`
+}
+
+func ValidateDeleteFriendshipRequest(v *validator.Validator, r *DeleteFriendshipRequest) {
+	validator.ValidateEmailPair(v, r.Friends)
+}
+
+type FriendRequestRequest struct {
+	Requestor string `json:"requestor"`
+	Target    string `json:"target"`
+}
+
+func ValidateFriendRequestRequest(v *validator.Validator, r *FriendRequestRequest) {
+	validator.ValidateUserPair(v, r.Requestor, r.Target, "requestor and target cannot be the same")
+}
+
+type GetFriendRequestsRequest struct {
+	Email string `json:"email"`
+}
+
+func ValidateGetFriendRequestsRequest(v *validator.Validator, r *GetFriendRequestsRequest) {
+	validator.ValidateEmail(v, r.Email)
+}
+
+// PageParams are the cursor and page size taken by every paginated list. The
+// cursor is the next_cursor of the previous page, or empty for the first page
+type PageParams struct {
+	Cursor string `json:"cursor" form:"cursor"`
+	Limit  int    `json:"limit" form:"limit"`
+}
+
+func ValidatePageParams(v *validator.Validator, p *PageParams) {
+	validator.ValidatePageLimit(v, p.Limit)
+}
+
+// PageLimit is the requested page size, or the default when none was given
+func (p *PageParams) PageLimit() int {
+	return validator.PageLimit(p.Limit)
 }
 
 type GetFriendListRequest struct {
 	Email string `json:"email"`
+	PageParams
 }
 
 func ValidateGetFriendListRequest(v *validator.Validator, r *GetFriendListRequest) {
 	validator.ValidateEmail(v, r.Email)
+	ValidatePageParams(v, &r.PageParams)
 }
 
 type GetCommonFriendsRequest struct {
 	Friends []string `json:"friends"`
+	PageParams
 }
 
 func ValidateGetCommonFriendsRequest(v *validator.Validator, r *GetCommonFriendsRequest) {
-	v.Check(len(r.Friends) == 2, "emails count", "exactly 2 emails required")
+	validator.ValidateCommonFriendsEmails(v, r.Friends)
+	ValidatePageParams(v, &r.PageParams)
+}
+
+// GetUserCommonFriendsRequest is the query of GET /users/:email/friends/common,
+// which compares the path user with every ?with= email
+type GetUserCommonFriendsRequest struct {
+	With []string `form:"with"`
+	PageParams
+}
+
+func ValidateGetUserCommonFriendsRequest(v *validator.Validator, email string, r *GetUserCommonFriendsRequest) {
+	validator.ValidateEmail(v, email)
+	v.Check(len(r.With) >= validator.MinCommonFriendsUsers-1 && len(r.With) <= validator.MaxCommonFriendsUsers-1, "with", "between 1 and 19 emails required")
 
-	for _, email := range r.Friends {
-		v.Check(len(email) > 0, "email", "email cannot be empty")
-		validator.ValidateEmail(v, email)
+	for _, other := range r.With {
+		validator.ValidateEmail(v, other)
 	}
+	ValidatePageParams(v, &r.PageParams)
+}
+
+type GetFriendshipPathRequest struct {
+	Requestor string `json:"requestor"`
+	Target    string `json:"target"`
+	MaxDepth  int    `json:"max_depth"`
+}
+
+func ValidateGetFriendshipPathRequest(v *validator.Validator, r *GetFriendshipPathRequest) {
+	validator.ValidateUserPair(v, r.Requestor, r.Target, "requestor and target cannot be the same")
+	validator.ValidatePathMaxDepth(v, r.MaxDepth)
+}
+
+type GetFriendSuggestionsRequest struct {
+	Email string `json:"email"`
+	Limit int    `json:"limit"`
+}
+
+func ValidateGetFriendSuggestionsRequest(v *validator.Validator, r *GetFriendSuggestionsRequest) {
+	validator.ValidateEmail(v, r.Email)
+	validator.ValidateSuggestionLimit(v, r.Limit)
 }
 
 type SubscriptionRequest struct {
@@ -42,11 +118,29 @@ type SubscriptionRequest struct {
 }
 
 func ValidateSubscriptionRequest(v *validator.Validator, r *SubscriptionRequest) {
-	v.Check(len(r.Requestor) > 0, "requestor", "requestor email cannot be empty")
-	v.Check(len(r.Target) > 0, "target", "target email cannot be empty")
-	validator.ValidateEmail(v, r.Requestor)
-	validator.ValidateEmail(v, r.Target)
-	v.Check(r.Requestor != r.Target, "emails", "requestor and target cannot be the same")
+	validator.ValidateUserPair(v, r.Requestor, r.Target, "requestor and target cannot be the same")
+}
+
+type GetSubscriptionFilterRequest struct {
+	Requestor string `form:"requestor"`
+	Target    string `form:"target"`
+}
+
+func ValidateGetSubscriptionFilterRequest(v *validator.Validator, r *GetSubscriptionFilterRequest) {
+	validator.ValidateUserPair(v, r.Requestor, r.Target, "requestor and target cannot be the same")
+}
+
+type UpdateSubscriptionFilterRequest struct {
+	Requestor string   `json:"requestor"`
+	Target    string   `json:"target"`
+	Include   []string `json:"include"`
+	Exclude   []string `json:"exclude"`
+}
+
+func ValidateUpdateSubscriptionFilterRequest(v *validator.Validator, r *UpdateSubscriptionFilterRequest) {
+	validator.ValidateUserPair(v, r.Requestor, r.Target, "requestor and target cannot be the same")
+	validator.ValidateKeywords(v, "include", r.Include)
+	validator.ValidateKeywords(v, "exclude", r.Exclude)
 }
 
 type CreateBlockRequest struct {
@@ -55,37 +149,437 @@ type CreateBlockRequest struct {
 }
 
 func ValidateCreateBlockRequest(v *validator.Validator, r *CreateBlockRequest) {
-	v.Check(len(r.Requestor) > 0, "requestor", "requestor email cannot be empty")
-	v.Check(len(r.Target) > 0, "target", "target email cannot be empty")
-	validator.ValidateEmail(v, r.Requestor)
-	validator.ValidateEmail(v, r.Target)
-	v.Check(r.Requestor != r.Target, "emails", "cannot block yourself")
+	validator.ValidateUserPair(v, r.Requestor, r.Target, "cannot block yourself")
+}
+
+type DeleteBlockRequest struct {
+	Requestor string `json:"requestor" binding:"required,email"`
+	Target    string `json:"target" binding:"required,email"`
+	Restore   bool   `json:"restore"`
+}
+
+func ValidateDeleteBlockRequest(v *validator.Validator, r *DeleteBlockRequest) {
+	validator.ValidateUserPair(v, r.Requestor, r.Target, "cannot unblock yourself")
+}
+
+type CreateMuteRequest struct {
+	Requestor string     `json:"requestor"`
+	Target    string     `json:"target"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+func ValidateCreateMuteRequest(v *validator.Validator, r *CreateMuteRequest) {
+	validator.ValidateUserPair(v, r.Requestor, r.Target, "cannot mute yourself")
+}
+
+type DeleteMuteRequest struct {
+	Requestor string `json:"requestor"`
+	Target    string `json:"target"`
+}
+
+func ValidateDeleteMuteRequest(v *validator.Validator, r *DeleteMuteRequest) {
+	validator.ValidateUserPair(v, r.Requestor, r.Target, "cannot unmute yourself")
+}
+
+type GetMutesRequest struct {
+	Email string `json:"email"`
+}
+
+func ValidateGetMutesRequest(v *validator.Validator, r *GetMutesRequest) {
+	validator.ValidateEmail(v, r.Email)
+}
+
+type GetRelationshipRequest struct {
+	Requestor string `json:"requestor"`
+	Target    string `json:"target"`
+}
+
+func ValidateGetRelationshipRequest(v *validator.Validator, r *GetRelationshipRequest) {
+	validator.ValidateUserPair(v, r.Requestor, r.Target, "requestor and target cannot be the same")
 }
 
 type GetRecipientsRequest struct {
+	Sender  string `json:"sender"`
+	Text    string `json:"text"`
+	Explain bool   `json:"explain"`
+	PageParams
+}
+
+func ValidateGetRecipientsRequest(v *validator.Validator, r *GetRecipientsRequest) {
+	validator.ValidateUpdateText(v, r.Sender, r.Text)
+	ValidatePageParams(v, &r.PageParams)
+}
+
+type PublishUpdateRequest struct {
 	Sender string `json:"sender"`
 	Text   string `json:"text"`
 }
 
-func ValidateGetRecipientsRequest(v *validator.Validator, r *GetRecipientsRequest) {
-	v.Check(len(r.Sender) > 0, "sender", "sender email cannot be empty")
-	validator.ValidateEmail(v, r.Sender)
-	v.Check(len(r.Text) > 0, "text", "text cannot be empty")
+func ValidatePublishUpdateRequest(v *validator.Validator, r *PublishUpdateRequest) {
+	validator.ValidateUpdateText(v, r.Sender, r.Text)
+}
+
+const (
+	DefaultTimelineLimit = 20
+	MaxTimelineLimit     = 100
+)
+
+type GetTimelineRequest struct {
+	Email  string `json:"email"`
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+}
+
+func ValidateGetTimelineRequest(v *validator.Validator, r *GetTimelineRequest) {
+	validator.ValidateEmail(v, r.Email)
+	v.Check(r.Limit >= 0, "limit", "must not be negative")
+	v.Check(r.Limit <= MaxTimelineLimit, "limit", "must not exceed 100")
+	v.Check(r.Offset >= 0, "offset", "must not be negative")
+}
+
+// StreamBacklogPageSize is how many missed updates are read from the inbox at a
+// time when a stream resumes, until all of them are replayed
+const StreamBacklogPageSize = 100
+
+// AuthenticatedEmailHeader names the header in which the authenticating proxy
+// in front of the service passes the email of the caller
+const AuthenticatedEmailHeader = "X-Authenticated-Email"
+
+type StreamUpdatesRequest struct {
+	Email string `header:"X-Authenticated-Email"`
+}
+
+func ValidateStreamUpdatesRequest(v *validator.Validator, r *StreamUpdatesRequest) {
+	validator.ValidateEmail(v, r.Email)
+}
+
+type GatewayRequest struct {
+	Email string `header:"X-Authenticated-Email"`
+}
+
+func ValidateGatewayRequest(v *validator.Validator, r *GatewayRequest) {
+	validator.ValidateEmail(v, r.Email)
+}
+
+// Messages a WebSocket client sends to the gateway
+const (
+	GatewayMessageSubscribe   = "subscribe"
+	GatewayMessageUnsubscribe = "unsubscribe"
+	GatewayMessageAck         = "ack"
+)
+
+// Messages the gateway sends to a WebSocket client
+const (
+	GatewayMessageSubscribed = "subscribed"
+	GatewayMessageEvent      = "event"
+	GatewayMessageError      = "error"
+)
+
+// GatewayTopics are the event topics a WebSocket client can subscribe to
+var GatewayTopics = []string{"friend", "subscription", "block", "update"}
+
+type GatewayClientMessage struct {
+	Type   string   `json:"type"`
+	Topics []string `json:"topics,omitempty"`
+	ID     int      `json:"id,omitempty"`
+}
+
+type RegisterWebhookRequest struct {
+	Email string `json:"email"`
+	URL   string `json:"url"`
+}
+
+func ValidateRegisterWebhookRequest(v *validator.Validator, r *RegisterWebhookRequest) {
+	validator.ValidateEmail(v, r.Email)
+	v.Check(len(r.URL) > 0, "url", "url cannot be empty")
+	v.Check(validator.IsHTTPURL(r.URL), "url", "must be an absolute http or https URL")
+	v.Check(validator.IsPublicURLHost(r.URL), "url", "must not point to a loopback, private or reserved address")
+}
+
+type RemoveWebhookRequest struct {
+	Email string `json:"email"`
+}
+
+func ValidateRemoveWebhookRequest(v *validator.Validator, r *RemoveWebhookRequest) {
+	validator.ValidateEmail(v, r.Email)
+}
+
+const (
+	DefaultWebhookDeliveryLimit = 20
+	MaxWebhookDeliveryLimit     = 100
+)
+
+type GetWebhookDeliveriesRequest struct {
+	Email string `json:"email"`
+	Limit int    `json:"limit"`
+}
+
+func ValidateGetWebhookDeliveriesRequest(v *validator.Validator, r *GetWebhookDeliveriesRequest) {
+	validator.ValidateEmail(v, r.Email)
+	v.Check(r.Limit >= 0, "limit", "must not be negative")
+	v.Check(r.Limit <= MaxWebhookDeliveryLimit, "limit", "must not exceed 100")
+}
+
+type CreateUserRequest struct {
+	Email string `json:"email"`
+}
+
+func ValidateCreateUserRequest(v *validator.Validator, r *CreateUserRequest) {
+	validator.ValidateEmail(v, r.Email)
+}
+
+type SetUsernameRequest struct {
+	Email    string `json:"email"`
+	Username string `json:"username"`
+}
+
+func ValidateSetUsernameRequest(v *validator.Validator, r *SetUsernameRequest) {
+	validator.ValidateEmail(v, r.Email)
+	validator.ValidateUsername(v, r.Username)
+}
+
+type GetSettingsRequest struct {
+	Email string `form:"email"`
+}
+
+func ValidateGetSettingsRequest(v *validator.Validator, r *GetSettingsRequest) {
+	validator.ValidateEmail(v, r.Email)
+}
+
+type UpdateSettingsRequest struct {
+	Email         string `json:"email"`
+	MentionPolicy string `json:"mention_policy"`
+}
+
+func ValidateUpdateSettingsRequest(v *validator.Validator, r *UpdateSettingsRequest) {
+	validator.ValidateEmail(v, r.Email)
+	validator.ValidateMentionPolicy(v, r.MentionPolicy)
+}
+
+// GraphQLRequest is a query as GraphQL clients post it, with camelCase keys
+type GraphQLRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+func ValidateGraphQLRequest(v *validator.Validator, r *GraphQLRequest) {
+	v.Check(r.Query != "", "query", "must be provided")
+}
+
+// PageInfo describes one page of a paginated list. Count is the number of items
+// on the page, Total the number in the whole list, and NextCursor is left out
+// on the last page
+type PageInfo struct {
+	Count      int    `json:"count"`
+	Total      int    `json:"total"`
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 type FriendListResponse struct {
 	Success bool     `json:"success"`
 	Friends []string `json:"friends"`
-	Count   int      `json:"count"`
+	PageInfo
 }
 
 type CommonFriendsResponse struct {
 	Success bool     `json:"success"`
 	Friends []string `json:"friends"`
-	Count   int      `json:"count"`
+	PageInfo
+}
+
+type SubscriberListResponse struct {
+	Success     bool     `json:"success"`
+	Subscribers []string `json:"subscribers"`
+	PageInfo
+}
+
+type SubscriptionListResponse struct {
+	Success       bool     `json:"success"`
+	Subscriptions []string `json:"subscriptions"`
+	PageInfo
+}
+
+type BlockListResponse struct {
+	Success bool     `json:"success"`
+	Blocked []string `json:"blocked"`
+	PageInfo
 }
 
 type RecipientsResponse struct {
 	Success    bool     `json:"success"`
 	Recipients []string `json:"recipients"`
+	PageInfo
+}
+
+type RecipientDetail struct {
+	Email   string   `json:"email"`
+	Reasons []string `json:"reasons"`
+}
+
+type DroppedMentionItem struct {
+	Email  string `json:"email,omitempty"`
+	Handle string `json:"handle,omitempty"`
+	Reason string `json:"reason"`
+}
+
+type RecipientsExplanationResponse struct {
+	Success         bool                 `json:"success"`
+	Recipients      []RecipientDetail    `json:"recipients"`
+	DroppedMentions []DroppedMentionItem `json:"dropped_mentions"`
+	PageInfo
+}
+
+type UpdateItem struct {
+	ID        int       `json:"id"`
+	Sender    string    `json:"sender"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type PublishUpdateResponse struct {
+	Success    bool       `json:"success"`
+	Update     UpdateItem `json:"update"`
+	Recipients []string   `json:"recipients"`
+}
+
+type TimelineResponse struct {
+	Success bool         `json:"success"`
+	Updates []UpdateItem `json:"updates"`
+	Count   int          `json:"count"`
+	HasMore bool         `json:"has_more"`
+}
+
+type GatewayServerMessage struct {
+	Type    string   `json:"type"`
+	ID      int      `json:"id,omitempty"`
+	Event   string   `json:"event,omitempty"`
+	Data    any      `json:"data,omitempty"`
+	Topics  []string `json:"topics,omitempty"`
+	Message string   `json:"message,omitempty"`
+}
+
+type UserEventItem struct {
+	Actor     string    `json:"actor"`
+	Target    string    `json:"target"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SubscriptionFilterResponse struct {
+	Success   bool     `json:"success"`
+	Requestor string   `json:"requestor"`
+	Target    string   `json:"target"`
+	Include   []string `json:"include"`
+	Exclude   []string `json:"exclude"`
+}
+
+type MuteItem struct {
+	Email     string     `json:"email"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type MuteResponse struct {
+	Success   bool       `json:"success"`
+	Requestor string     `json:"requestor"`
+	Target    string     `json:"target"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type MuteListResponse struct {
+	Success bool       `json:"success"`
+	Mutes   []MuteItem `json:"mutes"`
+	Count   int        `json:"count"`
+}
+
+type SettingsResponse struct {
+	Success       bool   `json:"success"`
+	Email         string `json:"email"`
+	MentionPolicy string `json:"mention_policy"`
+}
+
+type WebhookResponse struct {
+	Success bool   `json:"success"`
+	Email   string `json:"email"`
+	URL     string `json:"url"`
+	Secret  string `json:"secret"`
+}
+
+type WebhookDeliveryItem struct {
+	ID         int       `json:"id"`
+	UpdateID   int       `json:"update_id"`
+	Attempt    int       `json:"attempt"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Success    bool      `json:"success"`
+	Error      string    `json:"error,omitempty"`
+	DurationMS int64     `json:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type WebhookDeliveriesResponse struct {
+	Success    bool                  `json:"success"`
+	Deliveries []WebhookDeliveryItem `json:"deliveries"`
+	Count      int                   `json:"count"`
+}
+
+type UserResponse struct {
+	Success bool   `json:"success"`
+	Email   string `json:"email"`
+}
+
+type UserListResponse struct {
+	Success bool     `json:"success"`
+	Users   []string `json:"users"`
+	Count   int      `json:"count"`
+}
+
+type FriendRequestItem struct {
+	Requestor string    `json:"requestor"`
+	Target    string    `json:"target"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type FriendRequestResponse struct {
+	Success bool              `json:"success"`
+	Request FriendRequestItem `json:"request"`
+}
+
+type FriendRequestListResponse struct {
+	Success  bool                `json:"success"`
+	Requests []FriendRequestItem `json:"requests"`
+	Count    int                 `json:"count"`
+}
+
+type RelationshipStatus struct {
+	Friends                   bool `json:"friends"`
+	RequestorSubscribesTarget bool `json:"requestor_subscribes_target"`
+	TargetSubscribesRequestor bool `json:"target_subscribes_requestor"`
+	RequestorBlocksTarget     bool `json:"requestor_blocks_target"`
+	TargetBlocksRequestor     bool `json:"target_blocks_requestor"`
+}
+
+type RelationshipResponse struct {
+	Success      bool               `json:"success"`
+	Requestor    string             `json:"requestor"`
+	Target       string             `json:"target"`
+	Relationship RelationshipStatus `json:"relationship"`
+}
+
+type FriendshipPathResponse struct {
+	Success bool     `json:"success"`
+	Path    []string `json:"path"`
+	Hops    int      `json:"hops"`
+}
+
+type FriendSuggestionItem struct {
+	Email         string `json:"email"`
+	MutualFriends int    `json:"mutual_friends"`
+}
+
+type FriendSuggestionsResponse struct {
+	Success     bool                   `json:"success"`
+	Suggestions []FriendSuggestionItem `json:"suggestions"`
+	Count       int                    `json:"count"`
 }
\ No newline at end of file
@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"assignment/internal/domain/entities"
+	"assignment/internal/domain/interfaces"
+	"assignment/pkg/errors"
+	"assignment/pkg/validator"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// DefaultGatewayMaxInFlight is how many events a client may leave unacknowledged
+	// before the gateway stops sending it more
+	DefaultGatewayMaxInFlight = 32
+
+	gatewayWriteWait      = 10 * time.Second
+	gatewayPongWait       = 60 * time.Second
+	gatewayMaxMessageSize = 4096
+)
+
+// GatewayHandler serves the WebSocket endpoint that pushes relationship and update
+// events to a connected user
+type GatewayHandler struct {
+	userController   interfaces.UserControllerInterface
+	updateController interfaces.UpdateControllerInterface
+	upgrader         websocket.Upgrader
+	maxInFlight      int
+	pongWait         time.Duration
+}
+
+func NewGatewayHandler(userController interfaces.UserControllerInterface, updateController interfaces.UpdateControllerInterface) *GatewayHandler {
+	return &GatewayHandler{
+		userController:   userController,
+		updateController: updateController,
+		maxInFlight:      DefaultGatewayMaxInFlight,
+		pongWait:         gatewayPongWait,
+	}
+}
+
+// Connect upgrades the request to a WebSocket for the caller, the user named by
+// the header of the authenticating proxy; a request without it is refused before
+// the upgrade. Nothing is pushed until the client subscribes to topics; every
+// event carries a sequence id the client acknowledges, and at most maxInFlight
+// events are sent ahead of the last ack. A client that stays behind long enough
+// to overflow its buffer in the hub is disconnected
+func (h *GatewayHandler) Connect(c *gin.Context) {
+	var req GatewayRequest
+	if err := c.ShouldBindHeader(&req); err != nil {
+		errors.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+	if req.Email == "" {
+		errors.HandleError(c, errors.ErrNotAuthenticated)
+		return
+	}
+
+	v := validator.New()
+	if ValidateGatewayRequest(v, &req); !v.Valid() {
+		errors.HandleValidationErrors(c, v.Errors)
+		return
+	}
+
+	events, err := h.userController.SubscribeEvents(req.Email)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	defer events.Close()
+
+	updates, err := h.updateController.SubscribeUpdates(req.Email, 0, 0)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	defer updates.Close()
+
+	// The upgrader replies with an HTTP error itself when the handshake fails
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	session := &gatewaySession{
+		conn:        conn,
+		events:      events.Events,
+		updates:     updates.Updates,
+		topics:      make(map[string]bool),
+		maxInFlight: h.maxInFlight,
+	}
+	session.run(h.pongWait)
+}
+
+type gatewaySession struct {
+	conn        *websocket.Conn
+	events      <-chan *entities.UserEvent
+	updates     <-chan *entities.Update
+	topics      map[string]bool
+	maxInFlight int
+	sent        int
+	acked       int
+}
+
+func (s *gatewaySession) run(pongWait time.Duration) {
+	done := make(chan struct{})
+	defer close(done)
+
+	messages := s.readMessages(pongWait, done)
+
+	ping := time.NewTicker(pongWait * 9 / 10)
+	defer ping.Stop()
+
+	for {
+		// Backpressure: stop taking events from the hub while the window is full
+		events, updates := s.events, s.updates
+		if s.sent-s.acked >= s.maxInFlight {
+			events, updates = nil, nil
+		}
+
+		var err error
+		select {
+		case data, ok := <-messages:
+			if !ok {
+				return
+			}
+			err = s.handleMessage(data)
+		case event, ok := <-events:
+			if !ok {
+				s.closeStream()
+				return
+			}
+			if s.topics[event.Type.Topic()] {
+				err = s.sendEvent(string(event.Type), UserEventItem{
+					Actor:     event.Actor.Email,
+					Target:    event.Target.Email,
+					CreatedAt: event.CreatedAt,
+				})
+			}
+		case update, ok := <-updates:
+			if !ok {
+				s.closeStream()
+				return
+			}
+			if s.topics[entities.UserEventUpdateReceived.Topic()] {
+				err = s.sendEvent(string(entities.UserEventUpdateReceived), toUpdateItem(update))
+			}
+		case <-ping.C:
+			err = s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(gatewayWriteWait))
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// readMessages reads client messages until the connection fails or stops
+// answering pings, then closes the returned channel
+func (s *gatewaySession) readMessages(pongWait time.Duration, done <-chan struct{}) <-chan []byte {
+	messages := make(chan []byte)
+
+	s.conn.SetReadLimit(gatewayMaxMessageSize)
+	s.conn.SetReadDeadline(time.Now().Add(pongWait))
+	s.conn.SetPongHandler(func(string) error {
+		return s.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	go func() {
+		defer close(messages)
+		for {
+			_, data, err := s.conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			select {
+			case messages <- data:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return messages
+}
+
+func (s *gatewaySession) handleMessage(data []byte) error {
+	var msg GatewayClientMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return s.sendError("Invalid message format")
+	}
+
+	switch msg.Type {
+	case GatewayMessageSubscribe, GatewayMessageUnsubscribe:
+		for _, topic := range msg.Topics {
+			if !slices.Contains(GatewayTopics, topic) {
+				return s.sendError(fmt.Sprintf("Unknown topic: %s", topic))
+			}
+		}
+		for _, topic := range msg.Topics {
+			s.topics[topic] = msg.Type == GatewayMessageSubscribe
+		}
+		return s.sendSubscribed()
+	case GatewayMessageAck:
+		if msg.ID > s.sent {
+			return s.sendError(fmt.Sprintf("Cannot acknowledge event %d, last event sent is %d", msg.ID, s.sent))
+		}
+		// Acks are cumulative, an older ack changes nothing
+		s.acked = max(s.acked, msg.ID)
+		return nil
+	default:
+		return s.sendError(fmt.Sprintf("Unknown message type: %s", msg.Type))
+	}
+}
+
+func (s *gatewaySession) sendSubscribed() error {
+	var topics []string
+	for _, topic := range GatewayTopics {
+		if s.topics[topic] {
+			topics = append(topics, topic)
+		}
+	}
+
+	return s.write(GatewayServerMessage{Type: GatewayMessageSubscribed, Topics: topics})
+}
+
+func (s *gatewaySession) sendEvent(event string, data any) error {
+	s.sent++
+	return s.write(GatewayServerMessage{Type: GatewayMessageEvent, ID: s.sent, Event: event, Data: data})
+}
+
+func (s *gatewaySession) sendError(message string) error {
+	return s.write(GatewayServerMessage{Type: GatewayMessageError, Message: message})
+}
+
+func (s *gatewaySession) write(msg GatewayServerMessage) error {
+	s.conn.SetWriteDeadline(time.Now().Add(gatewayWriteWait))
+	return s.conn.WriteJSON(msg)
+}
+
+// closeStream tells the client the hub ended its subscription, either because it
+// fell too far behind or because the server is shutting down, so it should reconnect
+func (s *gatewaySession) closeStream() {
+	message := websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "Event stream closed, reconnect")
+	s.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(gatewayWriteWait))
+}
@@ -0,0 +1,259 @@
+package handler
+
+import (
+	"assignment/internal/domain/entities"
+	"assignment/mocks"
+	"assignment/pkg/errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// gatewayTestServer runs the gateway for kate@example.com with streams fed by the returned channels
+func gatewayTestServer(t *testing.T, ctrl *gomock.Controller, maxInFlight int) (*httptest.Server, chan *entities.UserEvent, chan *entities.Update) {
+	events := make(chan *entities.UserEvent)
+	updates := make(chan *entities.Update)
+
+	mockUser := mocks.NewMockUserControllerInterface(ctrl)
+	mockUpdate := mocks.NewMockUpdateControllerInterface(ctrl)
+	mockUser.EXPECT().SubscribeEvents("kate@example.com").Return(&entities.UserEventStream{Events: events, Close: func() {}}, nil)
+	mockUpdate.EXPECT().SubscribeUpdates("kate@example.com", 0, 0).Return(&entities.UpdateStream{Updates: updates, Close: func() {}}, nil)
+
+	handler := NewGatewayHandler(mockUser, mockUpdate)
+	handler.maxInFlight = maxInFlight
+
+	router := gin.New()
+	router.GET("/ws", handler.Connect)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	return server, events, updates
+}
+
+func dialGateway(t *testing.T, server *httptest.Server) *websocket.Conn {
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+	conn, _, err := websocket.DefaultDialer.Dial(url, http.Header{AuthenticatedEmailHeader: {"kate@example.com"}})
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	return conn
+}
+
+func readGatewayMessage(t *testing.T, conn *websocket.Conn) map[string]any {
+	var msg map[string]any
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatalf("failed to read message: %v", err)
+	}
+	return msg
+}
+
+func TestGateway_SubscribeAndReceiveEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	server, events, updates := gatewayTestServer(t, ctrl, DefaultGatewayMaxInFlight)
+	conn := dialGateway(t, server)
+
+	andy := &entities.User{ID: 1, Email: "andy@example.com"}
+	kate := &entities.User{ID: 2, Email: "kate@example.com"}
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	assert.NoError(t, conn.WriteJSON(GatewayClientMessage{Type: GatewayMessageSubscribe, Topics: []string{"friend", "update"}}))
+	assert.Equal(t, map[string]any{"type": "subscribed", "topics": []any{"friend", "update"}}, readGatewayMessage(t, conn))
+
+	// Not subscribed to blocks, so this one is dropped
+	events <- &entities.UserEvent{Type: entities.UserEventBlockAdded, Actor: kate, Target: andy, CreatedAt: createdAt}
+	events <- &entities.UserEvent{Type: entities.UserEventFriendAdded, Actor: andy, Target: kate, CreatedAt: createdAt}
+	assert.Equal(t, map[string]any{
+		"type":  "event",
+		"id":    float64(1),
+		"event": "friend.added",
+		"data":  map[string]any{"actor": "andy@example.com", "target": "kate@example.com", "created_at": "2024-01-02T03:04:05Z"},
+	}, readGatewayMessage(t, conn))
+
+	updates <- &entities.Update{ID: 7, Sender: andy, Text: "Hello kate", CreatedAt: createdAt}
+	assert.Equal(t, map[string]any{
+		"type":  "event",
+		"id":    float64(2),
+		"event": "update.received",
+		"data":  map[string]any{"id": float64(7), "sender": "andy@example.com", "text": "Hello kate", "created_at": "2024-01-02T03:04:05Z"},
+	}, readGatewayMessage(t, conn))
+
+	assert.NoError(t, conn.WriteJSON(GatewayClientMessage{Type: GatewayMessageUnsubscribe, Topics: []string{"update"}}))
+	assert.Equal(t, map[string]any{"type": "subscribed", "topics": []any{"friend"}}, readGatewayMessage(t, conn))
+}
+
+func TestGateway_Backpressure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	server, events, _ := gatewayTestServer(t, ctrl, 2)
+	conn := dialGateway(t, server)
+
+	andy := &entities.User{ID: 1, Email: "andy@example.com"}
+	kate := &entities.User{ID: 2, Email: "kate@example.com"}
+	event := &entities.UserEvent{Type: entities.UserEventSubscriptionAdded, Actor: andy, Target: kate}
+
+	assert.NoError(t, conn.WriteJSON(GatewayClientMessage{Type: GatewayMessageSubscribe, Topics: []string{"subscription"}}))
+	readGatewayMessage(t, conn)
+
+	events <- event
+	events <- event
+	assert.Equal(t, float64(1), readGatewayMessage(t, conn)["id"])
+	assert.Equal(t, float64(2), readGatewayMessage(t, conn)["id"])
+
+	// Two events are unacknowledged, so the gateway stops taking events
+	select {
+	case events <- event:
+		t.Fatal("expected the gateway to stop taking events while the window is full")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	assert.NoError(t, conn.WriteJSON(GatewayClientMessage{Type: GatewayMessageAck, ID: 2}))
+
+	select {
+	case events <- event:
+	case <-time.After(time.Second):
+		t.Fatal("expected the gateway to take events again after the ack")
+	}
+	assert.Equal(t, float64(3), readGatewayMessage(t, conn)["id"])
+}
+
+func TestGateway_InvalidMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	server, _, _ := gatewayTestServer(t, ctrl, DefaultGatewayMaxInFlight)
+	conn := dialGateway(t, server)
+
+	tests := []struct {
+		message  string
+		expected string
+	}{
+		{message: `not json`, expected: "Invalid message format"},
+		{message: `{"type":"subscribe","topics":["friend","gossip"]}`, expected: "Unknown topic: gossip"},
+		{message: `{"type":"ack","id":5}`, expected: "Cannot acknowledge event 5, last event sent is 0"},
+		{message: `{"type":"hello"}`, expected: "Unknown message type: hello"},
+	}
+
+	for _, tt := range tests {
+		assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(tt.message)))
+		assert.Equal(t, map[string]any{"type": "error", "message": tt.expected}, readGatewayMessage(t, conn))
+	}
+
+	// A rejected subscribe does not subscribe to any of its topics
+	assert.NoError(t, conn.WriteJSON(GatewayClientMessage{Type: GatewayMessageSubscribe}))
+	assert.Equal(t, map[string]any{"type": "subscribed"}, readGatewayMessage(t, conn))
+}
+
+func TestGateway_ClosedStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	server, events, _ := gatewayTestServer(t, ctrl, DefaultGatewayMaxInFlight)
+	conn := dialGateway(t, server)
+
+	// The hub closes the stream on shutdown or when the client falls behind
+	close(events)
+
+	_, _, err := conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseTryAgainLater), "unexpected error: %v", err)
+}
+
+func TestGateway_HandshakeErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		query          string
+		email          string
+		setupMock      func(mockUser *mocks.MockUserControllerInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "missing authenticated email",
+			query: "",
+			setupMock: func(mockUser *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as the request is refused before controller calls
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"success":false,"error":{"type":"UNAUTHORIZED","message":"Request is not authenticated"}}`,
+		},
+		{
+			name:  "email parameter is not trusted",
+			query: "?email=kate@example.com",
+			setupMock: func(mockUser *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as the request is refused before controller calls
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"success":false,"error":{"type":"UNAUTHORIZED","message":"Request is not authenticated"}}`,
+		},
+		{
+			name:  "invalid authenticated email",
+			email: "kate",
+			setupMock: func(mockUser *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"email: must be valid email address"}}`,
+		},
+		{
+			name:  "user not found",
+			email: "nonexistent@example.com",
+			setupMock: func(mockUser *mocks.MockUserControllerInterface) {
+				mockUser.EXPECT().SubscribeEvents("nonexistent@example.com").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", "nonexistent@example.com"))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"success":false,"error":{"type":"NOT_FOUND","message":"User not found: nonexistent@example.com"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUser := mocks.NewMockUserControllerInterface(ctrl)
+			tt.setupMock(mockUser)
+
+			handler := NewGatewayHandler(mockUser, mocks.NewMockUpdateControllerInterface(ctrl))
+
+			router := gin.New()
+			router.GET("/ws", handler.Connect)
+
+			req, err := http.NewRequest(http.MethodGet, "/ws"+tt.query, nil)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			if tt.email != "" {
+				req.Header.Set(AuthenticatedEmailHeader, tt.email)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"assignment/internal/domain/interfaces"
+	"assignment/internal/gql"
+	"assignment/pkg/errors"
+	"assignment/pkg/validator"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GraphQLHandler struct {
+	schema *gql.Schema
+}
+
+func NewGraphQLHandler(userController interfaces.UserControllerInterface) *GraphQLHandler {
+	return &GraphQLHandler{
+		schema: gql.NewSchema(userController),
+	}
+}
+
+// Query runs a GraphQL query. Errors in the query, and those returned by the
+// controller, are listed in the errors of the response, sent with a 200 status
+func (h *GraphQLHandler) Query(c *gin.Context) {
+	var req GraphQLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	v := validator.New()
+	if ValidateGraphQLRequest(v, &req); !v.Valid() {
+		errors.HandleValidationErrors(c, v.Errors)
+		return
+	}
+
+	response := h.schema.Exec(c.Request.Context(), req.Query, req.OperationName, req.Variables)
+
+	c.JSON(http.StatusOK, response)
+}
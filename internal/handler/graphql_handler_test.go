@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"assignment/internal/domain/entities"
+	"assignment/mocks"
+	"assignment/pkg/errors"
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGraphQLQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	andy := &entities.User{ID: 1, Email: "andy@example.com"}
+	john := &entities.User{ID: 2, Email: "john@example.com"}
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(mockController *mocks.MockUserControllerInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success",
+			body: `{"query":"query Friends($email: String!) { user(email: $email) { email friends(first: 1) { nodes { email } totalCount nextCursor } } }","operationName":"Friends","variables":{"email":"andy@example.com"}}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetUsersByEmails([]string{"andy@example.com"}).Return([]*entities.User{andy}, nil)
+				mockController.EXPECT().GetFriendListsBatch([]*entities.User{andy}, "", 1).Return(map[int]*entities.UserPage{andy.ID: {Users: []*entities.User{john}, Total: 2, NextCursor: "am9obg"}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"data":{"user":{"email":"andy@example.com","friends":{"nodes":[{"email":"john@example.com"}],"totalCount":2,"nextCursor":"am9obg"}}}}`,
+		},
+		{
+			name: "controller error is listed in the errors",
+			body: `{"query":"{ user(email: \"andy@example.com\") { blockedBy { totalCount } } }"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetUsersByEmails([]string{"andy@example.com"}).Return([]*entities.User{andy}, nil)
+				mockController.EXPECT().GetBlockersBatch([]*entities.User{andy}, "", 50).Return(nil, errors.New(errors.ErrorTypeDatabase, "Failed to fetch blockers"))
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"errors":[{"message":"Failed to fetch blockers","path":["user","blockedBy"],"extensions":{"type":"DATABASE_ERROR"}}],"data":{"user":null}}`,
+		},
+		{
+			name: "syntax error",
+			body: `{"query":"{ user(email: \"andy@example.com\") { email }"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as the query is never run
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"errors":[{"message":"syntax error: unexpected \"\", expecting Ident","locations":[{"line":1,"column":44}]}]}`,
+		},
+		{
+			name: "missing query",
+			body: `{"variables":{"email":"andy@example.com"}}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before the query is run
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"query: must be provided"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := mocks.NewMockUserControllerInterface(ctrl)
+			tt.setupMock(mockController)
+
+			handler := NewGraphQLHandler(mockController)
+
+			router := gin.New()
+			router.POST("/graphql", handler.Query)
+
+			req, err := http.NewRequest(http.MethodPost, "/graphql", bytes.NewBuffer([]byte(tt.body)))
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
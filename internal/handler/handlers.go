@@ -3,11 +3,19 @@ package handler
 import "assignment/internal/domain/interfaces"
 
 type Handlers struct {
-    UserHandler *UserHandler
+    UserHandler    *UserHandler
+    UpdateHandler  *UpdateHandler
+    WebhookHandler *WebhookHandler
+    GatewayHandler *GatewayHandler
+    GraphQLHandler *GraphQLHandler
 }
 
 func NewHandlers(controllers interfaces.Controllers) *Handlers {
     return &Handlers{
-        UserHandler: NewUserHandler(controllers.UserController()),
+        UserHandler:    NewUserHandler(controllers.UserController()),
+        UpdateHandler:  NewUpdateHandler(controllers.UpdateController()),
+        WebhookHandler: NewWebhookHandler(controllers.WebhookController()),
+        GatewayHandler: NewGatewayHandler(controllers.UserController(), controllers.UpdateController()),
+        GraphQLHandler: NewGraphQLHandler(controllers.UserController()),
     }
 }
\ No newline at end of file
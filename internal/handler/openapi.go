@@ -0,0 +1,90 @@
+package handler
+
+import (
+	_ "embed"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+
+	"assignment/pkg/errors"
+)
+
+// openAPISpec is the API contract served at /openapi.json. It is maintained by
+// hand alongside the routes and DTOs; openapi_test.go fails when they drift apart
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// LoadOpenAPISpec parses the embedded OpenAPI document and checks that it is valid
+func LoadOpenAPISpec() (*openapi3.T, error) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(openAPISpec)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := doc.Validate(loader.Context); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+func ServeOpenAPISpec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", openAPISpec)
+}
+
+// ValidateRequests returns middleware that rejects a request not matching its
+// operation in the spec before it reaches the handler. Requests for paths the
+// spec does not describe are passed on, so they still get the router's 404
+func ValidateRequests(doc *openapi3.T) (gin.HandlerFunc, error) {
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &openapi3filter.Options{}
+	options.WithCustomSchemaErrorFunc(schemaErrorMessage)
+
+	return func(c *gin.Context) {
+		route, pathParams, err := router.FindRoute(c.Request)
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		// Handlers bind bodies as JSON whatever their Content-Type, so a body
+		// sent without one is taken for JSON here too
+		if c.Request.Header.Get("Content-Type") == "" && c.Request.ContentLength != 0 && route.Operation.RequestBody != nil {
+			c.Request.Header.Set("Content-Type", "application/json")
+		}
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    c.Request,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		}
+		if err := openapi3filter.ValidateRequest(c.Request.Context(), input); err != nil {
+			errors.SendBadRequest(c, "Request does not match the API specification", err.Error())
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}, nil
+}
+
+// schemaErrorMessage reports a schema violation as "field: reason", like the
+// handlers' validation errors, instead of dumping the schema and the value
+func schemaErrorMessage(err *openapi3.SchemaError) string {
+	field := strings.Join(err.JSONPointer(), ".")
+	if field == "" {
+		return err.Reason
+	}
+	return field + ": " + err.Reason
+}
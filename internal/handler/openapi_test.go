@@ -0,0 +1,569 @@
+package handler
+
+import (
+	"assignment/internal/domain/entities"
+	"assignment/mocks"
+	"assignment/pkg/errors"
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// specSchemas maps every schema in openapi.json's components to the type the
+// handlers bind it to or respond with
+var specSchemas = map[string]any{
+	"PageParams":                      PageParams{},
+	"PageInfo":                        PageInfo{},
+	"CreateFriendshipRequest":         CreateFriendshipRequest{},
+	"DeleteFriendshipRequest":         DeleteFriendshipRequest{},
+	"FriendRequestRequest":            FriendRequestRequest{},
+	"GetFriendRequestsRequest":        GetFriendRequestsRequest{},
+	"GetFriendListRequest":            GetFriendListRequest{},
+	"GetCommonFriendsRequest":         GetCommonFriendsRequest{},
+	"GetFriendshipPathRequest":        GetFriendshipPathRequest{},
+	"GetFriendSuggestionsRequest":     GetFriendSuggestionsRequest{},
+	"SubscriptionRequest":             SubscriptionRequest{},
+	"UpdateSubscriptionFilterRequest": UpdateSubscriptionFilterRequest{},
+	"CreateBlockRequest":              CreateBlockRequest{},
+	"DeleteBlockRequest":              DeleteBlockRequest{},
+	"CreateMuteRequest":               CreateMuteRequest{},
+	"DeleteMuteRequest":               DeleteMuteRequest{},
+	"GetMutesRequest":                 GetMutesRequest{},
+	"GetRelationshipRequest":          GetRelationshipRequest{},
+	"GetRecipientsRequest":            GetRecipientsRequest{},
+	"PublishUpdateRequest":            PublishUpdateRequest{},
+	"GetTimelineRequest":              GetTimelineRequest{},
+	"RegisterWebhookRequest":          RegisterWebhookRequest{},
+	"RemoveWebhookRequest":            RemoveWebhookRequest{},
+	"GetWebhookDeliveriesRequest":     GetWebhookDeliveriesRequest{},
+	"CreateUserRequest":               CreateUserRequest{},
+	"SetUsernameRequest":              SetUsernameRequest{},
+	"UpdateSettingsRequest":           UpdateSettingsRequest{},
+	"GatewayClientMessage":            GatewayClientMessage{},
+	"GatewayServerMessage":            GatewayServerMessage{},
+	"UserEventItem":                   UserEventItem{},
+	"SuccessResponse": struct {
+		Success bool `json:"success"`
+	}{},
+	"ErrorResponse":                 errors.ErrorResponse{},
+	"FriendListResponse":            FriendListResponse{},
+	"CommonFriendsResponse":         CommonFriendsResponse{},
+	"SubscriberListResponse":        SubscriberListResponse{},
+	"SubscriptionListResponse":      SubscriptionListResponse{},
+	"BlockListResponse":             BlockListResponse{},
+	"RecipientsResponse":            RecipientsResponse{},
+	"RecipientDetail":               RecipientDetail{},
+	"DroppedMentionItem":            DroppedMentionItem{},
+	"RecipientsExplanationResponse": RecipientsExplanationResponse{},
+	"UpdateItem":                    UpdateItem{},
+	"PublishUpdateResponse":         PublishUpdateResponse{},
+	"TimelineResponse":              TimelineResponse{},
+	"SubscriptionFilterResponse":    SubscriptionFilterResponse{},
+	"MuteItem":                      MuteItem{},
+	"MuteResponse":                  MuteResponse{},
+	"MuteListResponse":              MuteListResponse{},
+	"SettingsResponse":              SettingsResponse{},
+	"WebhookResponse":               WebhookResponse{},
+	"WebhookDeliveryItem":           WebhookDeliveryItem{},
+	"WebhookDeliveriesResponse":     WebhookDeliveriesResponse{},
+	"UserResponse":                  UserResponse{},
+	"UserListResponse":              UserListResponse{},
+	"FriendRequestItem":             FriendRequestItem{},
+	"FriendRequestResponse":         FriendRequestResponse{},
+	"FriendRequestListResponse":     FriendRequestListResponse{},
+	"RelationshipStatus":            RelationshipStatus{},
+	"RelationshipResponse":          RelationshipResponse{},
+	"FriendshipPathResponse":        FriendshipPathResponse{},
+	"FriendSuggestionItem":          FriendSuggestionItem{},
+	"FriendSuggestionsResponse":     FriendSuggestionsResponse{},
+	"GraphQLRequest":                GraphQLRequest{},
+	// As graphql.Response is marshalled, data being the raw JSON of an object
+	"GraphQLResponse": struct {
+		Data   map[string]any `json:"data,omitempty"`
+		Errors []any          `json:"errors,omitempty"`
+	}{},
+}
+
+// specQueries maps every operation taking query parameters to the type the
+// handler binds them to
+var specQueries = map[string]any{
+	"GET /api/v1/user/subscriptions/filters":   GetSubscriptionFilterRequest{},
+	"GET /api/v1/user/settings":                GetSettingsRequest{},
+	"GET /api/v1/users/{email}/friends":        PageParams{},
+	"GET /api/v1/users/{email}/friends/common": GetUserCommonFriendsRequest{},
+	"GET /api/v1/users/{email}/subscribers":    PageParams{},
+	"GET /api/v1/users/{email}/subscriptions":  PageParams{},
+	"GET /api/v1/users/{email}/blocks":         PageParams{},
+}
+
+// specHeaders maps every operation binding request headers to the type the
+// handler binds them to
+var specHeaders = map[string]any{
+	"GET /api/v1/user/stream": StreamUpdatesRequest{},
+	"GET /api/v1/user/ws":     GatewayRequest{},
+}
+
+func loadTestSpec(t *testing.T) *openapi3.T {
+	doc, err := LoadOpenAPISpec()
+	if err != nil {
+		t.Fatalf("invalid OpenAPI spec: %v", err)
+	}
+	return doc
+}
+
+func TestOpenAPISpecCoversRoutes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	router := newSpecTestRouter(t, ctrl, mocks.NewMockUserControllerInterface(ctrl))
+
+	var routes []string
+	for _, route := range router.Routes() {
+		path := route.Path
+		for _, segment := range strings.Split(route.Path, "/") {
+			if name, ok := strings.CutPrefix(segment, ":"); ok {
+				path = strings.Replace(path, segment, "{"+name+"}", 1)
+			}
+		}
+		routes = append(routes, route.Method+" "+path)
+	}
+
+	var operations []string
+	for path, item := range loadTestSpec(t).Paths.Map() {
+		for method := range item.Operations() {
+			operations = append(operations, method+" "+path)
+		}
+	}
+
+	assert.ElementsMatch(t, routes, operations)
+}
+
+func TestOpenAPISpecMatchesDTOs(t *testing.T) {
+	doc := loadTestSpec(t)
+
+	// Every struct in dtos.go must be described by the spec
+	described := make(map[string]bool)
+	for _, dto := range specSchemas {
+		described[reflect.TypeOf(dto).Name()] = true
+	}
+	for _, dto := range specQueries {
+		described[reflect.TypeOf(dto).Name()] = true
+	}
+	for _, dto := range specHeaders {
+		described[reflect.TypeOf(dto).Name()] = true
+	}
+	for _, name := range dtoStructNames(t) {
+		assert.True(t, described[name], "%s in dtos.go is not in openapi.json", name)
+	}
+
+	var schemaNames []string
+	for name := range doc.Components.Schemas {
+		schemaNames = append(schemaNames, name)
+	}
+	sort.Strings(schemaNames)
+	for _, name := range schemaNames {
+		dto, ok := specSchemas[name]
+		if !assert.True(t, ok, "schema %s has no DTO", name) {
+			continue
+		}
+		assertSchemaMatches(t, name, reflect.TypeOf(dto), doc.Components.Schemas[name])
+	}
+
+	for key, dto := range specQueries {
+		method, path, _ := strings.Cut(key, " ")
+		item := doc.Paths.Value(path)
+		if !assert.NotNil(t, item, "%s is not in openapi.json", key) {
+			continue
+		}
+		operation := item.GetOperation(method)
+		if !assert.NotNil(t, operation, "%s is not in openapi.json", key) {
+			continue
+		}
+
+		params := make(map[string]*openapi3.SchemaRef)
+		for _, param := range operation.Parameters {
+			if param.Value.In == openapi3.ParameterInQuery {
+				params[param.Value.Name] = param.Value.Schema
+			}
+		}
+
+		fields := dtoFields(reflect.TypeOf(dto), "form")
+		assert.ElementsMatch(t, fieldNames(fields), mapKeys(params), "%s query parameters", key)
+		for name, field := range fields {
+			if schema, ok := params[name]; ok {
+				assertSchemaMatches(t, key+" "+name, field.Type, schema)
+			}
+		}
+	}
+
+	// Operations may document headers the handler reads on its own, such as
+	// Last-Event-ID, so only the bound ones have to be in the spec
+	for key, dto := range specHeaders {
+		method, path, _ := strings.Cut(key, " ")
+		operation := doc.Paths.Value(path).GetOperation(method)
+
+		params := make(map[string]*openapi3.SchemaRef)
+		for _, param := range operation.Parameters {
+			if param.Value.In == openapi3.ParameterInHeader {
+				params[param.Value.Name] = param.Value.Schema
+			}
+		}
+
+		for name, field := range dtoFields(reflect.TypeOf(dto), "header") {
+			schema, ok := params[name]
+			if assert.True(t, ok, "%s header %s is not in openapi.json", key, name) {
+				assertSchemaMatches(t, key+" "+name, field.Type, schema)
+			}
+		}
+	}
+}
+
+func TestValidateRequests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	pendingRequest := &entities.FriendRequest{
+		ID:        1,
+		Requester: &entities.User{ID: 1, Email: "andy@example.com"},
+		Addressee: &entities.User{ID: 2, Email: "john@example.com"},
+		Status:    entities.FriendRequestPending,
+		CreatedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		body           string
+		contentType    string
+		setupMock      func(mockController *mocks.MockUserControllerInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "valid request reaches the handler",
+			method:      http.MethodPost,
+			url:         "/api/v1/user/friends",
+			body:        `{"friends":["andy@example.com","john@example.com"]}`,
+			contentType: "application/json",
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().SendFriendRequest("andy@example.com", "john@example.com").Return(pendingRequest, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true}`,
+		},
+		{
+			name:   "body without content type is taken for json",
+			method: http.MethodPost,
+			url:    "/api/v1/user/friends",
+			body:   `{"friends":["andy@example.com","john@example.com"]}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().SendFriendRequest("andy@example.com", "john@example.com").Return(pendingRequest, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true}`,
+		},
+		{
+			name:           "wrong field type",
+			method:         http.MethodPost,
+			url:            "/api/v1/user/friends",
+			body:           `{"friends":"andy@example.com"}`,
+			contentType:    "application/json",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Request does not match the API specification","details":"request body has an error: doesn't match schema #/components/schemas/CreateFriendshipRequest: friends: value must be an array"}}`,
+		},
+		{
+			name:           "missing required field",
+			method:         http.MethodPost,
+			url:            "/api/v1/user/subscriptions",
+			body:           `{"requestor":"andy@example.com"}`,
+			contentType:    "application/json",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Request does not match the API specification","details":"request body has an error: doesn't match schema #/components/schemas/SubscriptionRequest: target: property \"target\" is missing"}}`,
+		},
+		{
+			name:           "graphql variables not an object",
+			method:         http.MethodPost,
+			url:            "/api/v1/graphql",
+			body:           `{"query":"{ user(email: \"andy@example.com\") { email } }","variables":["andy@example.com"]}`,
+			contentType:    "application/json",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Request does not match the API specification","details":"request body has an error: doesn't match schema #/components/schemas/GraphQLRequest: variables: value must be an object"}}`,
+		},
+		{
+			name:           "missing body",
+			method:         http.MethodPost,
+			url:            "/api/v1/user/friends/list",
+			contentType:    "application/json",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Request does not match the API specification","details":"request body has an error: value is required but missing"}}`,
+		},
+		{
+			name:           "unsupported content type",
+			method:         http.MethodPost,
+			url:            "/api/v1/user/friends",
+			body:           `friends=andy@example.com`,
+			contentType:    "application/x-www-form-urlencoded",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Request does not match the API specification","details":"request body has an error: header Content-Type has unexpected value \"application/x-www-form-urlencoded\""}}`,
+		},
+		{
+			name:           "query parameter out of range",
+			method:         http.MethodGet,
+			url:            "/api/v1/users/andy@example.com/friends?limit=101",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Request does not match the API specification","details":"parameter \"limit\" in query has an error: number must be at most 100"}}`,
+		},
+		{
+			name:           "missing query parameter",
+			method:         http.MethodGet,
+			url:            "/api/v1/user/settings",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Request does not match the API specification","details":"parameter \"email\" in query has an error: value is required but missing"}}`,
+		},
+		{
+			name:   "valid query reaches the handler",
+			method: http.MethodGet,
+			url:    "/api/v1/users/andy@example.com/friends/common?with=john@example.com&limit=1",
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetCommonFriends([]string{"andy@example.com", "john@example.com"}, "", 1).Return(&entities.UserPage{Total: 0}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"friends":[],"count":0,"total":0,"limit":1}`,
+		},
+		{
+			name:           "unknown path is left to the router",
+			method:         http.MethodGet,
+			url:            "/api/v1/unknown",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `404 page not found`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := mocks.NewMockUserControllerInterface(ctrl)
+			if tt.setupMock != nil {
+				tt.setupMock(mockController)
+			}
+
+			router := newSpecTestRouter(t, ctrl, mockController)
+
+			req, _ := http.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if strings.HasPrefix(tt.expectedBody, "{") {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			} else {
+				assert.Equal(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestServeOpenAPISpec(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	router := newSpecTestRouter(t, ctrl, mocks.NewMockUserControllerInterface(ctrl))
+
+	req, _ := http.NewRequest(http.MethodGet, "/openapi.json", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.True(t, bytes.Equal(openAPISpec, w.Body.Bytes()))
+}
+
+// newSpecTestRouter sets up every route, with the user controller mocked
+func newSpecTestRouter(t *testing.T, ctrl *gomock.Controller, userController *mocks.MockUserControllerInterface) *gin.Engine {
+	controllers := mocks.NewMockControllers(ctrl)
+	controllers.EXPECT().UserController().Return(userController).AnyTimes()
+	controllers.EXPECT().UpdateController().Return(mocks.NewMockUpdateControllerInterface(ctrl)).AnyTimes()
+	controllers.EXPECT().WebhookController().Return(mocks.NewMockWebhookControllerInterface(ctrl)).AnyTimes()
+
+	router := gin.New()
+	if err := SetupRoutes(router, controllers); err != nil {
+		t.Fatalf("failed to setup routes: %v", err)
+	}
+	return router
+}
+
+// dtoStructNames lists the struct types declared in dtos.go
+func dtoStructNames(t *testing.T) []string {
+	file, err := parser.ParseFile(token.NewFileSet(), "dtos.go", nil, 0)
+	if err != nil {
+		t.Fatalf("failed to parse dtos.go: %v", err)
+	}
+
+	var names []string
+	ast.Inspect(file, func(node ast.Node) bool {
+		if spec, ok := node.(*ast.TypeSpec); ok {
+			if _, ok := spec.Type.(*ast.StructType); ok {
+				names = append(names, spec.Name.Name)
+			}
+		}
+		return true
+	})
+	return names
+}
+
+type dtoField struct {
+	Type      reflect.Type
+	OmitEmpty bool
+}
+
+// dtoFields returns the fields of a struct by their name in the given tag, with
+// the fields of embedded structs flattened in as encoding/json and gin do
+func dtoFields(typ reflect.Type, tag string) map[string]dtoField {
+	fields := make(map[string]dtoField)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			for name, embedded := range dtoFields(field.Type, tag) {
+				fields[name] = embedded
+			}
+			continue
+		}
+
+		name, options, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = dtoField{Type: field.Type, OmitEmpty: strings.Contains(options, "omitempty")}
+	}
+	return fields
+}
+
+// schemaProperties returns the properties and required properties of an object
+// schema, including those of the schemas it combines with allOf
+func schemaProperties(schema *openapi3.Schema) (map[string]*openapi3.SchemaRef, map[string]bool) {
+	properties := make(map[string]*openapi3.SchemaRef)
+	required := make(map[string]bool)
+	for name, property := range schema.Properties {
+		properties[name] = property
+	}
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+	for _, part := range schema.AllOf {
+		partProperties, partRequired := schemaProperties(part.Value)
+		for name, property := range partProperties {
+			properties[name] = property
+		}
+		for name := range partRequired {
+			required[name] = true
+		}
+	}
+	return properties, required
+}
+
+// assertSchemaMatches checks that the schema describes values of typ, down to
+// the fields of nested structs
+func assertSchemaMatches(t *testing.T, path string, typ reflect.Type, ref *openapi3.SchemaRef) {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	expected := schemaTypeOf(typ)
+	if expected == "" {
+		return
+	}
+
+	schema := ref.Value
+	actual := ""
+	if schema.Type != nil && len(schema.Type.Slice()) == 1 {
+		actual = schema.Type.Slice()[0]
+	} else if len(schema.AllOf) > 0 {
+		actual = openapi3.TypeObject
+	}
+	if !assert.Equal(t, expected, actual, "%s type", path) {
+		return
+	}
+
+	switch {
+	case expected == openapi3.TypeArray:
+		assertSchemaMatches(t, path+"[]", typ.Elem(), schema.Items)
+	case expected == openapi3.TypeObject && typ.Kind() == reflect.Struct:
+		fields := dtoFields(typ, "json")
+		properties, required := schemaProperties(schema)
+		assert.ElementsMatch(t, fieldNames(fields), mapKeys(properties), "%s properties", path)
+
+		for name, field := range fields {
+			if property, ok := properties[name]; ok {
+				assertSchemaMatches(t, path+"."+name, field.Type, property)
+			}
+			if field.OmitEmpty {
+				assert.False(t, required[name], "%s.%s is omitted when empty but required", path, name)
+			}
+		}
+	}
+}
+
+func schemaTypeOf(typ reflect.Type) string {
+	if typ == reflect.TypeOf(time.Time{}) {
+		return openapi3.TypeString
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		return openapi3.TypeString
+	case reflect.Bool:
+		return openapi3.TypeBoolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return openapi3.TypeInteger
+	case reflect.Float32, reflect.Float64:
+		return openapi3.TypeNumber
+	case reflect.Slice, reflect.Array:
+		return openapi3.TypeArray
+	case reflect.Struct, reflect.Map:
+		return openapi3.TypeObject
+	}
+	// any is described by a schema without a type
+	return ""
+}
+
+func fieldNames(fields map[string]dtoField) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	return names
+}
+
+func mapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
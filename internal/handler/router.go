@@ -6,19 +6,76 @@ import (
 	"assignment/internal/domain/interfaces"
 )
 
-func SetupRoutes(r *gin.Engine, controllers interfaces.Controllers) {
+// SetupRoutes registers every route described in openapi.json, with requests
+// under /api/v1 validated against it
+func SetupRoutes(r *gin.Engine, controllers interfaces.Controllers) error {
 	handlers := NewHandlers(controllers)
 
-	v1 := r.Group("/api/v1")
+	spec, err := LoadOpenAPISpec()
+	if err != nil {
+		return err
+	}
+	validateRequests, err := ValidateRequests(spec)
+	if err != nil {
+		return err
+	}
+
+	r.GET("/openapi.json", ServeOpenAPISpec)
+
+	v1 := r.Group("/api/v1", validateRequests)
 	{
-		users := v1.Group("/user")
+		user := v1.Group("/user")
 		{
-			users.POST("/friends", handlers.UserHandler.CreateFriendships)
-			users.POST("/friends/list", handlers.UserHandler.GetFriendList)
-			users.POST("/friends/common", handlers.UserHandler.GetCommonFriends)
-			users.POST("/subscriptions", handlers.UserHandler.CreateSubscription)
-			users.POST("/blocks", handlers.UserHandler.CreateBlock)
-			users.POST("/recipients", handlers.UserHandler.GetRecipients)
+			user.POST("/friends", handlers.UserHandler.CreateFriendships)
+			user.DELETE("/friends", handlers.UserHandler.DeleteFriendship)
+			user.POST("/friends/list", handlers.UserHandler.GetFriendList)
+			user.POST("/friend-requests", handlers.UserHandler.SendFriendRequest)
+			user.POST("/friend-requests/incoming", handlers.UserHandler.GetIncomingFriendRequests)
+			user.POST("/friend-requests/outgoing", handlers.UserHandler.GetOutgoingFriendRequests)
+			user.POST("/friend-requests/accept", handlers.UserHandler.AcceptFriendRequest)
+			user.POST("/friend-requests/reject", handlers.UserHandler.RejectFriendRequest)
+			user.POST("/friend-requests/cancel", handlers.UserHandler.CancelFriendRequest)
+			user.POST("/friends/common", handlers.UserHandler.GetCommonFriends)
+			user.POST("/friends/suggestions", handlers.UserHandler.GetFriendSuggestions)
+			user.POST("/friends/path", handlers.UserHandler.GetFriendshipPath)
+			user.POST("/subscriptions", handlers.UserHandler.CreateSubscription)
+			user.DELETE("/subscriptions", handlers.UserHandler.DeleteSubscription)
+			user.GET("/subscriptions/filters", handlers.UserHandler.GetSubscriptionFilter)
+			user.PUT("/subscriptions/filters", handlers.UserHandler.UpdateSubscriptionFilter)
+			user.POST("/blocks", handlers.UserHandler.CreateBlock)
+			user.DELETE("/blocks", handlers.UserHandler.DeleteBlock)
+			user.POST("/mutes", handlers.UserHandler.CreateMute)
+			user.DELETE("/mutes", handlers.UserHandler.DeleteMute)
+			user.POST("/mutes/list", handlers.UserHandler.GetMutes)
+			user.POST("/relationship", handlers.UserHandler.GetRelationship)
+			user.POST("/username", handlers.UserHandler.SetUsername)
+			user.GET("/settings", handlers.UserHandler.GetSettings)
+			user.PUT("/settings", handlers.UserHandler.UpdateSettings)
+			user.POST("/recipients", handlers.UserHandler.GetRecipients)
+			user.POST("/updates", handlers.UpdateHandler.PublishUpdate)
+			user.POST("/timeline", handlers.UpdateHandler.GetTimeline)
+			user.GET("/stream", handlers.UpdateHandler.StreamUpdates)
+			user.GET("/ws", handlers.GatewayHandler.Connect)
+			user.POST("/webhooks", handlers.WebhookHandler.RegisterWebhook)
+			user.DELETE("/webhooks", handlers.WebhookHandler.RemoveWebhook)
+			user.POST("/webhooks/deliveries", handlers.WebhookHandler.GetWebhookDeliveries)
 		}
+
+		users := v1.Group("/users")
+		{
+			users.POST("", handlers.UserHandler.CreateUser)
+			users.GET("", handlers.UserHandler.GetUsers)
+			users.GET("/:email", handlers.UserHandler.GetUser)
+			users.GET("/:email/friends", handlers.UserHandler.GetUserFriends)
+			users.GET("/:email/friends/common", handlers.UserHandler.GetUserCommonFriends)
+			users.GET("/:email/subscribers", handlers.UserHandler.GetUserSubscribers)
+			users.GET("/:email/subscriptions", handlers.UserHandler.GetUserSubscriptions)
+			users.GET("/:email/blocks", handlers.UserHandler.GetUserBlocks)
+			users.DELETE("/:email", handlers.UserHandler.DeleteUser)
+		}
+
+		v1.POST("/graphql", handlers.GraphQLHandler.Query)
 	}
+
+	return nil
 }
@@ -0,0 +1,243 @@
+package handler
+
+import (
+	"assignment/internal/domain/entities"
+	"assignment/internal/domain/interfaces"
+	"assignment/pkg/errors"
+	"assignment/pkg/validator"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultStreamHeartbeat is how often an idle stream sends a comment line so
+// proxies do not close the connection
+const DefaultStreamHeartbeat = 15 * time.Second
+
+type UpdateHandler struct {
+	updateController interfaces.UpdateControllerInterface
+	heartbeat        time.Duration
+}
+
+func NewUpdateHandler(updateController interfaces.UpdateControllerInterface) *UpdateHandler {
+	return &UpdateHandler{
+		updateController: updateController,
+		heartbeat:        DefaultStreamHeartbeat,
+	}
+}
+
+func (h *UpdateHandler) PublishUpdate(c *gin.Context) {
+	var req PublishUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	v := validator.New()
+	if ValidatePublishUpdateRequest(v, &req); !v.Valid() {
+		errors.HandleValidationErrors(c, v.Errors)
+		return
+	}
+
+	update, err := h.updateController.PublishUpdate(req.Sender, req.Text)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	recipientEmails := make([]string, len(update.Recipients))
+	for i, recipient := range update.Recipients {
+		recipientEmails[i] = recipient.Email
+	}
+
+	response := PublishUpdateResponse{
+		Success:    true,
+		Update:     toUpdateItem(update),
+		Recipients: recipientEmails,
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+func (h *UpdateHandler) GetTimeline(c *gin.Context) {
+	var req GetTimelineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	v := validator.New()
+	if ValidateGetTimelineRequest(v, &req); !v.Valid() {
+		errors.HandleValidationErrors(c, v.Errors)
+		return
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = DefaultTimelineLimit
+	}
+
+	page, err := h.updateController.GetTimeline(req.Email, limit, req.Offset)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	items := make([]UpdateItem, len(page.Updates))
+	for i, update := range page.Updates {
+		items[i] = toUpdateItem(update)
+	}
+
+	response := TimelineResponse{
+		Success: true,
+		Updates: items,
+		Count:   len(items),
+		HasMore: page.HasMore,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// StreamUpdates sends the caller's incoming updates as Server-Sent Events. The
+// caller is the user named by the header of the authenticating proxy, never by
+// the request itself. Each event id is the update ID, so a reconnecting client
+// that sends Last-Event-ID first receives everything it missed from the inbox
+func (h *UpdateHandler) StreamUpdates(c *gin.Context) {
+	var req StreamUpdatesRequest
+	if err := c.ShouldBindHeader(&req); err != nil {
+		errors.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+	if req.Email == "" {
+		errors.HandleError(c, errors.ErrNotAuthenticated)
+		return
+	}
+
+	v := validator.New()
+	if ValidateStreamUpdatesRequest(v, &req); !v.Valid() {
+		errors.HandleValidationErrors(c, v.Errors)
+		return
+	}
+
+	lastEventID := 0
+	if header := c.GetHeader("Last-Event-ID"); header != "" {
+		id, err := strconv.Atoi(header)
+		if err != nil || id < 0 {
+			errors.SendBadRequest(c, "Invalid request format", "Last-Event-ID must be an update ID")
+			return
+		}
+		lastEventID = id
+	}
+
+	stream, err := h.updateController.SubscribeUpdates(req.Email, lastEventID, StreamBacklogPageSize)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	defer stream.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	// Updates published while the backlog was read arrive live as well. They
+	// are skipped by ID rather than by comparing with the newest ID sent, as
+	// an update committed out of order can have a lower ID and still be new
+	sent := make(map[int]struct{})
+	var live []*entities.Update
+	err = stream.Replay(func(page []*entities.Update) error {
+		if err := c.Request.Context().Err(); err != nil {
+			return err
+		}
+		for _, update := range page {
+			if err := writeUpdateEvent(c.Writer, update); err != nil {
+				return err
+			}
+			sent[update.ID] = struct{}{}
+		}
+		c.Writer.Flush()
+
+		// Keep taking the live updates between pages, or a long backlog would
+		// fill the subscription's buffer and the hub would drop the client
+		for {
+			select {
+			case update, ok := <-stream.Updates:
+				if !ok {
+					return io.EOF
+				}
+				live = append(live, update)
+			default:
+				return nil
+			}
+		}
+	})
+	if err != nil {
+		// Headers are sent already; the client reconnects with Last-Event-ID
+		return
+	}
+
+	for _, update := range live {
+		if _, ok := sent[update.ID]; ok {
+			delete(sent, update.ID)
+			continue
+		}
+		if err := writeUpdateEvent(c.Writer, update); err != nil {
+			return
+		}
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case update, ok := <-stream.Updates:
+			if !ok {
+				// Disconnected by the hub; the client reconnects with Last-Event-ID
+				return
+			}
+			if _, ok := sent[update.ID]; ok {
+				delete(sent, update.ID)
+				continue
+			}
+			if err := writeUpdateEvent(c.Writer, update); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := io.WriteString(c.Writer, ": ping\n\n"); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
+
+func writeUpdateEvent(w io.Writer, update *entities.Update) error {
+	data, err := json.Marshal(toUpdateItem(update))
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: update\ndata: %s\n\n", update.ID, data)
+	return err
+}
+
+func toUpdateItem(update *entities.Update) UpdateItem {
+	return UpdateItem{
+		ID:        update.ID,
+		Sender:    update.Sender.Email,
+		Text:      update.Text,
+		CreatedAt: update.CreatedAt,
+	}
+}
@@ -0,0 +1,442 @@
+package handler
+
+import (
+	"assignment/internal/domain/entities"
+	"assignment/mocks"
+	"assignment/pkg/errors"
+	"bufio"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPublishUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	sender := &entities.User{ID: 1, Email: "andy@example.com"}
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(mockController *mocks.MockUpdateControllerInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success",
+			body: `{"sender":"andy@example.com","text":"Hello kate@example.com"}`,
+			setupMock: func(mockController *mocks.MockUpdateControllerInterface) {
+				mockController.EXPECT().PublishUpdate("andy@example.com", "Hello kate@example.com").Return(&entities.Update{
+					ID:     7,
+					Sender: sender,
+					Text:   "Hello kate@example.com",
+					Recipients: []*entities.User{
+						{ID: 2, Email: "john@example.com"},
+						{ID: 3, Email: "kate@example.com"},
+					},
+					CreatedAt: createdAt,
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"success":true,"update":{"id":7,"sender":"andy@example.com","text":"Hello kate@example.com","created_at":"2024-01-02T03:04:05Z"},"recipients":["john@example.com","kate@example.com"]}`,
+		},
+		{
+			name: "success without recipients",
+			body: `{"sender":"andy@example.com","text":"Hello"}`,
+			setupMock: func(mockController *mocks.MockUpdateControllerInterface) {
+				mockController.EXPECT().PublishUpdate("andy@example.com", "Hello").Return(&entities.Update{
+					ID:         8,
+					Sender:     sender,
+					Text:       "Hello",
+					Recipients: []*entities.User{},
+					CreatedAt:  createdAt,
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"success":true,"update":{"id":8,"sender":"andy@example.com","text":"Hello","created_at":"2024-01-02T03:04:05Z"},"recipients":[]}`,
+		},
+		{
+			name: "sender not found",
+			body: `{"sender":"nonexistent@example.com","text":"Hello"}`,
+			setupMock: func(mockController *mocks.MockUpdateControllerInterface) {
+				mockController.EXPECT().PublishUpdate("nonexistent@example.com", "Hello").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", "nonexistent@example.com"))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"success":false,"error":{"type":"NOT_FOUND","message":"User not found: nonexistent@example.com"}}`,
+		},
+		{
+			name: "empty text",
+			body: `{"sender":"andy@example.com","text":""}`,
+			setupMock: func(mockController *mocks.MockUpdateControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"text: text cannot be empty"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := mocks.NewMockUpdateControllerInterface(ctrl)
+			tt.setupMock(mockController)
+
+			handler := NewUpdateHandler(mockController)
+
+			router := gin.New()
+			router.POST("/updates", handler.PublishUpdate)
+
+			req, err := http.NewRequest(http.MethodPost, "/updates", bytes.NewBuffer([]byte(tt.body)))
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestGetTimeline(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	sender := &entities.User{ID: 1, Email: "andy@example.com"}
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(mockController *mocks.MockUpdateControllerInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success with default limit",
+			body: `{"email":"john@example.com"}`,
+			setupMock: func(mockController *mocks.MockUpdateControllerInterface) {
+				mockController.EXPECT().GetTimeline("john@example.com", DefaultTimelineLimit, 0).Return(&entities.TimelinePage{
+					Updates: []*entities.Update{
+						{ID: 2, Sender: sender, Text: "second", CreatedAt: createdAt},
+						{ID: 1, Sender: sender, Text: "first", CreatedAt: createdAt},
+					},
+					HasMore: false,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"success":true,"updates":[` +
+				`{"id":2,"sender":"andy@example.com","text":"second","created_at":"2024-01-02T03:04:05Z"},` +
+				`{"id":1,"sender":"andy@example.com","text":"first","created_at":"2024-01-02T03:04:05Z"}],` +
+				`"count":2,"has_more":false}`,
+		},
+		{
+			name: "success with explicit page",
+			body: `{"email":"john@example.com","limit":1,"offset":1}`,
+			setupMock: func(mockController *mocks.MockUpdateControllerInterface) {
+				mockController.EXPECT().GetTimeline("john@example.com", 1, 1).Return(&entities.TimelinePage{
+					Updates: []*entities.Update{
+						{ID: 1, Sender: sender, Text: "first", CreatedAt: createdAt},
+					},
+					HasMore: true,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"updates":[{"id":1,"sender":"andy@example.com","text":"first","created_at":"2024-01-02T03:04:05Z"}],"count":1,"has_more":true}`,
+		},
+		{
+			name: "negative offset",
+			body: `{"email":"john@example.com","offset":-1}`,
+			setupMock: func(mockController *mocks.MockUpdateControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"offset: must not be negative"}}`,
+		},
+		{
+			name: "user not found",
+			body: `{"email":"nonexistent@example.com"}`,
+			setupMock: func(mockController *mocks.MockUpdateControllerInterface) {
+				mockController.EXPECT().GetTimeline("nonexistent@example.com", DefaultTimelineLimit, 0).Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", "nonexistent@example.com"))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"success":false,"error":{"type":"NOT_FOUND","message":"User not found: nonexistent@example.com"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := mocks.NewMockUpdateControllerInterface(ctrl)
+			tt.setupMock(mockController)
+
+			handler := NewUpdateHandler(mockController)
+
+			router := gin.New()
+			router.POST("/timeline", handler.GetTimeline)
+
+			req, err := http.NewRequest(http.MethodPost, "/timeline", bytes.NewBuffer([]byte(tt.body)))
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestStreamUpdates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	sender := &entities.User{ID: 1, Email: "andy@example.com"}
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	update := func(id int, text string) *entities.Update {
+		return &entities.Update{ID: id, Sender: sender, Text: text, CreatedAt: createdAt}
+	}
+
+	// replayStream returns a stream replaying the given backlog pages. live[i] is
+	// published to a subscription buffering two updates before page i is sent,
+	// and live[len(pages)] after the replay; the subscription is then closed
+	replayStream := func(pages [][]*entities.Update, live ...[]*entities.Update) *entities.UpdateStream {
+		updates := make(chan *entities.Update, 2)
+		publish := func(i int) {
+			if i >= len(live) {
+				return
+			}
+			for _, u := range live[i] {
+				select {
+				case updates <- u:
+				default:
+					t.Errorf("update %d overflowed the subscription", u.ID)
+				}
+			}
+		}
+
+		replay := func(send func(page []*entities.Update) error) error {
+			defer close(updates)
+			for i, page := range pages {
+				publish(i)
+				if err := send(page); err != nil {
+					return err
+				}
+			}
+			publish(len(pages))
+			return nil
+		}
+		return &entities.UpdateStream{Replay: replay, Updates: updates, Close: func() {}}
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		email          string
+		lastEventID    string
+		setupMock      func(mockController *mocks.MockUpdateControllerInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "live updates",
+			email: "kate@example.com",
+			setupMock: func(mockController *mocks.MockUpdateControllerInterface) {
+				mockController.EXPECT().SubscribeUpdates("kate@example.com", 0, StreamBacklogPageSize).Return(replayStream(nil, []*entities.Update{update(7, "Hello kate")}), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "id: 7\nevent: update\ndata: {\"id\":7,\"sender\":\"andy@example.com\",\"text\":\"Hello kate\",\"created_at\":\"2024-01-02T03:04:05Z\"}\n\n",
+		},
+		{
+			name:        "resume replays backlog and skips duplicates",
+			email:       "kate@example.com",
+			lastEventID: "3",
+			setupMock: func(mockController *mocks.MockUpdateControllerInterface) {
+				mockController.EXPECT().SubscribeUpdates("kate@example.com", 3, StreamBacklogPageSize).Return(
+					replayStream([][]*entities.Update{{update(4, "four"), update(5, "five")}}, nil, []*entities.Update{update(5, "five"), update(6, "six")}), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: "id: 4\nevent: update\ndata: {\"id\":4,\"sender\":\"andy@example.com\",\"text\":\"four\",\"created_at\":\"2024-01-02T03:04:05Z\"}\n\n" +
+				"id: 5\nevent: update\ndata: {\"id\":5,\"sender\":\"andy@example.com\",\"text\":\"five\",\"created_at\":\"2024-01-02T03:04:05Z\"}\n\n" +
+				"id: 6\nevent: update\ndata: {\"id\":6,\"sender\":\"andy@example.com\",\"text\":\"six\",\"created_at\":\"2024-01-02T03:04:05Z\"}\n\n",
+		},
+		{
+			name:        "update committed out of order is not skipped",
+			email:       "kate@example.com",
+			lastEventID: "3",
+			setupMock: func(mockController *mocks.MockUpdateControllerInterface) {
+				mockController.EXPECT().SubscribeUpdates("kate@example.com", 3, StreamBacklogPageSize).Return(
+					replayStream([][]*entities.Update{{update(4, "four"), update(6, "six")}}, nil, []*entities.Update{update(6, "six"), update(5, "five")}), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: "id: 4\nevent: update\ndata: {\"id\":4,\"sender\":\"andy@example.com\",\"text\":\"four\",\"created_at\":\"2024-01-02T03:04:05Z\"}\n\n" +
+				"id: 6\nevent: update\ndata: {\"id\":6,\"sender\":\"andy@example.com\",\"text\":\"six\",\"created_at\":\"2024-01-02T03:04:05Z\"}\n\n" +
+				"id: 5\nevent: update\ndata: {\"id\":5,\"sender\":\"andy@example.com\",\"text\":\"five\",\"created_at\":\"2024-01-02T03:04:05Z\"}\n\n",
+		},
+		{
+			name:        "updates published during a long replay are kept",
+			email:       "kate@example.com",
+			lastEventID: "3",
+			setupMock: func(mockController *mocks.MockUpdateControllerInterface) {
+				mockController.EXPECT().SubscribeUpdates("kate@example.com", 3, StreamBacklogPageSize).Return(
+					replayStream([][]*entities.Update{{update(4, "four"), update(5, "five")}, {update(6, "six")}},
+						nil, []*entities.Update{update(6, "six"), update(7, "seven")}, []*entities.Update{update(8, "eight")}), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: "id: 4\nevent: update\ndata: {\"id\":4,\"sender\":\"andy@example.com\",\"text\":\"four\",\"created_at\":\"2024-01-02T03:04:05Z\"}\n\n" +
+				"id: 5\nevent: update\ndata: {\"id\":5,\"sender\":\"andy@example.com\",\"text\":\"five\",\"created_at\":\"2024-01-02T03:04:05Z\"}\n\n" +
+				"id: 6\nevent: update\ndata: {\"id\":6,\"sender\":\"andy@example.com\",\"text\":\"six\",\"created_at\":\"2024-01-02T03:04:05Z\"}\n\n" +
+				"id: 7\nevent: update\ndata: {\"id\":7,\"sender\":\"andy@example.com\",\"text\":\"seven\",\"created_at\":\"2024-01-02T03:04:05Z\"}\n\n" +
+				"id: 8\nevent: update\ndata: {\"id\":8,\"sender\":\"andy@example.com\",\"text\":\"eight\",\"created_at\":\"2024-01-02T03:04:05Z\"}\n\n",
+		},
+		{
+			name:        "invalid Last-Event-ID",
+			email:       "kate@example.com",
+			lastEventID: "abc",
+			setupMock: func(mockController *mocks.MockUpdateControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Invalid request format","details":"Last-Event-ID must be an update ID"}}`,
+		},
+		{
+			name:  "missing authenticated email",
+			query: "",
+			setupMock: func(mockController *mocks.MockUpdateControllerInterface) {
+				// No mock expectations needed as the request is refused before controller calls
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"success":false,"error":{"type":"UNAUTHORIZED","message":"Request is not authenticated"}}`,
+		},
+		{
+			name:  "email parameter is not trusted",
+			query: "?email=kate@example.com",
+			setupMock: func(mockController *mocks.MockUpdateControllerInterface) {
+				// No mock expectations needed as the request is refused before controller calls
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"success":false,"error":{"type":"UNAUTHORIZED","message":"Request is not authenticated"}}`,
+		},
+		{
+			name:  "invalid authenticated email",
+			email: "kate",
+			setupMock: func(mockController *mocks.MockUpdateControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"email: must be valid email address"}}`,
+		},
+		{
+			name:  "user not found",
+			email: "nonexistent@example.com",
+			setupMock: func(mockController *mocks.MockUpdateControllerInterface) {
+				mockController.EXPECT().SubscribeUpdates("nonexistent@example.com", 0, StreamBacklogPageSize).Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", "nonexistent@example.com"))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"success":false,"error":{"type":"NOT_FOUND","message":"User not found: nonexistent@example.com"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := mocks.NewMockUpdateControllerInterface(ctrl)
+			tt.setupMock(mockController)
+
+			handler := NewUpdateHandler(mockController)
+
+			router := gin.New()
+			router.GET("/stream", handler.StreamUpdates)
+
+			req, err := http.NewRequest(http.MethodGet, "/stream"+tt.query, nil)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			if tt.email != "" {
+				req.Header.Set(AuthenticatedEmailHeader, tt.email)
+			}
+			if tt.lastEventID != "" {
+				req.Header.Set("Last-Event-ID", tt.lastEventID)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+				assert.Equal(t, tt.expectedBody, w.Body.String())
+				return
+			}
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestStreamUpdates_ClientDisconnect(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	closed := make(chan struct{})
+	mockController := mocks.NewMockUpdateControllerInterface(ctrl)
+	mockController.EXPECT().SubscribeUpdates("kate@example.com", 0, StreamBacklogPageSize).Return(&entities.UpdateStream{
+		Replay:  func(send func(page []*entities.Update) error) error { return nil },
+		Updates: make(chan *entities.Update),
+		Close:   func() { close(closed) },
+	}, nil)
+
+	handler := NewUpdateHandler(mockController)
+	handler.heartbeat = 5 * time.Millisecond
+
+	router := gin.New()
+	router.GET("/stream", handler.StreamUpdates)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/stream", nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	req.Header.Set(AuthenticatedEmailHeader, "kate@example.com")
+
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("failed to open stream: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// Idle streams send heartbeat comments
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, ": ping\n", line)
+
+	cancel()
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("expected the subscription to be closed after the client disconnected")
+	}
+}
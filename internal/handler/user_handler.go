@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"assignment/internal/domain/entities"
 	"assignment/internal/domain/interfaces"
 	"assignment/pkg/errors"
 	"assignment/pkg/validator"
@@ -19,7 +20,12 @@ func NewUserHandler(userController interfaces.UserControllerInterface) *UserHand
 	}
 }
 
+// CreateFriendships is deprecated: friendships need the target's consent, so it
+// sends a friend request from the first user to the second, as the
+// friend-requests route does, and is kept for existing clients
 func (h *UserHandler) CreateFriendships(c *gin.Context) {
+	c.Header("Deprecation", "true")
+
 	var req CreateFriendshipRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		errors.SendBadRequest(c, "Invalid request format", err.Error())
@@ -32,47 +38,169 @@ func (h *UserHandler) CreateFriendships(c *gin.Context) {
 		return
 	}
 
-	if err := h.userController.CreateFriendship(req.Friends[0], req.Friends[1]); err != nil {
+	if _, err := h.userController.SendFriendRequest(req.Friends[0], req.Friends[1]); err != nil {
 		errors.HandleError(c, err)
 		return
 	}
 
+	// Existing clients expect the response the route had before it was deprecated
 	c.JSON(http.StatusOK, gin.H{"success": true})
 }
 
-func (h *UserHandler) GetFriendList(c *gin.Context) {
-	var req GetFriendListRequest
+func (h *UserHandler) DeleteFriendship(c *gin.Context) {
+	var req DeleteFriendshipRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		errors.SendBadRequest(c, "Invalid request format", err.Error())
 		return
 	}
 
 	v := validator.New()
-	if ValidateGetFriendListRequest(v, &req); !v.Valid() {
+	if ValidateDeleteFriendshipRequest(v, &req); !v.Valid() {
 		errors.HandleValidationErrors(c, v.Errors)
 		return
 	}
 
-	friends, err := h.userController.GetFriendList(req.Email)
-	if err != nil {
+	if err := h.userController.DeleteFriendship(req.Friends[0], req.Friends[1]); err != nil {
 		errors.HandleError(c, err)
 		return
 	}
 
-	friendEmails := make([]string, len(friends))
-	for i, friend := range friends {
-		friendEmails[i] = friend.Email
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func (h *UserHandler) SendFriendRequest(c *gin.Context) {
+	var req FriendRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	v := validator.New()
+	if ValidateFriendRequestRequest(v, &req); !v.Valid() {
+		errors.HandleValidationErrors(c, v.Errors)
+		return
+	}
+
+	request, err := h.userController.SendFriendRequest(req.Requestor, req.Target)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
 	}
 
-	response := FriendListResponse{
+	response := FriendRequestResponse{
 		Success: true,
-		Friends: friendEmails,
-		Count:   len(friendEmails),
+		Request: toFriendRequestItem(request),
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+func (h *UserHandler) GetIncomingFriendRequests(c *gin.Context) {
+	h.getFriendRequests(c, h.userController.GetIncomingFriendRequests)
+}
+
+func (h *UserHandler) GetOutgoingFriendRequests(c *gin.Context) {
+	h.getFriendRequests(c, h.userController.GetOutgoingFriendRequests)
+}
+
+func (h *UserHandler) getFriendRequests(c *gin.Context, list func(email string) ([]*entities.FriendRequest, error)) {
+	var req GetFriendRequestsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	v := validator.New()
+	if ValidateGetFriendRequestsRequest(v, &req); !v.Valid() {
+		errors.HandleValidationErrors(c, v.Errors)
+		return
+	}
+
+	requests, err := list(req.Email)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	items := make([]FriendRequestItem, len(requests))
+	for i, request := range requests {
+		items[i] = toFriendRequestItem(request)
+	}
+
+	response := FriendRequestListResponse{
+		Success:  true,
+		Requests: items,
+		Count:    len(items),
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+func (h *UserHandler) AcceptFriendRequest(c *gin.Context) {
+	h.respondToFriendRequest(c, h.userController.AcceptFriendRequest)
+}
+
+func (h *UserHandler) RejectFriendRequest(c *gin.Context) {
+	h.respondToFriendRequest(c, h.userController.RejectFriendRequest)
+}
+
+func (h *UserHandler) CancelFriendRequest(c *gin.Context) {
+	h.respondToFriendRequest(c, h.userController.CancelFriendRequest)
+}
+
+func (h *UserHandler) respondToFriendRequest(c *gin.Context, action func(requestorEmail, targetEmail string) error) {
+	var req FriendRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	v := validator.New()
+	if ValidateFriendRequestRequest(v, &req); !v.Valid() {
+		errors.HandleValidationErrors(c, v.Errors)
+		return
+	}
+
+	if err := action(req.Requestor, req.Target); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func toFriendRequestItem(request *entities.FriendRequest) FriendRequestItem {
+	return FriendRequestItem{
+		Requestor: request.Requester.Email,
+		Target:    request.Addressee.Email,
+		Status:    string(request.Status),
+		CreatedAt: request.CreatedAt,
+	}
+}
+
+func (h *UserHandler) GetFriendList(c *gin.Context) {
+	var req GetFriendListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	v := validator.New()
+	if ValidateGetFriendListRequest(v, &req); !v.Valid() {
+		errors.HandleValidationErrors(c, v.Errors)
+		return
+	}
+
+	limit := req.PageLimit()
+	friends, err := h.userController.GetFriendList(req.Email, req.Cursor, limit)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newFriendListResponse(friends, limit))
+}
+
 func (h *UserHandler) GetCommonFriends(c *gin.Context) {
 	var req GetCommonFriendsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -86,21 +214,202 @@ func (h *UserHandler) GetCommonFriends(c *gin.Context) {
 		return
 	}
 
-	friends, err := h.userController.GetCommonFriends(req.Friends[0], req.Friends[1])
+	limit := req.PageLimit()
+	friends, err := h.userController.GetCommonFriends(req.Friends, req.Cursor, limit)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCommonFriendsResponse(friends, limit))
+}
+
+// GetUserFriends serves GET /users/:email/friends, the cacheable form of GetFriendList
+func (h *UserHandler) GetUserFriends(c *gin.Context) {
+	h.getUserList(c, h.userController.GetFriendList, func(page *entities.UserPage, limit int) any {
+		return newFriendListResponse(page, limit)
+	})
+}
+
+// GetUserCommonFriends serves GET /users/:email/friends/common?with=, the
+// cacheable form of GetCommonFriends
+func (h *UserHandler) GetUserCommonFriends(c *gin.Context) {
+	email := c.Param("email")
+
+	var req GetUserCommonFriendsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		errors.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	v := validator.New()
+	if ValidateGetUserCommonFriendsRequest(v, email, &req); !v.Valid() {
+		errors.HandleValidationErrors(c, v.Errors)
+		return
+	}
+
+	limit := req.PageLimit()
+	friends, err := h.userController.GetCommonFriends(append([]string{email}, req.With...), req.Cursor, limit)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	sendCacheableJSON(c, newCommonFriendsResponse(friends, limit))
+}
+
+func (h *UserHandler) GetUserSubscribers(c *gin.Context) {
+	h.getUserList(c, h.userController.GetSubscribers, func(page *entities.UserPage, limit int) any {
+		return SubscriberListResponse{Success: true, Subscribers: userEmails(page.Users), PageInfo: newPageInfo(page, limit)}
+	})
+}
+
+func (h *UserHandler) GetUserSubscriptions(c *gin.Context) {
+	h.getUserList(c, h.userController.GetSubscriptions, func(page *entities.UserPage, limit int) any {
+		return SubscriptionListResponse{Success: true, Subscriptions: userEmails(page.Users), PageInfo: newPageInfo(page, limit)}
+	})
+}
+
+func (h *UserHandler) GetUserBlocks(c *gin.Context) {
+	h.getUserList(c, h.userController.GetBlockedUsers, func(page *entities.UserPage, limit int) any {
+		return BlockListResponse{Success: true, Blocked: userEmails(page.Users), PageInfo: newPageInfo(page, limit)}
+	})
+}
+
+// getUserList serves a page of a GET /users/:email/... list of related users
+func (h *UserHandler) getUserList(c *gin.Context, list func(email, cursor string, limit int) (*entities.UserPage, error), respond func(page *entities.UserPage, limit int) any) {
+	email := c.Param("email")
+
+	var req PageParams
+	if err := c.ShouldBindQuery(&req); err != nil {
+		errors.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	v := validator.New()
+	validator.ValidateEmail(v, email)
+	if ValidatePageParams(v, &req); !v.Valid() {
+		errors.HandleValidationErrors(c, v.Errors)
+		return
+	}
+
+	limit := req.PageLimit()
+	page, err := list(email, req.Cursor, limit)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	sendCacheableJSON(c, respond(page, limit))
+}
+
+func newFriendListResponse(page *entities.UserPage, limit int) FriendListResponse {
+	return FriendListResponse{
+		Success:  true,
+		Friends:  userEmails(page.Users),
+		PageInfo: newPageInfo(page, limit),
+	}
+}
+
+func newCommonFriendsResponse(page *entities.UserPage, limit int) CommonFriendsResponse {
+	return CommonFriendsResponse{
+		Success:  true,
+		Friends:  userEmails(page.Users),
+		PageInfo: newPageInfo(page, limit),
+	}
+}
+
+func newPageInfo(page *entities.UserPage, limit int) PageInfo {
+	return PageInfo{
+		Count:      len(page.Users),
+		Total:      page.Total,
+		Limit:      limit,
+		NextCursor: page.NextCursor,
+	}
+}
+
+func userEmails(users []*entities.User) []string {
+	emails := make([]string, len(users))
+	for i, user := range users {
+		emails[i] = user.Email
+	}
+	return emails
+}
+
+func (h *UserHandler) GetFriendshipPath(c *gin.Context) {
+	var req GetFriendshipPathRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	v := validator.New()
+	if ValidateGetFriendshipPathRequest(v, &req); !v.Valid() {
+		errors.HandleValidationErrors(c, v.Errors)
+		return
+	}
+
+	maxDepth := req.MaxDepth
+	if maxDepth == 0 {
+		maxDepth = validator.DefaultPathMaxDepth
+	}
+
+	path, err := h.userController.GetFriendshipPath(req.Requestor, req.Target, maxDepth)
 	if err != nil {
 		errors.HandleError(c, err)
 		return
 	}
 
-	friendEmails := make([]string, len(friends))
-	for i, friend := range friends {
-		friendEmails[i] = friend.Email
+	emails := make([]string, len(path))
+	for i, user := range path {
+		emails[i] = user.Email
 	}
 
-	response := CommonFriendsResponse{
+	response := FriendshipPathResponse{
 		Success: true,
-		Friends: friendEmails,
-		Count:   len(friendEmails),
+		Path:    emails,
+		Hops:    len(path) - 1,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *UserHandler) GetFriendSuggestions(c *gin.Context) {
+	var req GetFriendSuggestionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	v := validator.New()
+	if ValidateGetFriendSuggestionsRequest(v, &req); !v.Valid() {
+		errors.HandleValidationErrors(c, v.Errors)
+		return
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = validator.DefaultSuggestionLimit
+	}
+
+	suggestions, err := h.userController.GetFriendSuggestions(req.Email, limit)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	items := make([]FriendSuggestionItem, len(suggestions))
+	for i, suggestion := range suggestions {
+		items[i] = FriendSuggestionItem{
+			Email:         suggestion.User.Email,
+			MutualFriends: suggestion.MutualFriends,
+		}
+	}
+
+	response := FriendSuggestionsResponse{
+		Success:     true,
+		Suggestions: items,
+		Count:       len(items),
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -127,6 +436,93 @@ func (h *UserHandler) CreateSubscription(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"success": true})
 }
 
+func (h *UserHandler) DeleteSubscription(c *gin.Context) {
+	var req SubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	v := validator.New()
+	if ValidateSubscriptionRequest(v, &req); !v.Valid() {
+		errors.HandleValidationErrors(c, v.Errors)
+		return
+	}
+
+	if err := h.userController.DeleteSubscription(req.Requestor, req.Target); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func (h *UserHandler) GetSubscriptionFilter(c *gin.Context) {
+	var req GetSubscriptionFilterRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		errors.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	v := validator.New()
+	if ValidateGetSubscriptionFilterRequest(v, &req); !v.Valid() {
+		errors.HandleValidationErrors(c, v.Errors)
+		return
+	}
+
+	filter, err := h.userController.GetSubscriptionFilter(req.Requestor, req.Target)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newSubscriptionFilterResponse(req.Requestor, req.Target, filter))
+}
+
+func (h *UserHandler) UpdateSubscriptionFilter(c *gin.Context) {
+	var req UpdateSubscriptionFilterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	v := validator.New()
+	if ValidateUpdateSubscriptionFilterRequest(v, &req); !v.Valid() {
+		errors.HandleValidationErrors(c, v.Errors)
+		return
+	}
+
+	filter, err := h.userController.UpdateSubscriptionFilter(req.Requestor, req.Target, &entities.SubscriptionFilter{
+		Include: req.Include,
+		Exclude: req.Exclude,
+	})
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newSubscriptionFilterResponse(req.Requestor, req.Target, filter))
+}
+
+func newSubscriptionFilterResponse(requestor, target string, filter *entities.SubscriptionFilter) SubscriptionFilterResponse {
+	include := filter.Include
+	if include == nil {
+		include = []string{}
+	}
+	exclude := filter.Exclude
+	if exclude == nil {
+		exclude = []string{}
+	}
+
+	return SubscriptionFilterResponse{
+		Success:   true,
+		Requestor: requestor,
+		Target:    target,
+		Include:   include,
+		Exclude:   exclude,
+	}
+}
+
 func (h *UserHandler) CreateBlock(c *gin.Context) {
 	var req CreateBlockRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -148,6 +544,149 @@ func (h *UserHandler) CreateBlock(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"success": true})
 }
 
+func (h *UserHandler) DeleteBlock(c *gin.Context) {
+	var req DeleteBlockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	v := validator.New()
+	if ValidateDeleteBlockRequest(v, &req); !v.Valid() {
+		errors.HandleValidationErrors(c, v.Errors)
+		return
+	}
+
+	if err := h.userController.DeleteBlock(req.Requestor, req.Target, req.Restore); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func (h *UserHandler) CreateMute(c *gin.Context) {
+	var req CreateMuteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	v := validator.New()
+	if ValidateCreateMuteRequest(v, &req); !v.Valid() {
+		errors.HandleValidationErrors(c, v.Errors)
+		return
+	}
+
+	mute, err := h.userController.CreateMute(req.Requestor, req.Target, req.ExpiresAt)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	response := MuteResponse{
+		Success:   true,
+		Requestor: mute.Muter.Email,
+		Target:    mute.Muted.Email,
+		ExpiresAt: mute.ExpiresAt,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *UserHandler) DeleteMute(c *gin.Context) {
+	var req DeleteMuteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	v := validator.New()
+	if ValidateDeleteMuteRequest(v, &req); !v.Valid() {
+		errors.HandleValidationErrors(c, v.Errors)
+		return
+	}
+
+	if err := h.userController.DeleteMute(req.Requestor, req.Target); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func (h *UserHandler) GetMutes(c *gin.Context) {
+	var req GetMutesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	v := validator.New()
+	if ValidateGetMutesRequest(v, &req); !v.Valid() {
+		errors.HandleValidationErrors(c, v.Errors)
+		return
+	}
+
+	mutes, err := h.userController.GetMutes(req.Email)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	items := make([]MuteItem, len(mutes))
+	for i, mute := range mutes {
+		items[i] = MuteItem{
+			Email:     mute.Muted.Email,
+			ExpiresAt: mute.ExpiresAt,
+			CreatedAt: mute.CreatedAt,
+		}
+	}
+
+	response := MuteListResponse{
+		Success: true,
+		Mutes:   items,
+		Count:   len(items),
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *UserHandler) GetRelationship(c *gin.Context) {
+	var req GetRelationshipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	v := validator.New()
+	if ValidateGetRelationshipRequest(v, &req); !v.Valid() {
+		errors.HandleValidationErrors(c, v.Errors)
+		return
+	}
+
+	relationship, err := h.userController.GetRelationship(req.Requestor, req.Target)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	response := RelationshipResponse{
+		Success:   true,
+		Requestor: req.Requestor,
+		Target:    req.Target,
+		Relationship: RelationshipStatus{
+			Friends:                   relationship.AreFriends,
+			RequestorSubscribesTarget: relationship.ASubscribesToB,
+			TargetSubscribesRequestor: relationship.BSubscribesToA,
+			RequestorBlocksTarget:     relationship.ABlocksB,
+			TargetBlocksRequestor:     relationship.BBlocksA,
+		},
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 func (h *UserHandler) GetRecipients(c *gin.Context) {
 	var req GetRecipientsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -161,21 +700,228 @@ func (h *UserHandler) GetRecipients(c *gin.Context) {
 		return
 	}
 
-	recipients, err := h.userController.GetRecipients(req.Sender, req.Text)
-	if err != nil {
-		errors.HandleError(c, err)
+	if req.Explain {
+		h.explainRecipients(c, &req)
 		return
 	}
 
-	recipientEmails := make([]string, len(recipients))
-	for i, recipient := range recipients {
-		recipientEmails[i] = recipient.Email
+	limit := req.PageLimit()
+	recipients, err := h.userController.GetRecipients(req.Sender, req.Text, req.Cursor, limit)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
 	}
 
 	response := RecipientsResponse{
 		Success:    true,
-		Recipients: recipientEmails,
+		Recipients: userEmails(recipients.Users),
+		PageInfo:   newPageInfo(recipients, limit),
 	}
 
 	c.JSON(http.StatusOK, response)
 }
+
+func (h *UserHandler) explainRecipients(c *gin.Context, req *GetRecipientsRequest) {
+	limit := req.PageLimit()
+	explanation, err := h.userController.ExplainRecipients(req.Sender, req.Text, req.Cursor, limit)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	recipients := make([]RecipientDetail, len(explanation.Recipients))
+	for i, recipient := range explanation.Recipients {
+		reasons := make([]string, len(recipient.Reasons))
+		for j, reason := range recipient.Reasons {
+			reasons[j] = string(reason)
+		}
+		recipients[i] = RecipientDetail{
+			Email:   recipient.User.Email,
+			Reasons: reasons,
+		}
+	}
+
+	droppedMentions := make([]DroppedMentionItem, len(explanation.DroppedMentions))
+	for i, mention := range explanation.DroppedMentions {
+		droppedMentions[i] = DroppedMentionItem{
+			Email:  mention.Email,
+			Handle: mention.Handle,
+			Reason: string(mention.Reason),
+		}
+	}
+
+	response := RecipientsExplanationResponse{
+		Success:         true,
+		Recipients:      recipients,
+		DroppedMentions: droppedMentions,
+		PageInfo: PageInfo{
+			Count:      len(recipients),
+			Total:      explanation.Total,
+			Limit:      limit,
+			NextCursor: explanation.NextCursor,
+		},
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *UserHandler) CreateUser(c *gin.Context) {
+	var req CreateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	v := validator.New()
+	if ValidateCreateUserRequest(v, &req); !v.Valid() {
+		errors.HandleValidationErrors(c, v.Errors)
+		return
+	}
+
+	user, err := h.userController.CreateUser(req.Email)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	response := UserResponse{
+		Success: true,
+		Email:   user.Email,
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+func (h *UserHandler) SetUsername(c *gin.Context) {
+	var req SetUsernameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	v := validator.New()
+	if ValidateSetUsernameRequest(v, &req); !v.Valid() {
+		errors.HandleValidationErrors(c, v.Errors)
+		return
+	}
+
+	if err := h.userController.SetUsername(req.Email, req.Username); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func (h *UserHandler) GetSettings(c *gin.Context) {
+	var req GetSettingsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		errors.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	v := validator.New()
+	if ValidateGetSettingsRequest(v, &req); !v.Valid() {
+		errors.HandleValidationErrors(c, v.Errors)
+		return
+	}
+
+	settings, err := h.userController.GetSettings(req.Email)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newSettingsResponse(settings))
+}
+
+func (h *UserHandler) UpdateSettings(c *gin.Context) {
+	var req UpdateSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	v := validator.New()
+	if ValidateUpdateSettingsRequest(v, &req); !v.Valid() {
+		errors.HandleValidationErrors(c, v.Errors)
+		return
+	}
+
+	settings, err := h.userController.UpdateSettings(req.Email, entities.MentionPolicy(req.MentionPolicy))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newSettingsResponse(settings))
+}
+
+func newSettingsResponse(settings *entities.UserSettings) SettingsResponse {
+	return SettingsResponse{
+		Success:       true,
+		Email:         settings.User.Email,
+		MentionPolicy: string(settings.MentionPolicy),
+	}
+}
+
+func (h *UserHandler) GetUser(c *gin.Context) {
+	email := c.Param("email")
+
+	v := validator.New()
+	if validator.ValidateEmail(v, email); !v.Valid() {
+		errors.HandleValidationErrors(c, v.Errors)
+		return
+	}
+
+	user, err := h.userController.GetUser(email)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	response := UserResponse{
+		Success: true,
+		Email:   user.Email,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *UserHandler) GetUsers(c *gin.Context) {
+	users, err := h.userController.GetUsers()
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	userEmails := make([]string, len(users))
+	for i, user := range users {
+		userEmails[i] = user.Email
+	}
+
+	response := UserListResponse{
+		Success: true,
+		Users:   userEmails,
+		Count:   len(userEmails),
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *UserHandler) DeleteUser(c *gin.Context) {
+	email := c.Param("email")
+
+	v := validator.New()
+	if validator.ValidateEmail(v, email); !v.Valid() {
+		errors.HandleValidationErrors(c, v.Errors)
+		return
+	}
+
+	if err := h.userController.DeleteUser(email); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
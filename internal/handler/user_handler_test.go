@@ -4,10 +4,12 @@ import (
 	"assignment/internal/domain/entities"
 	"assignment/mocks"
 	"assignment/pkg/errors"
+	"assignment/pkg/validator"
 	"bytes"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -20,6 +22,8 @@ func TestCreateFriendships(t *testing.T) {
 
 	gin.SetMode(gin.TestMode)
 
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
 	tests := []struct {
 		name           string
 		body           string
@@ -31,7 +35,13 @@ func TestCreateFriendships(t *testing.T) {
 			name: "success",
 			body: `{"friends":["andy@example.com", "john@example.com"]}`,
 			setupMock: func(mockController *mocks.MockUserControllerInterface) {
-				mockController.EXPECT().CreateFriendship("andy@example.com", "john@example.com").Return(nil)
+				mockController.EXPECT().SendFriendRequest("andy@example.com", "john@example.com").Return(&entities.FriendRequest{
+					ID:        1,
+					Requester: &entities.User{ID: 1, Email: "andy@example.com"},
+					Addressee: &entities.User{ID: 2, Email: "john@example.com"},
+					Status:    entities.FriendRequestPending,
+					CreatedAt: createdAt,
+				}, nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedBody:   `{"success":true}`,
@@ -49,19 +59,19 @@ func TestCreateFriendships(t *testing.T) {
 			name: "user not found error",
 			body: `{"friends":["andy@example.com", "john@example.com"]}`,
 			setupMock: func(mockController *mocks.MockUserControllerInterface) {
-				mockController.EXPECT().CreateFriendship("andy@example.com", "john@example.com").Return(errors.Newf(errors.ErrorTypeNotFound, "User with email '%s' not found", "andy@example.com"))
+				mockController.EXPECT().SendFriendRequest("andy@example.com", "john@example.com").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User with email '%s' not found", "andy@example.com"))
 			},
 			expectedStatus: http.StatusNotFound,
 			expectedBody:   `{"success":false,"error":{"type":"NOT_FOUND","message":"User with email 'andy@example.com' not found"}}`,
 		},
 		{
-			name: "cannot friend self",
+			name: "already friends",
 			body: `{"friends":["andy@example.com", "john@example.com"]}`,
 			setupMock: func(mockController *mocks.MockUserControllerInterface) {
-				mockController.EXPECT().CreateFriendship("andy@example.com", "john@example.com").Return(errors.New(errors.ErrorTypeBusiness, "Cannot add yourself as a friend"))
+				mockController.EXPECT().SendFriendRequest("andy@example.com", "john@example.com").Return(nil, errors.ErrAlreadyFriends)
 			},
-			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"success":false,"error":{"type":"BUSINESS_ERROR","message":"Cannot add yourself as a friend"}}`,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"success":false,"error":{"type":"CONFLICT","message":"Users are already friends"}}`,
 		},
 		{
 			name: "invalid json",
@@ -95,10 +105,16 @@ func TestCreateFriendships(t *testing.T) {
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
 			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			assert.Equal(t, "true", w.Header().Get("Deprecation"))
 		})
 	}
 }
 
+// asUserPage wraps users as the only page of their list
+func asUserPage(users []*entities.User) *entities.UserPage {
+	return &entities.UserPage{Users: users, Total: len(users)}
+}
+
 func TestGetFriendList(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -116,28 +132,59 @@ func TestGetFriendList(t *testing.T) {
 			name: "success with friends",
 			body: `{"email":"andy@example.com"}`,
 			setupMock: func(mockController *mocks.MockUserControllerInterface) {
-				mockController.EXPECT().GetFriendList("andy@example.com").Return([]*entities.User{
+				mockController.EXPECT().GetFriendList("andy@example.com", "", 50).Return(asUserPage([]*entities.User{
 					{ID: 1, Email: "john@example.com"},
 					{ID: 2, Email: "jane@example.com"},
-				}, nil)
+				}), nil)
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   `{"success":true,"friends":["john@example.com","jane@example.com"],"count":2}`,
+			expectedBody:   `{"success":true,"friends":["john@example.com","jane@example.com"],"count":2,"total":2,"limit":50}`,
 		},
 		{
 			name: "success with no friends",
 			body: `{"email":"andy@example.com"}`,
 			setupMock: func(mockController *mocks.MockUserControllerInterface) {
-				mockController.EXPECT().GetFriendList("andy@example.com").Return([]*entities.User{}, nil)
+				mockController.EXPECT().GetFriendList("andy@example.com", "", 50).Return(asUserPage([]*entities.User{}), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"friends":[],"count":0,"total":0,"limit":50}`,
+		},
+		{
+			name: "page with next cursor",
+			body: `{"email":"andy@example.com","cursor":"YWxpY2VAZXhhbXBsZS5jb20","limit":2}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetFriendList("andy@example.com", "YWxpY2VAZXhhbXBsZS5jb20", 2).Return(&entities.UserPage{
+					Users:      []*entities.User{{ID: 1, Email: "jane@example.com"}, {ID: 2, Email: "john@example.com"}},
+					Total:      5,
+					NextCursor: "am9obkBleGFtcGxlLmNvbQ",
+				}, nil)
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   `{"success":true,"friends":[],"count":0}`,
+			expectedBody:   `{"success":true,"friends":["jane@example.com","john@example.com"],"count":2,"total":5,"limit":2,"next_cursor":"am9obkBleGFtcGxlLmNvbQ"}`,
+		},
+		{
+			name: "limit too large",
+			body: `{"email":"andy@example.com","limit":101}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"limit: must not exceed 100"}}`,
+		},
+		{
+			name: "invalid cursor",
+			body: `{"email":"andy@example.com","cursor":"???"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetFriendList("andy@example.com", "???", 50).Return(nil, errors.ErrInvalidCursor)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Invalid cursor"}}`,
 		},
 		{
 			name: "user not found error",
 			body: `{"email":"nonexistent@example.com"}`,
 			setupMock: func(mockController *mocks.MockUserControllerInterface) {
-				mockController.EXPECT().GetFriendList("nonexistent@example.com").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User with email '%s' not found", "nonexistent@example.com"))
+				mockController.EXPECT().GetFriendList("nonexistent@example.com", "", 50).Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User with email '%s' not found", "nonexistent@example.com"))
 			},
 			expectedStatus: http.StatusNotFound,
 			expectedBody:   `{"success":false,"error":{"type":"NOT_FOUND","message":"User with email 'nonexistent@example.com' not found"}}`,
@@ -213,28 +260,28 @@ func TestGetCommonFriends(t *testing.T) {
 			name: "success with common friends",
 			body: `{"friends":["andy@example.com", "john@example.com"]}`,
 			setupMock: func(mockController *mocks.MockUserControllerInterface) {
-				mockController.EXPECT().GetCommonFriends("andy@example.com", "john@example.com").Return([]*entities.User{
+				mockController.EXPECT().GetCommonFriends([]string{"andy@example.com", "john@example.com"}, "", 50).Return(asUserPage([]*entities.User{
 					{ID: 3, Email: "common@example.com"},
 					{ID: 4, Email: "mutual@example.com"},
-				}, nil)
+				}), nil)
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   `{"success":true,"friends":["common@example.com","mutual@example.com"],"count":2}`,
+			expectedBody:   `{"success":true,"friends":["common@example.com","mutual@example.com"],"count":2,"total":2,"limit":50}`,
 		},
 		{
 			name: "success with no common friends",
 			body: `{"friends":["andy@example.com", "john@example.com"]}`,
 			setupMock: func(mockController *mocks.MockUserControllerInterface) {
-				mockController.EXPECT().GetCommonFriends("andy@example.com", "john@example.com").Return([]*entities.User{}, nil)
+				mockController.EXPECT().GetCommonFriends([]string{"andy@example.com", "john@example.com"}, "", 50).Return(asUserPage([]*entities.User{}), nil)
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   `{"success":true,"friends":[],"count":0}`,
+			expectedBody:   `{"success":true,"friends":[],"count":0,"total":0,"limit":50}`,
 		},
 		{
 			name: "user not found error",
 			body: `{"friends":["nonexistent@example.com", "john@example.com"]}`,
 			setupMock: func(mockController *mocks.MockUserControllerInterface) {
-				mockController.EXPECT().GetCommonFriends("nonexistent@example.com", "john@example.com").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User with email '%s' not found", "nonexistent@example.com"))
+				mockController.EXPECT().GetCommonFriends([]string{"nonexistent@example.com", "john@example.com"}, "", 50).Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User with email '%s' not found", "nonexistent@example.com"))
 			},
 			expectedStatus: http.StatusNotFound,
 			expectedBody:   `{"success":false,"error":{"type":"NOT_FOUND","message":"User with email 'nonexistent@example.com' not found"}}`,
@@ -246,7 +293,27 @@ func TestGetCommonFriends(t *testing.T) {
 				// No mock expectations needed as validation happens before controller calls
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"emails count: exactly 2 emails required"}}`,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"emails count: between 2 and 20 emails required"}}`,
+		},
+		{
+			name: "success with more than two users",
+			body: `{"friends":["andy@example.com", "john@example.com", "kate@example.com"]}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetCommonFriends([]string{"andy@example.com", "john@example.com", "kate@example.com"}, "", 50).Return(asUserPage([]*entities.User{
+					{ID: 4, Email: "mutual@example.com"},
+				}), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"friends":["mutual@example.com"],"count":1,"total":1,"limit":50}`,
+		},
+		{
+			name: "too many emails",
+			body: `{"friends":["u1@example.com","u2@example.com","u3@example.com","u4@example.com","u5@example.com","u6@example.com","u7@example.com","u8@example.com","u9@example.com","u10@example.com","u11@example.com","u12@example.com","u13@example.com","u14@example.com","u15@example.com","u16@example.com","u17@example.com","u18@example.com","u19@example.com","u20@example.com","u21@example.com"]}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"emails count: between 2 and 20 emails required"}}`,
 		},
 		{
 			name: "invalid email format",
@@ -577,4 +644,1810 @@ func TestCreateBlock(t *testing.T) {
 			assert.JSONEq(t, tt.expectedBody, w.Body.String())
 		})
 	}
-}
\ No newline at end of file
+}
+func TestCreateUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(mockController *mocks.MockUserControllerInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success",
+			body: `{"email":"new@example.com"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().CreateUser("new@example.com").Return(&entities.User{ID: 6, Email: "new@example.com"}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"success":true,"email":"new@example.com"}`,
+		},
+		{
+			name: "duplicate email",
+			body: `{"email":"andy@example.com"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().CreateUser("andy@example.com").Return(nil, errors.New(errors.ErrorTypeConflict, "Resource already exists").WithDetails("Email address already exists"))
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"success":false,"error":{"type":"CONFLICT","message":"Resource already exists","details":"Email address already exists"}}`,
+		},
+		{
+			name: "invalid email format",
+			body: `{"email":"invalid-email"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"email: must be valid email address"}}`,
+		},
+		{
+			name: "invalid json",
+			body: `{"email": }`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Invalid request format","details":"invalid character '}' looking for beginning of value"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := mocks.NewMockUserControllerInterface(ctrl)
+			tt.setupMock(mockController)
+
+			handler := NewUserHandler(mockController)
+
+			router := gin.New()
+			router.POST("/users", handler.CreateUser)
+
+			req, err := http.NewRequest(http.MethodPost, "/users", bytes.NewBuffer([]byte(tt.body)))
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestSetUsername(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(mockController *mocks.MockUserControllerInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success",
+			body: `{"email":"andy@example.com","username":"andy"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().SetUsername("andy@example.com", "andy").Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true}`,
+		},
+		{
+			name: "username taken",
+			body: `{"email":"andy@example.com","username":"bob"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().SetUsername("andy@example.com", "bob").Return(errors.New(errors.ErrorTypeConflict, "Resource already exists").WithDetails("Username is already taken"))
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"success":false,"error":{"type":"CONFLICT","message":"Resource already exists","details":"Username is already taken"}}`,
+		},
+		{
+			name: "invalid username",
+			body: `{"email":"andy@example.com","username":"andy-b"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"username: must be 3-32 letters, digits or underscores"}}`,
+		},
+		{
+			name: "invalid json",
+			body: `{"email": }`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Invalid request format","details":"invalid character '}' looking for beginning of value"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := mocks.NewMockUserControllerInterface(ctrl)
+			tt.setupMock(mockController)
+
+			handler := NewUserHandler(mockController)
+
+			router := gin.New()
+			router.POST("/user/username", handler.SetUsername)
+
+			req, err := http.NewRequest(http.MethodPost, "/user/username", bytes.NewBuffer([]byte(tt.body)))
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestGetSettings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(mockController *mocks.MockUserControllerInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "success",
+			query: "?email=andy@example.com",
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetSettings("andy@example.com").Return(&entities.UserSettings{
+					User:          &entities.User{ID: 1, Email: "andy@example.com"},
+					MentionPolicy: entities.MentionPolicyEveryone,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"email":"andy@example.com","mention_policy":"everyone"}`,
+		},
+		{
+			name:  "user not found",
+			query: "?email=ghost@example.com",
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetSettings("ghost@example.com").Return(nil, errors.ErrUserNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"success":false,"error":{"type":"NOT_FOUND","message":"User not found"}}`,
+		},
+		{
+			name:  "missing email",
+			query: "",
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"email: must be provided"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := mocks.NewMockUserControllerInterface(ctrl)
+			tt.setupMock(mockController)
+
+			handler := NewUserHandler(mockController)
+
+			router := gin.New()
+			router.GET("/user/settings", handler.GetSettings)
+
+			req, err := http.NewRequest(http.MethodGet, "/user/settings"+tt.query, nil)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestUpdateSettings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(mockController *mocks.MockUserControllerInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success",
+			body: `{"email":"andy@example.com","mention_policy":"friends"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().UpdateSettings("andy@example.com", entities.MentionPolicyFriends).Return(&entities.UserSettings{
+					User:          &entities.User{ID: 1, Email: "andy@example.com"},
+					MentionPolicy: entities.MentionPolicyFriends,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"email":"andy@example.com","mention_policy":"friends"}`,
+		},
+		{
+			name: "unsupported policy",
+			body: `{"email":"andy@example.com","mention_policy":"strangers"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"mention_policy: must be one of everyone, friends or nobody"}}`,
+		},
+		{
+			name: "invalid json",
+			body: `{"email": }`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Invalid request format","details":"invalid character '}' looking for beginning of value"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := mocks.NewMockUserControllerInterface(ctrl)
+			tt.setupMock(mockController)
+
+			handler := NewUserHandler(mockController)
+
+			router := gin.New()
+			router.PUT("/user/settings", handler.UpdateSettings)
+
+			req, err := http.NewRequest(http.MethodPut, "/user/settings", bytes.NewBuffer([]byte(tt.body)))
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestGetUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		email          string
+		setupMock      func(mockController *mocks.MockUserControllerInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "success",
+			email: "andy@example.com",
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetUser("andy@example.com").Return(&entities.User{ID: 1, Email: "andy@example.com"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"email":"andy@example.com"}`,
+		},
+		{
+			name:  "user not found",
+			email: "nonexistent@example.com",
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetUser("nonexistent@example.com").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", "nonexistent@example.com"))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"success":false,"error":{"type":"NOT_FOUND","message":"User not found: nonexistent@example.com"}}`,
+		},
+		{
+			name:  "invalid email format",
+			email: "invalid-email",
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"email: must be valid email address"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := mocks.NewMockUserControllerInterface(ctrl)
+			tt.setupMock(mockController)
+
+			handler := NewUserHandler(mockController)
+
+			router := gin.New()
+			router.GET("/users/:email", handler.GetUser)
+
+			req, err := http.NewRequest(http.MethodGet, "/users/"+tt.email, nil)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestGetUserLists(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	users := []*entities.User{{ID: 2, Email: "john@example.com"}, {ID: 3, Email: "lisa@example.com"}}
+
+	tests := []struct {
+		name           string
+		path           string
+		setupMock      func(mockController *mocks.MockUserControllerInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "friends",
+			path: "/users/andy@example.com/friends",
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetFriendList("andy@example.com", "", 50).Return(asUserPage(users), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"friends":["john@example.com","lisa@example.com"],"count":2,"total":2,"limit":50}`,
+		},
+		{
+			name: "common friends",
+			path: "/users/andy@example.com/friends/common?with=john@example.com&with=lisa@example.com",
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetCommonFriends([]string{"andy@example.com", "john@example.com", "lisa@example.com"}, "", 50).Return(asUserPage(users[:1]), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"friends":["john@example.com"],"count":1,"total":1,"limit":50}`,
+		},
+		{
+			name: "subscribers page",
+			path: "/users/andy@example.com/subscribers?cursor=am9obkBleGFtcGxlLmNvbQ&limit=1",
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetSubscribers("andy@example.com", "am9obkBleGFtcGxlLmNvbQ", 1).Return(&entities.UserPage{
+					Users:      users[1:],
+					Total:      3,
+					NextCursor: "bGlzYUBleGFtcGxlLmNvbQ",
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"subscribers":["lisa@example.com"],"count":1,"total":3,"limit":1,"next_cursor":"bGlzYUBleGFtcGxlLmNvbQ"}`,
+		},
+		{
+			name: "negative limit",
+			path: "/users/andy@example.com/blocks?limit=-1",
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"limit: must not be negative"}}`,
+		},
+		{
+			name: "common friends without with",
+			path: "/users/andy@example.com/friends/common",
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"with: between 1 and 19 emails required"}}`,
+		},
+		{
+			name: "subscribers",
+			path: "/users/andy@example.com/subscribers",
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetSubscribers("andy@example.com", "", 50).Return(asUserPage(users), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"subscribers":["john@example.com","lisa@example.com"],"count":2,"total":2,"limit":50}`,
+		},
+		{
+			name: "subscriptions",
+			path: "/users/andy@example.com/subscriptions",
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetSubscriptions("andy@example.com", "", 50).Return(asUserPage(nil), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"subscriptions":[],"count":0,"total":0,"limit":50}`,
+		},
+		{
+			name: "blocks",
+			path: "/users/andy@example.com/blocks",
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetBlockedUsers("andy@example.com", "", 50).Return(asUserPage(users[1:]), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"blocked":["lisa@example.com"],"count":1,"total":1,"limit":50}`,
+		},
+		{
+			name: "user not found",
+			path: "/users/nonexistent@example.com/friends",
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetFriendList("nonexistent@example.com", "", 50).Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", "nonexistent@example.com"))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"success":false,"error":{"type":"NOT_FOUND","message":"User not found: nonexistent@example.com"}}`,
+		},
+		{
+			name: "invalid email format",
+			path: "/users/invalid-email/blocks",
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"email: must be valid email address"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := mocks.NewMockUserControllerInterface(ctrl)
+			tt.setupMock(mockController)
+
+			handler := NewUserHandler(mockController)
+
+			router := gin.New()
+			router.GET("/users/:email/friends", handler.GetUserFriends)
+			router.GET("/users/:email/friends/common", handler.GetUserCommonFriends)
+			router.GET("/users/:email/subscribers", handler.GetUserSubscribers)
+			router.GET("/users/:email/subscriptions", handler.GetUserSubscriptions)
+			router.GET("/users/:email/blocks", handler.GetUserBlocks)
+
+			req, err := http.NewRequest(http.MethodGet, tt.path, nil)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestGetUserListsRevalidation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	mockController := mocks.NewMockUserControllerInterface(ctrl)
+	mockController.EXPECT().GetFriendList("andy@example.com", "", 50).Return(asUserPage([]*entities.User{{ID: 2, Email: "john@example.com"}}), nil).Times(3)
+
+	router := gin.New()
+	router.GET("/users/:email/friends", NewUserHandler(mockController).GetUserFriends)
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodGet, "/users/andy@example.com/friends", nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := get("")
+	etag := first.Header().Get("ETag")
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.NotEmpty(t, etag)
+	assert.Equal(t, "private, no-cache", first.Header().Get("Cache-Control"))
+
+	notModified := get("W/" + etag)
+	assert.Equal(t, http.StatusNotModified, notModified.Code)
+	assert.Empty(t, notModified.Body.String())
+	assert.Equal(t, etag, notModified.Header().Get("ETag"))
+
+	stale := get(`"stale"`)
+	assert.Equal(t, http.StatusOK, stale.Code)
+	assert.JSONEq(t, `{"success":true,"friends":["john@example.com"],"count":1,"total":1,"limit":50}`, stale.Body.String())
+}
+
+func TestGetUsers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		setupMock      func(mockController *mocks.MockUserControllerInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success with users",
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetUsers().Return([]*entities.User{
+					{ID: 2, Email: "alice@example.com"},
+					{ID: 1, Email: "andy@example.com"},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"users":["alice@example.com","andy@example.com"],"count":2}`,
+		},
+		{
+			name: "success with no users",
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetUsers().Return([]*entities.User{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"users":[],"count":0}`,
+		},
+		{
+			name: "database error",
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetUsers().Return(nil, errors.New(errors.ErrorTypeDatabase, "Failed to fetch users"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"success":false,"error":{"type":"DATABASE_ERROR","message":"Failed to fetch users"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := mocks.NewMockUserControllerInterface(ctrl)
+			tt.setupMock(mockController)
+
+			handler := NewUserHandler(mockController)
+
+			router := gin.New()
+			router.GET("/users", handler.GetUsers)
+
+			req, err := http.NewRequest(http.MethodGet, "/users", nil)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestDeleteUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		email          string
+		setupMock      func(mockController *mocks.MockUserControllerInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "success",
+			email: "andy@example.com",
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().DeleteUser("andy@example.com").Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true}`,
+		},
+		{
+			name:  "user not found",
+			email: "nonexistent@example.com",
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().DeleteUser("nonexistent@example.com").Return(errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", "nonexistent@example.com"))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"success":false,"error":{"type":"NOT_FOUND","message":"User not found: nonexistent@example.com"}}`,
+		},
+		{
+			name:  "invalid email format",
+			email: "invalid-email",
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"email: must be valid email address"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := mocks.NewMockUserControllerInterface(ctrl)
+			tt.setupMock(mockController)
+
+			handler := NewUserHandler(mockController)
+
+			router := gin.New()
+			router.DELETE("/users/:email", handler.DeleteUser)
+
+			req, err := http.NewRequest(http.MethodDelete, "/users/"+tt.email, nil)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestDeleteFriendship(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(mockController *mocks.MockUserControllerInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success",
+			body: `{"friends":["andy@example.com", "john@example.com"]}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().DeleteFriendship("andy@example.com", "john@example.com").Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true}`,
+		},
+		{
+			name: "friendship not found",
+			body: `{"friends":["andy@example.com", "john@example.com"]}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().DeleteFriendship("andy@example.com", "john@example.com").Return(errors.ErrFriendshipNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"success":false,"error":{"type":"NOT_FOUND","message":"Friendship not found"}}`,
+		},
+		{
+			name: "missing email validation",
+			body: `{"friends":["andy@example.com"]}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"emails count: exactly 2 emails required"}}`,
+		},
+		{
+			name: "invalid json",
+			body: `{"friends": [}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Invalid request format","details":"invalid character '}' looking for beginning of value"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := mocks.NewMockUserControllerInterface(ctrl)
+			tt.setupMock(mockController)
+
+			handler := NewUserHandler(mockController)
+
+			router := gin.New()
+			router.DELETE("/friends", handler.DeleteFriendship)
+
+			req, err := http.NewRequest(http.MethodDelete, "/friends", bytes.NewBuffer([]byte(tt.body)))
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestDeleteSubscription(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(mockController *mocks.MockUserControllerInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success",
+			body: `{"requestor":"andy@example.com","target":"john@example.com"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().DeleteSubscription("andy@example.com", "john@example.com").Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true}`,
+		},
+		{
+			name: "subscription not found",
+			body: `{"requestor":"andy@example.com","target":"john@example.com"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().DeleteSubscription("andy@example.com", "john@example.com").Return(errors.ErrSubscriptionNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"success":false,"error":{"type":"NOT_FOUND","message":"Subscription not found"}}`,
+		},
+		{
+			name: "same requestor and target",
+			body: `{"requestor":"andy@example.com","target":"andy@example.com"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"emails: requestor and target cannot be the same"}}`,
+		},
+		{
+			name: "invalid json",
+			body: `{"requestor": }`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Invalid request format","details":"invalid character '}' looking for beginning of value"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := mocks.NewMockUserControllerInterface(ctrl)
+			tt.setupMock(mockController)
+
+			handler := NewUserHandler(mockController)
+
+			router := gin.New()
+			router.DELETE("/subscriptions", handler.DeleteSubscription)
+
+			req, err := http.NewRequest(http.MethodDelete, "/subscriptions", bytes.NewBuffer([]byte(tt.body)))
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestDeleteBlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(mockController *mocks.MockUserControllerInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success without restore",
+			body: `{"requestor":"andy@example.com","target":"john@example.com"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().DeleteBlock("andy@example.com", "john@example.com", false).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true}`,
+		},
+		{
+			name: "success with restore",
+			body: `{"requestor":"andy@example.com","target":"john@example.com","restore":true}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().DeleteBlock("andy@example.com", "john@example.com", true).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true}`,
+		},
+		{
+			name: "block not found",
+			body: `{"requestor":"andy@example.com","target":"john@example.com"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().DeleteBlock("andy@example.com", "john@example.com", false).Return(errors.ErrBlockNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"success":false,"error":{"type":"NOT_FOUND","message":"Block not found"}}`,
+		},
+		{
+			name: "same requestor and target",
+			body: `{"requestor":"andy@example.com","target":"andy@example.com"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"emails: cannot unblock yourself"}}`,
+		},
+		{
+			name: "missing target field",
+			body: `{"requestor":"andy@example.com"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Invalid request format","details":"Key: 'DeleteBlockRequest.Target' Error:Field validation for 'Target' failed on the 'required' tag"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := mocks.NewMockUserControllerInterface(ctrl)
+			tt.setupMock(mockController)
+
+			handler := NewUserHandler(mockController)
+
+			router := gin.New()
+			router.DELETE("/blocks", handler.DeleteBlock)
+
+			req, err := http.NewRequest(http.MethodDelete, "/blocks", bytes.NewBuffer([]byte(tt.body)))
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestUpdateSubscriptionFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(mockController *mocks.MockUserControllerInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success",
+			body: `{"requestor":"andy@example.com","target":"john@example.com","include":["Go","#rust"],"exclude":["spam"]}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().UpdateSubscriptionFilter("andy@example.com", "john@example.com", &entities.SubscriptionFilter{
+					Include: []string{"Go", "#rust"},
+					Exclude: []string{"spam"},
+				}).Return(&entities.SubscriptionFilter{Include: []string{"go", "#rust"}, Exclude: []string{"spam"}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"requestor":"andy@example.com","target":"john@example.com","include":["go","#rust"],"exclude":["spam"]}`,
+		},
+		{
+			name: "clear filters",
+			body: `{"requestor":"andy@example.com","target":"john@example.com"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().UpdateSubscriptionFilter("andy@example.com", "john@example.com", &entities.SubscriptionFilter{}).Return(&entities.SubscriptionFilter{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"requestor":"andy@example.com","target":"john@example.com","include":[],"exclude":[]}`,
+		},
+		{
+			name: "subscription not found",
+			body: `{"requestor":"andy@example.com","target":"john@example.com","include":["go"]}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().UpdateSubscriptionFilter("andy@example.com", "john@example.com", gomock.Any()).Return(nil, errors.ErrSubscriptionNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"success":false,"error":{"type":"NOT_FOUND","message":"Subscription not found"}}`,
+		},
+		{
+			name: "keyword with spaces",
+			body: `{"requestor":"andy@example.com","target":"john@example.com","include":["two words"]}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"include: keywords must be a single word or #hashtag of at most 50 characters"}}`,
+		},
+		{
+			name: "too many keywords",
+			body: `{"requestor":"andy@example.com","target":"john@example.com","exclude":["a","b","c","d","e","f","g","h","i","j","k","l","m","n","o","p","q","r","s","t","u"]}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"exclude: must not contain more than 20 keywords"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := mocks.NewMockUserControllerInterface(ctrl)
+			tt.setupMock(mockController)
+
+			handler := NewUserHandler(mockController)
+
+			router := gin.New()
+			router.PUT("/user/subscriptions/filters", handler.UpdateSubscriptionFilter)
+
+			req, err := http.NewRequest(http.MethodPut, "/user/subscriptions/filters", bytes.NewBuffer([]byte(tt.body)))
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestGetSubscriptionFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(mockController *mocks.MockUserControllerInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "success",
+			query: "?requestor=andy@example.com&target=john@example.com",
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetSubscriptionFilter("andy@example.com", "john@example.com").Return(&entities.SubscriptionFilter{Include: []string{"#go"}, Exclude: []string{}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"requestor":"andy@example.com","target":"john@example.com","include":["#go"],"exclude":[]}`,
+		},
+		{
+			name:  "same requestor and target",
+			query: "?requestor=andy@example.com&target=andy@example.com",
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"emails: requestor and target cannot be the same"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := mocks.NewMockUserControllerInterface(ctrl)
+			tt.setupMock(mockController)
+
+			handler := NewUserHandler(mockController)
+
+			router := gin.New()
+			router.GET("/user/subscriptions/filters", handler.GetSubscriptionFilter)
+
+			req, err := http.NewRequest(http.MethodGet, "/user/subscriptions/filters"+tt.query, nil)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestCreateMute(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	requestor := &entities.User{ID: 1, Email: "andy@example.com"}
+	target := &entities.User{ID: 2, Email: "john@example.com"}
+	expiresAt := time.Date(2030, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(mockController *mocks.MockUserControllerInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success without expiry",
+			body: `{"requestor":"andy@example.com","target":"john@example.com"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().CreateMute("andy@example.com", "john@example.com", nil).Return(&entities.Mute{Muter: requestor, Muted: target}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"requestor":"andy@example.com","target":"john@example.com"}`,
+		},
+		{
+			name: "success with expiry",
+			body: `{"requestor":"andy@example.com","target":"john@example.com","expires_at":"2030-01-02T15:04:05Z"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().CreateMute("andy@example.com", "john@example.com", &expiresAt).Return(&entities.Mute{Muter: requestor, Muted: target, ExpiresAt: &expiresAt}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"requestor":"andy@example.com","target":"john@example.com","expires_at":"2030-01-02T15:04:05Z"}`,
+		},
+		{
+			name: "expiry in the past",
+			body: `{"requestor":"andy@example.com","target":"john@example.com","expires_at":"2020-01-02T15:04:05Z"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().CreateMute("andy@example.com", "john@example.com", gomock.Any()).Return(nil, errors.ErrMuteExpiryInPast)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Mute expiry must be in the future"}}`,
+		},
+		{
+			name: "mute yourself",
+			body: `{"requestor":"andy@example.com","target":"andy@example.com"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"emails: cannot mute yourself"}}`,
+		},
+		{
+			name: "invalid expiry",
+			body: `{"requestor":"andy@example.com","target":"john@example.com","expires_at":"tomorrow"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as binding fails before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Invalid request format","details":"parsing time \"tomorrow\" as \"2006-01-02T15:04:05Z07:00\": cannot parse \"tomorrow\" as \"2006\""}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := mocks.NewMockUserControllerInterface(ctrl)
+			tt.setupMock(mockController)
+
+			handler := NewUserHandler(mockController)
+
+			router := gin.New()
+			router.POST("/user/mutes", handler.CreateMute)
+
+			req, err := http.NewRequest(http.MethodPost, "/user/mutes", bytes.NewBuffer([]byte(tt.body)))
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestGetMutes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	muter := &entities.User{ID: 1, Email: "andy@example.com"}
+	createdAt := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+	expiresAt := time.Date(2030, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(mockController *mocks.MockUserControllerInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success",
+			body: `{"email":"andy@example.com"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetMutes("andy@example.com").Return([]*entities.Mute{
+					{Muter: muter, Muted: &entities.User{ID: 2, Email: "john@example.com"}, CreatedAt: createdAt},
+					{Muter: muter, Muted: &entities.User{ID: 3, Email: "kate@example.com"}, ExpiresAt: &expiresAt, CreatedAt: createdAt},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"success":true,"mutes":[` +
+				`{"email":"john@example.com","created_at":"2025-01-02T15:04:05Z"},` +
+				`{"email":"kate@example.com","expires_at":"2030-01-02T15:04:05Z","created_at":"2025-01-02T15:04:05Z"}],"count":2}`,
+		},
+		{
+			name: "no mutes",
+			body: `{"email":"andy@example.com"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetMutes("andy@example.com").Return([]*entities.Mute{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"mutes":[],"count":0}`,
+		},
+		{
+			name: "invalid email",
+			body: `{"email":"invalid-email"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"email: must be valid email address"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := mocks.NewMockUserControllerInterface(ctrl)
+			tt.setupMock(mockController)
+
+			handler := NewUserHandler(mockController)
+
+			router := gin.New()
+			router.POST("/user/mutes/list", handler.GetMutes)
+
+			req, err := http.NewRequest(http.MethodPost, "/user/mutes/list", bytes.NewBuffer([]byte(tt.body)))
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestSendFriendRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(mockController *mocks.MockUserControllerInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success",
+			body: `{"requestor":"andy@example.com","target":"john@example.com"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().SendFriendRequest("andy@example.com", "john@example.com").Return(&entities.FriendRequest{
+					ID:        1,
+					Requester: &entities.User{ID: 1, Email: "andy@example.com"},
+					Addressee: &entities.User{ID: 2, Email: "john@example.com"},
+					Status:    entities.FriendRequestPending,
+					CreatedAt: createdAt,
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"success":true,"request":{"requestor":"andy@example.com","target":"john@example.com","status":"pending","created_at":"2025-01-02T03:04:05Z"}}`,
+		},
+		{
+			name: "blocked user",
+			body: `{"requestor":"andy@example.com","target":"john@example.com"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().SendFriendRequest("andy@example.com", "john@example.com").Return(nil, errors.ErrUserBlocked)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"success":false,"error":{"type":"FORBIDDEN","message":"Cannot perform action on blocked user"}}`,
+		},
+		{
+			name: "same requestor and target",
+			body: `{"requestor":"andy@example.com","target":"andy@example.com"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"emails: requestor and target cannot be the same"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := mocks.NewMockUserControllerInterface(ctrl)
+			tt.setupMock(mockController)
+
+			handler := NewUserHandler(mockController)
+
+			router := gin.New()
+			router.POST("/friend-requests", handler.SendFriendRequest)
+
+			req, err := http.NewRequest(http.MethodPost, "/friend-requests", bytes.NewBuffer([]byte(tt.body)))
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestGetIncomingFriendRequests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(mockController *mocks.MockUserControllerInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success with requests",
+			body: `{"email":"john@example.com"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetIncomingFriendRequests("john@example.com").Return([]*entities.FriendRequest{
+					{
+						ID:        1,
+						Requester: &entities.User{ID: 1, Email: "andy@example.com"},
+						Addressee: &entities.User{ID: 2, Email: "john@example.com"},
+						Status:    entities.FriendRequestPending,
+						CreatedAt: createdAt,
+					},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"requests":[{"requestor":"andy@example.com","target":"john@example.com","status":"pending","created_at":"2025-01-02T03:04:05Z"}],"count":1}`,
+		},
+		{
+			name: "success with no requests",
+			body: `{"email":"john@example.com"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetIncomingFriendRequests("john@example.com").Return([]*entities.FriendRequest{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"requests":[],"count":0}`,
+		},
+		{
+			name: "invalid email",
+			body: `{"email":"invalid-email"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"email: must be valid email address"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := mocks.NewMockUserControllerInterface(ctrl)
+			tt.setupMock(mockController)
+
+			handler := NewUserHandler(mockController)
+
+			router := gin.New()
+			router.POST("/friend-requests/incoming", handler.GetIncomingFriendRequests)
+
+			req, err := http.NewRequest(http.MethodPost, "/friend-requests/incoming", bytes.NewBuffer([]byte(tt.body)))
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestRespondToFriendRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		setupMock      func(mockController *mocks.MockUserControllerInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "accept success",
+			path: "/friend-requests/accept",
+			body: `{"requestor":"john@example.com","target":"andy@example.com"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().AcceptFriendRequest("john@example.com", "andy@example.com").Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true}`,
+		},
+		{
+			name: "accept blocked",
+			path: "/friend-requests/accept",
+			body: `{"requestor":"john@example.com","target":"andy@example.com"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().AcceptFriendRequest("john@example.com", "andy@example.com").Return(errors.ErrUserBlocked)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"success":false,"error":{"type":"FORBIDDEN","message":"Cannot perform action on blocked user"}}`,
+		},
+		{
+			name: "reject without pending request",
+			path: "/friend-requests/reject",
+			body: `{"requestor":"john@example.com","target":"andy@example.com"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().RejectFriendRequest("john@example.com", "andy@example.com").Return(errors.ErrFriendRequestNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"success":false,"error":{"type":"NOT_FOUND","message":"Pending friend request not found"}}`,
+		},
+		{
+			name: "cancel success",
+			path: "/friend-requests/cancel",
+			body: `{"requestor":"andy@example.com","target":"john@example.com"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().CancelFriendRequest("andy@example.com", "john@example.com").Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true}`,
+		},
+		{
+			name: "invalid json",
+			path: "/friend-requests/accept",
+			body: `{"requestor": }`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Invalid request format","details":"invalid character '}' looking for beginning of value"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := mocks.NewMockUserControllerInterface(ctrl)
+			tt.setupMock(mockController)
+
+			handler := NewUserHandler(mockController)
+
+			router := gin.New()
+			router.POST("/friend-requests/accept", handler.AcceptFriendRequest)
+			router.POST("/friend-requests/reject", handler.RejectFriendRequest)
+			router.POST("/friend-requests/cancel", handler.CancelFriendRequest)
+
+			req, err := http.NewRequest(http.MethodPost, tt.path, bytes.NewBuffer([]byte(tt.body)))
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestGetRelationship(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(mockController *mocks.MockUserControllerInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success",
+			body: `{"requestor":"andy@example.com","target":"john@example.com"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetRelationship("andy@example.com", "john@example.com").Return(&entities.Relationship{
+					AreFriends:     true,
+					ASubscribesToB: true,
+					BBlocksA:       false,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"requestor":"andy@example.com","target":"john@example.com","relationship":{"friends":true,"requestor_subscribes_target":true,"target_subscribes_requestor":false,"requestor_blocks_target":false,"target_blocks_requestor":false}}`,
+		},
+		{
+			name: "user not found",
+			body: `{"requestor":"andy@example.com","target":"nonexistent@example.com"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetRelationship("andy@example.com", "nonexistent@example.com").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", "nonexistent@example.com"))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"success":false,"error":{"type":"NOT_FOUND","message":"User not found: nonexistent@example.com"}}`,
+		},
+		{
+			name: "same requestor and target",
+			body: `{"requestor":"andy@example.com","target":"andy@example.com"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"emails: requestor and target cannot be the same"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := mocks.NewMockUserControllerInterface(ctrl)
+			tt.setupMock(mockController)
+
+			handler := NewUserHandler(mockController)
+
+			router := gin.New()
+			router.POST("/relationship", handler.GetRelationship)
+
+			req, err := http.NewRequest(http.MethodPost, "/relationship", bytes.NewBuffer([]byte(tt.body)))
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestGetFriendSuggestions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(mockController *mocks.MockUserControllerInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success with default limit",
+			body: `{"email":"andy@example.com"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetFriendSuggestions("andy@example.com", validator.DefaultSuggestionLimit).Return([]*entities.FriendSuggestion{
+					{User: &entities.User{ID: 4, Email: "kate@example.com"}, MutualFriends: 2},
+					{User: &entities.User{ID: 5, Email: "lisa@example.com"}, MutualFriends: 1},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"suggestions":[{"email":"kate@example.com","mutual_friends":2},{"email":"lisa@example.com","mutual_friends":1}],"count":2}`,
+		},
+		{
+			name: "success with explicit limit and no suggestions",
+			body: `{"email":"andy@example.com","limit":3}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetFriendSuggestions("andy@example.com", 3).Return([]*entities.FriendSuggestion{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"suggestions":[],"count":0}`,
+		},
+		{
+			name: "limit too large",
+			body: `{"email":"andy@example.com","limit":100}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"limit: must not exceed 50"}}`,
+		},
+		{
+			name: "user not found",
+			body: `{"email":"nonexistent@example.com"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetFriendSuggestions("nonexistent@example.com", validator.DefaultSuggestionLimit).Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", "nonexistent@example.com"))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"success":false,"error":{"type":"NOT_FOUND","message":"User not found: nonexistent@example.com"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := mocks.NewMockUserControllerInterface(ctrl)
+			tt.setupMock(mockController)
+
+			handler := NewUserHandler(mockController)
+
+			router := gin.New()
+			router.POST("/friends/suggestions", handler.GetFriendSuggestions)
+
+			req, err := http.NewRequest(http.MethodPost, "/friends/suggestions", bytes.NewBuffer([]byte(tt.body)))
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestGetFriendshipPath(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(mockController *mocks.MockUserControllerInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success with default max depth",
+			body: `{"requestor":"andy@example.com","target":"kate@example.com"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetFriendshipPath("andy@example.com", "kate@example.com", validator.DefaultPathMaxDepth).Return([]*entities.User{
+					{ID: 1, Email: "andy@example.com"},
+					{ID: 2, Email: "john@example.com"},
+					{ID: 3, Email: "kate@example.com"},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"path":["andy@example.com","john@example.com","kate@example.com"],"hops":2}`,
+		},
+		{
+			name: "success with explicit max depth",
+			body: `{"requestor":"andy@example.com","target":"john@example.com","max_depth":1}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetFriendshipPath("andy@example.com", "john@example.com", 1).Return([]*entities.User{
+					{ID: 1, Email: "andy@example.com"},
+					{ID: 2, Email: "john@example.com"},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"path":["andy@example.com","john@example.com"],"hops":1}`,
+		},
+		{
+			name: "max depth too large",
+			body: `{"requestor":"andy@example.com","target":"kate@example.com","max_depth":11}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"max_depth: must not exceed 10"}}`,
+		},
+		{
+			name: "no path found",
+			body: `{"requestor":"andy@example.com","target":"kate@example.com"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetFriendshipPath("andy@example.com", "kate@example.com", validator.DefaultPathMaxDepth).Return(nil, errors.ErrFriendshipPathNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"success":false,"error":{"type":"NOT_FOUND","message":"No friendship path found within max depth"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := mocks.NewMockUserControllerInterface(ctrl)
+			tt.setupMock(mockController)
+
+			handler := NewUserHandler(mockController)
+
+			router := gin.New()
+			router.POST("/friends/path", handler.GetFriendshipPath)
+
+			req, err := http.NewRequest(http.MethodPost, "/friends/path", bytes.NewBuffer([]byte(tt.body)))
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestGetRecipients(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(mockController *mocks.MockUserControllerInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success",
+			body: `{"sender":"andy@example.com","text":"Hello kate@example.com"}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetRecipients("andy@example.com", "Hello kate@example.com", "", 50).Return(asUserPage([]*entities.User{
+					{ID: 2, Email: "john@example.com"},
+					{ID: 3, Email: "kate@example.com"},
+				}), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"recipients":["john@example.com","kate@example.com"],"count":2,"total":2,"limit":50}`,
+		},
+		{
+			name: "success with explanation",
+			body: `{"sender":"andy@example.com","text":"Hello kate@example.com lisa@example.com ghost@example.com @nobody","explain":true}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().ExplainRecipients("andy@example.com", "Hello kate@example.com lisa@example.com ghost@example.com @nobody", "", 50).Return(&entities.RecipientExplanation{
+					RecipientPage: entities.RecipientPage{Total: 2, Recipients: []*entities.Recipient{
+						{
+							User:    &entities.User{ID: 2, Email: "john@example.com"},
+							Reasons: []entities.RecipientReason{entities.RecipientReasonFriend, entities.RecipientReasonSubscriber},
+						},
+						{
+							User:    &entities.User{ID: 3, Email: "kate@example.com"},
+							Reasons: []entities.RecipientReason{entities.RecipientReasonMentioned},
+						},
+					}},
+					DroppedMentions: []*entities.DroppedMention{
+						{Email: "ghost@example.com", Reason: entities.DroppedMentionUnknown},
+						{Email: "lisa@example.com", Reason: entities.DroppedMentionBlocked},
+						{Handle: "nobody", Reason: entities.DroppedMentionUnknown},
+					},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"success":true,"recipients":[` +
+				`{"email":"john@example.com","reasons":["friend","subscriber"]},` +
+				`{"email":"kate@example.com","reasons":["mentioned"]}],` +
+				`"dropped_mentions":[` +
+				`{"email":"ghost@example.com","reason":"unknown"},` +
+				`{"email":"lisa@example.com","reason":"blocked"},` +
+				`{"handle":"nobody","reason":"unknown"}],` +
+				`"count":2,"total":2,"limit":50}`,
+		},
+		{
+			name: "explanation with no recipients",
+			body: `{"sender":"andy@example.com","text":"Hello","explain":true}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().ExplainRecipients("andy@example.com", "Hello", "", 50).Return(&entities.RecipientExplanation{
+					RecipientPage:   entities.RecipientPage{Recipients: []*entities.Recipient{}},
+					DroppedMentions: []*entities.DroppedMention{},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"recipients":[],"dropped_mentions":[],"count":0,"total":0,"limit":50}`,
+		},
+		{
+			name: "sender not found with explanation",
+			body: `{"sender":"nonexistent@example.com","text":"Hello","explain":true}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().ExplainRecipients("nonexistent@example.com", "Hello", "", 50).Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", "nonexistent@example.com"))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"success":false,"error":{"type":"NOT_FOUND","message":"User not found: nonexistent@example.com"}}`,
+		},
+		{
+			name: "empty text",
+			body: `{"sender":"andy@example.com","text":""}`,
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"text: text cannot be empty"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := mocks.NewMockUserControllerInterface(ctrl)
+			tt.setupMock(mockController)
+
+			handler := NewUserHandler(mockController)
+
+			router := gin.New()
+			router.POST("/recipients", handler.GetRecipients)
+
+			req, err := http.NewRequest(http.MethodPost, "/recipients", bytes.NewBuffer([]byte(tt.body)))
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
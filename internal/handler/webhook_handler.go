@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"assignment/internal/domain/interfaces"
+	"assignment/pkg/errors"
+	"assignment/pkg/validator"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	webhookController interfaces.WebhookControllerInterface
+}
+
+func NewWebhookHandler(webhookController interfaces.WebhookControllerInterface) *WebhookHandler {
+	return &WebhookHandler{
+		webhookController: webhookController,
+	}
+}
+
+func (h *WebhookHandler) RegisterWebhook(c *gin.Context) {
+	var req RegisterWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	v := validator.New()
+	if ValidateRegisterWebhookRequest(v, &req); !v.Valid() {
+		errors.HandleValidationErrors(c, v.Errors)
+		return
+	}
+
+	webhook, err := h.webhookController.RegisterWebhook(req.Email, req.URL)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	response := WebhookResponse{
+		Success: true,
+		Email:   webhook.User.Email,
+		URL:     webhook.URL,
+		Secret:  webhook.Secret,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *WebhookHandler) RemoveWebhook(c *gin.Context) {
+	var req RemoveWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	v := validator.New()
+	if ValidateRemoveWebhookRequest(v, &req); !v.Valid() {
+		errors.HandleValidationErrors(c, v.Errors)
+		return
+	}
+
+	if err := h.webhookController.RemoveWebhook(req.Email); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func (h *WebhookHandler) GetWebhookDeliveries(c *gin.Context) {
+	var req GetWebhookDeliveriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	v := validator.New()
+	if ValidateGetWebhookDeliveriesRequest(v, &req); !v.Valid() {
+		errors.HandleValidationErrors(c, v.Errors)
+		return
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = DefaultWebhookDeliveryLimit
+	}
+
+	deliveries, err := h.webhookController.GetWebhookDeliveries(req.Email, limit)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	items := make([]WebhookDeliveryItem, len(deliveries))
+	for i, delivery := range deliveries {
+		items[i] = WebhookDeliveryItem{
+			ID:         delivery.ID,
+			UpdateID:   delivery.UpdateID,
+			Attempt:    delivery.Attempt,
+			StatusCode: delivery.StatusCode,
+			Success:    delivery.Success,
+			Error:      delivery.Error,
+			DurationMS: delivery.Duration.Milliseconds(),
+			CreatedAt:  delivery.CreatedAt,
+		}
+	}
+
+	response := WebhookDeliveriesResponse{
+		Success:    true,
+		Deliveries: items,
+		Count:      len(items),
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"assignment/internal/domain/entities"
+	"assignment/mocks"
+	"assignment/pkg/errors"
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRegisterWebhook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	user := &entities.User{ID: 1, Email: "andy@example.com"}
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(mockController *mocks.MockWebhookControllerInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success",
+			body: `{"email":"andy@example.com","url":"https://example.com/hook"}`,
+			setupMock: func(mockController *mocks.MockWebhookControllerInterface) {
+				mockController.EXPECT().RegisterWebhook("andy@example.com", "https://example.com/hook").Return(&entities.Webhook{
+					ID:     1,
+					User:   user,
+					URL:    "https://example.com/hook",
+					Secret: "s3cr3t",
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"email":"andy@example.com","url":"https://example.com/hook","secret":"s3cr3t"}`,
+		},
+		{
+			name: "user not found",
+			body: `{"email":"nonexistent@example.com","url":"https://example.com/hook"}`,
+			setupMock: func(mockController *mocks.MockWebhookControllerInterface) {
+				mockController.EXPECT().RegisterWebhook("nonexistent@example.com", "https://example.com/hook").Return(nil, errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", "nonexistent@example.com"))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"success":false,"error":{"type":"NOT_FOUND","message":"User not found: nonexistent@example.com"}}`,
+		},
+		{
+			name: "url without http scheme",
+			body: `{"email":"andy@example.com","url":"ftp://example.com/hook"}`,
+			setupMock: func(mockController *mocks.MockWebhookControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"url: must be an absolute http or https URL"}}`,
+		},
+		{
+			name: "relative url",
+			body: `{"email":"andy@example.com","url":"/hook"}`,
+			setupMock: func(mockController *mocks.MockWebhookControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"url: must be an absolute http or https URL"}}`,
+		},
+		{
+			name: "localhost url",
+			body: `{"email":"andy@example.com","url":"http://localhost:8080/hook"}`,
+			setupMock: func(mockController *mocks.MockWebhookControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"url: must not point to a loopback, private or reserved address"}}`,
+		},
+		{
+			name: "metadata service url",
+			body: `{"email":"andy@example.com","url":"http://169.254.169.254/latest/meta-data"}`,
+			setupMock: func(mockController *mocks.MockWebhookControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"url: must not point to a loopback, private or reserved address"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := mocks.NewMockWebhookControllerInterface(ctrl)
+			tt.setupMock(mockController)
+
+			handler := NewWebhookHandler(mockController)
+
+			router := gin.New()
+			router.POST("/webhooks", handler.RegisterWebhook)
+
+			req, err := http.NewRequest(http.MethodPost, "/webhooks", bytes.NewBuffer([]byte(tt.body)))
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestRemoveWebhook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(mockController *mocks.MockWebhookControllerInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success",
+			body: `{"email":"andy@example.com"}`,
+			setupMock: func(mockController *mocks.MockWebhookControllerInterface) {
+				mockController.EXPECT().RemoveWebhook("andy@example.com").Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true}`,
+		},
+		{
+			name: "no webhook registered",
+			body: `{"email":"andy@example.com"}`,
+			setupMock: func(mockController *mocks.MockWebhookControllerInterface) {
+				mockController.EXPECT().RemoveWebhook("andy@example.com").Return(errors.ErrWebhookNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"success":false,"error":{"type":"NOT_FOUND","message":"Webhook not found"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := mocks.NewMockWebhookControllerInterface(ctrl)
+			tt.setupMock(mockController)
+
+			handler := NewWebhookHandler(mockController)
+
+			router := gin.New()
+			router.DELETE("/webhooks", handler.RemoveWebhook)
+
+			req, err := http.NewRequest(http.MethodDelete, "/webhooks", bytes.NewBuffer([]byte(tt.body)))
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestGetWebhookDeliveries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(mockController *mocks.MockWebhookControllerInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success with default limit",
+			body: `{"email":"andy@example.com"}`,
+			setupMock: func(mockController *mocks.MockWebhookControllerInterface) {
+				mockController.EXPECT().GetWebhookDeliveries("andy@example.com", DefaultWebhookDeliveryLimit).Return([]*entities.WebhookDelivery{
+					{ID: 2, UpdateID: 5, Attempt: 2, StatusCode: 200, Success: true, Duration: 12 * time.Millisecond, CreatedAt: createdAt},
+					{ID: 1, UpdateID: 5, Attempt: 1, Error: "connection refused", Duration: 3 * time.Millisecond, CreatedAt: createdAt},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"success":true,"deliveries":[
+				{"id":2,"update_id":5,"attempt":2,"status_code":200,"success":true,"duration_ms":12,"created_at":"2024-01-02T03:04:05Z"},
+				{"id":1,"update_id":5,"attempt":1,"success":false,"error":"connection refused","duration_ms":3,"created_at":"2024-01-02T03:04:05Z"}
+			],"count":2}`,
+		},
+		{
+			name: "no webhook registered",
+			body: `{"email":"andy@example.com","limit":5}`,
+			setupMock: func(mockController *mocks.MockWebhookControllerInterface) {
+				mockController.EXPECT().GetWebhookDeliveries("andy@example.com", 5).Return(nil, errors.ErrWebhookNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"success":false,"error":{"type":"NOT_FOUND","message":"Webhook not found"}}`,
+		},
+		{
+			name: "limit too large",
+			body: `{"email":"andy@example.com","limit":101}`,
+			setupMock: func(mockController *mocks.MockWebhookControllerInterface) {
+				// No mock expectations needed as validation happens before controller calls
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"error":{"type":"VALIDATION_ERROR","message":"Validation failed","details":"limit: must not exceed 100"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := mocks.NewMockWebhookControllerInterface(ctrl)
+			tt.setupMock(mockController)
+
+			handler := NewWebhookHandler(mockController)
+
+			router := gin.New()
+			router.POST("/webhooks/deliveries", handler.GetWebhookDeliveries)
+
+			req, err := http.NewRequest(http.MethodPost, "/webhooks/deliveries", bytes.NewBuffer([]byte(tt.body)))
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package pubsub
+
+import "sync"
+
+// DefaultBufferSize is how many messages a subscriber can fall behind before it
+// is disconnected
+const DefaultBufferSize = 64
+
+type subscriber[T any] struct {
+	ch chan T
+}
+
+// Hub delivers published messages to subscribers keyed by recipient user ID. It
+// only reaches subscribers connected to this process; anything that must not be
+// missed is persisted elsewhere (updates are recovered from the inbox)
+type Hub[T any] struct {
+	mu          sync.Mutex
+	subscribers map[int]map[*subscriber[T]]struct{}
+	bufferSize  int
+	closed      bool
+}
+
+func NewHub[T any](bufferSize int) *Hub[T] {
+	return &Hub[T]{
+		subscribers: make(map[int]map[*subscriber[T]]struct{}),
+		bufferSize:  bufferSize,
+	}
+}
+
+// Subscribe registers a subscriber for the user's messages. The channel is closed
+// when the returned cancel func is called, when the subscriber falls too far
+// behind, or when the hub is closed
+func (h *Hub[T]) Subscribe(userID int) (<-chan T, func()) {
+	sub := &subscriber[T]{ch: make(chan T, h.bufferSize)}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		close(sub.ch)
+		return sub.ch, func() {}
+	}
+
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[*subscriber[T]]struct{})
+	}
+	h.subscribers[userID][sub] = struct{}{}
+
+	return sub.ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.remove(userID, sub)
+	}
+}
+
+// Publish sends the message to every subscriber of the given recipients without
+// blocking. A subscriber whose buffer is full is disconnected so it can resume
+// instead of silently missing messages
+func (h *Hub[T]) Publish(recipientIDs []int, message T) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, userID := range recipientIDs {
+		for sub := range h.subscribers[userID] {
+			select {
+			case sub.ch <- message:
+			default:
+				h.remove(userID, sub)
+			}
+		}
+	}
+}
+
+// Close disconnects every subscriber and makes later subscriptions end
+// immediately, so open streams finish during shutdown
+func (h *Hub[T]) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return
+	}
+	h.closed = true
+
+	for userID, subs := range h.subscribers {
+		for sub := range subs {
+			h.remove(userID, sub)
+		}
+	}
+}
+
+// remove closes the subscriber's channel once. Callers must hold mu
+func (h *Hub[T]) remove(userID int, sub *subscriber[T]) {
+	subs, ok := h.subscribers[userID]
+	if !ok {
+		return
+	}
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+
+	delete(subs, sub)
+	close(sub.ch)
+	if len(subs) == 0 {
+		delete(h.subscribers, userID)
+	}
+}
@@ -0,0 +1,77 @@
+package pubsub
+
+import (
+	"assignment/internal/domain/entities"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHub_PublishToRecipients(t *testing.T) {
+	hub := NewHub[*entities.Update](DefaultBufferSize)
+
+	alice, cancelAlice := hub.Subscribe(2)
+	defer cancelAlice()
+	aliceOtherTab, cancelAliceOtherTab := hub.Subscribe(2)
+	defer cancelAliceOtherTab()
+	bob, cancelBob := hub.Subscribe(3)
+	defer cancelBob()
+
+	update := &entities.Update{ID: 1, Text: "Hello alice"}
+	hub.Publish([]int{2, 4}, update)
+
+	assert.Equal(t, update, <-alice)
+	assert.Equal(t, update, <-aliceOtherTab)
+	assert.Empty(t, bob)
+}
+
+func TestHub_CancelClosesChannel(t *testing.T) {
+	hub := NewHub[*entities.Update](DefaultBufferSize)
+
+	updates, cancel := hub.Subscribe(2)
+	cancel()
+	// Cancelling twice is a no-op
+	cancel()
+
+	_, ok := <-updates
+	assert.False(t, ok)
+
+	// Publishing to a user without subscribers does nothing
+	hub.Publish([]int{2}, &entities.Update{ID: 1})
+}
+
+func TestHub_SlowSubscriberIsDisconnected(t *testing.T) {
+	hub := NewHub[*entities.Update](2)
+
+	slow, cancel := hub.Subscribe(2)
+	defer cancel()
+
+	for id := 1; id <= 3; id++ {
+		hub.Publish([]int{2}, &entities.Update{ID: id})
+	}
+
+	// The buffered updates are still readable, then the channel ends
+	assert.Equal(t, 1, (<-slow).ID)
+	assert.Equal(t, 2, (<-slow).ID)
+	_, ok := <-slow
+	assert.False(t, ok)
+}
+
+func TestHub_Close(t *testing.T) {
+	hub := NewHub[*entities.Update](DefaultBufferSize)
+
+	updates, cancel := hub.Subscribe(2)
+	defer cancel()
+
+	hub.Close()
+	hub.Close()
+
+	_, ok := <-updates
+	assert.False(t, ok)
+
+	// Subscriptions after close end immediately
+	late, lateCancel := hub.Subscribe(3)
+	defer lateCancel()
+	_, ok = <-late
+	assert.False(t, ok)
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"assignment/internal/domain/entities"
+	"assignment/internal/domain/interfaces"
+	"assignment/pkg/errors"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+)
+
+type outboxRepository struct {
+	db *sql.DB
+}
+
+func NewOutboxRepository(db *sql.DB) interfaces.OutboxRepositoryInterface {
+	return &outboxRepository{db: db}
+}
+
+// insertOutboxEvent adds an event to the outbox using the caller's transaction,
+// so the event is only visible once the data it describes is committed
+func insertOutboxEvent(exec boil.ContextExecutor, eventType string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrorTypeInternal, "Failed to encode outbox payload")
+	}
+
+	_, err = queries.Raw(
+		`INSERT INTO outbox (event_type, payload) VALUES ($1, $2)`,
+		eventType, string(data),
+	).ExecContext(context.Background(), exec)
+	if err != nil {
+		return errors.FromError(err)
+	}
+
+	return nil
+}
+
+// ClaimOutboxEvents picks up due events and leases them to the caller. Each claim
+// counts as an attempt, and an event whose lease expires without being marked
+// (for example after a crash) becomes due again
+func (r *outboxRepository) ClaimOutboxEvents(limit int, lease time.Duration) ([]*entities.OutboxEvent, error) {
+	var rows []struct {
+		ID        int       `boil:"id"`
+		EventType string    `boil:"event_type"`
+		Payload   []byte    `boil:"payload"`
+		Attempts  int       `boil:"attempts"`
+		CreatedAt time.Time `boil:"created_at"`
+	}
+
+	err := queries.Raw(
+		`UPDATE outbox
+		SET attempts = attempts + 1, next_attempt_at = NOW() + make_interval(secs => $2::double precision)
+		WHERE id IN (
+			SELECT id FROM outbox
+			WHERE status = $3 AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at, id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, event_type, payload, attempts, created_at`,
+		limit, lease.Seconds(), string(entities.OutboxPending),
+	).Bind(context.Background(), r.db, &rows)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to claim outbox events")
+	}
+
+	events := make([]*entities.OutboxEvent, len(rows))
+	for i, row := range rows {
+		events[i] = &entities.OutboxEvent{
+			ID:        row.ID,
+			EventType: row.EventType,
+			Payload:   row.Payload,
+			Attempts:  row.Attempts,
+			CreatedAt: row.CreatedAt,
+		}
+	}
+
+	// RETURNING does not keep the order of the subquery
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].ID < events[j].ID
+	})
+
+	return events, nil
+}
+
+// The updates below only apply while the caller still holds the claim: the
+// event is pending and has not been claimed again since, which would have
+// counted another attempt. Otherwise they change nothing and return
+// ErrOutboxClaimLost
+
+func (r *outboxRepository) MarkOutboxEventDelivered(id, attempts int) error {
+	return r.updateClaimed(
+		`UPDATE outbox SET status = $4, delivered_at = NOW(), last_error = NULL
+		WHERE id = $1 AND attempts = $2 AND status = $3`,
+		[]any{id, attempts, string(entities.OutboxPending), string(entities.OutboxDelivered)},
+		"Failed to mark outbox event delivered",
+	)
+}
+
+func (r *outboxRepository) RescheduleOutboxEvent(id, attempts int, nextAttemptAt time.Time, lastError string) error {
+	return r.updateClaimed(
+		`UPDATE outbox SET next_attempt_at = $4, last_error = $5
+		WHERE id = $1 AND attempts = $2 AND status = $3`,
+		[]any{id, attempts, string(entities.OutboxPending), nextAttemptAt, lastError},
+		"Failed to reschedule outbox event",
+	)
+}
+
+func (r *outboxRepository) DeadLetterOutboxEvent(id, attempts int, lastError string) error {
+	return r.updateClaimed(
+		`UPDATE outbox SET status = $4, last_error = $5
+		WHERE id = $1 AND attempts = $2 AND status = $3`,
+		[]any{id, attempts, string(entities.OutboxPending), string(entities.OutboxDead), lastError},
+		"Failed to dead-letter outbox event",
+	)
+}
+
+func (r *outboxRepository) updateClaimed(query string, args []any, failure string) error {
+	result, err := queries.Raw(query, args...).ExecContext(context.Background(), r.db)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrorTypeDatabase, failure)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, errors.ErrorTypeDatabase, failure)
+	}
+	if affected == 0 {
+		return errors.ErrOutboxClaimLost
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"assignment/internal/domain/entities"
+	"assignment/pkg/errors"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestOutboxRepository_ClaimAndMark(t *testing.T) {
+	db, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	repo := NewOutboxRepository(db)
+
+	for i := 1; i <= 3; i++ {
+		if err := insertOutboxEvent(db, entities.EventUpdatePublished, map[string]int{"n": i}); err != nil {
+			t.Fatalf("Failed to insert outbox event: %v", err)
+		}
+	}
+
+	// First claim takes the two oldest events and counts an attempt
+	events, err := repo.ClaimOutboxEvents(2, time.Minute)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	for i, event := range events {
+		if event.Attempts != 1 {
+			t.Errorf("expected 1 attempt for event %d, got %d", event.ID, event.Attempts)
+		}
+		var payload map[string]int
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			t.Fatalf("Failed to decode payload: %v", err)
+		}
+		if payload["n"] != i+1 {
+			t.Errorf("expected payload n=%d, got %d", i+1, payload["n"])
+		}
+	}
+
+	// Leased events are not handed out again
+	remaining, err := repo.ClaimOutboxEvents(10, time.Minute)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(remaining) != 1 {
+		t.Fatalf("expected 1 remaining event, got %d", len(remaining))
+	}
+
+	if err := repo.MarkOutboxEventDelivered(events[0].ID, events[0].Attempts); err != nil {
+		t.Fatalf("Failed to mark delivered: %v", err)
+	}
+	if err := repo.DeadLetterOutboxEvent(events[1].ID, events[1].Attempts, "gave up"); err != nil {
+		t.Fatalf("Failed to dead-letter: %v", err)
+	}
+	if err := repo.RescheduleOutboxEvent(remaining[0].ID, remaining[0].Attempts, time.Now().Add(-time.Second), "try again"); err != nil {
+		t.Fatalf("Failed to reschedule: %v", err)
+	}
+
+	// An event already marked, or claimed again since, is no longer held
+	if err := repo.RescheduleOutboxEvent(events[0].ID, events[0].Attempts, time.Now(), "late"); err != errors.ErrOutboxClaimLost {
+		t.Errorf("expected the claim on a delivered event to be lost, got %v", err)
+	}
+
+	// Only the rescheduled event is due again
+	retried, err := repo.ClaimOutboxEvents(10, time.Minute)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(retried) != 1 || retried[0].ID != remaining[0].ID {
+		t.Fatalf("expected only event %d to be retried, got %v", remaining[0].ID, retried)
+	}
+	if retried[0].Attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", retried[0].Attempts)
+	}
+	if err := repo.MarkOutboxEventDelivered(remaining[0].ID, remaining[0].Attempts); err != errors.ErrOutboxClaimLost {
+		t.Errorf("expected the first claim on event %d to be lost once it was claimed again, got %v", remaining[0].ID, err)
+	}
+
+	statuses := map[int]string{}
+	rows, err := db.QueryContext(context.Background(), "SELECT id, status FROM outbox")
+	if err != nil {
+		t.Fatalf("Failed to query outbox: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int
+		var status string
+		if err := rows.Scan(&id, &status); err != nil {
+			t.Fatalf("Failed to scan outbox row: %v", err)
+		}
+		statuses[id] = status
+	}
+
+	if statuses[events[0].ID] != string(entities.OutboxDelivered) {
+		t.Errorf("expected event %d to be delivered, got %s", events[0].ID, statuses[events[0].ID])
+	}
+	if statuses[events[1].ID] != string(entities.OutboxDead) {
+		t.Errorf("expected event %d to be dead, got %s", events[1].ID, statuses[events[1].ID])
+	}
+	if statuses[remaining[0].ID] != string(entities.OutboxPending) {
+		t.Errorf("expected event %d to be pending, got %s", remaining[0].ID, statuses[remaining[0].ID])
+	}
+}
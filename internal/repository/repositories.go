@@ -6,15 +6,33 @@ import (
 )
 
 type repositories struct {
-    userRepo interfaces.UserRepositoryInterface
+    userRepo    interfaces.UserRepositoryInterface
+    updateRepo  interfaces.UpdateRepositoryInterface
+    outboxRepo  interfaces.OutboxRepositoryInterface
+    webhookRepo interfaces.WebhookRepositoryInterface
 }
 
 func NewRepositories(db *sql.DB) interfaces.Repositories {
     return &repositories{
-        userRepo: NewUserRepository(db),
+        userRepo:    NewUserRepository(db),
+        updateRepo:  NewUpdateRepository(db),
+        outboxRepo:  NewOutboxRepository(db),
+        webhookRepo: NewWebhookRepository(db),
     }
 }
 
 func (r *repositories) UserRepository() interfaces.UserRepositoryInterface {
     return r.userRepo
+}
+
+func (r *repositories) UpdateRepository() interfaces.UpdateRepositoryInterface {
+    return r.updateRepo
+}
+
+func (r *repositories) OutboxRepository() interfaces.OutboxRepositoryInterface {
+    return r.outboxRepo
+}
+
+func (r *repositories) WebhookRepository() interfaces.WebhookRepositoryInterface {
+    return r.webhookRepo
 }
\ No newline at end of file
@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"assignment/internal/domain/entities"
+	"assignment/internal/domain/interfaces"
+	"assignment/pkg/errors"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+)
+
+type updateRepository struct {
+	db *sql.DB
+}
+
+func NewUpdateRepository(db *sql.DB) interfaces.UpdateRepositoryInterface {
+	return &updateRepository{db: db}
+}
+
+// CreateUpdateTx stores the update, one inbox row per recipient, the
+// update.published outbox event and one webhook.delivery event per recipient
+// webhook atomically
+func (r *updateRepository) CreateUpdateTx(sender *entities.User, text string, recipients []*entities.User) (*entities.Update, error) {
+	// Begin transaction
+	tx, err := r.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to begin transaction")
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var row struct {
+		ID        int       `boil:"id"`
+		CreatedAt time.Time `boil:"created_at"`
+	}
+	err = queries.Raw(
+		`INSERT INTO updates (sender_id, text) VALUES ($1, $2) RETURNING id, created_at`,
+		sender.ID, text,
+	).Bind(context.Background(), tx, &row)
+	if err != nil {
+		return nil, errors.FromError(err)
+	}
+
+	recipientEmails := make([]string, len(recipients))
+	for i, recipient := range recipients {
+		recipientEmails[i] = recipient.Email
+	}
+
+	if len(recipients) > 0 {
+		recipientIDs := make([]int, len(recipients))
+		for i, recipient := range recipients {
+			recipientIDs[i] = recipient.ID
+		}
+
+		_, err = queries.Raw(
+			`INSERT INTO inbox (user_id, update_id) SELECT unnest($1::int[]), $2`,
+			pq.Array(recipientIDs), row.ID,
+		).ExecContext(context.Background(), tx)
+		if err != nil {
+			return nil, errors.FromError(err)
+		}
+
+		// Queue a signed delivery for every recipient with a webhook
+		var webhooks []*entities.Webhook
+		webhooks, err = getWebhooksByUserIDs(tx, recipientIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, webhook := range webhooks {
+			err = insertOutboxEvent(tx, entities.EventWebhookDelivery, entities.WebhookDeliveryPayload{
+				WebhookID: webhook.ID,
+				Recipient: webhook.User.Email,
+				UpdateID:  row.ID,
+				Sender:    sender.Email,
+				Text:      text,
+				CreatedAt: row.CreatedAt,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	err = insertOutboxEvent(tx, entities.EventUpdatePublished, entities.UpdatePublishedPayload{
+		UpdateID:   row.ID,
+		Sender:     sender.Email,
+		Text:       text,
+		Recipients: recipientEmails,
+		CreatedAt:  row.CreatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to commit transaction")
+	}
+
+	return &entities.Update{
+		ID:         row.ID,
+		Sender:     sender,
+		Text:       text,
+		Recipients: recipients,
+		CreatedAt:  row.CreatedAt,
+	}, nil
+}
+
+// GetTimeline returns a page of the updates the user received, newest first.
+// It sorts on the inbox's update_id so the page is read off idx_inbox_user_update
+func (r *updateRepository) GetTimeline(user *entities.User, limit, offset int) ([]*entities.Update, error) {
+	var rows []struct {
+		ID          int       `boil:"id"`
+		Text        string    `boil:"text"`
+		CreatedAt   time.Time `boil:"created_at"`
+		SenderID    int       `boil:"sender_id"`
+		SenderEmail string    `boil:"sender_email"`
+	}
+
+	err := queries.Raw(
+		`SELECT u.id, u.text, u.created_at, s.id AS sender_id, s.email AS sender_email
+		FROM inbox i
+		JOIN updates u ON u.id = i.update_id
+		JOIN users s ON s.id = u.sender_id
+		WHERE i.user_id = $1
+		ORDER BY i.update_id DESC
+		LIMIT $2 OFFSET $3`,
+		user.ID, limit, offset,
+	).Bind(context.Background(), r.db, &rows)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to fetch timeline")
+	}
+
+	updates := make([]*entities.Update, len(rows))
+	for i, row := range rows {
+		updates[i] = &entities.Update{
+			ID:        row.ID,
+			Sender:    &entities.User{ID: row.SenderID, Email: row.SenderEmail},
+			Text:      row.Text,
+			CreatedAt: row.CreatedAt,
+		}
+	}
+
+	return updates, nil
+}
+
+// GetInboxSince returns the updates the user received after the given update,
+// oldest first
+func (r *updateRepository) GetInboxSince(user *entities.User, afterUpdateID, limit int) ([]*entities.Update, error) {
+	var rows []struct {
+		ID          int       `boil:"id"`
+		Text        string    `boil:"text"`
+		CreatedAt   time.Time `boil:"created_at"`
+		SenderID    int       `boil:"sender_id"`
+		SenderEmail string    `boil:"sender_email"`
+	}
+
+	err := queries.Raw(
+		`SELECT u.id, u.text, u.created_at, s.id AS sender_id, s.email AS sender_email
+		FROM inbox i
+		JOIN updates u ON u.id = i.update_id
+		JOIN users s ON s.id = u.sender_id
+		WHERE i.user_id = $1 AND i.update_id > $2
+		ORDER BY i.update_id
+		LIMIT $3`,
+		user.ID, afterUpdateID, limit,
+	).Bind(context.Background(), r.db, &rows)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to fetch inbox")
+	}
+
+	updates := make([]*entities.Update, len(rows))
+	for i, row := range rows {
+		updates[i] = &entities.Update{
+			ID:        row.ID,
+			Sender:    &entities.User{ID: row.SenderID, Email: row.SenderEmail},
+			Text:      row.Text,
+			CreatedAt: row.CreatedAt,
+		}
+	}
+
+	return updates, nil
+}
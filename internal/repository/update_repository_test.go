@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"assignment/internal/domain/entities"
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestUpdateRepository_CreateUpdateTx(t *testing.T) {
+	db, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	repo := NewUpdateRepository(db)
+
+	andy := &entities.User{ID: 1, Email: "andy@mail.com"}
+	alice := &entities.User{ID: 2, Email: "alice@mail.com"}
+	bob := &entities.User{ID: 3, Email: "bob@mail.com"}
+
+	update, err := repo.CreateUpdateTx(andy, "Hello world", []*entities.User{alice, bob})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if update.ID == 0 {
+		t.Error("expected update ID to be set")
+	}
+	if update.CreatedAt.IsZero() {
+		t.Error("expected created_at to be set")
+	}
+
+	var inboxCount int
+	err = db.QueryRowContext(context.Background(), "SELECT COUNT(*) FROM inbox WHERE update_id = $1", update.ID).Scan(&inboxCount)
+	if err != nil {
+		t.Fatalf("Failed to count inbox rows: %v", err)
+	}
+	if inboxCount != 2 {
+		t.Errorf("expected 2 inbox rows, got %d", inboxCount)
+	}
+
+	// The update.published event is written with the update
+	var payload []byte
+	err = db.QueryRowContext(context.Background(), "SELECT payload FROM outbox WHERE event_type = $1", entities.EventUpdatePublished).Scan(&payload)
+	if err != nil {
+		t.Fatalf("Failed to load outbox event: %v", err)
+	}
+	var event entities.UpdatePublishedPayload
+	if err := json.Unmarshal(payload, &event); err != nil {
+		t.Fatalf("Failed to decode outbox payload: %v", err)
+	}
+	if event.UpdateID != update.ID || event.Sender != "andy@mail.com" || len(event.Recipients) != 2 {
+		t.Errorf("unexpected outbox payload: %+v", event)
+	}
+
+	// An update without recipients is still stored
+	lonely, err := repo.CreateUpdateTx(bob, "Anyone there?", nil)
+	if err != nil {
+		t.Fatalf("expected no error for update without recipients, got %v", err)
+	}
+	if lonely.ID == update.ID {
+		t.Error("expected a new update ID")
+	}
+
+	// Duplicate recipients violate the inbox constraint and roll back the update
+	_, err = repo.CreateUpdateTx(andy, "Duplicated", []*entities.User{alice, alice})
+	if err == nil {
+		t.Fatal("expected error for duplicate recipients, got nil")
+	}
+
+	var updateCount int
+	err = db.QueryRowContext(context.Background(), "SELECT COUNT(*) FROM updates WHERE text = 'Duplicated'").Scan(&updateCount)
+	if err != nil {
+		t.Fatalf("Failed to count updates: %v", err)
+	}
+	if updateCount != 0 {
+		t.Errorf("expected update to be rolled back, found %d rows", updateCount)
+	}
+}
+
+func TestUpdateRepository_GetTimeline(t *testing.T) {
+	db, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	repo := NewUpdateRepository(db)
+
+	andy := &entities.User{ID: 1, Email: "andy@mail.com"}
+	alice := &entities.User{ID: 2, Email: "alice@mail.com"}
+	bob := &entities.User{ID: 3, Email: "bob@mail.com"}
+
+	texts := []string{"first", "second", "third"}
+	for _, text := range texts {
+		if _, err := repo.CreateUpdateTx(andy, text, []*entities.User{alice}); err != nil {
+			t.Fatalf("Failed to create update: %v", err)
+		}
+	}
+	if _, err := repo.CreateUpdateTx(bob, "from bob", []*entities.User{andy}); err != nil {
+		t.Fatalf("Failed to create update: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		user     *entities.User
+		limit    int
+		offset   int
+		expected []string
+	}{
+		{
+			name:     "newest first",
+			user:     alice,
+			limit:    10,
+			expected: []string{"third", "second", "first"},
+		},
+		{
+			name:     "first page",
+			user:     alice,
+			limit:    2,
+			expected: []string{"third", "second"},
+		},
+		{
+			name:     "second page",
+			user:     alice,
+			limit:    2,
+			offset:   2,
+			expected: []string{"first"},
+		},
+		{
+			name:     "only received updates are listed",
+			user:     andy,
+			limit:    10,
+			expected: []string{"from bob"},
+		},
+		{
+			name:     "empty timeline",
+			user:     bob,
+			limit:    10,
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updates, err := repo.GetTimeline(tt.user, tt.limit, tt.offset)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if len(updates) != len(tt.expected) {
+				t.Fatalf("expected %d updates, got %d", len(tt.expected), len(updates))
+			}
+			for i, update := range updates {
+				if update.Text != tt.expected[i] {
+					t.Errorf("expected update %d to be %q, got %q", i, tt.expected[i], update.Text)
+				}
+				if update.Sender == nil || update.Sender.Email == "" {
+					t.Errorf("expected sender to be loaded for update %d", i)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateRepository_GetInboxSince(t *testing.T) {
+	db, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	repo := NewUpdateRepository(db)
+
+	andy := &entities.User{ID: 1, Email: "andy@mail.com"}
+	alice := &entities.User{ID: 2, Email: "alice@mail.com"}
+	bob := &entities.User{ID: 3, Email: "bob@mail.com"}
+
+	var aliceUpdates []int
+	for _, text := range []string{"first", "second", "third"} {
+		update, err := repo.CreateUpdateTx(andy, text, []*entities.User{alice})
+		if err != nil {
+			t.Fatalf("Failed to create update: %v", err)
+		}
+		aliceUpdates = append(aliceUpdates, update.ID)
+	}
+	// Updates for other users are not part of alice's inbox
+	if _, err := repo.CreateUpdateTx(andy, "for bob", []*entities.User{bob}); err != nil {
+		t.Fatalf("Failed to create update: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		after    int
+		limit    int
+		expected []string
+	}{
+		{name: "everything after the first update", after: aliceUpdates[0], limit: 10, expected: []string{"second", "third"}},
+		{name: "limit keeps the oldest", after: 0, limit: 2, expected: []string{"first", "second"}},
+		{name: "nothing missed", after: aliceUpdates[2], limit: 10, expected: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updates, err := repo.GetInboxSince(alice, tt.after, tt.limit)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if len(updates) != len(tt.expected) {
+				t.Fatalf("expected %d updates, got %d", len(tt.expected), len(updates))
+			}
+			for i, update := range updates {
+				if update.Text != tt.expected[i] {
+					t.Errorf("expected update %d to be %q, got %q", i, tt.expected[i], update.Text)
+				}
+			}
+		})
+	}
+}
@@ -5,12 +5,15 @@ import (
 	"assignment/internal/domain/interfaces"
 	"assignment/internal/infrastructure/database/models"
 	"assignment/pkg/errors"
-	"assignment/pkg/utils"
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/lib/pq"
 	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
 	"github.com/volatiletech/sqlboiler/v4/queries/qm"
 )
 
@@ -22,7 +25,7 @@ func NewUserRepository(db *sql.DB) interfaces.UserRepositoryInterface {
 	return &userRepository{db: db}
 }
 
-func (r *userRepository) CreateFriendship(user1, user2 *entities.User) error {
+func (r *userRepository) DeleteFriendship(user1, user2 *entities.User) error {
 	// Begin transaction
 	tx, err := r.db.BeginTx(context.Background(), nil)
 	if err != nil {
@@ -34,11 +37,36 @@ func (r *userRepository) CreateFriendship(user1, user2 *entities.User) error {
 		}
 	}()
 
-	// Use the IDs from domain entities directly
-	user1ID := user1.ID
-	user2ID := user2.ID
+	// Friendships are stored with the smaller ID first
+	firstUserID := user1.ID
+	secondUserID := user2.ID
+	if user1.ID > user2.ID {
+		firstUserID = user2.ID
+		secondUserID = user1.ID
+	}
+
+	rowsAff, err := models.Friends(
+		models.FriendWhere.User1ID.EQ(firstUserID),
+		models.FriendWhere.User2ID.EQ(secondUserID),
+	).DeleteAll(context.Background(), tx)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to delete friendship")
+	}
+	if rowsAff == 0 {
+		err = errors.ErrFriendshipNotFound
+		return err
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to commit transaction")
+	}
+
+	return nil
+}
 
-	// Ensure consistent ordering (smaller ID first)
+func (r *userRepository) CheckFriendshipExists(user1ID, user2ID int) (bool, error) {
+	// Friendships are stored with the smaller ID first
 	firstUserID := user1ID
 	secondUserID := user2ID
 	if user1ID > user2ID {
@@ -46,7 +74,139 @@ func (r *userRepository) CreateFriendship(user1, user2 *entities.User) error {
 		secondUserID = user1ID
 	}
 
-	// Try to insert friendship directly - let database constraint handle duplicates
+	exists, err := models.Friends(
+		models.FriendWhere.User1ID.EQ(firstUserID),
+		models.FriendWhere.User2ID.EQ(secondUserID),
+	).Exists(context.Background(), r.db)
+	if err != nil {
+		return false, errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to check friendship existence")
+	}
+	return exists, nil
+}
+
+// friendRequestRow is a friend_requests row joined with both users' emails
+type friendRequestRow struct {
+	ID             int       `boil:"id"`
+	Status         string    `boil:"status"`
+	CreatedAt      time.Time `boil:"created_at"`
+	RequesterID    int       `boil:"requester_id"`
+	RequesterEmail string    `boil:"requester_email"`
+	AddresseeID    int       `boil:"addressee_id"`
+	AddresseeEmail string    `boil:"addressee_email"`
+}
+
+func (row *friendRequestRow) toEntity() *entities.FriendRequest {
+	return &entities.FriendRequest{
+		ID:        row.ID,
+		Requester: &entities.User{ID: row.RequesterID, Email: row.RequesterEmail},
+		Addressee: &entities.User{ID: row.AddresseeID, Email: row.AddresseeEmail},
+		Status:    entities.FriendRequestStatus(row.Status),
+		CreatedAt: row.CreatedAt,
+	}
+}
+
+func (r *userRepository) CreateFriendRequest(requester, addressee *entities.User) (*entities.FriendRequest, error) {
+	var row struct {
+		ID        int       `boil:"id"`
+		Status    string    `boil:"status"`
+		CreatedAt time.Time `boil:"created_at"`
+	}
+
+	// Let the partial unique index report an already pending request between the pair
+	err := queries.Raw(
+		`INSERT INTO friend_requests (requester_id, addressee_id) VALUES ($1, $2) RETURNING id, status, created_at`,
+		requester.ID, addressee.ID,
+	).Bind(context.Background(), r.db, &row)
+	if err != nil {
+		return nil, errors.FromError(err)
+	}
+
+	return &entities.FriendRequest{
+		ID:        row.ID,
+		Requester: requester,
+		Addressee: addressee,
+		Status:    entities.FriendRequestStatus(row.Status),
+		CreatedAt: row.CreatedAt,
+	}, nil
+}
+
+func (r *userRepository) GetIncomingFriendRequests(user *entities.User) ([]*entities.FriendRequest, error) {
+	return r.getPendingFriendRequests("fr.addressee_id", user.ID)
+}
+
+func (r *userRepository) GetOutgoingFriendRequests(user *entities.User) ([]*entities.FriendRequest, error) {
+	return r.getPendingFriendRequests("fr.requester_id", user.ID)
+}
+
+// getPendingFriendRequests lists pending requests where column matches userID, oldest first
+func (r *userRepository) getPendingFriendRequests(column string, userID int) ([]*entities.FriendRequest, error) {
+	var rows []*friendRequestRow
+	err := queries.Raw(
+		`SELECT fr.id, fr.status, fr.created_at,
+			requester.id AS requester_id, requester.email AS requester_email,
+			addressee.id AS addressee_id, addressee.email AS addressee_email
+		FROM friend_requests fr
+		JOIN users requester ON requester.id = fr.requester_id
+		JOIN users addressee ON addressee.id = fr.addressee_id
+		WHERE `+column+` = $1 AND fr.status = $2
+		ORDER BY fr.created_at, fr.id`,
+		userID, string(entities.FriendRequestPending),
+	).Bind(context.Background(), r.db, &rows)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to fetch friend requests")
+	}
+
+	requests := make([]*entities.FriendRequest, len(rows))
+	for i, row := range rows {
+		requests[i] = row.toEntity()
+	}
+
+	return requests, nil
+}
+
+func (r *userRepository) AcceptFriendRequestTx(requester, addressee *entities.User) error {
+	// Begin transaction
+	tx, err := r.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to begin transaction")
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// 1. Refuse while either user blocks the other. The pair is locked first,
+	// so a block being created at the same time waits for this transaction
+	// and removes the friendship, or is seen here
+	err = r.lockUserPair(tx, requester.ID, addressee.ID)
+	if err != nil {
+		return err
+	}
+
+	isBlocked, err := r.checkBidirectionalBlock(tx, requester.ID, addressee.ID)
+	if err != nil {
+		return err
+	}
+	if isBlocked {
+		err = errors.ErrUserBlocked
+		return err
+	}
+
+	// 2. Mark the pending request as accepted
+	err = r.updateFriendRequestStatus(tx, requester, addressee, entities.FriendRequestAccepted)
+	if err != nil {
+		return err
+	}
+
+	// 3. Create the friendship with the smaller ID first
+	firstUserID := requester.ID
+	secondUserID := addressee.ID
+	if requester.ID > addressee.ID {
+		firstUserID = addressee.ID
+		secondUserID = requester.ID
+	}
+
 	friend := &models.Friend{
 		User1ID: firstUserID,
 		User2ID: secondUserID,
@@ -65,7 +225,33 @@ func (r *userRepository) CreateFriendship(user1, user2 *entities.User) error {
 	return nil
 }
 
-func (r *userRepository) GetFriendList(user *entities.User) ([]*entities.User, error) {
+func (r *userRepository) UpdateFriendRequestStatus(requester, addressee *entities.User, status entities.FriendRequestStatus) error {
+	return r.updateFriendRequestStatus(r.db, requester, addressee, status)
+}
+
+// updateFriendRequestStatus moves the pending request from requester to addressee into status
+func (r *userRepository) updateFriendRequestStatus(exec boil.ContextExecutor, requester, addressee *entities.User, status entities.FriendRequestStatus) error {
+	result, err := queries.Raw(
+		`UPDATE friend_requests SET status = $1, updated_at = NOW()
+		WHERE requester_id = $2 AND addressee_id = $3 AND status = $4`,
+		string(status), requester.ID, addressee.ID, string(entities.FriendRequestPending),
+	).ExecContext(context.Background(), exec)
+	if err != nil {
+		return errors.FromError(err)
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to update friend request")
+	}
+	if rowsAff == 0 {
+		return errors.ErrFriendRequestNotFound
+	}
+
+	return nil
+}
+
+func (r *userRepository) GetFriendList(user *entities.User, page entities.PageRequest) (*entities.UserPage, error) {
 	// First verify that the user exists
 	_, err := models.Users(
 		models.UserWhere.ID.EQ(user.ID),
@@ -77,92 +263,133 @@ func (r *userRepository) GetFriendList(user *entities.User) ([]*entities.User, e
 		return nil, errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to fetch user")
 	}
 
-	// Get friendships where this user is user1
-	user1Friends, err := models.Friends(
-		models.FriendWhere.User1ID.EQ(user.ID),
-		qm.Load(models.FriendRels.User2),
-	).All(context.Background(), r.db)
-	if err != nil {
-		return nil, errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to fetch user1 friends")
+	// friends stores each friendship once, so the friend is whichever side is not the user
+	return r.getUserPage(
+		`SELECT u.id, u.email
+		FROM friends f
+		JOIN users u ON u.id = CASE WHEN f.user1_id = $1 THEN f.user2_id ELSE f.user1_id END
+		WHERE f.user1_id = $1 OR f.user2_id = $1`,
+		[]any{user.ID}, page, "Failed to fetch friends",
+	)
+}
+
+func (r *userRepository) GetCommonFriends(users []*entities.User, page entities.PageRequest) (*entities.UserPage, error) {
+	if len(users) == 0 {
+		return &entities.UserPage{Users: []*entities.User{}}, nil
 	}
 
-	// Get friendships where this user is user2
-	user2Friends, err := models.Friends(
-		models.FriendWhere.User2ID.EQ(user.ID),
-		qm.Load(models.FriendRels.User1),
-	).All(context.Background(), r.db)
-	if err != nil {
-		return nil, errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to fetch user2 friends")
+	userIDs := make([]int, len(users))
+	for i, user := range users {
+		userIDs[i] = user.ID
 	}
 
-	// Collect all friend users
-	friendMap := make(map[int]*entities.User)
+	// Intersect the friend lists in the database: a common friend is linked
+	// to every one of the given users
+	return r.getUserPage(
+		`SELECT u.id, u.email
+		FROM (
+			SELECT user1_id AS user_id, user2_id AS friend_id FROM friends WHERE user1_id = ANY($1::int[])
+			UNION ALL
+			SELECT user2_id AS user_id, user1_id AS friend_id FROM friends WHERE user2_id = ANY($1::int[])
+		) e
+		JOIN users u ON u.id = e.friend_id
+		GROUP BY u.id, u.email
+		HAVING COUNT(DISTINCT e.user_id) = $2`,
+		[]any{pq.Array(userIDs), len(userIDs)}, page, "Failed to fetch common friends",
+	)
+}
 
-	// Add friends from user1 relationships (where current user is user1)
-	for _, friendship := range user1Friends {
-		if friendship.R != nil && friendship.R.User2 != nil {
-			friendUser := friendship.R.User2
-			friendMap[friendUser.ID] = &entities.User{
-				ID:    friendUser.ID,
-				Email: friendUser.Email,
-			}
-		}
+// GetUnblockedFriendsBatch returns the friends of every given user, skipping
+// friendships between two users where either one blocks the other
+func (r *userRepository) GetUnblockedFriendsBatch(userIDs []int) (map[int][]*entities.User, error) {
+	result := make(map[int][]*entities.User)
+	if len(userIDs) == 0 {
+		return result, nil
 	}
 
-	// Add friends from user2 relationships (where current user is user2)
-	for _, friendship := range user2Friends {
-		if friendship.R != nil && friendship.R.User1 != nil {
-			friendUser := friendship.R.User1
-			friendMap[friendUser.ID] = &entities.User{
-				ID:    friendUser.ID,
-				Email: friendUser.Email,
-			}
-		}
+	var rows []struct {
+		UserID      int    `boil:"user_id"`
+		FriendID    int    `boil:"friend_id"`
+		FriendEmail string `boil:"friend_email"`
 	}
 
-	// Convert map to slice and sort by email
-	var friends []*entities.User
-	for _, friend := range friendMap {
-		friends = append(friends, friend)
+	err := queries.Raw(
+		`SELECT e.user_id, u.id AS friend_id, u.email AS friend_email
+		FROM (
+			SELECT user1_id AS user_id, user2_id AS friend_id FROM friends WHERE user1_id = ANY($1::int[])
+			UNION ALL
+			SELECT user2_id AS user_id, user1_id AS friend_id FROM friends WHERE user2_id = ANY($1::int[])
+		) e
+		JOIN users u ON u.id = e.friend_id
+		WHERE NOT EXISTS (
+			SELECT 1 FROM blocks b
+			WHERE (b.blocker_id = e.user_id AND b.blocked_id = e.friend_id)
+				OR (b.blocker_id = e.friend_id AND b.blocked_id = e.user_id)
+		)
+		ORDER BY e.user_id, u.id`,
+		pq.Array(userIDs),
+	).Bind(context.Background(), r.db, &rows)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to fetch friends batch")
 	}
 
-	// Sort friends by email using utility function
-	utils.SortUsersByEmail(friends)
+	for _, row := range rows {
+		result[row.UserID] = append(result[row.UserID], &entities.User{
+			ID:    row.FriendID,
+			Email: row.FriendEmail,
+		})
+	}
 
-	return friends, nil
+	return result, nil
 }
 
-func (r *userRepository) GetCommonFriends(user1, user2 *entities.User) ([]*entities.User, error) {
-	// Get friends of user1
-	user1Friends, err := r.GetFriendList(user1)
-	if err != nil {
-		return nil, err
+func (r *userRepository) GetFriendSuggestions(user *entities.User, limit int) ([]*entities.FriendSuggestion, error) {
+	var rows []struct {
+		ID            int    `boil:"id"`
+		Email         string `boil:"email"`
+		MutualFriends int    `boil:"mutual_friends"`
 	}
 
-	// Get friends of user2
-	user2Friends, err := r.GetFriendList(user2)
+	// Every friends-of-friends row is one mutual friend, so counting rows per
+	// candidate ranks them by mutual friends
+	err := queries.Raw(
+		`WITH user_friends AS (
+			SELECT CASE WHEN user1_id = $1 THEN user2_id ELSE user1_id END AS friend_id
+			FROM friends
+			WHERE user1_id = $1 OR user2_id = $1
+		), candidates AS (
+			SELECT CASE WHEN f.user1_id = uf.friend_id THEN f.user2_id ELSE f.user1_id END AS candidate_id
+			FROM friends f
+			JOIN user_friends uf ON f.user1_id = uf.friend_id OR f.user2_id = uf.friend_id
+		)
+		SELECT u.id, u.email, COUNT(*) AS mutual_friends
+		FROM candidates c
+		JOIN users u ON u.id = c.candidate_id
+		WHERE c.candidate_id <> $1
+			AND c.candidate_id NOT IN (SELECT friend_id FROM user_friends)
+			AND NOT EXISTS (
+				SELECT 1 FROM blocks b
+				WHERE (b.blocker_id = $1 AND b.blocked_id = c.candidate_id)
+					OR (b.blocker_id = c.candidate_id AND b.blocked_id = $1)
+			)
+		GROUP BY u.id, u.email
+		ORDER BY mutual_friends DESC, u.email
+		LIMIT $2`,
+		user.ID, limit,
+	).Bind(context.Background(), r.db, &rows)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to fetch friend suggestions")
 	}
 
-	// Create a map of user1's friends for efficient lookup
-	user1FriendMap := make(map[string]*entities.User)
-	for _, friend := range user1Friends {
-		user1FriendMap[friend.Email] = friend
-	}
-
-	// Find common friends
-	var commonFriends []*entities.User
-	for _, friend := range user2Friends {
-		if _, exists := user1FriendMap[friend.Email]; exists {
-			commonFriends = append(commonFriends, friend)
+	suggestions := make([]*entities.FriendSuggestion, len(rows))
+	for i, row := range rows {
+		suggestions[i] = &entities.FriendSuggestion{
+			User:          &entities.User{ID: row.ID, Email: row.Email},
+			MutualFriends: row.MutualFriends,
 		}
 	}
 
-	// Sort common friends by email using utility function
-	utils.SortUsersByEmail(commonFriends)
-
-	return commonFriends, nil
+	return suggestions, nil
 }
 
 func (r *userRepository) CreateSubscription(requestor, target *entities.User) error {
@@ -179,6 +406,65 @@ func (r *userRepository) CreateSubscription(requestor, target *entities.User) er
 	return nil
 }
 
+func (r *userRepository) DeleteSubscription(requestor, target *entities.User) error {
+	rowsAff, err := models.Subscriptions(
+		models.SubscriptionWhere.SubscriberID.EQ(requestor.ID),
+		models.SubscriptionWhere.TargetID.EQ(target.ID),
+	).DeleteAll(context.Background(), r.db)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to delete subscription")
+	}
+	if rowsAff == 0 {
+		return errors.ErrSubscriptionNotFound
+	}
+
+	return nil
+}
+
+func (r *userRepository) GetSubscriptionFilter(subscriber, target *entities.User) (*entities.SubscriptionFilter, error) {
+	var row struct {
+		IncludeKeywords pq.StringArray `boil:"include_keywords"`
+		ExcludeKeywords pq.StringArray `boil:"exclude_keywords"`
+	}
+
+	err := queries.Raw(
+		`SELECT include_keywords, exclude_keywords FROM subscriptions WHERE subscriber_id = $1 AND target_id = $2`,
+		subscriber.ID, target.ID,
+	).Bind(context.Background(), r.db, &row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrSubscriptionNotFound
+		}
+		return nil, errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to fetch subscription filter")
+	}
+
+	return &entities.SubscriptionFilter{
+		Include: row.IncludeKeywords,
+		Exclude: row.ExcludeKeywords,
+	}, nil
+}
+
+func (r *userRepository) UpdateSubscriptionFilter(subscriber, target *entities.User, filter *entities.SubscriptionFilter) error {
+	result, err := queries.Raw(
+		`UPDATE subscriptions SET include_keywords = $1, exclude_keywords = $2
+		WHERE subscriber_id = $3 AND target_id = $4`,
+		pq.Array(nonNilStrings(filter.Include)), pq.Array(nonNilStrings(filter.Exclude)), subscriber.ID, target.ID,
+	).ExecContext(context.Background(), r.db)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to update subscription filter")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to update subscription filter")
+	}
+	if rowsAff == 0 {
+		return errors.ErrSubscriptionNotFound
+	}
+
+	return nil
+}
+
 func (r *userRepository) CreateBlockTx(requestor, target *entities.User) error {
 	// Begin transaction
 	tx, err := r.db.BeginTx(context.Background(), nil)
@@ -191,6 +477,12 @@ func (r *userRepository) CreateBlockTx(requestor, target *entities.User) error {
 		}
 	}()
 
+	// Serialise with a friend request being accepted between the same users
+	err = r.lockUserPair(tx, requestor.ID, target.ID)
+	if err != nil {
+		return err
+	}
+
 	// 1. Remove friendship if it exists (bidirectional)
 	firstUserID := requestor.ID
 	secondUserID := target.ID
@@ -199,7 +491,7 @@ func (r *userRepository) CreateBlockTx(requestor, target *entities.User) error {
 		secondUserID = requestor.ID
 	}
 
-	_, err = models.Friends(
+	friendsDeleted, err := models.Friends(
 		models.FriendWhere.User1ID.EQ(firstUserID),
 		models.FriendWhere.User2ID.EQ(secondUserID),
 	).DeleteAll(context.Background(), tx)
@@ -209,7 +501,7 @@ func (r *userRepository) CreateBlockTx(requestor, target *entities.User) error {
 
 	// 2. Remove subscriptions from both sides
 	// Remove requestor's subscription to target
-	_, err = models.Subscriptions(
+	requestorSubsDeleted, err := models.Subscriptions(
 		models.SubscriptionWhere.SubscriberID.EQ(requestor.ID),
 		models.SubscriptionWhere.TargetID.EQ(target.ID),
 	).DeleteAll(context.Background(), tx)
@@ -218,7 +510,7 @@ func (r *userRepository) CreateBlockTx(requestor, target *entities.User) error {
 	}
 
 	// Remove target's subscription to requestor
-	_, err = models.Subscriptions(
+	targetSubsDeleted, err := models.Subscriptions(
 		models.SubscriptionWhere.SubscriberID.EQ(target.ID),
 		models.SubscriptionWhere.TargetID.EQ(requestor.ID),
 	).DeleteAll(context.Background(), tx)
@@ -237,6 +529,134 @@ func (r *userRepository) CreateBlockTx(requestor, target *entities.User) error {
 		return errors.FromError(err)
 	}
 
+	// 4. Snapshot the removed relationships so an unblock can restore them
+	_, err = queries.Raw(
+		`INSERT INTO block_snapshots (block_id, had_friendship, blocker_subscribed, blocked_subscribed)
+		VALUES ($1, $2, $3, $4)`,
+		block.ID, friendsDeleted > 0, requestorSubsDeleted > 0, targetSubsDeleted > 0,
+	).ExecContext(context.Background(), tx)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to snapshot block relationships")
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to commit transaction")
+	}
+
+	return nil
+}
+
+// blockSnapshot mirrors a row of the block_snapshots table
+type blockSnapshot struct {
+	HadFriendship     bool `boil:"had_friendship"`
+	BlockerSubscribed bool `boil:"blocker_subscribed"`
+	BlockedSubscribed bool `boil:"blocked_subscribed"`
+}
+
+func (r *userRepository) DeleteBlockTx(requestor, target *entities.User, restore bool) error {
+	// Begin transaction
+	tx, err := r.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to begin transaction")
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Serialise with the target blocking the requestor at the same time
+	err = r.lockUserPair(tx, requestor.ID, target.ID)
+	if err != nil {
+		return err
+	}
+
+	// 1. Find the block
+	block, err := models.Blocks(
+		models.BlockWhere.BlockerID.EQ(requestor.ID),
+		models.BlockWhere.BlockedID.EQ(target.ID),
+	).One(context.Background(), tx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = errors.ErrBlockNotFound
+			return err
+		}
+		return errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to fetch block")
+	}
+
+	// 2. Read the snapshot before the block (and its snapshot) is deleted
+	var snapshot blockSnapshot
+	if restore {
+		// Nothing is restored while the target still blocks the requestor
+		var isBlocked bool
+		isBlocked, err = r.checkBlockExists(tx, target.ID, requestor.ID)
+		if err != nil {
+			return err
+		}
+		if isBlocked {
+			err = errors.ErrUserBlocked
+			return err
+		}
+
+		err = queries.Raw(
+			`SELECT had_friendship, blocker_subscribed, blocked_subscribed FROM block_snapshots WHERE block_id = $1`,
+			block.ID,
+		).Bind(context.Background(), tx, &snapshot)
+		if err != nil && err != sql.ErrNoRows {
+			return errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to fetch block snapshot")
+		}
+		// Blocks created before snapshots existed have nothing to restore
+		err = nil
+	}
+
+	// 3. Remove the block
+	_, err = block.Delete(context.Background(), tx)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to delete block")
+	}
+
+	// 4. Restore the relationships removed at block time
+	if snapshot.HadFriendship {
+		firstUserID := requestor.ID
+		secondUserID := target.ID
+		if requestor.ID > target.ID {
+			firstUserID = target.ID
+			secondUserID = requestor.ID
+		}
+
+		friend := &models.Friend{
+			User1ID: firstUserID,
+			User2ID: secondUserID,
+		}
+		err = friend.Insert(context.Background(), tx, boil.Infer())
+		if err != nil {
+			return errors.FromError(err)
+		}
+	}
+
+	if snapshot.BlockerSubscribed {
+		subscription := &models.Subscription{
+			SubscriberID: requestor.ID,
+			TargetID:     target.ID,
+		}
+		err = subscription.Insert(context.Background(), tx, boil.Infer())
+		if err != nil {
+			return errors.FromError(err)
+		}
+	}
+
+	if snapshot.BlockedSubscribed {
+		subscription := &models.Subscription{
+			SubscriberID: target.ID,
+			TargetID:     requestor.ID,
+		}
+		err = subscription.Insert(context.Background(), tx, boil.Infer())
+		if err != nil {
+			return errors.FromError(err)
+		}
+	}
+
 	// Commit transaction
 	if err = tx.Commit(); err != nil {
 		return errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to commit transaction")
@@ -246,10 +666,14 @@ func (r *userRepository) CreateBlockTx(requestor, target *entities.User) error {
 }
 
 func (r *userRepository) CheckBlockExists(requestorID, targetID int) (bool, error) {
+	return r.checkBlockExists(r.db, requestorID, targetID)
+}
+
+func (r *userRepository) checkBlockExists(exec boil.ContextExecutor, requestorID, targetID int) (bool, error) {
 	_, err := models.Blocks(
 		models.BlockWhere.BlockerID.EQ(requestorID),
 		models.BlockWhere.BlockedID.EQ(targetID),
-	).One(context.Background(), r.db)
+	).One(context.Background(), exec)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return false, nil
@@ -260,8 +684,12 @@ func (r *userRepository) CheckBlockExists(requestorID, targetID int) (bool, erro
 }
 
 func (r *userRepository) CheckBidirectionalBlock(user1ID, user2ID int) (bool, error) {
+	return r.checkBidirectionalBlock(r.db, user1ID, user2ID)
+}
+
+func (r *userRepository) checkBidirectionalBlock(exec boil.ContextExecutor, user1ID, user2ID int) (bool, error) {
 	// Check if user1 blocks user2
-	blocked1, err := r.CheckBlockExists(user1ID, user2ID)
+	blocked1, err := r.checkBlockExists(exec, user1ID, user2ID)
 	if err != nil {
 		return false, err
 	}
@@ -270,13 +698,27 @@ func (r *userRepository) CheckBidirectionalBlock(user1ID, user2ID int) (bool, er
 	}
 
 	// Check if user2 blocks user1
-	blocked2, err := r.CheckBlockExists(user2ID, user1ID)
+	blocked2, err := r.checkBlockExists(exec, user2ID, user1ID)
 	if err != nil {
 		return false, err
 	}
 	return blocked2, nil
 }
 
+// lockUserPair locks the rows of both users until the transaction ends, so
+// transactions changing the relationship between the two run one at a time.
+// Rows are locked in ID order to avoid deadlocks
+func (r *userRepository) lockUserPair(exec boil.ContextExecutor, user1ID, user2ID int) error {
+	_, err := queries.Raw(
+		`SELECT id FROM users WHERE id IN ($1, $2) ORDER BY id FOR UPDATE`,
+		user1ID, user2ID,
+	).ExecContext(context.Background(), exec)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to lock users")
+	}
+	return nil
+}
+
 func (r *userRepository) CheckBidirectionalBlocksBatch(senderID int, userIDs []int) (map[int]bool, error) {
 	if len(userIDs) == 0 {
 		return make(map[int]bool), nil
@@ -323,9 +765,136 @@ func (r *userRepository) CheckBidirectionalBlocksBatch(senderID int, userIDs []i
 	return result, nil
 }
 
+// UpsertMute mutes muted for muter, replacing the expiry of an existing mute
+func (r *userRepository) UpsertMute(muter, muted *entities.User, expiresAt *time.Time) (*entities.Mute, error) {
+	var row struct {
+		ExpiresAt sql.NullTime `boil:"expires_at"`
+		CreatedAt time.Time    `boil:"created_at"`
+	}
+
+	expires := sql.NullTime{}
+	if expiresAt != nil {
+		expires = sql.NullTime{Time: *expiresAt, Valid: true}
+	}
+
+	err := queries.Raw(
+		`INSERT INTO mutes (muter_id, muted_id, expires_at) VALUES ($1, $2, $3)
+		ON CONFLICT (muter_id, muted_id) DO UPDATE SET expires_at = EXCLUDED.expires_at, created_at = NOW()
+		RETURNING expires_at, created_at`,
+		muter.ID, muted.ID, expires,
+	).Bind(context.Background(), r.db, &row)
+	if err != nil {
+		return nil, errors.FromError(err)
+	}
+
+	return &entities.Mute{
+		Muter:     muter,
+		Muted:     muted,
+		ExpiresAt: nullTimePtr(row.ExpiresAt),
+		CreatedAt: row.CreatedAt,
+	}, nil
+}
+
+func (r *userRepository) DeleteMute(muter, muted *entities.User) error {
+	result, err := queries.Raw(
+		`DELETE FROM mutes
+		WHERE muter_id = $1 AND muted_id = $2
+			AND (expires_at IS NULL OR expires_at > NOW())`,
+		muter.ID, muted.ID,
+	).ExecContext(context.Background(), r.db)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to delete mute")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to delete mute")
+	}
+	if rowsAff == 0 {
+		return errors.ErrMuteNotFound
+	}
+
+	return nil
+}
+
+// GetMutes lists the muter's active mutes, sorted by the muted user's email
+func (r *userRepository) GetMutes(muter *entities.User) ([]*entities.Mute, error) {
+	var rows []struct {
+		MutedID   int          `boil:"muted_id"`
+		Email     string       `boil:"email"`
+		ExpiresAt sql.NullTime `boil:"expires_at"`
+		CreatedAt time.Time    `boil:"created_at"`
+	}
+
+	err := queries.Raw(
+		`SELECT mu.muted_id, u.email, mu.expires_at, mu.created_at
+		FROM mutes mu
+		JOIN users u ON u.id = mu.muted_id
+		WHERE mu.muter_id = $1
+			AND (mu.expires_at IS NULL OR mu.expires_at > NOW())
+		ORDER BY u.email`,
+		muter.ID,
+	).Bind(context.Background(), r.db, &rows)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to fetch mutes")
+	}
+
+	mutes := make([]*entities.Mute, len(rows))
+	for i, row := range rows {
+		mutes[i] = &entities.Mute{
+			Muter:     muter,
+			Muted:     &entities.User{ID: row.MutedID, Email: row.Email},
+			ExpiresAt: nullTimePtr(row.ExpiresAt),
+			CreatedAt: row.CreatedAt,
+		}
+	}
+
+	return mutes, nil
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func (r *userRepository) GetRelationship(userA, userB *entities.User) (*entities.Relationship, error) {
+	var row struct {
+		AreFriends     bool `boil:"are_friends"`
+		ASubscribesToB bool `boil:"a_subscribes_to_b"`
+		BSubscribesToA bool `boil:"b_subscribes_to_a"`
+		ABlocksB       bool `boil:"a_blocks_b"`
+		BBlocksA       bool `boil:"b_blocks_a"`
+	}
+
+	err := queries.Raw(
+		`SELECT
+			EXISTS (SELECT 1 FROM friends WHERE user1_id = LEAST($1::int, $2::int) AND user2_id = GREATEST($1::int, $2::int)) AS are_friends,
+			EXISTS (SELECT 1 FROM subscriptions WHERE subscriber_id = $1 AND target_id = $2) AS a_subscribes_to_b,
+			EXISTS (SELECT 1 FROM subscriptions WHERE subscriber_id = $2 AND target_id = $1) AS b_subscribes_to_a,
+			EXISTS (SELECT 1 FROM blocks WHERE blocker_id = $1 AND blocked_id = $2) AS a_blocks_b,
+			EXISTS (SELECT 1 FROM blocks WHERE blocker_id = $2 AND blocked_id = $1) AS b_blocks_a`,
+		userA.ID, userB.ID,
+	).Bind(context.Background(), r.db, &row)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to fetch relationship")
+	}
+
+	return &entities.Relationship{
+		AreFriends:     row.AreFriends,
+		ASubscribesToB: row.ASubscribesToB,
+		BSubscribesToA: row.BSubscribesToA,
+		ABlocksB:       row.ABlocksB,
+		BBlocksA:       row.BBlocksA,
+	}, nil
+}
+
+// GetUserByEmail finds a user regardless of the case of email, as emails are
+// stored lowercase
 func (r *userRepository) GetUserByEmail(email string) (*entities.User, error) {
 	user, err := models.Users(
-		models.UserWhere.Email.EQ(email),
+		models.UserWhere.Email.EQ(strings.ToLower(email)),
 	).One(context.Background(), r.db)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -345,8 +914,13 @@ func (r *userRepository) GetUsersByEmails(emails []string) ([]*entities.User, er
 		return []*entities.User{}, nil
 	}
 
+	lowered := make([]string, len(emails))
+	for i, email := range emails {
+		lowered[i] = strings.ToLower(email)
+	}
+
 	users, err := models.Users(
-		models.UserWhere.Email.IN(emails),
+		models.UserWhere.Email.IN(lowered),
 	).All(context.Background(), r.db)
 	if err != nil {
 		return nil, errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to fetch users by emails")
@@ -363,30 +937,590 @@ func (r *userRepository) GetUsersByEmails(emails []string) ([]*entities.User, er
 	return result, nil
 }
 
-func (r *userRepository) GetSubscribersByUserID(userID int) ([]*entities.User, error) {
-	// Get all subscriptions where this user is the target
-	subscriptions, err := models.Subscriptions(
-		models.SubscriptionWhere.TargetID.EQ(userID),
-		qm.Load(models.SubscriptionRels.Subscriber),
+// GetEmailsByUsernames maps each of the given usernames that belongs to a user
+// to that user's email, matching usernames case-insensitively
+func (r *userRepository) GetEmailsByUsernames(usernames []string) (map[string]string, error) {
+	emails := make(map[string]string)
+	if len(usernames) == 0 {
+		return emails, nil
+	}
+
+	var rows []struct {
+		Username string `boil:"username"`
+		Email    string `boil:"email"`
+	}
+
+	err := queries.Raw(
+		`SELECT lower(username) AS username, email FROM users WHERE lower(username) = ANY($1::text[])`,
+		pq.Array(usernames),
+	).Bind(context.Background(), r.db, &rows)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to fetch users by usernames")
+	}
+
+	for _, row := range rows {
+		emails[row.Username] = row.Email
+	}
+
+	return emails, nil
+}
+
+func (r *userRepository) SetUsername(user *entities.User, username string) error {
+	// Let the unique index on lower(username) report a handle that is taken
+	result, err := queries.Raw(
+		`UPDATE users SET username = $1 WHERE id = $2`,
+		username, user.ID,
+	).ExecContext(context.Background(), r.db)
+	if err != nil {
+		return errors.FromError(err)
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to set username")
+	}
+	if rowsAff == 0 {
+		return errors.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *userRepository) GetSubscribersByUserID(userID int, page entities.PageRequest) (*entities.UserPage, error) {
+	return r.getUserPage(
+		`SELECT u.id, u.email
+		FROM subscriptions s
+		JOIN users u ON u.id = s.subscriber_id
+		WHERE s.target_id = $1`,
+		[]any{userID}, page, "Failed to fetch subscribers",
+	)
+}
+
+func (r *userRepository) GetSubscriptionsByUserID(userID int, page entities.PageRequest) (*entities.UserPage, error) {
+	return r.getUserPage(
+		`SELECT u.id, u.email
+		FROM subscriptions s
+		JOIN users u ON u.id = s.target_id
+		WHERE s.subscriber_id = $1`,
+		[]any{userID}, page, "Failed to fetch subscriptions",
+	)
+}
+
+// CountSubscribersBatch returns the number of subscribers of every given user
+// that has any, users without subscribers are left out
+func (r *userRepository) CountSubscribersBatch(userIDs []int) (map[int]int, error) {
+	result := make(map[int]int)
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+
+	var rows []struct {
+		UserID      int `boil:"user_id"`
+		Subscribers int `boil:"subscribers"`
+	}
+
+	err := queries.Raw(
+		`SELECT target_id AS user_id, COUNT(*) AS subscribers
+		FROM subscriptions
+		WHERE target_id = ANY($1::int[])
+		GROUP BY target_id`,
+		pq.Array(userIDs),
+	).Bind(context.Background(), r.db, &rows)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to count subscribers")
+	}
+
+	for _, row := range rows {
+		result[row.UserID] = row.Subscribers
+	}
+
+	return result, nil
+}
+
+func (r *userRepository) GetBlockedUsers(blocker *entities.User, page entities.PageRequest) (*entities.UserPage, error) {
+	return r.getUserPage(
+		`SELECT u.id, u.email
+		FROM blocks b
+		JOIN users u ON u.id = b.blocked_id
+		WHERE b.blocker_id = $1`,
+		[]any{blocker.ID}, page, "Failed to fetch blocked users",
+	)
+}
+
+// GetBlockers lists the users who block the given user
+func (r *userRepository) GetBlockers(blocked *entities.User, page entities.PageRequest) (*entities.UserPage, error) {
+	return r.getUserPage(
+		`SELECT u.id, u.email
+		FROM blocks b
+		JOIN users u ON u.id = b.blocker_id
+		WHERE b.blocked_id = $1`,
+		[]any{blocked.ID}, page, "Failed to fetch blockers",
+	)
+}
+
+// friendEdges selects a row per friend of each user whose ID is in the array
+// bound to param, as user_id and friend_id
+func friendEdges(param string) string {
+	return fmt.Sprintf(
+		`SELECT user1_id AS user_id, user2_id AS friend_id FROM friends WHERE user1_id = ANY(%[1]s::int[])
+		UNION ALL
+		SELECT user2_id AS user_id, user1_id AS friend_id FROM friends WHERE user2_id = ANY(%[1]s::int[])`,
+		param,
+	)
+}
+
+// GetFriendListsBatch returns the same page of the friend list of every given
+// user, by user ID
+func (r *userRepository) GetFriendListsBatch(userIDs []int, page entities.PageRequest) (map[int]*entities.UserPage, error) {
+	return r.getUserPagesBatch(
+		`SELECT e.user_id AS owner_id, u.id, u.email
+		FROM (`+friendEdges("$1")+`) e
+		JOIN users u ON u.id = e.friend_id`,
+		userIDs, nil, page, "Failed to fetch friends",
+	)
+}
+
+// GetSubscribersBatch returns the same page of the subscribers of every given
+// user, by user ID
+func (r *userRepository) GetSubscribersBatch(userIDs []int, page entities.PageRequest) (map[int]*entities.UserPage, error) {
+	return r.getUserPagesBatch(
+		`SELECT s.target_id AS owner_id, u.id, u.email
+		FROM subscriptions s
+		JOIN users u ON u.id = s.subscriber_id
+		WHERE s.target_id = ANY($1::int[])`,
+		userIDs, nil, page, "Failed to fetch subscribers",
+	)
+}
+
+// GetBlockersBatch returns the same page of the users who block every given
+// user, by user ID
+func (r *userRepository) GetBlockersBatch(userIDs []int, page entities.PageRequest) (map[int]*entities.UserPage, error) {
+	return r.getUserPagesBatch(
+		`SELECT b.blocked_id AS owner_id, u.id, u.email
+		FROM blocks b
+		JOIN users u ON u.id = b.blocker_id
+		WHERE b.blocked_id = ANY($1::int[])`,
+		userIDs, nil, page, "Failed to fetch blockers",
+	)
+}
+
+// GetCommonFriendsBatch returns the same page of the friends every given user
+// has in common with all of others, by user ID
+func (r *userRepository) GetCommonFriendsBatch(userIDs []int, others []*entities.User, page entities.PageRequest) (map[int]*entities.UserPage, error) {
+	otherIDs := make([]int, len(others))
+	for i, other := range others {
+		otherIDs[i] = other.ID
+	}
+
+	// The friends common to the others are found once, then intersected with
+	// the friends of each user
+	return r.getUserPagesBatch(
+		`SELECT e.user_id AS owner_id, u.id, u.email
+		FROM (`+friendEdges("$1")+`) e
+		JOIN (
+			SELECT o.friend_id
+			FROM (`+friendEdges("$2")+`) o
+			GROUP BY o.friend_id
+			HAVING COUNT(DISTINCT o.user_id) = $3
+		) c ON c.friend_id = e.friend_id
+		JOIN users u ON u.id = e.friend_id`,
+		userIDs, []any{pq.Array(otherIDs), len(otherIDs)}, page, "Failed to fetch common friends",
+	)
+}
+
+// getUserPagesBatch fetches the same keyset page of list for every user whose
+// ID is in userIDs, together with the size of each whole list, in one query.
+// list selects the owner_id of the user a row is listed for and the id and email
+// of the user listed; userIDs is bound to $1 and args to the parameters after it.
+// Every given user gets a page, empty when nobody is listed for them
+func (r *userRepository) getUserPagesBatch(list string, userIDs []int, args []any, page entities.PageRequest, failure string) (map[int]*entities.UserPage, error) {
+	pages := make(map[int]*entities.UserPage, len(userIDs))
+	for _, userID := range userIDs {
+		pages[userID] = &entities.UserPage{Users: []*entities.User{}}
+	}
+	if len(userIDs) == 0 {
+		return pages, nil
+	}
+
+	// An owner's total is counted before the cursor is applied, and kept by
+	// the left join when no row of theirs is left after it
+	n := len(args) + 1
+	var rows []struct {
+		OwnerID int            `boil:"owner_id"`
+		Total   int            `boil:"total"`
+		ID      sql.NullInt64  `boil:"id"`
+		Email   sql.NullString `boil:"email"`
+	}
+
+	err := queries.Raw(
+		fmt.Sprintf(
+			`WITH l AS (%s),
+			t AS (
+				SELECT owner_id, COUNT(*) AS total FROM l GROUP BY owner_id
+			),
+			p AS (
+				SELECT owner_id, id, email,
+					ROW_NUMBER() OVER (PARTITION BY owner_id ORDER BY email COLLATE "C") AS position
+				FROM l
+				WHERE email COLLATE "C" > $%d
+			)
+			SELECT t.owner_id, t.total, p.id, p.email
+			FROM t
+			LEFT JOIN p ON p.owner_id = t.owner_id AND ($%d::int = 0 OR p.position <= $%d::int)
+			ORDER BY t.owner_id, p.email COLLATE "C"`,
+			list, n+1, n+2, n+2,
+		),
+		append(append([]any{pq.Array(userIDs)}, args...), page.After, page.Limit)...,
+	).Bind(context.Background(), r.db, &rows)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeDatabase, failure)
+	}
+
+	for _, row := range rows {
+		userPage := pages[row.OwnerID]
+		userPage.Total = row.Total
+		if row.ID.Valid {
+			userPage.Users = append(userPage.Users, &entities.User{ID: int(row.ID.Int64), Email: row.Email.String})
+		}
+	}
+
+	return pages, nil
+}
+
+// keysetPage wraps list, a query whose rows have an email column, to return the
+// rows after the email bound to $<n+1>, at most $<n+2> of them or all when that
+// is 0, where n is the number of arguments list takes. Emails compare with the
+// "C" collation, bytewise whatever the database locale, so cursors stay stable
+func keysetPage(list string, n int) string {
+	return fmt.Sprintf(
+		`SELECT * FROM (%s) l
+		WHERE l.email COLLATE "C" > $%d
+		ORDER BY l.email COLLATE "C"
+		LIMIT NULLIF($%d::int, 0)`,
+		list, n+1, n+2,
+	)
+}
+
+// countTotal counts the rows of list for a page of fetched rows. The count query
+// is skipped when the page is the first one and not full, since it then holds
+// the whole list
+func (r *userRepository) countTotal(list string, args []any, page entities.PageRequest, fetched int) (int, error) {
+	if page.After == "" && (page.Limit == 0 || fetched < page.Limit) {
+		return fetched, nil
+	}
+
+	var row struct {
+		Total int `boil:"total"`
+	}
+
+	err := queries.Raw(
+		`SELECT COUNT(*) AS total FROM (`+list+`) l`,
+		args...,
+	).Bind(context.Background(), r.db, &row)
+	if err != nil {
+		return 0, err
+	}
+
+	return row.Total, nil
+}
+
+// getUserPage fetches a keyset page of list, a query selecting the id and email
+// of users, together with the size of the whole list
+func (r *userRepository) getUserPage(list string, args []any, page entities.PageRequest, failure string) (*entities.UserPage, error) {
+	var rows []struct {
+		ID    int    `boil:"id"`
+		Email string `boil:"email"`
+	}
+
+	err := queries.Raw(
+		keysetPage(list, len(args)),
+		append(append([]any{}, args...), page.After, page.Limit)...,
+	).Bind(context.Background(), r.db, &rows)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeDatabase, failure)
+	}
+
+	total, err := r.countTotal(list, args, page, len(rows))
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeDatabase, failure)
+	}
+
+	users := make([]*entities.User, len(rows))
+	for i, row := range rows {
+		users[i] = &entities.User{ID: row.ID, Email: row.Email}
+	}
+
+	return &entities.UserPage{Users: users, Total: total}, nil
+}
+
+// subscriptionFilterMatches is the condition under which a subscriptions row
+// passes its keyword filters for an update whose keywords are bound to $3
+const subscriptionFilterMatches = `(cardinality(include_keywords) = 0 OR include_keywords && $3::text[])
+				AND NOT (exclude_keywords && $3::text[])`
+
+// nonNilStrings keeps pq.Array from sending NULL for a nil slice
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// GetRecipients resolves everyone who should receive an update from sender in a
+// single query: friends, subscribers whose keyword filters match the update's
+// keywords and mentioned users whose mention policy allows the sender, minus
+// anyone who blocks or is blocked by the sender and anyone with an active mute
+// on the sender. Recipients are paged by email
+func (r *userRepository) GetRecipients(sender *entities.User, mentionedEmails, keywords []string, page entities.PageRequest) (*entities.RecipientPage, error) {
+	var rows []struct {
+		ID           int    `boil:"id"`
+		Email        string `boil:"email"`
+		IsFriend     bool   `boil:"is_friend"`
+		IsSubscriber bool   `boil:"is_subscriber"`
+		IsMentioned  bool   `boil:"is_mentioned"`
+	}
+
+	list := `WITH candidates AS (
+			SELECT CASE WHEN user1_id = $1 THEN user2_id ELSE user1_id END AS user_id, 'friend' AS reason
+			FROM friends
+			WHERE user1_id = $1 OR user2_id = $1
+			UNION ALL
+			SELECT subscriber_id AS user_id, 'subscriber' AS reason
+			FROM subscriptions
+			WHERE target_id = $1
+				AND `+subscriptionFilterMatches+`
+			UNION ALL
+			SELECT u.id AS user_id, 'mentioned' AS reason
+			FROM users u
+			LEFT JOIN user_settings us ON us.user_id = u.id
+			WHERE u.email = ANY($2::text[])
+				AND (
+					COALESCE(us.mention_policy, 'everyone') = 'everyone'
+					OR (us.mention_policy = 'friends' AND EXISTS (
+						SELECT 1 FROM friends f
+						WHERE (f.user1_id = $1 AND f.user2_id = u.id)
+							OR (f.user1_id = u.id AND f.user2_id = $1)
+					))
+				)
+		)
+		SELECT u.id, u.email,
+			bool_or(c.reason = 'friend') AS is_friend,
+			bool_or(c.reason = 'subscriber') AS is_subscriber,
+			bool_or(c.reason = 'mentioned') AS is_mentioned
+		FROM candidates c
+		JOIN users u ON u.id = c.user_id
+		WHERE c.user_id <> $1
+			AND NOT EXISTS (
+				SELECT 1 FROM blocks b
+				WHERE (b.blocker_id = $1 AND b.blocked_id = c.user_id)
+					OR (b.blocker_id = c.user_id AND b.blocked_id = $1)
+			)
+			AND NOT EXISTS (
+				SELECT 1 FROM mutes mu
+				WHERE mu.muter_id = c.user_id AND mu.muted_id = $1
+					AND (mu.expires_at IS NULL OR mu.expires_at > NOW())
+			)
+		GROUP BY u.id, u.email`
+	args := []any{sender.ID, pq.Array(mentionedEmails), pq.Array(nonNilStrings(keywords))}
+
+	err := queries.Raw(
+		keysetPage(list, len(args)),
+		append(args, page.After, page.Limit)...,
+	).Bind(context.Background(), r.db, &rows)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to fetch recipients")
+	}
+
+	total, err := r.countTotal(list, args, page, len(rows))
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to fetch recipients")
+	}
+
+	recipients := make([]*entities.Recipient, len(rows))
+	for i, row := range rows {
+		var reasons []entities.RecipientReason
+		if row.IsFriend {
+			reasons = append(reasons, entities.RecipientReasonFriend)
+		}
+		if row.IsSubscriber {
+			reasons = append(reasons, entities.RecipientReasonSubscriber)
+		}
+		if row.IsMentioned {
+			reasons = append(reasons, entities.RecipientReasonMentioned)
+		}
+
+		recipients[i] = &entities.Recipient{
+			User:    &entities.User{ID: row.ID, Email: row.Email},
+			Reasons: reasons,
+		}
+	}
+
+	return &entities.RecipientPage{Recipients: recipients, Total: total}, nil
+}
+
+// GetUserSettings returns the user's settings, or the defaults when they were never changed
+func (r *userRepository) GetUserSettings(user *entities.User) (*entities.UserSettings, error) {
+	var row struct {
+		MentionPolicy string `boil:"mention_policy"`
+	}
+
+	err := queries.Raw(
+		`SELECT mention_policy FROM user_settings WHERE user_id = $1`,
+		user.ID,
+	).Bind(context.Background(), r.db, &row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return &entities.UserSettings{
+				User:          user,
+				MentionPolicy: entities.MentionPolicyEveryone,
+			}, nil
+		}
+		return nil, errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to fetch user settings")
+	}
+
+	return &entities.UserSettings{
+		User:          user,
+		MentionPolicy: entities.MentionPolicy(row.MentionPolicy),
+	}, nil
+}
+
+func (r *userRepository) UpdateUserSettings(settings *entities.UserSettings) error {
+	_, err := queries.Raw(
+		`INSERT INTO user_settings (user_id, mention_policy) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET mention_policy = EXCLUDED.mention_policy, updated_at = NOW()`,
+		settings.User.ID, string(settings.MentionPolicy),
+	).ExecContext(context.Background(), r.db)
+	if err != nil {
+		return errors.FromError(err)
+	}
+
+	return nil
+}
+
+// GetDroppedMentions lists the mentioned emails that GetRecipients leaves out,
+// because no such user exists, it is the sender, a block exists either way, the
+// user has muted the sender, or the user's mention policy does not allow the sender
+func (r *userRepository) GetDroppedMentions(sender *entities.User, mentionedEmails, keywords []string) ([]*entities.DroppedMention, error) {
+	if len(mentionedEmails) == 0 {
+		return []*entities.DroppedMention{}, nil
+	}
+
+	var rows []struct {
+		Email  string `boil:"email"`
+		Reason string `boil:"reason"`
+	}
+
+	// A restricted mention is only dropped when the user is not a recipient
+	// anyway as a friend or subscriber
+	err := queries.Raw(
+		`WITH mentioned AS (
+			SELECT m.email, u.id,
+				EXISTS (
+					SELECT 1 FROM blocks b
+					WHERE (b.blocker_id = $1 AND b.blocked_id = u.id)
+						OR (b.blocker_id = u.id AND b.blocked_id = $1)
+				) AS is_blocked,
+				EXISTS (
+					SELECT 1 FROM mutes mu
+					WHERE mu.muter_id = u.id AND mu.muted_id = $1
+						AND (mu.expires_at IS NULL OR mu.expires_at > NOW())
+				) AS is_muted,
+				EXISTS (
+					SELECT 1 FROM friends f
+					WHERE (f.user1_id = $1 AND f.user2_id = u.id)
+						OR (f.user1_id = u.id AND f.user2_id = $1)
+				) AS is_friend,
+				EXISTS (
+					SELECT 1 FROM subscriptions
+					WHERE subscriber_id = u.id AND target_id = $1
+						AND `+subscriptionFilterMatches+`
+				) AS is_subscriber,
+				COALESCE(us.mention_policy, 'everyone') AS mention_policy
+			FROM (SELECT DISTINCT unnest($2::text[]) AS email) m
+			LEFT JOIN users u ON u.email = m.email
+			LEFT JOIN user_settings us ON us.user_id = u.id
+		)
+		SELECT email,
+			CASE
+				WHEN id IS NULL THEN 'unknown'
+				WHEN id = $1 THEN 'self'
+				WHEN is_blocked THEN 'blocked'
+				WHEN is_muted THEN 'muted'
+				ELSE 'restricted'
+			END AS reason
+		FROM mentioned
+		WHERE id IS NULL
+			OR id = $1
+			OR is_blocked
+			OR is_muted
+			OR (mention_policy <> 'everyone' AND NOT is_friend AND NOT is_subscriber)
+		ORDER BY email`,
+		sender.ID, pq.Array(mentionedEmails), pq.Array(nonNilStrings(keywords)),
+	).Bind(context.Background(), r.db, &rows)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to fetch dropped mentions")
+	}
+
+	dropped := make([]*entities.DroppedMention, len(rows))
+	for i, row := range rows {
+		dropped[i] = &entities.DroppedMention{
+			Email:  row.Email,
+			Reason: entities.DroppedMentionReason(row.Reason),
+		}
+	}
+
+	return dropped, nil
+}
+
+// CreateUser stores the email lowercase, so that mentions and lookups, which
+// ignore case, find the user
+func (r *userRepository) CreateUser(email string) (*entities.User, error) {
+	user := &models.User{
+		Email: strings.ToLower(email),
+	}
+
+	// Let the unique constraint on users.email report duplicates
+	err := user.Insert(context.Background(), r.db, boil.Infer())
+	if err != nil {
+		return nil, errors.FromError(err)
+	}
+
+	return &entities.User{
+		ID:    user.ID,
+		Email: user.Email,
+	}, nil
+}
+
+func (r *userRepository) GetAllUsers() ([]*entities.User, error) {
+	users, err := models.Users(
+		qm.OrderBy(models.UserColumns.Email),
 	).All(context.Background(), r.db)
 	if err != nil {
-		return nil, errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to fetch subscribers")
+		return nil, errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to fetch users")
 	}
 
-	// Convert to entities
-	var subscribers []*entities.User
-	for _, subscription := range subscriptions {
-		if subscription.R != nil && subscription.R.Subscriber != nil {
-			subscriber := subscription.R.Subscriber
-			subscribers = append(subscribers, &entities.User{
-				ID:    subscriber.ID,
-				Email: subscriber.Email,
-			})
+	result := make([]*entities.User, len(users))
+	for i, user := range users {
+		result[i] = &entities.User{
+			ID:    user.ID,
+			Email: user.Email,
 		}
 	}
 
-	// Sort subscribers by email using utility function
-	utils.SortUsersByEmail(subscribers)
+	return result, nil
+}
+
+func (r *userRepository) DeleteUser(user *entities.User) error {
+	// Friendships, subscriptions and blocks are removed by ON DELETE CASCADE
+	rowsAff, err := models.Users(
+		models.UserWhere.ID.EQ(user.ID),
+	).DeleteAll(context.Background(), r.db)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to delete user")
+	}
+	if rowsAff == 0 {
+		return errors.Newf(errors.ErrorTypeNotFound, "User not found: %s", user.Email)
+	}
 
-	return subscribers, nil
+	return nil
 }
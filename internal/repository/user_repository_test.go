@@ -2,11 +2,16 @@ package repository
 
 import (
 	"assignment/internal/domain/entities"
+	"assignment/internal/domain/interfaces"
+	"assignment/pkg/errors"
 	"context"
 	"database/sql"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -18,7 +23,7 @@ import (
 )
 
 // runMigrations executes all .up.sql migration files in order
-func runMigrations(t *testing.T, db *sql.DB) error {
+func runMigrations(t testing.TB, db *sql.DB) error {
 	migrationsDir := filepath.Join("..", "..", "db", "migrations")
 
 	// Read all files in migrations directory
@@ -56,7 +61,7 @@ func runMigrations(t *testing.T, db *sql.DB) error {
 	return nil
 }
 
-func setupTestContainer(t *testing.T) (*sql.DB, func()) {
+func setupTestContainer(t testing.TB) (*sql.DB, func()) {
 	ctx := context.Background()
 
 	pgContainer, err := postgres.Run(ctx,
@@ -98,75 +103,14 @@ func setupTestContainer(t *testing.T) (*sql.DB, func()) {
 	return db, cleanup
 }
 
-func TestUserRepository_CreateFriendship(t *testing.T) {
-	db, cleanup := setupTestContainer(t)
-	defer cleanup()
-
-	repo := NewUserRepository(db)
-
-	tests := []struct {
-		name    string
-		user1   *entities.User
-		user2   *entities.User
-		wantErr bool
-	}{
-		{
-			name: "successful friendship creation",
-			user1: &entities.User{
-				ID:    1,
-				Email: "andy@mail.com",
-			},
-			user2: &entities.User{
-				ID:    2,
-				Email: "alice@mail.com",
-			},
-			wantErr: false,
-		},
-		{
-			name: "duplicate friendship should fail",
-			user1: &entities.User{
-				ID:    1,
-				Email: "andy@mail.com",
-			},
-			user2: &entities.User{
-				ID:    2,
-				Email: "alice@mail.com",
-			},
-			wantErr: true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			err := repo.CreateFriendship(tt.user1, tt.user2)
-
-			if tt.wantErr {
-				if err == nil {
-					t.Error("expected error, got nil")
-				}
-			} else {
-				if err != nil {
-					t.Errorf("expected no error, got %v", err)
-				}
-
-				// Verify friendship was created
-				var count int
-				query := "SELECT COUNT(*) FROM friends WHERE user1_id = $1 AND user2_id = $2"
-				user1ID, user2ID := tt.user1.ID, tt.user2.ID
-				if user1ID > user2ID {
-					user1ID, user2ID = user2ID, user1ID
-				}
-
-				err = db.QueryRow(query, user1ID, user2ID).Scan(&count)
-				if err != nil {
-					t.Errorf("Failed to verify friendship: %v", err)
-				}
-				if count != 1 {
-					t.Errorf("Expected 1 friendship record, got %d", count)
-				}
-			}
-		})
-	}
+// createFriendship inserts the friendship between two users directly, as the
+// repository only makes friends by accepting a friend request
+func createFriendship(db *sql.DB, user1, user2 *entities.User) error {
+	_, err := db.Exec(
+		"INSERT INTO friends (user1_id, user2_id) VALUES ($1, $2)",
+		min(user1.ID, user2.ID), max(user1.ID, user2.ID),
+	)
+	return err
 }
 
 func TestUserRepository_GetUserByEmail(t *testing.T) {
@@ -244,23 +188,23 @@ func TestUserRepository_GetFriendList(t *testing.T) {
 
 	// Create friendships for testing
 	// andy (1) is friends with alice (2) and bob (3)
-	err = repo.CreateFriendship(user1, user2)
+	err = createFriendship(db, user1, user2)
 	if err != nil {
 		t.Fatalf("Failed to create friendship 1-2: %v", err)
 	}
-	err = repo.CreateFriendship(user1, user3)
+	err = createFriendship(db, user1, user3)
 	if err != nil {
 		t.Fatalf("Failed to create friendship 1-3: %v", err)
 	}
 
 	// alice (2) is also friends with jack (4) - testing user2 as user1 in friendship table
-	err = repo.CreateFriendship(user2, user4)
+	err = createFriendship(db, user2, user4)
 	if err != nil {
 		t.Fatalf("Failed to create friendship 2-4: %v", err)
 	}
 
 	// bob (3) is friends with lisa (5) - testing user1 as user2 in friendship table
-	err = repo.CreateFriendship(user5, user3) // This should store as (3,5) since 3 < 5
+	err = createFriendship(db, user5, user3) // This should store as (3,5) since 3 < 5
 	if err != nil {
 		t.Fatalf("Failed to create friendship 5-3: %v", err)
 	}
@@ -329,7 +273,7 @@ func TestUserRepository_GetFriendList(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			friends, err := repo.GetFriendList(tt.user)
+			page, err := repo.GetFriendList(tt.user, entities.PageRequest{})
 
 			if tt.wantErr {
 				if err == nil {
@@ -343,6 +287,10 @@ func TestUserRepository_GetFriendList(t *testing.T) {
 				return
 			}
 
+			friends := page.Users
+			if page.Total != len(tt.expectedFriends) {
+				t.Errorf("expected a total of %d friends, got %d", len(tt.expectedFriends), page.Total)
+			}
 			if len(friends) != len(tt.expectedFriends) {
 				t.Errorf("expected %d friends, got %d", len(tt.expectedFriends), len(friends))
 				return
@@ -417,7 +365,7 @@ func TestUserRepository_GetCommonFriends(t *testing.T) {
 	}
 
 	for _, friendship := range friendships {
-		err := repo.CreateFriendship(friendship[0], friendship[1])
+		err := createFriendship(db, friendship[0], friendship[1])
 		if err != nil {
 			t.Fatalf("Failed to create friendship between %s and %s: %v", 
 				friendship[0].Email, friendship[1].Email, err)
@@ -425,16 +373,16 @@ func TestUserRepository_GetCommonFriends(t *testing.T) {
 	}
 
 	tests := []struct {
-		name            string
-		user1           *entities.User
-		user2           *entities.User
-		expectedCommon  []string // emails of expected common friends, sorted
-		wantErr         bool
+		name           string
+		users          []*entities.User
+		expectedCommon []string // emails of expected common friends, sorted
+		wantErr        bool
 	}{
 		{
-			name:  "users with multiple common friends",
-			user1: user1, // andy: friends with alice, bob, jack, charlie
-			user2: user2, // alice: friends with andy, bob, jack, lisa, charlie
+			name: "users with multiple common friends",
+			// andy: friends with alice, bob, jack, charlie
+			// alice: friends with andy, bob, jack, lisa, charlie
+			users: []*entities.User{user1, user2},
 			expectedCommon: []string{
 				"bob@mail.com",     // bob is common friend of andy and alice
 				"charlie@mail.com", // charlie is common friend of andy and alice
@@ -443,53 +391,57 @@ func TestUserRepository_GetCommonFriends(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name:  "users with two common friends",
-			user1: user1, // andy: friends with alice, bob, jack, charlie
-			user2: user3, // bob: friends with andy, alice, charlie
+			name: "users with two common friends",
+			// andy: friends with alice, bob, jack, charlie
+			// bob: friends with andy, alice, charlie
+			users: []*entities.User{user1, user3},
 			expectedCommon: []string{
-				"alice@mail.com", // alice is common friend of andy and bob
+				"alice@mail.com",   // alice is common friend of andy and bob
 				"charlie@mail.com", // charlie is common friend of andy and bob
 			},
 			wantErr: false,
 		},
 		{
 			name:           "users with one common friend",
-			user1:          user4, // jack: friends with andy, alice
-			user2:          user5, // lisa: friends with alice
+			users:          []*entities.User{user4, user5}, // jack and lisa share alice
+			expectedCommon: []string{"alice@mail.com"},
+			wantErr:        false,
+		},
+		{
+			name:           "three users share one common friend",
+			users:          []*entities.User{user1, user3, user4}, // andy, bob and jack share alice
 			expectedCommon: []string{"alice@mail.com"},
 			wantErr:        false,
 		},
+		{
+			name:           "three users who are all friends with each other",
+			users:          []*entities.User{user1, user2, user3}, // only charlie is friends with all three
+			expectedCommon: []string{"charlie@mail.com"},
+			wantErr:        false,
+		},
 		{
 			name:           "one user has no friends",
-			user1:          user1, // andy: has friends
-			user2:          user7, // diana: no friends
+			users:          []*entities.User{user1, user7}, // diana has no friends
 			expectedCommon: []string{},
 			wantErr:        false,
 		},
 		{
 			name:           "user with no friends and user with friends",
-			user1:          user7, // diana: no friends
-			user2:          user6, // charlie: has friends but no common friends with diana
+			users:          []*entities.User{user7, user6},
 			expectedCommon: []string{},
 			wantErr:        false,
 		},
 		{
-			name:    "first user doesn't exist",
-			user1:   &entities.User{ID: 999, Email: "nonexistent@example.com"},
-			user2:   user1,
-			wantErr: true,
-		},
-		{
-			name:    "second user doesn't exist",
-			user1:   user1,
-			user2:   &entities.User{ID: 999, Email: "nonexistent@example.com"},
-			wantErr: true,
+			name:           "user doesn't exist",
+			users:          []*entities.User{{ID: 999, Email: "nonexistent@example.com"}, user1},
+			expectedCommon: []string{},
+			wantErr:        false,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			commonFriends, err := repo.GetCommonFriends(tt.user1, tt.user2)
+			page, err := repo.GetCommonFriends(tt.users, entities.PageRequest{})
 
 			if tt.wantErr {
 				if err == nil {
@@ -503,6 +455,7 @@ func TestUserRepository_GetCommonFriends(t *testing.T) {
 				return
 			}
 
+			commonFriends := page.Users
 			if len(commonFriends) != len(tt.expectedCommon) {
 				t.Errorf("expected %d common friends, got %d", len(tt.expectedCommon), len(commonFriends))
 				return
@@ -535,10 +488,12 @@ func TestUserRepository_GetCommonFriends(t *testing.T) {
 				emailSet[friend.Email] = true
 			}
 
-			// Verify neither user is in their own common friends list
+			// Verify none of the users is in their own common friends list
 			for _, friend := range commonFriends {
-				if friend.ID == tt.user1.ID || friend.ID == tt.user2.ID {
-					t.Errorf("user found in common friends list: %s", friend.Email)
+				for _, user := range tt.users {
+					if friend.ID == user.ID {
+						t.Errorf("user found in common friends list: %s", friend.Email)
+					}
 				}
 			}
 		})
@@ -647,11 +602,11 @@ func TestUserRepository_CreateBlockTx(t *testing.T) {
 	user4 := &entities.User{ID: 4, Email: "jack@mail.com"}
 
 	// Create friendships between users 1-2 and 3-4
-	err := repo.CreateFriendship(user1, user2)
+	err := createFriendship(db, user1, user2)
 	if err != nil {
 		t.Fatalf("Failed to create friendship 1-2: %v", err)
 	}
-	err = repo.CreateFriendship(user3, user4)
+	err = createFriendship(db, user3, user4)
 	if err != nil {
 		t.Fatalf("Failed to create friendship 3-4: %v", err)
 	}
@@ -992,3 +947,1695 @@ func TestUserRepository_CheckBidirectionalBlock(t *testing.T) {
 		})
 	}
 }
+
+func TestUserRepository_CreateUser(t *testing.T) {
+	db, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	repo := NewUserRepository(db)
+
+	tests := []struct {
+		name    string
+		email   string
+		wantErr bool
+	}{
+		{
+			name:    "successful user creation",
+			email:   "new@mail.com",
+			wantErr: false,
+		},
+		{
+			name:    "duplicate seeded email should fail",
+			email:   "andy@mail.com",
+			wantErr: true,
+		},
+		{
+			name:    "duplicate created email should fail",
+			email:   "new@mail.com",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := repo.CreateUser(tt.email)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if user.ID == 0 {
+				t.Error("expected generated ID, got 0")
+			}
+			if user.Email != tt.email {
+				t.Errorf("expected email %s, got %s", tt.email, user.Email)
+			}
+		})
+	}
+}
+
+func TestUserRepository_MixedCaseEmails(t *testing.T) {
+	db, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	repo := NewUserRepository(db)
+
+	andy := &entities.User{ID: 1, Email: "andy@mail.com"}
+
+	kate, err := repo.CreateUser("Kate.Smith@Mail.com")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	if kate.Email != "kate.smith@mail.com" {
+		t.Errorf("expected email to be stored lowercase, got %s", kate.Email)
+	}
+
+	if _, err := repo.CreateUser("kate.smith@MAIL.com"); err == nil {
+		t.Error("expected the same email in another case to be a duplicate")
+	}
+
+	found, err := repo.GetUserByEmail("KATE.SMITH@mail.com")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if found.ID != kate.ID {
+		t.Errorf("expected user %d, got %d", kate.ID, found.ID)
+	}
+
+	users, err := repo.GetUsersByEmails([]string{"Kate.Smith@Mail.com", "ANDY@mail.com"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(users) != 2 {
+		t.Errorf("expected 2 users, got %d", len(users))
+	}
+
+	// Mentions are parsed lowercase and must reach the user
+	page, err := repo.GetRecipients(andy, []string{"kate.smith@mail.com"}, nil, entities.PageRequest{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(page.Recipients) != 1 || page.Recipients[0].User.ID != kate.ID {
+		t.Errorf("expected the mentioned user to be the only recipient, got %+v", page.Recipients)
+	}
+
+	dropped, err := repo.GetDroppedMentions(andy, []string{"kate.smith@mail.com"}, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(dropped) != 0 {
+		t.Errorf("expected no dropped mentions, got %+v", dropped)
+	}
+
+	// Rows written around the repository must be lowercase as well
+	if _, err := db.ExecContext(context.Background(), "INSERT INTO users (email) VALUES('Mixed@mail.com')"); err == nil {
+		t.Error("expected a mixed-case email to violate the lowercase check")
+	}
+}
+
+func TestUserRepository_GetAllUsers(t *testing.T) {
+	db, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	repo := NewUserRepository(db)
+
+	users, err := repo.GetAllUsers()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Seeded users ordered by email
+	expected := []string{"alice@mail.com", "andy@mail.com", "bob@mail.com", "jack@mail.com", "lisa@mail.com"}
+	if len(users) != len(expected) {
+		t.Fatalf("expected %d users, got %d", len(expected), len(users))
+	}
+	for i, email := range expected {
+		if users[i].Email != email {
+			t.Errorf("expected user %d to be %s, got %s", i, email, users[i].Email)
+		}
+	}
+}
+
+func TestUserRepository_DeleteUser(t *testing.T) {
+	db, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	repo := NewUserRepository(db)
+
+	user1 := &entities.User{ID: 1, Email: "andy@mail.com"}
+	user2 := &entities.User{ID: 2, Email: "alice@mail.com"}
+
+	// Relationships referencing the user should be cascaded
+	if err := createFriendship(db, user1, user2); err != nil {
+		t.Fatalf("Failed to create friendship 1-2: %v", err)
+	}
+	if err := repo.CreateSubscription(user2, user1); err != nil {
+		t.Fatalf("Failed to create subscription 2->1: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		user    *entities.User
+		wantErr bool
+	}{
+		{
+			name:    "successful user deletion",
+			user:    user1,
+			wantErr: false,
+		},
+		{
+			name:    "already deleted user should fail",
+			user:    user1,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := repo.DeleteUser(tt.user)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			var count int
+			query := "SELECT (SELECT COUNT(*) FROM friends WHERE user1_id = $1 OR user2_id = $1) + (SELECT COUNT(*) FROM subscriptions WHERE subscriber_id = $1 OR target_id = $1)"
+			if err := db.QueryRow(query, tt.user.ID).Scan(&count); err != nil {
+				t.Errorf("Failed to verify cascade: %v", err)
+			}
+			if count != 0 {
+				t.Errorf("Expected relationships to be removed, got %d", count)
+			}
+		})
+	}
+}
+
+func TestUserRepository_DeleteFriendship(t *testing.T) {
+	db, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	repo := NewUserRepository(db)
+
+	user1 := &entities.User{ID: 1, Email: "andy@mail.com"}
+	user2 := &entities.User{ID: 2, Email: "alice@mail.com"}
+	user3 := &entities.User{ID: 3, Email: "bob@mail.com"}
+
+	if err := createFriendship(db, user1, user2); err != nil {
+		t.Fatalf("Failed to create friendship 1-2: %v", err)
+	}
+	if err := repo.CreateSubscription(user1, user2); err != nil {
+		t.Fatalf("Failed to create subscription 1->2: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		user1   *entities.User
+		user2   *entities.User
+		wantErr bool
+	}{
+		{
+			name:    "successful removal with reversed order",
+			user1:   user2,
+			user2:   user1,
+			wantErr: false,
+		},
+		{
+			name:    "already removed friendship should fail",
+			user1:   user1,
+			user2:   user2,
+			wantErr: true,
+		},
+		{
+			name:    "never existing friendship should fail",
+			user1:   user1,
+			user2:   user3,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := repo.DeleteFriendship(tt.user1, tt.user2)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			var count int
+			err = db.QueryRow("SELECT COUNT(*) FROM friends WHERE user1_id = $1 AND user2_id = $2", user1.ID, user2.ID).Scan(&count)
+			if err != nil {
+				t.Errorf("Failed to verify friendship: %v", err)
+			}
+			if count != 0 {
+				t.Errorf("Expected friendship to be removed, got %d", count)
+			}
+
+			// Unfriending must not touch subscriptions
+			err = db.QueryRow("SELECT COUNT(*) FROM subscriptions WHERE subscriber_id = $1 AND target_id = $2", user1.ID, user2.ID).Scan(&count)
+			if err != nil {
+				t.Errorf("Failed to verify subscription: %v", err)
+			}
+			if count != 1 {
+				t.Errorf("Expected subscription to be kept, got %d", count)
+			}
+		})
+	}
+}
+
+func TestUserRepository_DeleteSubscription(t *testing.T) {
+	db, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	repo := NewUserRepository(db)
+
+	user1 := &entities.User{ID: 1, Email: "andy@mail.com"}
+	user2 := &entities.User{ID: 2, Email: "alice@mail.com"}
+
+	// Subscriptions in both directions; only one should be removed
+	if err := repo.CreateSubscription(user1, user2); err != nil {
+		t.Fatalf("Failed to create subscription 1->2: %v", err)
+	}
+	if err := repo.CreateSubscription(user2, user1); err != nil {
+		t.Fatalf("Failed to create subscription 2->1: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		subscriber *entities.User
+		target     *entities.User
+		wantErr    bool
+	}{
+		{
+			name:       "successful unsubscribe",
+			subscriber: user1,
+			target:     user2,
+			wantErr:    false,
+		},
+		{
+			name:       "already removed subscription should fail",
+			subscriber: user1,
+			target:     user2,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := repo.DeleteSubscription(tt.subscriber, tt.target)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			var count int
+			query := "SELECT COUNT(*) FROM subscriptions WHERE subscriber_id = $1 AND target_id = $2"
+			if err := db.QueryRow(query, tt.subscriber.ID, tt.target.ID).Scan(&count); err != nil {
+				t.Errorf("Failed to verify subscription: %v", err)
+			}
+			if count != 0 {
+				t.Errorf("Expected subscription to be removed, got %d", count)
+			}
+
+			if err := db.QueryRow(query, tt.target.ID, tt.subscriber.ID).Scan(&count); err != nil {
+				t.Errorf("Failed to verify reverse subscription: %v", err)
+			}
+			if count != 1 {
+				t.Errorf("Expected reverse subscription to be kept, got %d", count)
+			}
+		})
+	}
+}
+
+func TestUserRepository_DeleteBlockTx(t *testing.T) {
+	db, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	repo := NewUserRepository(db)
+
+	user1 := &entities.User{ID: 1, Email: "andy@mail.com"}
+	user2 := &entities.User{ID: 2, Email: "alice@mail.com"}
+	user3 := &entities.User{ID: 3, Email: "bob@mail.com"}
+	user4 := &entities.User{ID: 4, Email: "jack@mail.com"}
+
+	// Relationships removed by the blocks below
+	if err := createFriendship(db, user1, user2); err != nil {
+		t.Fatalf("Failed to create friendship 1-2: %v", err)
+	}
+	if err := repo.CreateSubscription(user2, user1); err != nil {
+		t.Fatalf("Failed to create subscription 2->1: %v", err)
+	}
+	if err := createFriendship(db, user3, user4); err != nil {
+		t.Fatalf("Failed to create friendship 3-4: %v", err)
+	}
+	if err := repo.CreateBlockTx(user1, user2); err != nil {
+		t.Fatalf("Failed to create block 1->2: %v", err)
+	}
+	if err := repo.CreateBlockTx(user3, user4); err != nil {
+		t.Fatalf("Failed to create block 3->4: %v", err)
+	}
+
+	countRows := func(t *testing.T, query string, args ...interface{}) int {
+		var count int
+		if err := db.QueryRow(query, args...).Scan(&count); err != nil {
+			t.Fatalf("Failed to count rows: %v", err)
+		}
+		return count
+	}
+
+	tests := []struct {
+		name      string
+		requestor *entities.User
+		target    *entities.User
+		restore   bool
+		wantErr   bool
+		verify    func(t *testing.T)
+	}{
+		{
+			name:      "unblock with restore re-creates friendship and subscription",
+			requestor: user1,
+			target:    user2,
+			restore:   true,
+			wantErr:   false,
+			verify: func(t *testing.T) {
+				if n := countRows(t, "SELECT COUNT(*) FROM blocks WHERE blocker_id = $1 AND blocked_id = $2", user1.ID, user2.ID); n != 0 {
+					t.Errorf("Expected block to be removed, got %d", n)
+				}
+				if n := countRows(t, "SELECT COUNT(*) FROM friends WHERE user1_id = $1 AND user2_id = $2", user1.ID, user2.ID); n != 1 {
+					t.Errorf("Expected friendship to be restored, got %d", n)
+				}
+				if n := countRows(t, "SELECT COUNT(*) FROM subscriptions WHERE subscriber_id = $1 AND target_id = $2", user2.ID, user1.ID); n != 1 {
+					t.Errorf("Expected subscription 2->1 to be restored, got %d", n)
+				}
+				if n := countRows(t, "SELECT COUNT(*) FROM subscriptions WHERE subscriber_id = $1 AND target_id = $2", user1.ID, user2.ID); n != 0 {
+					t.Errorf("Expected no subscription 1->2, got %d", n)
+				}
+				if n := countRows(t, "SELECT COUNT(*) FROM block_snapshots"); n != 1 {
+					t.Errorf("Expected only the 3->4 snapshot to remain, got %d", n)
+				}
+			},
+		},
+		{
+			name:      "unblock without restore leaves relationships removed",
+			requestor: user3,
+			target:    user4,
+			restore:   false,
+			wantErr:   false,
+			verify: func(t *testing.T) {
+				if n := countRows(t, "SELECT COUNT(*) FROM blocks WHERE blocker_id = $1 AND blocked_id = $2", user3.ID, user4.ID); n != 0 {
+					t.Errorf("Expected block to be removed, got %d", n)
+				}
+				if n := countRows(t, "SELECT COUNT(*) FROM friends WHERE user1_id = $1 AND user2_id = $2", user3.ID, user4.ID); n != 0 {
+					t.Errorf("Expected friendship to stay removed, got %d", n)
+				}
+				if n := countRows(t, "SELECT COUNT(*) FROM block_snapshots"); n != 0 {
+					t.Errorf("Expected snapshots to be removed with their blocks, got %d", n)
+				}
+			},
+		},
+		{
+			name:      "unblock of missing block should fail",
+			requestor: user1,
+			target:    user2,
+			restore:   true,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := repo.DeleteBlockTx(tt.requestor, tt.target, tt.restore)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if tt.verify != nil {
+				tt.verify(t)
+			}
+		})
+	}
+
+	// Restoring is refused, and the block kept, while the target blocks back
+	if err := repo.CreateBlockTx(user1, user3); err != nil {
+		t.Fatalf("Failed to create block 1->3: %v", err)
+	}
+	if err := repo.CreateBlockTx(user3, user1); err != nil {
+		t.Fatalf("Failed to create block 3->1: %v", err)
+	}
+	if err := repo.DeleteBlockTx(user1, user3, true); err != errors.ErrUserBlocked {
+		t.Errorf("expected ErrUserBlocked, got %v", err)
+	}
+	if n := countRows(t, "SELECT COUNT(*) FROM blocks WHERE blocker_id = $1 AND blocked_id = $2", user1.ID, user3.ID); n != 1 {
+		t.Errorf("Expected block 1->3 to be kept, got %d", n)
+	}
+}
+
+func TestUserRepository_FriendRequests(t *testing.T) {
+	db, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	repo := NewUserRepository(db)
+
+	user1 := &entities.User{ID: 1, Email: "andy@mail.com"}
+	user2 := &entities.User{ID: 2, Email: "alice@mail.com"}
+	user3 := &entities.User{ID: 3, Email: "bob@mail.com"}
+
+	request, err := repo.CreateFriendRequest(user1, user2)
+	if err != nil {
+		t.Fatalf("Failed to create friend request 1->2: %v", err)
+	}
+	if request.Status != entities.FriendRequestPending {
+		t.Errorf("expected pending status, got %s", request.Status)
+	}
+
+	// Only one pending request per pair, in either direction
+	if _, err := repo.CreateFriendRequest(user1, user2); err == nil {
+		t.Error("expected duplicate request to fail")
+	}
+	if _, err := repo.CreateFriendRequest(user2, user1); err == nil {
+		t.Error("expected reverse request to fail while one is pending")
+	}
+
+	if _, err := repo.CreateFriendRequest(user3, user2); err != nil {
+		t.Fatalf("Failed to create friend request 3->2: %v", err)
+	}
+
+	incoming, err := repo.GetIncomingFriendRequests(user2)
+	if err != nil {
+		t.Fatalf("Failed to get incoming requests: %v", err)
+	}
+	if len(incoming) != 2 || incoming[0].Requester.Email != user1.Email || incoming[1].Requester.Email != user3.Email {
+		t.Errorf("unexpected incoming requests: %+v", incoming)
+	}
+
+	outgoing, err := repo.GetOutgoingFriendRequests(user1)
+	if err != nil {
+		t.Fatalf("Failed to get outgoing requests: %v", err)
+	}
+	if len(outgoing) != 1 || outgoing[0].Addressee.Email != user2.Email {
+		t.Errorf("unexpected outgoing requests: %+v", outgoing)
+	}
+
+	// Accepting creates the friendship and closes the request
+	if err := repo.AcceptFriendRequestTx(user1, user2); err != nil {
+		t.Fatalf("Failed to accept friend request: %v", err)
+	}
+	exists, err := repo.CheckFriendshipExists(user2.ID, user1.ID)
+	if err != nil {
+		t.Fatalf("Failed to check friendship: %v", err)
+	}
+	if !exists {
+		t.Error("expected friendship to exist after acceptance")
+	}
+	if err := repo.AcceptFriendRequestTx(user1, user2); err == nil {
+		t.Error("expected accepting a closed request to fail")
+	}
+
+	// Rejecting leaves no friendship behind
+	if err := repo.UpdateFriendRequestStatus(user3, user2, entities.FriendRequestRejected); err != nil {
+		t.Fatalf("Failed to reject friend request: %v", err)
+	}
+	exists, err = repo.CheckFriendshipExists(user3.ID, user2.ID)
+	if err != nil {
+		t.Fatalf("Failed to check friendship: %v", err)
+	}
+	if exists {
+		t.Error("expected no friendship after rejection")
+	}
+
+	incoming, err = repo.GetIncomingFriendRequests(user2)
+	if err != nil {
+		t.Fatalf("Failed to get incoming requests: %v", err)
+	}
+	if len(incoming) != 0 {
+		t.Errorf("expected no pending incoming requests, got %d", len(incoming))
+	}
+
+	// A closed request does not prevent a new one
+	if _, err := repo.CreateFriendRequest(user3, user2); err != nil {
+		t.Errorf("expected new request after rejection to succeed, got %v", err)
+	}
+	if err := repo.UpdateFriendRequestStatus(user3, user2, entities.FriendRequestCancelled); err != nil {
+		t.Errorf("Failed to cancel friend request: %v", err)
+	}
+
+	// A block created while a request is pending prevents accepting it
+	if _, err := repo.CreateFriendRequest(user3, user1); err != nil {
+		t.Fatalf("Failed to create friend request 3->1: %v", err)
+	}
+	if err := repo.CreateBlockTx(user1, user3); err != nil {
+		t.Fatalf("Failed to create block 1->3: %v", err)
+	}
+	if err := repo.AcceptFriendRequestTx(user3, user1); err != errors.ErrUserBlocked {
+		t.Errorf("expected ErrUserBlocked, got %v", err)
+	}
+	exists, err = repo.CheckFriendshipExists(user1.ID, user3.ID)
+	if err != nil {
+		t.Fatalf("Failed to check friendship: %v", err)
+	}
+	if exists {
+		t.Error("expected no friendship between blocked users")
+	}
+}
+
+func TestUserRepository_GetRelationship(t *testing.T) {
+	db, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	repo := NewUserRepository(db)
+
+	user1 := &entities.User{ID: 1, Email: "andy@mail.com"}
+	user2 := &entities.User{ID: 2, Email: "alice@mail.com"}
+	user3 := &entities.User{ID: 3, Email: "bob@mail.com"}
+	user4 := &entities.User{ID: 4, Email: "jack@mail.com"}
+
+	if err := createFriendship(db, user2, user1); err != nil {
+		t.Fatalf("Failed to create friendship 1-2: %v", err)
+	}
+	if err := repo.CreateSubscription(user2, user1); err != nil {
+		t.Fatalf("Failed to create subscription 2->1: %v", err)
+	}
+	if err := repo.CreateBlockTx(user4, user3); err != nil {
+		t.Fatalf("Failed to create block 4->3: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		userA    *entities.User
+		userB    *entities.User
+		expected entities.Relationship
+	}{
+		{
+			name:     "friends with one-way subscription",
+			userA:    user1,
+			userB:    user2,
+			expected: entities.Relationship{AreFriends: true, BSubscribesToA: true},
+		},
+		{
+			name:     "same pair from the other side",
+			userA:    user2,
+			userB:    user1,
+			expected: entities.Relationship{AreFriends: true, ASubscribesToB: true},
+		},
+		{
+			name:     "blocked by the other user",
+			userA:    user3,
+			userB:    user4,
+			expected: entities.Relationship{BBlocksA: true},
+		},
+		{
+			name:     "unrelated users",
+			userA:    user1,
+			userB:    user3,
+			expected: entities.Relationship{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			relationship, err := repo.GetRelationship(tt.userA, tt.userB)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if *relationship != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, *relationship)
+			}
+		})
+	}
+}
+
+func TestUserRepository_GetFriendSuggestions(t *testing.T) {
+	db, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	repo := NewUserRepository(db)
+
+	andy := &entities.User{ID: 1, Email: "andy@mail.com"}
+	alice := &entities.User{ID: 2, Email: "alice@mail.com"}
+	bob := &entities.User{ID: 3, Email: "bob@mail.com"}
+	jack := &entities.User{ID: 4, Email: "jack@mail.com"}
+	lisa := &entities.User{ID: 5, Email: "lisa@mail.com"}
+
+	_, err := db.ExecContext(context.Background(), "INSERT INTO users (email) VALUES('kate@mail.com')")
+	if err != nil {
+		t.Fatalf("Failed to create test user: %v", err)
+	}
+	kate := &entities.User{ID: 6, Email: "kate@mail.com"}
+
+	// andy's friends: alice, bob
+	// jack is friends with alice and bob (2 mutual), lisa with alice (1 mutual)
+	// kate is friends with bob but blocks andy
+	friendships := [][2]*entities.User{
+		{andy, alice}, {andy, bob},
+		{jack, alice}, {jack, bob},
+		{lisa, alice},
+		{kate, bob},
+	}
+	for _, pair := range friendships {
+		if err := createFriendship(db, pair[0], pair[1]); err != nil {
+			t.Fatalf("Failed to create friendship %s-%s: %v", pair[0].Email, pair[1].Email, err)
+		}
+	}
+	if err := repo.CreateBlockTx(kate, andy); err != nil {
+		t.Fatalf("Failed to create block kate->andy: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		user     *entities.User
+		limit    int
+		expected []string
+		mutual   []int
+	}{
+		{
+			name:     "ranked by mutual friends excluding friends and blocked users",
+			user:     andy,
+			limit:    10,
+			expected: []string{"jack@mail.com", "lisa@mail.com"},
+			mutual:   []int{2, 1},
+		},
+		{
+			name:     "limit is applied",
+			user:     andy,
+			limit:    1,
+			expected: []string{"jack@mail.com"},
+			mutual:   []int{2},
+		},
+		{
+			name:     "suggestions for user with a single friend",
+			user:     lisa,
+			limit:    10,
+			expected: []string{"andy@mail.com", "jack@mail.com"},
+			mutual:   []int{1, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suggestions, err := repo.GetFriendSuggestions(tt.user, tt.limit)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if len(suggestions) != len(tt.expected) {
+				t.Fatalf("expected %d suggestions, got %d", len(tt.expected), len(suggestions))
+			}
+			for i, suggestion := range suggestions {
+				if suggestion.User.Email != tt.expected[i] {
+					t.Errorf("expected suggestion %d to be %s, got %s", i, tt.expected[i], suggestion.User.Email)
+				}
+				if suggestion.MutualFriends != tt.mutual[i] {
+					t.Errorf("expected %d mutual friends for %s, got %d", tt.mutual[i], suggestion.User.Email, suggestion.MutualFriends)
+				}
+			}
+		})
+	}
+}
+
+func TestUserRepository_GetUnblockedFriendsBatch(t *testing.T) {
+	db, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	repo := NewUserRepository(db)
+
+	andy := &entities.User{ID: 1, Email: "andy@mail.com"}
+	alice := &entities.User{ID: 2, Email: "alice@mail.com"}
+	bob := &entities.User{ID: 3, Email: "bob@mail.com"}
+	jack := &entities.User{ID: 4, Email: "jack@mail.com"}
+	lisa := &entities.User{ID: 5, Email: "lisa@mail.com"}
+
+	for _, pair := range [][2]*entities.User{{andy, alice}, {andy, bob}, {alice, jack}, {bob, lisa}} {
+		if err := createFriendship(db, pair[0], pair[1]); err != nil {
+			t.Fatalf("Failed to create friendship %s-%s: %v", pair[0].Email, pair[1].Email, err)
+		}
+	}
+
+	// Blocking drops the friendship, so restore it directly to test that the
+	// edge is still skipped while the block exists
+	if err := repo.CreateBlockTx(lisa, bob); err != nil {
+		t.Fatalf("Failed to create block lisa->bob: %v", err)
+	}
+	if err := createFriendship(db, bob, lisa); err != nil {
+		t.Fatalf("Failed to recreate friendship bob-lisa: %v", err)
+	}
+
+	friends, err := repo.GetUnblockedFriendsBatch([]int{andy.ID, bob.ID, jack.ID})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := map[int][]string{
+		andy.ID: {"alice@mail.com", "bob@mail.com"},
+		bob.ID:  {"andy@mail.com"},
+		jack.ID: {"alice@mail.com"},
+	}
+	if len(friends) != len(expected) {
+		t.Fatalf("expected friends for %d users, got %d", len(expected), len(friends))
+	}
+	for userID, emails := range expected {
+		if len(friends[userID]) != len(emails) {
+			t.Fatalf("expected %d friends for user %d, got %d", len(emails), userID, len(friends[userID]))
+		}
+		for i, friend := range friends[userID] {
+			if friend.Email != emails[i] {
+				t.Errorf("expected friend %d of user %d to be %s, got %s", i, userID, emails[i], friend.Email)
+			}
+		}
+	}
+
+	empty, err := repo.GetUnblockedFriendsBatch([]int{})
+	if err != nil {
+		t.Fatalf("expected no error for empty input, got %v", err)
+	}
+	if len(empty) != 0 {
+		t.Errorf("expected empty result, got %d entries", len(empty))
+	}
+}
+
+func TestUserRepository_GetRecipients(t *testing.T) {
+	db, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	repo := NewUserRepository(db)
+
+	andy := &entities.User{ID: 1, Email: "andy@mail.com"}
+	alice := &entities.User{ID: 2, Email: "alice@mail.com"}
+	bob := &entities.User{ID: 3, Email: "bob@mail.com"}
+	jack := &entities.User{ID: 4, Email: "jack@mail.com"}
+	lisa := &entities.User{ID: 5, Email: "lisa@mail.com"}
+
+	// alice is andy's friend and subscriber, bob subscribes to andy and lisa blocks andy
+	if err := createFriendship(db, andy, alice); err != nil {
+		t.Fatalf("Failed to create friendship: %v", err)
+	}
+	if err := repo.CreateSubscription(alice, andy); err != nil {
+		t.Fatalf("Failed to create subscription: %v", err)
+	}
+	if err := repo.CreateSubscription(bob, andy); err != nil {
+		t.Fatalf("Failed to create subscription: %v", err)
+	}
+	if err := repo.CreateBlockTx(lisa, andy); err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+
+	friend := entities.RecipientReasonFriend
+	subscriber := entities.RecipientReasonSubscriber
+	mentioned := entities.RecipientReasonMentioned
+
+	tests := []struct {
+		name      string
+		sender    *entities.User
+		mentioned []string
+		expected  []string
+		reasons   [][]entities.RecipientReason
+	}{
+		{
+			name:     "friends and subscribers without mentions",
+			sender:   andy,
+			expected: []string{"alice@mail.com", "bob@mail.com"},
+			reasons:  [][]entities.RecipientReason{{friend, subscriber}, {subscriber}},
+		},
+		{
+			name:      "mentioned user is added",
+			sender:    andy,
+			mentioned: []string{"jack@mail.com"},
+			expected:  []string{"alice@mail.com", "bob@mail.com", "jack@mail.com"},
+			reasons:   [][]entities.RecipientReason{{friend, subscriber}, {subscriber}, {mentioned}},
+		},
+		{
+			name:      "mentioned user who is already a friend is not duplicated",
+			sender:    andy,
+			mentioned: []string{"alice@mail.com", "alice@mail.com"},
+			expected:  []string{"alice@mail.com", "bob@mail.com"},
+			reasons:   [][]entities.RecipientReason{{friend, subscriber, mentioned}, {subscriber}},
+		},
+		{
+			name:      "blocked, unknown and self mentions are dropped",
+			sender:    andy,
+			mentioned: []string{"lisa@mail.com", "unknown@mail.com", "andy@mail.com"},
+			expected:  []string{"alice@mail.com", "bob@mail.com"},
+			reasons:   [][]entities.RecipientReason{{friend, subscriber}, {subscriber}},
+		},
+		{
+			name:      "blocker does not reach the blocked user through a mention",
+			sender:    lisa,
+			mentioned: []string{"andy@mail.com", "jack@mail.com"},
+			expected:  []string{"jack@mail.com"},
+			reasons:   [][]entities.RecipientReason{{mentioned}},
+		},
+		{
+			name:     "user without friends or subscribers",
+			sender:   jack,
+			expected: []string{},
+			reasons:  [][]entities.RecipientReason{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := repo.GetRecipients(tt.sender, tt.mentioned, nil, entities.PageRequest{})
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			recipients := page.Recipients
+
+			if len(recipients) != len(tt.expected) {
+				t.Fatalf("expected %d recipients, got %d", len(tt.expected), len(recipients))
+			}
+			for i, recipient := range recipients {
+				if recipient.User.Email != tt.expected[i] {
+					t.Errorf("expected recipient %d to be %s, got %s", i, tt.expected[i], recipient.User.Email)
+				}
+				if !slices.Equal(recipient.Reasons, tt.reasons[i]) {
+					t.Errorf("expected reasons %v for %s, got %v", tt.reasons[i], recipient.User.Email, recipient.Reasons)
+				}
+			}
+		})
+	}
+}
+
+func TestUserRepository_GetDroppedMentions(t *testing.T) {
+	db, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	repo := NewUserRepository(db)
+
+	andy := &entities.User{ID: 1, Email: "andy@mail.com"}
+	bob := &entities.User{ID: 3, Email: "bob@mail.com"}
+	lisa := &entities.User{ID: 5, Email: "lisa@mail.com"}
+
+	// andy blocks bob and lisa blocks andy
+	if err := repo.CreateBlockTx(andy, bob); err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+	if err := repo.CreateBlockTx(lisa, andy); err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		mentioned []string
+		expected  []entities.DroppedMention
+	}{
+		{
+			name:      "no mentions",
+			mentioned: nil,
+			expected:  []entities.DroppedMention{},
+		},
+		{
+			name:      "all mentions are deliverable",
+			mentioned: []string{"alice@mail.com", "jack@mail.com"},
+			expected:  []entities.DroppedMention{},
+		},
+		{
+			name:      "unknown, blocked and self mentions sorted by email",
+			mentioned: []string{"zoe@mail.com", "lisa@mail.com", "alice@mail.com", "bob@mail.com", "andy@mail.com", "zoe@mail.com"},
+			expected: []entities.DroppedMention{
+				{Email: "andy@mail.com", Reason: entities.DroppedMentionSelf},
+				{Email: "bob@mail.com", Reason: entities.DroppedMentionBlocked},
+				{Email: "lisa@mail.com", Reason: entities.DroppedMentionBlocked},
+				{Email: "zoe@mail.com", Reason: entities.DroppedMentionUnknown},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dropped, err := repo.GetDroppedMentions(andy, tt.mentioned, nil)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if len(dropped) != len(tt.expected) {
+				t.Fatalf("expected %d dropped mentions, got %d", len(tt.expected), len(dropped))
+			}
+			for i, mention := range dropped {
+				if *mention != tt.expected[i] {
+					t.Errorf("expected dropped mention %d to be %+v, got %+v", i, tt.expected[i], *mention)
+				}
+			}
+		})
+	}
+}
+
+func TestUserRepository_GetEmailsByUsernames(t *testing.T) {
+	db, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	repo := NewUserRepository(db)
+
+	tests := []struct {
+		name      string
+		usernames []string
+		expected  map[string]string
+	}{
+		{
+			name:      "no usernames",
+			usernames: nil,
+			expected:  map[string]string{},
+		},
+		{
+			name:      "seeded usernames",
+			usernames: []string{"alice", "bob"},
+			expected:  map[string]string{"alice": "alice@mail.com", "bob": "bob@mail.com"},
+		},
+		{
+			name:      "unknown usernames are left out",
+			usernames: []string{"jack", "zoe"},
+			expected:  map[string]string{"jack": "jack@mail.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			emails, err := repo.GetEmailsByUsernames(tt.usernames)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if !maps.Equal(emails, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, emails)
+			}
+		})
+	}
+}
+
+func TestUserRepository_SetUsername(t *testing.T) {
+	db, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	repo := NewUserRepository(db)
+
+	andy := &entities.User{ID: 1, Email: "andy@mail.com"}
+
+	if err := repo.SetUsername(andy, "andy_b"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	emails, err := repo.GetEmailsByUsernames([]string{"andy", "andy_b"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !maps.Equal(emails, map[string]string{"andy_b": "andy@mail.com"}) {
+		t.Errorf("expected only the new username to resolve, got %v", emails)
+	}
+
+	// Usernames are unique regardless of case
+	if err := repo.SetUsername(andy, "BOB"); err == nil {
+		t.Error("expected error for a taken username, got nil")
+	}
+
+	if err := repo.SetUsername(&entities.User{ID: 999}, "ghost"); err == nil {
+		t.Error("expected error for a missing user, got nil")
+	}
+}
+
+func TestUserRepository_UserSettings(t *testing.T) {
+	db, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	repo := NewUserRepository(db)
+
+	andy := &entities.User{ID: 1, Email: "andy@mail.com"}
+
+	settings, err := repo.GetUserSettings(andy)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if settings.MentionPolicy != entities.MentionPolicyEveryone {
+		t.Errorf("expected default mention policy %q, got %q", entities.MentionPolicyEveryone, settings.MentionPolicy)
+	}
+
+	for _, policy := range []entities.MentionPolicy{entities.MentionPolicyFriends, entities.MentionPolicyNobody} {
+		if err := repo.UpdateUserSettings(&entities.UserSettings{User: andy, MentionPolicy: policy}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		settings, err := repo.GetUserSettings(andy)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if settings.MentionPolicy != policy {
+			t.Errorf("expected mention policy %q, got %q", policy, settings.MentionPolicy)
+		}
+	}
+
+	if err := repo.UpdateUserSettings(&entities.UserSettings{User: andy, MentionPolicy: "strangers"}); err == nil {
+		t.Error("expected error for an unsupported mention policy, got nil")
+	}
+}
+
+func TestUserRepository_MentionPolicy(t *testing.T) {
+	db, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	repo := NewUserRepository(db)
+
+	andy := &entities.User{ID: 1, Email: "andy@mail.com"}
+	alice := &entities.User{ID: 2, Email: "alice@mail.com"}
+	bob := &entities.User{ID: 3, Email: "bob@mail.com"}
+	jack := &entities.User{ID: 4, Email: "jack@mail.com"}
+	lisa := &entities.User{ID: 5, Email: "lisa@mail.com"}
+
+	// alice and lisa are andy's friends and bob subscribes to andy
+	if err := createFriendship(db, andy, alice); err != nil {
+		t.Fatalf("Failed to create friendship: %v", err)
+	}
+	if err := createFriendship(db, lisa, andy); err != nil {
+		t.Fatalf("Failed to create friendship: %v", err)
+	}
+	if err := repo.CreateSubscription(bob, andy); err != nil {
+		t.Fatalf("Failed to create subscription: %v", err)
+	}
+
+	policies := map[*entities.User]entities.MentionPolicy{
+		alice: entities.MentionPolicyNobody,
+		bob:   entities.MentionPolicyNobody,
+		jack:  entities.MentionPolicyFriends,
+		lisa:  entities.MentionPolicyFriends,
+	}
+	for user, policy := range policies {
+		if err := repo.UpdateUserSettings(&entities.UserSettings{User: user, MentionPolicy: policy}); err != nil {
+			t.Fatalf("Failed to update settings: %v", err)
+		}
+	}
+
+	mentioned := []string{"alice@mail.com", "bob@mail.com", "jack@mail.com", "lisa@mail.com"}
+
+	page, err := repo.GetRecipients(andy, mentioned, nil, entities.PageRequest{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	recipients := page.Recipients
+
+	// Friends and subscribers still receive the update, only the mention is ignored
+	expected := map[string][]entities.RecipientReason{
+		"alice@mail.com": {entities.RecipientReasonFriend},
+		"bob@mail.com":   {entities.RecipientReasonSubscriber},
+		"lisa@mail.com":  {entities.RecipientReasonFriend, entities.RecipientReasonMentioned},
+	}
+	if len(recipients) != len(expected) {
+		t.Fatalf("expected %d recipients, got %d", len(expected), len(recipients))
+	}
+	for _, recipient := range recipients {
+		if !slices.Equal(recipient.Reasons, expected[recipient.User.Email]) {
+			t.Errorf("expected %s reasons %v, got %v", recipient.User.Email, expected[recipient.User.Email], recipient.Reasons)
+		}
+	}
+
+	dropped, err := repo.GetDroppedMentions(andy, mentioned, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(dropped) != 1 || *dropped[0] != (entities.DroppedMention{Email: "jack@mail.com", Reason: entities.DroppedMentionRestricted}) {
+		t.Errorf("expected only jack to be dropped as restricted, got %v", dropped)
+	}
+}
+
+func TestUserRepository_SubscriptionFilters(t *testing.T) {
+	db, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	repo := NewUserRepository(db)
+
+	andy := &entities.User{ID: 1, Email: "andy@mail.com"}
+	alice := &entities.User{ID: 2, Email: "alice@mail.com"}
+	bob := &entities.User{ID: 3, Email: "bob@mail.com"}
+	jack := &entities.User{ID: 4, Email: "jack@mail.com"}
+
+	for _, subscriber := range []*entities.User{alice, bob, jack} {
+		if err := repo.CreateSubscription(subscriber, andy); err != nil {
+			t.Fatalf("Failed to create subscription: %v", err)
+		}
+	}
+
+	filter, err := repo.GetSubscriptionFilter(alice, andy)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(filter.Include) != 0 || len(filter.Exclude) != 0 {
+		t.Errorf("expected a new subscription to have no filters, got %+v", filter)
+	}
+
+	// alice only wants #go updates, bob wants everything but spam and jack keeps everything
+	if err := repo.UpdateSubscriptionFilter(alice, andy, &entities.SubscriptionFilter{Include: []string{"#go"}}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := repo.UpdateSubscriptionFilter(bob, andy, &entities.SubscriptionFilter{Exclude: []string{"spam"}}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	filter, err = repo.GetSubscriptionFilter(alice, andy)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !slices.Equal(filter.Include, []string{"#go"}) || len(filter.Exclude) != 0 {
+		t.Errorf("expected alice's filter to include #go, got %+v", filter)
+	}
+
+	tests := []struct {
+		name     string
+		keywords []string
+		expected []string
+	}{
+		{
+			name:     "no keywords",
+			keywords: nil,
+			expected: []string{"bob@mail.com", "jack@mail.com"},
+		},
+		{
+			name:     "matching hashtag",
+			keywords: []string{"#go", "go", "release"},
+			expected: []string{"alice@mail.com", "bob@mail.com", "jack@mail.com"},
+		},
+		{
+			name:     "plain word does not match a hashtag filter",
+			keywords: []string{"go", "release"},
+			expected: []string{"bob@mail.com", "jack@mail.com"},
+		},
+		{
+			name:     "excluded keyword",
+			keywords: []string{"#go", "go", "spam"},
+			expected: []string{"alice@mail.com", "jack@mail.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := repo.GetRecipients(andy, nil, tt.keywords, entities.PageRequest{})
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			recipients := page.Recipients
+
+			emails := make([]string, len(recipients))
+			for i, recipient := range recipients {
+				emails[i] = recipient.User.Email
+			}
+			if !slices.Equal(emails, tt.expected) {
+				t.Errorf("expected recipients %v, got %v", tt.expected, emails)
+			}
+		})
+	}
+
+	if err := repo.UpdateSubscriptionFilter(andy, alice, &entities.SubscriptionFilter{}); err != errors.ErrSubscriptionNotFound {
+		t.Errorf("expected ErrSubscriptionNotFound, got %v", err)
+	}
+	if _, err := repo.GetSubscriptionFilter(andy, alice); err != errors.ErrSubscriptionNotFound {
+		t.Errorf("expected ErrSubscriptionNotFound, got %v", err)
+	}
+}
+
+func TestUserRepository_Mutes(t *testing.T) {
+	db, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	repo := NewUserRepository(db)
+
+	andy := &entities.User{ID: 1, Email: "andy@mail.com"}
+	alice := &entities.User{ID: 2, Email: "alice@mail.com"}
+	bob := &entities.User{ID: 3, Email: "bob@mail.com"}
+	jack := &entities.User{ID: 4, Email: "jack@mail.com"}
+
+	// alice is andy's friend and bob subscribes to andy
+	if err := createFriendship(db, andy, alice); err != nil {
+		t.Fatalf("Failed to create friendship: %v", err)
+	}
+	if err := repo.CreateSubscription(bob, andy); err != nil {
+		t.Fatalf("Failed to create subscription: %v", err)
+	}
+
+	// alice mutes andy for good, bob's mute on andy has already lapsed and jack mutes andy for an hour
+	if _, err := repo.UpsertMute(alice, andy, nil); err != nil {
+		t.Fatalf("Failed to create mute: %v", err)
+	}
+	if _, err := repo.UpsertMute(bob, andy, nil); err != nil {
+		t.Fatalf("Failed to create mute: %v", err)
+	}
+	if _, err := db.ExecContext(context.Background(), "UPDATE mutes SET expires_at = NOW() - INTERVAL '1 minute' WHERE muter_id = $1", bob.ID); err != nil {
+		t.Fatalf("Failed to expire mute: %v", err)
+	}
+	expiresAt := time.Now().Add(time.Hour)
+	mute, err := repo.UpsertMute(jack, andy, &expiresAt)
+	if err != nil {
+		t.Fatalf("Failed to create mute: %v", err)
+	}
+	if mute.ExpiresAt == nil || !mute.ExpiresAt.Equal(expiresAt.Truncate(time.Microsecond)) {
+		t.Errorf("expected mute to expire at %v, got %v", expiresAt, mute.ExpiresAt)
+	}
+
+	page, err := repo.GetRecipients(andy, []string{"alice@mail.com", "jack@mail.com"}, nil, entities.PageRequest{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	recipients := page.Recipients
+	if len(recipients) != 1 || recipients[0].User.Email != "bob@mail.com" {
+		t.Errorf("expected only bob to receive andy's update, got %v", recipients)
+	}
+
+	dropped, err := repo.GetDroppedMentions(andy, []string{"alice@mail.com", "jack@mail.com"}, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expectedDropped := []entities.DroppedMention{
+		{Email: "alice@mail.com", Reason: entities.DroppedMentionMuted},
+		{Email: "jack@mail.com", Reason: entities.DroppedMentionMuted},
+	}
+	if len(dropped) != len(expectedDropped) {
+		t.Fatalf("expected %d dropped mentions, got %d", len(expectedDropped), len(dropped))
+	}
+	for i, mention := range dropped {
+		if *mention != expectedDropped[i] {
+			t.Errorf("expected dropped mention %d to be %+v, got %+v", i, expectedDropped[i], *mention)
+		}
+	}
+
+	// Muting leaves the friendship and subscription in place
+	relationship, err := repo.GetRelationship(andy, alice)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !relationship.AreFriends {
+		t.Error("expected andy and alice to still be friends")
+	}
+
+	mutes, err := repo.GetMutes(alice)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(mutes) != 1 || mutes[0].Muted.Email != "andy@mail.com" || mutes[0].ExpiresAt != nil {
+		t.Errorf("expected alice to have a permanent mute on andy, got %v", mutes)
+	}
+
+	mutes, err = repo.GetMutes(bob)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(mutes) != 0 {
+		t.Errorf("expected bob's lapsed mute to be hidden, got %v", mutes)
+	}
+
+	if err := repo.DeleteMute(alice, andy); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := repo.DeleteMute(alice, andy); err != errors.ErrMuteNotFound {
+		t.Errorf("expected ErrMuteNotFound, got %v", err)
+	}
+	if err := repo.DeleteMute(bob, andy); err != errors.ErrMuteNotFound {
+		t.Errorf("expected ErrMuteNotFound for a lapsed mute, got %v", err)
+	}
+}
+
+// seedRecipientsBenchmark gives andy (ID 1) 200 friends, 200 subscribers and a
+// few blocks so both recipient resolution paths do comparable work
+func seedRecipientsBenchmark(b *testing.B, db *sql.DB) []string {
+	statements := []string{
+		"INSERT INTO users (email) SELECT 'bench' || g || '@mail.com' FROM generate_series(1, 500) g",
+		"INSERT INTO friends (user1_id, user2_id) SELECT 1, id FROM users WHERE id BETWEEN 6 AND 205",
+		"INSERT INTO subscriptions (subscriber_id, target_id) SELECT id, 1 FROM users WHERE id BETWEEN 156 AND 355",
+		"INSERT INTO blocks (blocker_id, blocked_id) SELECT id, 1 FROM users WHERE id BETWEEN 400 AND 404",
+	}
+	for _, statement := range statements {
+		if _, err := db.ExecContext(context.Background(), statement); err != nil {
+			b.Fatalf("Failed to seed benchmark data: %v", err)
+		}
+	}
+
+	// Mention a mix of new users, blocked users and existing friends
+	var mentioned []string
+	for i := 390; i < 410; i++ {
+		mentioned = append(mentioned, "bench"+strconv.Itoa(i)+"@mail.com")
+	}
+	return append(mentioned, "bench10@mail.com", "unknown@mail.com")
+}
+
+// getRecipientsMultiQuery resolves recipients the way the controller did before
+// GetRecipients existed, one round trip per relationship type
+func getRecipientsMultiQuery(repo interfaces.UserRepositoryInterface, sender *entities.User, mentionedEmails []string) ([]*entities.User, error) {
+	mentionedUsers, err := repo.GetUsersByEmails(mentionedEmails)
+	if err != nil {
+		return nil, err
+	}
+
+	friends, err := repo.GetFriendList(sender, entities.PageRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	subscribers, err := repo.GetSubscribersByUserID(sender.ID, entities.PageRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	recipients := make(map[int]*entities.User)
+	for _, friend := range friends.Users {
+		recipients[friend.ID] = friend
+	}
+	for _, subscriber := range subscribers.Users {
+		recipients[subscriber.ID] = subscriber
+	}
+
+	mentionedIDs := make([]int, len(mentionedUsers))
+	for i, user := range mentionedUsers {
+		mentionedIDs[i] = user.ID
+	}
+	blocked, err := repo.CheckBidirectionalBlocksBatch(sender.ID, mentionedIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range mentionedUsers {
+		if !blocked[user.ID] {
+			recipients[user.ID] = user
+		}
+	}
+
+	return slices.Collect(maps.Values(recipients)), nil
+}
+
+func BenchmarkGetRecipients_SingleQuery(b *testing.B) {
+	db, cleanup := setupTestContainer(b)
+	defer cleanup()
+
+	repo := NewUserRepository(db)
+	mentioned := seedRecipientsBenchmark(b, db)
+	sender := &entities.User{ID: 1, Email: "andy@mail.com"}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.GetRecipients(sender, mentioned, nil, entities.PageRequest{}); err != nil {
+			b.Fatalf("GetRecipients failed: %v", err)
+		}
+	}
+}
+
+func BenchmarkGetRecipients_MultiQuery(b *testing.B) {
+	db, cleanup := setupTestContainer(b)
+	defer cleanup()
+
+	repo := NewUserRepository(db)
+	mentioned := seedRecipientsBenchmark(b, db)
+	sender := &entities.User{ID: 1, Email: "andy@mail.com"}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := getRecipientsMultiQuery(repo, sender, mentioned); err != nil {
+			b.Fatalf("multi-query recipients failed: %v", err)
+		}
+	}
+}
+
+func TestUserRepository_SubscriptionsAndBlockedUsers(t *testing.T) {
+	db, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	repo := NewUserRepository(db)
+
+	andy := &entities.User{ID: 1, Email: "andy@mail.com"}
+	alice := &entities.User{ID: 2, Email: "alice@mail.com"}
+	bob := &entities.User{ID: 3, Email: "bob@mail.com"}
+	jack := &entities.User{ID: 4, Email: "jack@mail.com"}
+
+	// andy subscribes to bob and alice, jack subscribes to andy
+	for _, pair := range [][2]*entities.User{{andy, bob}, {andy, alice}, {jack, andy}} {
+		if err := repo.CreateSubscription(pair[0], pair[1]); err != nil {
+			t.Fatalf("Failed to create subscription: %v", err)
+		}
+	}
+
+	subscriptions, err := repo.GetSubscriptionsByUserID(andy.ID, entities.PageRequest{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(subscriptions.Users) != 2 || subscriptions.Users[0].Email != "alice@mail.com" || subscriptions.Users[1].Email != "bob@mail.com" {
+		t.Errorf("expected andy to subscribe to alice and bob, got %v", subscriptions)
+	}
+
+	// Blocking drops andy's subscription to bob
+	if err := repo.CreateBlockTx(andy, bob); err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+
+	blocked, err := repo.GetBlockedUsers(andy, entities.PageRequest{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(blocked.Users) != 1 || blocked.Users[0].Email != "bob@mail.com" {
+		t.Errorf("expected andy to block only bob, got %v", blocked)
+	}
+
+	subscriptions, err = repo.GetSubscriptionsByUserID(andy.ID, entities.PageRequest{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(subscriptions.Users) != 1 || subscriptions.Users[0].Email != "alice@mail.com" {
+		t.Errorf("expected andy to subscribe to alice only, got %v", subscriptions)
+	}
+
+	blocked, err = repo.GetBlockedUsers(bob, entities.PageRequest{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(blocked.Users) != 0 {
+		t.Errorf("expected bob to block nobody, got %v", blocked)
+	}
+
+	blockers, err := repo.GetBlockers(bob, entities.PageRequest{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(blockers.Users) != 1 || blockers.Users[0].Email != "andy@mail.com" {
+		t.Errorf("expected bob to be blocked by andy only, got %v", blockers)
+	}
+
+	counts, err := repo.CountSubscribersBatch([]int{andy.ID, alice.ID, bob.ID})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := map[int]int{andy.ID: 1, alice.ID: 1}
+	if len(counts) != len(expected) || counts[andy.ID] != 1 || counts[alice.ID] != 1 {
+		t.Errorf("expected subscriber counts %v, got %v", expected, counts)
+	}
+}
+
+func TestUserRepository_UserPagesBatch(t *testing.T) {
+	db, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	repo := NewUserRepository(db)
+
+	andy := &entities.User{ID: 1, Email: "andy@mail.com"}
+	alice := &entities.User{ID: 2, Email: "alice@mail.com"}
+	bob := &entities.User{ID: 3, Email: "bob@mail.com"}
+	jack := &entities.User{ID: 4, Email: "jack@mail.com"}
+	lisa := &entities.User{ID: 5, Email: "lisa@mail.com"}
+
+	for _, pair := range [][2]*entities.User{{andy, alice}, {andy, bob}, {andy, jack}, {bob, alice}, {bob, jack}, {lisa, jack}} {
+		if err := createFriendship(db, pair[0], pair[1]); err != nil {
+			t.Fatalf("Failed to create friendship %s-%s: %v", pair[0].Email, pair[1].Email, err)
+		}
+	}
+	for _, pair := range [][2]*entities.User{{alice, andy}, {jack, andy}, {lisa, bob}} {
+		if err := repo.CreateSubscription(pair[0], pair[1]); err != nil {
+			t.Fatalf("Failed to create subscription: %v", err)
+		}
+	}
+
+	emailsOf := func(page *entities.UserPage) []string {
+		emails := make([]string, len(page.Users))
+		for i, user := range page.Users {
+			emails[i] = user.Email
+		}
+		return emails
+	}
+
+	// Every user gets the same page of their own list and the size of all of it
+	friends, err := repo.GetFriendListsBatch([]int{andy.ID, bob.ID, lisa.ID}, entities.PageRequest{After: "alice@mail.com", Limit: 1})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := map[int]struct {
+		emails []string
+		total  int
+	}{
+		andy.ID: {[]string{"bob@mail.com"}, 3},
+		bob.ID:  {[]string{"andy@mail.com"}, 3},
+		lisa.ID: {[]string{"jack@mail.com"}, 1},
+	}
+	if len(friends) != len(expected) {
+		t.Fatalf("expected pages for %d users, got %d", len(expected), len(friends))
+	}
+	for userID, page := range expected {
+		if !slices.Equal(emailsOf(friends[userID]), page.emails) || friends[userID].Total != page.total {
+			t.Errorf("expected friends %v of %d for user %d, got %v of %d", page.emails, page.total, userID, emailsOf(friends[userID]), friends[userID].Total)
+		}
+	}
+
+	// A user with nothing listed after the cursor keeps their total, one with
+	// nobody at all gets an empty page
+	friends, err = repo.GetFriendListsBatch([]int{lisa.ID, 99}, entities.PageRequest{After: "zzz", Limit: 2})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(friends[lisa.ID].Users) != 0 || friends[lisa.ID].Total != 1 {
+		t.Errorf("expected an empty page of 1 friend for lisa, got %v", friends[lisa.ID])
+	}
+	if len(friends[99].Users) != 0 || friends[99].Total != 0 {
+		t.Errorf("expected an empty page for an unknown user, got %v", friends[99])
+	}
+
+	subscribers, err := repo.GetSubscribersBatch([]int{andy.ID, bob.ID, jack.ID}, entities.PageRequest{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !slices.Equal(emailsOf(subscribers[andy.ID]), []string{"alice@mail.com", "jack@mail.com"}) ||
+		!slices.Equal(emailsOf(subscribers[bob.ID]), []string{"lisa@mail.com"}) ||
+		len(subscribers[jack.ID].Users) != 0 {
+		t.Errorf("expected subscribers alice and jack for andy, lisa for bob and none for jack, got %v", subscribers)
+	}
+
+	// Blocking drops the friendship and subscription between lisa and bob
+	if err := repo.CreateBlockTx(lisa, bob); err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+	blockers, err := repo.GetBlockersBatch([]int{andy.ID, bob.ID}, entities.PageRequest{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(blockers[andy.ID].Users) != 0 || !slices.Equal(emailsOf(blockers[bob.ID]), []string{"lisa@mail.com"}) {
+		t.Errorf("expected bob to be blocked by lisa only, got %v", blockers)
+	}
+
+	// jack's friends are andy, bob and lisa: andy shares bob with jack, bob
+	// shares andy and lisa shares nobody
+	common, err := repo.GetCommonFriendsBatch([]int{andy.ID, bob.ID, lisa.ID}, []*entities.User{jack}, entities.PageRequest{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !slices.Equal(emailsOf(common[andy.ID]), []string{"bob@mail.com"}) ||
+		!slices.Equal(emailsOf(common[bob.ID]), []string{"andy@mail.com"}) ||
+		len(common[lisa.ID].Users) != 0 {
+		t.Errorf("expected andy and bob to share each other with jack and lisa nobody, got %v", common)
+	}
+
+	common, err = repo.GetCommonFriendsBatch([]int{andy.ID}, []*entities.User{bob, jack}, entities.PageRequest{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(common[andy.ID].Users) != 0 {
+		t.Errorf("expected andy to share no friend with both bob and jack, got %v", emailsOf(common[andy.ID]))
+	}
+
+	empty, err := repo.GetFriendListsBatch([]int{}, entities.PageRequest{})
+	if err != nil {
+		t.Fatalf("expected no error for empty input, got %v", err)
+	}
+	if len(empty) != 0 {
+		t.Errorf("expected empty result, got %d entries", len(empty))
+	}
+}
+
+func TestUserRepository_KeysetPagination(t *testing.T) {
+	db, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	repo := NewUserRepository(db)
+
+	andy := &entities.User{ID: 1, Email: "andy@mail.com"}
+
+	// a.z@mail.com sorts first bytewise, though locales that skip punctuation
+	// would put it after alice@mail.com. Emails are stored lowercase, so case
+	// cannot show the difference
+	az, err := repo.CreateUser("a.z@mail.com")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	friends := []*entities.User{
+		az,
+		{ID: 2, Email: "alice@mail.com"},
+		{ID: 3, Email: "bob@mail.com"},
+		{ID: 4, Email: "jack@mail.com"},
+		{ID: 5, Email: "lisa@mail.com"},
+	}
+	for _, friend := range friends {
+		if err := createFriendship(db, andy, friend); err != nil {
+			t.Fatalf("Failed to create friendship: %v", err)
+		}
+	}
+
+	// Walk the friend list two at a time, the way the controller asks for pages
+	var emails []string
+	after := ""
+	for {
+		page, err := repo.GetFriendList(andy, entities.PageRequest{After: after, Limit: 2})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if page.Total != len(friends) {
+			t.Errorf("expected a total of %d friends after %q, got %d", len(friends), after, page.Total)
+		}
+		for _, friend := range page.Users {
+			emails = append(emails, friend.Email)
+		}
+		if len(page.Users) < 2 {
+			break
+		}
+		after = page.Users[len(page.Users)-1].Email
+	}
+
+	expected := []string{"a.z@mail.com", "alice@mail.com", "bob@mail.com", "jack@mail.com", "lisa@mail.com"}
+	if !slices.Equal(emails, expected) {
+		t.Errorf("expected friends %v, got %v", expected, emails)
+	}
+
+	recipients, err := repo.GetRecipients(andy, nil, nil, entities.PageRequest{After: "alice@mail.com", Limit: 2})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if recipients.Total != len(friends) {
+		t.Errorf("expected a total of %d recipients, got %d", len(friends), recipients.Total)
+	}
+	if len(recipients.Recipients) != 2 || recipients.Recipients[0].User.Email != "bob@mail.com" || recipients.Recipients[1].User.Email != "jack@mail.com" {
+		t.Errorf("expected bob and jack after alice, got %v", recipients.Recipients)
+	}
+
+	// A page past the end is empty but still reports the total
+	page, err := repo.GetFriendList(andy, entities.PageRequest{After: "zzz", Limit: 2})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(page.Users) != 0 || page.Total != len(friends) {
+		t.Errorf("expected an empty page of %d friends, got %d of %d", len(friends), len(page.Users), page.Total)
+	}
+}
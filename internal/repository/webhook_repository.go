@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"assignment/internal/domain/entities"
+	"assignment/internal/domain/interfaces"
+	"assignment/pkg/errors"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+)
+
+type webhookRepository struct {
+	db *sql.DB
+}
+
+func NewWebhookRepository(db *sql.DB) interfaces.WebhookRepositoryInterface {
+	return &webhookRepository{db: db}
+}
+
+type webhookRow struct {
+	ID        int       `boil:"id"`
+	UserID    int       `boil:"user_id"`
+	Email     string    `boil:"email"`
+	URL       string    `boil:"url"`
+	Secret    string    `boil:"secret"`
+	CreatedAt time.Time `boil:"created_at"`
+}
+
+func (row *webhookRow) toEntity() *entities.Webhook {
+	return &entities.Webhook{
+		ID:        row.ID,
+		User:      &entities.User{ID: row.UserID, Email: row.Email},
+		URL:       row.URL,
+		Secret:    row.Secret,
+		CreatedAt: row.CreatedAt,
+	}
+}
+
+// getWebhooksByUserIDs returns the webhooks registered by any of the given users,
+// using the caller's executor so it can run inside a transaction
+func getWebhooksByUserIDs(exec boil.ContextExecutor, userIDs []int) ([]*entities.Webhook, error) {
+	var rows []*webhookRow
+	err := queries.Raw(
+		`SELECT w.id, w.user_id, u.email, w.url, w.secret, w.created_at
+		FROM webhooks w
+		JOIN users u ON u.id = w.user_id
+		WHERE w.user_id = ANY($1)
+		ORDER BY w.id`,
+		pq.Array(userIDs),
+	).Bind(context.Background(), exec, &rows)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to fetch webhooks")
+	}
+
+	webhooks := make([]*entities.Webhook, len(rows))
+	for i, row := range rows {
+		webhooks[i] = row.toEntity()
+	}
+
+	return webhooks, nil
+}
+
+// UpsertWebhook registers the user's webhook, replacing the URL and secret of an
+// existing one
+func (r *webhookRepository) UpsertWebhook(user *entities.User, url, secret string) (*entities.Webhook, error) {
+	var row struct {
+		ID        int       `boil:"id"`
+		CreatedAt time.Time `boil:"created_at"`
+	}
+
+	err := queries.Raw(
+		`INSERT INTO webhooks (user_id, url, secret) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET url = EXCLUDED.url, secret = EXCLUDED.secret, updated_at = NOW()
+		RETURNING id, created_at`,
+		user.ID, url, secret,
+	).Bind(context.Background(), r.db, &row)
+	if err != nil {
+		return nil, errors.FromError(err)
+	}
+
+	return &entities.Webhook{
+		ID:        row.ID,
+		User:      user,
+		URL:       url,
+		Secret:    secret,
+		CreatedAt: row.CreatedAt,
+	}, nil
+}
+
+func (r *webhookRepository) GetWebhookByID(id int) (*entities.Webhook, error) {
+	var row webhookRow
+	err := queries.Raw(
+		`SELECT w.id, w.user_id, u.email, w.url, w.secret, w.created_at
+		FROM webhooks w
+		JOIN users u ON u.id = w.user_id
+		WHERE w.id = $1`,
+		id,
+	).Bind(context.Background(), r.db, &row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrWebhookNotFound
+		}
+		return nil, errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to fetch webhook")
+	}
+
+	return row.toEntity(), nil
+}
+
+func (r *webhookRepository) GetWebhookByUser(user *entities.User) (*entities.Webhook, error) {
+	webhooks, err := getWebhooksByUserIDs(r.db, []int{user.ID})
+	if err != nil {
+		return nil, err
+	}
+	if len(webhooks) == 0 {
+		return nil, errors.ErrWebhookNotFound
+	}
+
+	return webhooks[0], nil
+}
+
+func (r *webhookRepository) DeleteWebhook(user *entities.User) error {
+	result, err := queries.Raw(
+		`DELETE FROM webhooks WHERE user_id = $1`,
+		user.ID,
+	).ExecContext(context.Background(), r.db)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to delete webhook")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to delete webhook")
+	}
+	if rowsAff == 0 {
+		return errors.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+func (r *webhookRepository) RecordWebhookDelivery(delivery *entities.WebhookDelivery) error {
+	statusCode := sql.NullInt64{Int64: int64(delivery.StatusCode), Valid: delivery.StatusCode != 0}
+	deliveryErr := sql.NullString{String: delivery.Error, Valid: delivery.Error != ""}
+
+	_, err := queries.Raw(
+		`INSERT INTO webhook_deliveries
+			(webhook_id, outbox_event_id, update_id, attempt, status_code, success, error, duration_ms)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		delivery.WebhookID, delivery.OutboxEventID, delivery.UpdateID, delivery.Attempt,
+		statusCode, delivery.Success, deliveryErr, delivery.Duration.Milliseconds(),
+	).ExecContext(context.Background(), r.db)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to record webhook delivery")
+	}
+
+	return nil
+}
+
+// GetWebhookDeliveries returns the most recent delivery attempts for the webhook, newest first
+func (r *webhookRepository) GetWebhookDeliveries(webhook *entities.Webhook, limit int) ([]*entities.WebhookDelivery, error) {
+	var rows []struct {
+		ID            int            `boil:"id"`
+		WebhookID     int            `boil:"webhook_id"`
+		OutboxEventID int            `boil:"outbox_event_id"`
+		UpdateID      int            `boil:"update_id"`
+		Attempt       int            `boil:"attempt"`
+		StatusCode    sql.NullInt64  `boil:"status_code"`
+		Success       bool           `boil:"success"`
+		Error         sql.NullString `boil:"error"`
+		DurationMS    int64          `boil:"duration_ms"`
+		CreatedAt     time.Time      `boil:"created_at"`
+	}
+
+	err := queries.Raw(
+		`SELECT id, webhook_id, outbox_event_id, update_id, attempt, status_code, success, error, duration_ms, created_at
+		FROM webhook_deliveries
+		WHERE webhook_id = $1
+		ORDER BY id DESC
+		LIMIT $2`,
+		webhook.ID, limit,
+	).Bind(context.Background(), r.db, &rows)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeDatabase, "Failed to fetch webhook deliveries")
+	}
+
+	deliveries := make([]*entities.WebhookDelivery, len(rows))
+	for i, row := range rows {
+		deliveries[i] = &entities.WebhookDelivery{
+			ID:            row.ID,
+			WebhookID:     row.WebhookID,
+			OutboxEventID: row.OutboxEventID,
+			UpdateID:      row.UpdateID,
+			Attempt:       row.Attempt,
+			StatusCode:    int(row.StatusCode.Int64),
+			Success:       row.Success,
+			Error:         row.Error.String,
+			Duration:      time.Duration(row.DurationMS) * time.Millisecond,
+			CreatedAt:     row.CreatedAt,
+		}
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"assignment/internal/domain/entities"
+	"assignment/pkg/errors"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestWebhookRepository_UpsertAndDelete(t *testing.T) {
+	db, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	repo := NewWebhookRepository(db)
+
+	alice := &entities.User{ID: 2, Email: "alice@mail.com"}
+
+	first, err := repo.UpsertWebhook(alice, "https://example.com/a", "secret-1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Registering again replaces the URL and secret of the same webhook
+	second, err := repo.UpsertWebhook(alice, "https://example.com/b", "secret-2")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if second.ID != first.ID {
+		t.Errorf("expected webhook %d to be updated, got new webhook %d", first.ID, second.ID)
+	}
+
+	webhook, err := repo.GetWebhookByID(first.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if webhook.URL != "https://example.com/b" || webhook.Secret != "secret-2" || webhook.User.Email != "alice@mail.com" {
+		t.Errorf("unexpected webhook: %+v", webhook)
+	}
+
+	if err := repo.DeleteWebhook(alice); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if _, err := repo.GetWebhookByUser(alice); err != errors.ErrWebhookNotFound {
+		t.Errorf("expected ErrWebhookNotFound, got %v", err)
+	}
+	if err := repo.DeleteWebhook(alice); err != errors.ErrWebhookNotFound {
+		t.Errorf("expected ErrWebhookNotFound, got %v", err)
+	}
+}
+
+func TestWebhookRepository_DeliveriesForPublishedUpdate(t *testing.T) {
+	db, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	webhookRepo := NewWebhookRepository(db)
+	updateRepo := NewUpdateRepository(db)
+
+	andy := &entities.User{ID: 1, Email: "andy@mail.com"}
+	alice := &entities.User{ID: 2, Email: "alice@mail.com"}
+	bob := &entities.User{ID: 3, Email: "bob@mail.com"}
+
+	webhook, err := webhookRepo.UpsertWebhook(alice, "https://example.com/alice", "secret")
+	if err != nil {
+		t.Fatalf("Failed to register webhook: %v", err)
+	}
+
+	// Only alice has a webhook, so only she gets a webhook.delivery event
+	update, err := updateRepo.CreateUpdateTx(andy, "Hello", []*entities.User{alice, bob})
+	if err != nil {
+		t.Fatalf("Failed to publish update: %v", err)
+	}
+
+	var payloads [][]byte
+	rows, err := db.QueryContext(context.Background(), "SELECT payload FROM outbox WHERE event_type = $1", entities.EventWebhookDelivery)
+	if err != nil {
+		t.Fatalf("Failed to query outbox: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var payload []byte
+		if err := rows.Scan(&payload); err != nil {
+			t.Fatalf("Failed to scan outbox row: %v", err)
+		}
+		payloads = append(payloads, payload)
+	}
+
+	if len(payloads) != 1 {
+		t.Fatalf("expected 1 webhook delivery event, got %d", len(payloads))
+	}
+	var payload entities.WebhookDeliveryPayload
+	if err := json.Unmarshal(payloads[0], &payload); err != nil {
+		t.Fatalf("Failed to decode payload: %v", err)
+	}
+	if payload.WebhookID != webhook.ID || payload.Recipient != "alice@mail.com" || payload.UpdateID != update.ID {
+		t.Errorf("unexpected webhook delivery payload: %+v", payload)
+	}
+
+	// Record a failed and a successful attempt and read them back newest first
+	attempts := []*entities.WebhookDelivery{
+		{WebhookID: webhook.ID, OutboxEventID: 1, UpdateID: update.ID, Attempt: 1, Error: "connection refused", Duration: 5 * time.Millisecond},
+		{WebhookID: webhook.ID, OutboxEventID: 1, UpdateID: update.ID, Attempt: 2, StatusCode: 200, Success: true, Duration: 12 * time.Millisecond},
+	}
+	for _, attempt := range attempts {
+		if err := webhookRepo.RecordWebhookDelivery(attempt); err != nil {
+			t.Fatalf("Failed to record delivery: %v", err)
+		}
+	}
+
+	deliveries, err := webhookRepo.GetWebhookDeliveries(webhook, 10)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(deliveries) != 2 {
+		t.Fatalf("expected 2 deliveries, got %d", len(deliveries))
+	}
+	if deliveries[0].Attempt != 2 || !deliveries[0].Success || deliveries[0].StatusCode != 200 {
+		t.Errorf("unexpected latest delivery: %+v", deliveries[0])
+	}
+	if deliveries[1].Attempt != 1 || deliveries[1].Success || deliveries[1].StatusCode != 0 || deliveries[1].Error != "connection refused" {
+		t.Errorf("unexpected first delivery: %+v", deliveries[1])
+	}
+	if deliveries[1].Duration != 5*time.Millisecond {
+		t.Errorf("expected duration 5ms, got %v", deliveries[1].Duration)
+	}
+}
@@ -0,0 +1,65 @@
+package rpc
+
+import (
+	"assignment/internal/domain/interfaces"
+	"assignment/internal/rpc/userpb"
+	"assignment/pkg/errors"
+	"assignment/pkg/validator"
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// NewServer returns a gRPC server with the services registered on top of the
+// controllers. Errors returned by the services are converted to gRPC statuses
+// from their AppError type
+func NewServer(controllers interfaces.Controllers) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryErrorInterceptor),
+		grpc.ChainStreamInterceptor(streamErrorInterceptor),
+	)
+
+	userpb.RegisterUserServiceServer(server, NewUserServer(controllers.UserController()))
+
+	return server
+}
+
+func unaryErrorInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return nil, errors.ToGRPCStatus(err)
+	}
+	return resp, nil
+}
+
+func streamErrorInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return errors.ToGRPCStatus(handler(srv, stream))
+}
+
+func validateEmail(email string) error {
+	v := validator.New()
+	if validator.ValidateEmail(v, email); !v.Valid() {
+		return newValidationError(v)
+	}
+	return nil
+}
+
+// maxEmails bounds the number of users an EmailsRequest names
+const maxEmails = 100
+
+func validateEmails(emails []string) error {
+	v := validator.New()
+	v.Check(len(emails) > 0, "emails", "must not be empty")
+	v.Check(len(emails) <= maxEmails, "emails", "must not contain more than 100 emails")
+	for _, email := range emails {
+		validator.ValidateEmail(v, email)
+	}
+	if !v.Valid() {
+		return newValidationError(v)
+	}
+	return nil
+}
+
+func newValidationError(v *validator.Validator) error {
+	return errors.NewValidationError(v.Errors)
+}
@@ -0,0 +1,653 @@
+package rpc
+
+import (
+	"assignment/internal/domain/entities"
+	"assignment/internal/domain/interfaces"
+	"assignment/internal/rpc/userpb"
+	"assignment/pkg/validator"
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// UserServer implements userpb.UserServiceServer on top of the user controller.
+// Requests are validated with the pkg/validator rules the HTTP handlers use, and
+// controller errors are turned into status codes by the server's interceptors
+type UserServer struct {
+	userpb.UnimplementedUserServiceServer
+	userController interfaces.UserControllerInterface
+}
+
+func NewUserServer(userController interfaces.UserControllerInterface) *UserServer {
+	return &UserServer{
+		userController: userController,
+	}
+}
+
+// CreateFriendship is deprecated: friendships need the target's consent, so it
+// sends a friend request from the first user to the second and is kept for
+// existing clients
+func (s *UserServer) CreateFriendship(ctx context.Context, req *userpb.FriendsRequest) (*emptypb.Empty, error) {
+	v := validator.New()
+	if validator.ValidateEmailPair(v, req.Friends); !v.Valid() {
+		return nil, newValidationError(v)
+	}
+
+	if _, err := s.userController.SendFriendRequest(req.Friends[0], req.Friends[1]); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *UserServer) DeleteFriendship(ctx context.Context, req *userpb.FriendsRequest) (*emptypb.Empty, error) {
+	v := validator.New()
+	if validator.ValidateEmailPair(v, req.Friends); !v.Valid() {
+		return nil, newValidationError(v)
+	}
+
+	if err := s.userController.DeleteFriendship(req.Friends[0], req.Friends[1]); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *UserServer) SendFriendRequest(ctx context.Context, req *userpb.UserPairRequest) (*userpb.FriendRequest, error) {
+	v := validator.New()
+	if validator.ValidateUserPair(v, req.Requestor, req.Target, "requestor and target cannot be the same"); !v.Valid() {
+		return nil, newValidationError(v)
+	}
+
+	request, err := s.userController.SendFriendRequest(req.Requestor, req.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	return toFriendRequest(request), nil
+}
+
+func (s *UserServer) GetIncomingFriendRequests(ctx context.Context, req *userpb.EmailRequest) (*userpb.FriendRequestList, error) {
+	return s.getFriendRequests(req, s.userController.GetIncomingFriendRequests)
+}
+
+func (s *UserServer) GetOutgoingFriendRequests(ctx context.Context, req *userpb.EmailRequest) (*userpb.FriendRequestList, error) {
+	return s.getFriendRequests(req, s.userController.GetOutgoingFriendRequests)
+}
+
+func (s *UserServer) getFriendRequests(req *userpb.EmailRequest, list func(email string) ([]*entities.FriendRequest, error)) (*userpb.FriendRequestList, error) {
+	if err := validateEmail(req.Email); err != nil {
+		return nil, err
+	}
+
+	requests, err := list(req.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &userpb.FriendRequestList{Requests: make([]*userpb.FriendRequest, len(requests))}
+	for i, request := range requests {
+		response.Requests[i] = toFriendRequest(request)
+	}
+
+	return response, nil
+}
+
+func (s *UserServer) AcceptFriendRequest(ctx context.Context, req *userpb.UserPairRequest) (*emptypb.Empty, error) {
+	return s.respondToFriendRequest(req, s.userController.AcceptFriendRequest)
+}
+
+func (s *UserServer) RejectFriendRequest(ctx context.Context, req *userpb.UserPairRequest) (*emptypb.Empty, error) {
+	return s.respondToFriendRequest(req, s.userController.RejectFriendRequest)
+}
+
+func (s *UserServer) CancelFriendRequest(ctx context.Context, req *userpb.UserPairRequest) (*emptypb.Empty, error) {
+	return s.respondToFriendRequest(req, s.userController.CancelFriendRequest)
+}
+
+func (s *UserServer) respondToFriendRequest(req *userpb.UserPairRequest, action func(requestorEmail, targetEmail string) error) (*emptypb.Empty, error) {
+	v := validator.New()
+	if validator.ValidateUserPair(v, req.Requestor, req.Target, "requestor and target cannot be the same"); !v.Valid() {
+		return nil, newValidationError(v)
+	}
+
+	if err := action(req.Requestor, req.Target); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func toFriendRequest(request *entities.FriendRequest) *userpb.FriendRequest {
+	return &userpb.FriendRequest{
+		Requestor: request.Requester.Email,
+		Target:    request.Addressee.Email,
+		Status:    string(request.Status),
+		CreatedAt: timestamppb.New(request.CreatedAt),
+	}
+}
+
+func (s *UserServer) GetFriendList(ctx context.Context, req *userpb.UserListRequest) (*userpb.UserPage, error) {
+	return s.getUserList(req, s.userController.GetFriendList)
+}
+
+func (s *UserServer) GetCommonFriends(ctx context.Context, req *userpb.CommonFriendsRequest) (*userpb.UserPage, error) {
+	v := validator.New()
+	validator.ValidateCommonFriendsEmails(v, req.Friends)
+	if validator.ValidatePageLimit(v, int(req.Limit)); !v.Valid() {
+		return nil, newValidationError(v)
+	}
+
+	page, err := s.userController.GetCommonFriends(req.Friends, req.Cursor, validator.PageLimit(int(req.Limit)))
+	if err != nil {
+		return nil, err
+	}
+
+	return toUserPage(page), nil
+}
+
+func (s *UserServer) GetFriendshipPath(ctx context.Context, req *userpb.FriendshipPathRequest) (*userpb.FriendshipPath, error) {
+	v := validator.New()
+	validator.ValidateUserPair(v, req.Requestor, req.Target, "requestor and target cannot be the same")
+	if validator.ValidatePathMaxDepth(v, int(req.MaxDepth)); !v.Valid() {
+		return nil, newValidationError(v)
+	}
+
+	maxDepth := int(req.MaxDepth)
+	if maxDepth == 0 {
+		maxDepth = validator.DefaultPathMaxDepth
+	}
+
+	path, err := s.userController.GetFriendshipPath(req.Requestor, req.Target, maxDepth)
+	if err != nil {
+		return nil, err
+	}
+
+	return &userpb.FriendshipPath{
+		Path: userEmails(path),
+		Hops: int32(len(path) - 1),
+	}, nil
+}
+
+func (s *UserServer) GetFriendSuggestions(ctx context.Context, req *userpb.FriendSuggestionsRequest) (*userpb.FriendSuggestionList, error) {
+	v := validator.New()
+	validator.ValidateEmail(v, req.Email)
+	if validator.ValidateSuggestionLimit(v, int(req.Limit)); !v.Valid() {
+		return nil, newValidationError(v)
+	}
+
+	limit := int(req.Limit)
+	if limit == 0 {
+		limit = validator.DefaultSuggestionLimit
+	}
+
+	suggestions, err := s.userController.GetFriendSuggestions(req.Email, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &userpb.FriendSuggestionList{Suggestions: make([]*userpb.FriendSuggestion, len(suggestions))}
+	for i, suggestion := range suggestions {
+		response.Suggestions[i] = &userpb.FriendSuggestion{
+			Email:         suggestion.User.Email,
+			MutualFriends: int32(suggestion.MutualFriends),
+		}
+	}
+
+	return response, nil
+}
+
+func (s *UserServer) CreateSubscription(ctx context.Context, req *userpb.UserPairRequest) (*emptypb.Empty, error) {
+	v := validator.New()
+	if validator.ValidateUserPair(v, req.Requestor, req.Target, "requestor and target cannot be the same"); !v.Valid() {
+		return nil, newValidationError(v)
+	}
+
+	if err := s.userController.CreateSubscription(req.Requestor, req.Target); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *UserServer) DeleteSubscription(ctx context.Context, req *userpb.UserPairRequest) (*emptypb.Empty, error) {
+	v := validator.New()
+	if validator.ValidateUserPair(v, req.Requestor, req.Target, "requestor and target cannot be the same"); !v.Valid() {
+		return nil, newValidationError(v)
+	}
+
+	if err := s.userController.DeleteSubscription(req.Requestor, req.Target); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *UserServer) GetSubscribers(ctx context.Context, req *userpb.UserListRequest) (*userpb.UserPage, error) {
+	return s.getUserList(req, s.userController.GetSubscribers)
+}
+
+func (s *UserServer) GetSubscriptions(ctx context.Context, req *userpb.UserListRequest) (*userpb.UserPage, error) {
+	return s.getUserList(req, s.userController.GetSubscriptions)
+}
+
+func (s *UserServer) CountSubscribers(ctx context.Context, req *userpb.EmailsRequest) (*userpb.SubscriberCounts, error) {
+	if err := validateEmails(req.Emails); err != nil {
+		return nil, err
+	}
+
+	counts, err := s.userController.CountSubscribers(req.Emails)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &userpb.SubscriberCounts{Counts: make(map[string]int32, len(counts))}
+	for email, count := range counts {
+		resp.Counts[email] = int32(count)
+	}
+
+	return resp, nil
+}
+
+func (s *UserServer) GetSubscriptionFilter(ctx context.Context, req *userpb.UserPairRequest) (*userpb.SubscriptionFilter, error) {
+	v := validator.New()
+	if validator.ValidateUserPair(v, req.Requestor, req.Target, "requestor and target cannot be the same"); !v.Valid() {
+		return nil, newValidationError(v)
+	}
+
+	filter, err := s.userController.GetSubscriptionFilter(req.Requestor, req.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	return toSubscriptionFilter(req.Requestor, req.Target, filter), nil
+}
+
+func (s *UserServer) UpdateSubscriptionFilter(ctx context.Context, req *userpb.SubscriptionFilter) (*userpb.SubscriptionFilter, error) {
+	v := validator.New()
+	validator.ValidateUserPair(v, req.Requestor, req.Target, "requestor and target cannot be the same")
+	validator.ValidateKeywords(v, "include", req.Include)
+	if validator.ValidateKeywords(v, "exclude", req.Exclude); !v.Valid() {
+		return nil, newValidationError(v)
+	}
+
+	filter, err := s.userController.UpdateSubscriptionFilter(req.Requestor, req.Target, &entities.SubscriptionFilter{
+		Include: req.Include,
+		Exclude: req.Exclude,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toSubscriptionFilter(req.Requestor, req.Target, filter), nil
+}
+
+func toSubscriptionFilter(requestor, target string, filter *entities.SubscriptionFilter) *userpb.SubscriptionFilter {
+	return &userpb.SubscriptionFilter{
+		Requestor: requestor,
+		Target:    target,
+		Include:   filter.Include,
+		Exclude:   filter.Exclude,
+	}
+}
+
+func (s *UserServer) CreateBlock(ctx context.Context, req *userpb.UserPairRequest) (*emptypb.Empty, error) {
+	v := validator.New()
+	if validator.ValidateUserPair(v, req.Requestor, req.Target, "cannot block yourself"); !v.Valid() {
+		return nil, newValidationError(v)
+	}
+
+	if err := s.userController.CreateBlock(req.Requestor, req.Target); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *UserServer) DeleteBlock(ctx context.Context, req *userpb.DeleteBlockRequest) (*emptypb.Empty, error) {
+	v := validator.New()
+	if validator.ValidateUserPair(v, req.Requestor, req.Target, "cannot unblock yourself"); !v.Valid() {
+		return nil, newValidationError(v)
+	}
+
+	if err := s.userController.DeleteBlock(req.Requestor, req.Target, req.Restore); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *UserServer) GetBlockedUsers(ctx context.Context, req *userpb.UserListRequest) (*userpb.UserPage, error) {
+	return s.getUserList(req, s.userController.GetBlockedUsers)
+}
+
+func (s *UserServer) GetBlockers(ctx context.Context, req *userpb.UserListRequest) (*userpb.UserPage, error) {
+	return s.getUserList(req, s.userController.GetBlockers)
+}
+
+func (s *UserServer) CreateMute(ctx context.Context, req *userpb.CreateMuteRequest) (*userpb.Mute, error) {
+	var expiresAt *time.Time
+	if req.ExpiresAt != nil {
+		t := req.ExpiresAt.AsTime()
+		expiresAt = &t
+	}
+
+	v := validator.New()
+	if validator.ValidateUserPair(v, req.Requestor, req.Target, "cannot mute yourself"); !v.Valid() {
+		return nil, newValidationError(v)
+	}
+
+	mute, err := s.userController.CreateMute(req.Requestor, req.Target, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return toMute(mute), nil
+}
+
+func (s *UserServer) DeleteMute(ctx context.Context, req *userpb.UserPairRequest) (*emptypb.Empty, error) {
+	v := validator.New()
+	if validator.ValidateUserPair(v, req.Requestor, req.Target, "cannot unmute yourself"); !v.Valid() {
+		return nil, newValidationError(v)
+	}
+
+	if err := s.userController.DeleteMute(req.Requestor, req.Target); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *UserServer) GetMutes(ctx context.Context, req *userpb.EmailRequest) (*userpb.MuteList, error) {
+	if err := validateEmail(req.Email); err != nil {
+		return nil, err
+	}
+
+	mutes, err := s.userController.GetMutes(req.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &userpb.MuteList{Mutes: make([]*userpb.Mute, len(mutes))}
+	for i, mute := range mutes {
+		response.Mutes[i] = toMute(mute)
+	}
+
+	return response, nil
+}
+
+func toMute(mute *entities.Mute) *userpb.Mute {
+	response := &userpb.Mute{
+		Requestor: mute.Muter.Email,
+		Target:    mute.Muted.Email,
+	}
+	if mute.ExpiresAt != nil {
+		response.ExpiresAt = timestamppb.New(*mute.ExpiresAt)
+	}
+	if !mute.CreatedAt.IsZero() {
+		response.CreatedAt = timestamppb.New(mute.CreatedAt)
+	}
+	return response
+}
+
+func (s *UserServer) GetRelationship(ctx context.Context, req *userpb.UserPairRequest) (*userpb.Relationship, error) {
+	v := validator.New()
+	if validator.ValidateUserPair(v, req.Requestor, req.Target, "requestor and target cannot be the same"); !v.Valid() {
+		return nil, newValidationError(v)
+	}
+
+	relationship, err := s.userController.GetRelationship(req.Requestor, req.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	return &userpb.Relationship{
+		Friends:                   relationship.AreFriends,
+		RequestorSubscribesTarget: relationship.ASubscribesToB,
+		TargetSubscribesRequestor: relationship.BSubscribesToA,
+		RequestorBlocksTarget:     relationship.ABlocksB,
+		TargetBlocksRequestor:     relationship.BBlocksA,
+	}, nil
+}
+
+// SubscribeEvents sends the user's events until the client goes away, or ends
+// with Unavailable when the server closes the event hub on shutdown
+func (s *UserServer) SubscribeEvents(req *userpb.EmailRequest, stream userpb.UserService_SubscribeEventsServer) error {
+	if err := validateEmail(req.Email); err != nil {
+		return err
+	}
+
+	events, err := s.userController.SubscribeEvents(req.Email)
+	if err != nil {
+		return err
+	}
+	defer events.Close()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case event, ok := <-events.Events:
+			if !ok {
+				return status.Error(codes.Unavailable, "Event stream closed")
+			}
+			err := stream.Send(&userpb.UserEvent{
+				Type:      string(event.Type),
+				Actor:     event.Actor.Email,
+				Target:    event.Target.Email,
+				CreatedAt: timestamppb.New(event.CreatedAt),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *UserServer) GetRecipients(ctx context.Context, req *userpb.RecipientsRequest) (*userpb.UserPage, error) {
+	limit, err := validateRecipientsRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := s.userController.GetRecipients(req.Sender, req.Text, req.Cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return toUserPage(page), nil
+}
+
+func (s *UserServer) ExplainRecipients(ctx context.Context, req *userpb.RecipientsRequest) (*userpb.RecipientExplanation, error) {
+	limit, err := validateRecipientsRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	explanation, err := s.userController.ExplainRecipients(req.Sender, req.Text, req.Cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &userpb.RecipientExplanation{
+		Recipients:      make([]*userpb.Recipient, len(explanation.Recipients)),
+		DroppedMentions: make([]*userpb.DroppedMention, len(explanation.DroppedMentions)),
+		Total:           int32(explanation.Total),
+		NextCursor:      explanation.NextCursor,
+	}
+	for i, recipient := range explanation.Recipients {
+		reasons := make([]string, len(recipient.Reasons))
+		for j, reason := range recipient.Reasons {
+			reasons[j] = string(reason)
+		}
+		response.Recipients[i] = &userpb.Recipient{
+			Email:   recipient.User.Email,
+			Reasons: reasons,
+		}
+	}
+	for i, mention := range explanation.DroppedMentions {
+		response.DroppedMentions[i] = &userpb.DroppedMention{
+			Email:  mention.Email,
+			Handle: mention.Handle,
+			Reason: string(mention.Reason),
+		}
+	}
+
+	return response, nil
+}
+
+// validateRecipientsRequest returns the page size asked for by a valid req
+func validateRecipientsRequest(req *userpb.RecipientsRequest) (int, error) {
+	v := validator.New()
+	validator.ValidateUpdateText(v, req.Sender, req.Text)
+	if validator.ValidatePageLimit(v, int(req.Limit)); !v.Valid() {
+		return 0, newValidationError(v)
+	}
+
+	return validator.PageLimit(int(req.Limit)), nil
+}
+
+func (s *UserServer) CreateUser(ctx context.Context, req *userpb.EmailRequest) (*userpb.User, error) {
+	if err := validateEmail(req.Email); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userController.CreateUser(req.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	return &userpb.User{Email: user.Email}, nil
+}
+
+func (s *UserServer) GetUser(ctx context.Context, req *userpb.EmailRequest) (*userpb.User, error) {
+	if err := validateEmail(req.Email); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userController.GetUser(req.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	return &userpb.User{Email: user.Email}, nil
+}
+
+func (s *UserServer) GetUsersByEmails(ctx context.Context, req *userpb.EmailsRequest) (*userpb.UserList, error) {
+	if err := validateEmails(req.Emails); err != nil {
+		return nil, err
+	}
+
+	users, err := s.userController.GetUsersByEmails(req.Emails)
+	if err != nil {
+		return nil, err
+	}
+
+	return &userpb.UserList{Users: userEmails(users)}, nil
+}
+
+func (s *UserServer) SetUsername(ctx context.Context, req *userpb.SetUsernameRequest) (*emptypb.Empty, error) {
+	v := validator.New()
+	validator.ValidateEmail(v, req.Email)
+	if validator.ValidateUsername(v, req.Username); !v.Valid() {
+		return nil, newValidationError(v)
+	}
+
+	if err := s.userController.SetUsername(req.Email, req.Username); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *UserServer) GetSettings(ctx context.Context, req *userpb.EmailRequest) (*userpb.Settings, error) {
+	if err := validateEmail(req.Email); err != nil {
+		return nil, err
+	}
+
+	settings, err := s.userController.GetSettings(req.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	return toSettings(settings), nil
+}
+
+func (s *UserServer) UpdateSettings(ctx context.Context, req *userpb.Settings) (*userpb.Settings, error) {
+	v := validator.New()
+	validator.ValidateEmail(v, req.Email)
+	if validator.ValidateMentionPolicy(v, req.MentionPolicy); !v.Valid() {
+		return nil, newValidationError(v)
+	}
+
+	settings, err := s.userController.UpdateSettings(req.Email, entities.MentionPolicy(req.MentionPolicy))
+	if err != nil {
+		return nil, err
+	}
+
+	return toSettings(settings), nil
+}
+
+func toSettings(settings *entities.UserSettings) *userpb.Settings {
+	return &userpb.Settings{
+		Email:         settings.User.Email,
+		MentionPolicy: string(settings.MentionPolicy),
+	}
+}
+
+func (s *UserServer) GetUsers(ctx context.Context, req *emptypb.Empty) (*userpb.UserList, error) {
+	users, err := s.userController.GetUsers()
+	if err != nil {
+		return nil, err
+	}
+
+	return &userpb.UserList{Users: userEmails(users)}, nil
+}
+
+func (s *UserServer) DeleteUser(ctx context.Context, req *userpb.EmailRequest) (*emptypb.Empty, error) {
+	if err := validateEmail(req.Email); err != nil {
+		return nil, err
+	}
+
+	if err := s.userController.DeleteUser(req.Email); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// getUserList serves a page of a list of users related to req.Email
+func (s *UserServer) getUserList(req *userpb.UserListRequest, list func(email, cursor string, limit int) (*entities.UserPage, error)) (*userpb.UserPage, error) {
+	v := validator.New()
+	validator.ValidateEmail(v, req.Email)
+	if validator.ValidatePageLimit(v, int(req.Limit)); !v.Valid() {
+		return nil, newValidationError(v)
+	}
+
+	page, err := list(req.Email, req.Cursor, validator.PageLimit(int(req.Limit)))
+	if err != nil {
+		return nil, err
+	}
+
+	return toUserPage(page), nil
+}
+
+func toUserPage(page *entities.UserPage) *userpb.UserPage {
+	return &userpb.UserPage{
+		Users:      userEmails(page.Users),
+		Total:      int32(page.Total),
+		NextCursor: page.NextCursor,
+	}
+}
+
+func userEmails(users []*entities.User) []string {
+	emails := make([]string, len(users))
+	for i, user := range users {
+		emails[i] = user.Email
+	}
+	return emails
+}
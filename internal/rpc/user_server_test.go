@@ -0,0 +1,334 @@
+package rpc
+
+import (
+	"assignment/internal/domain/entities"
+	"assignment/internal/rpc/userpb"
+	"assignment/mocks"
+	"assignment/pkg/errors"
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// newTestClient serves NewServer over an in-memory connection, with the user
+// controller mocked, and returns a client connected to it
+func newTestClient(t *testing.T, ctrl *gomock.Controller, userController *mocks.MockUserControllerInterface) userpb.UserServiceClient {
+	controllers := mocks.NewMockControllers(ctrl)
+	controllers.EXPECT().UserController().Return(userController)
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := NewServer(controllers)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return userpb.NewUserServiceClient(conn)
+}
+
+func TestUserServer_ErrorCodes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name            string
+		friends         []string
+		err             error
+		expectedCode    codes.Code
+		expectedMessage string
+	}{
+		{
+			name:            "validation failure",
+			friends:         []string{"andy@example.com"},
+			expectedCode:    codes.InvalidArgument,
+			expectedMessage: "Validation failed: emails count: exactly 2 emails required",
+		},
+		{
+			name:            "business error",
+			friends:         []string{"andy@example.com", "john@example.com"},
+			err:             errors.New(errors.ErrorTypeBusiness, "Cannot remove yourself as a friend"),
+			expectedCode:    codes.FailedPrecondition,
+			expectedMessage: "Cannot remove yourself as a friend",
+		},
+		{
+			name:            "not found",
+			friends:         []string{"andy@example.com", "john@example.com"},
+			err:             errors.Newf(errors.ErrorTypeNotFound, "User with email '%s' not found", "andy@example.com"),
+			expectedCode:    codes.NotFound,
+			expectedMessage: "User with email 'andy@example.com' not found",
+		},
+		{
+			name:            "conflict with details",
+			friends:         []string{"andy@example.com", "john@example.com"},
+			err:             errors.New(errors.ErrorTypeConflict, "Resource already exists").WithDetails("Friendship is being changed"),
+			expectedCode:    codes.AlreadyExists,
+			expectedMessage: "Resource already exists: Friendship is being changed",
+		},
+		{
+			name:            "forbidden",
+			friends:         []string{"andy@example.com", "john@example.com"},
+			err:             errors.New(errors.ErrorTypeForbidden, "Forbidden"),
+			expectedCode:    codes.PermissionDenied,
+			expectedMessage: "Forbidden",
+		},
+		{
+			name:            "database error",
+			friends:         []string{"andy@example.com", "john@example.com"},
+			err:             errors.Wrap(io.ErrUnexpectedEOF, errors.ErrorTypeDatabase, "Failed to delete friendship"),
+			expectedCode:    codes.Internal,
+			expectedMessage: "Failed to delete friendship",
+		},
+		{
+			name:            "plain error",
+			friends:         []string{"andy@example.com", "john@example.com"},
+			err:             io.ErrUnexpectedEOF,
+			expectedCode:    codes.Internal,
+			expectedMessage: "Internal server error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := mocks.NewMockUserControllerInterface(ctrl)
+			if tt.err != nil {
+				mockController.EXPECT().DeleteFriendship(tt.friends[0], tt.friends[1]).Return(tt.err)
+			}
+
+			client := newTestClient(t, ctrl, mockController)
+			_, err := client.DeleteFriendship(context.Background(), &userpb.FriendsRequest{Friends: tt.friends})
+
+			st, ok := status.FromError(err)
+			assert.True(t, ok)
+			assert.Equal(t, tt.expectedCode, st.Code())
+			assert.Equal(t, tt.expectedMessage, st.Message())
+		})
+	}
+}
+
+func TestUserServer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	andy := &entities.User{ID: 1, Email: "andy@example.com"}
+	john := &entities.User{ID: 2, Email: "john@example.com"}
+	lisa := &entities.User{ID: 3, Email: "lisa@example.com"}
+	createdAt := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	expiresAt := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		call      func(client userpb.UserServiceClient) (proto.Message, error)
+		setupMock func(mockController *mocks.MockUserControllerInterface)
+		expected  proto.Message
+	}{
+		{
+			name: "deprecated create friendship sends a friend request",
+			call: func(client userpb.UserServiceClient) (proto.Message, error) {
+				return client.CreateFriendship(context.Background(), &userpb.FriendsRequest{Friends: []string{"andy@example.com", "john@example.com"}})
+			},
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().SendFriendRequest("andy@example.com", "john@example.com").Return(&entities.FriendRequest{
+					ID: 1, Requester: andy, Addressee: john, Status: entities.FriendRequestPending, CreatedAt: createdAt,
+				}, nil)
+			},
+			expected: &emptypb.Empty{},
+		},
+		{
+			name: "delete friendship",
+			call: func(client userpb.UserServiceClient) (proto.Message, error) {
+				return client.DeleteFriendship(context.Background(), &userpb.FriendsRequest{Friends: []string{"andy@example.com", "john@example.com"}})
+			},
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().DeleteFriendship("andy@example.com", "john@example.com").Return(nil)
+			},
+			expected: &emptypb.Empty{},
+		},
+		{
+			name: "friend list uses the default page size",
+			call: func(client userpb.UserServiceClient) (proto.Message, error) {
+				return client.GetFriendList(context.Background(), &userpb.UserListRequest{Email: "andy@example.com"})
+			},
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetFriendList("andy@example.com", "", 50).Return(&entities.UserPage{Users: []*entities.User{john, lisa}, Total: 2}, nil)
+			},
+			expected: &userpb.UserPage{Users: []string{"john@example.com", "lisa@example.com"}, Total: 2},
+		},
+		{
+			name: "common friends page",
+			call: func(client userpb.UserServiceClient) (proto.Message, error) {
+				return client.GetCommonFriends(context.Background(), &userpb.CommonFriendsRequest{Friends: []string{"andy@example.com", "john@example.com"}, Cursor: "abc", Limit: 1})
+			},
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetCommonFriends([]string{"andy@example.com", "john@example.com"}, "abc", 1).Return(&entities.UserPage{Users: []*entities.User{lisa}, Total: 3, NextCursor: "def"}, nil)
+			},
+			expected: &userpb.UserPage{Users: []string{"lisa@example.com"}, Total: 3, NextCursor: "def"},
+		},
+		{
+			name: "subscriber counts",
+			call: func(client userpb.UserServiceClient) (proto.Message, error) {
+				return client.CountSubscribers(context.Background(), &userpb.EmailsRequest{Emails: []string{"andy@example.com", "john@example.com"}})
+			},
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().CountSubscribers([]string{"andy@example.com", "john@example.com"}).Return(map[string]int{"andy@example.com": 2, "john@example.com": 0}, nil)
+			},
+			expected: &userpb.SubscriberCounts{Counts: map[string]int32{"andy@example.com": 2, "john@example.com": 0}},
+		},
+		{
+			name: "send friend request",
+			call: func(client userpb.UserServiceClient) (proto.Message, error) {
+				return client.SendFriendRequest(context.Background(), &userpb.UserPairRequest{Requestor: "andy@example.com", Target: "john@example.com"})
+			},
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().SendFriendRequest("andy@example.com", "john@example.com").Return(&entities.FriendRequest{Requester: andy, Addressee: john, Status: entities.FriendRequestPending, CreatedAt: createdAt}, nil)
+			},
+			expected: &userpb.FriendRequest{Requestor: "andy@example.com", Target: "john@example.com", Status: "pending", CreatedAt: timestamppb.New(createdAt)},
+		},
+		{
+			name: "mute with expiry",
+			call: func(client userpb.UserServiceClient) (proto.Message, error) {
+				return client.CreateMute(context.Background(), &userpb.CreateMuteRequest{Requestor: "andy@example.com", Target: "john@example.com", ExpiresAt: timestamppb.New(expiresAt)})
+			},
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().CreateMute("andy@example.com", "john@example.com", &expiresAt).Return(&entities.Mute{Muter: andy, Muted: john, ExpiresAt: &expiresAt, CreatedAt: createdAt}, nil)
+			},
+			expected: &userpb.Mute{Requestor: "andy@example.com", Target: "john@example.com", ExpiresAt: timestamppb.New(expiresAt), CreatedAt: timestamppb.New(createdAt)},
+		},
+		{
+			name: "relationship",
+			call: func(client userpb.UserServiceClient) (proto.Message, error) {
+				return client.GetRelationship(context.Background(), &userpb.UserPairRequest{Requestor: "andy@example.com", Target: "john@example.com"})
+			},
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetRelationship("andy@example.com", "john@example.com").Return(&entities.Relationship{AreFriends: true, BSubscribesToA: true}, nil)
+			},
+			expected: &userpb.Relationship{Friends: true, TargetSubscribesRequestor: true},
+		},
+		{
+			name: "explain recipients",
+			call: func(client userpb.UserServiceClient) (proto.Message, error) {
+				return client.ExplainRecipients(context.Background(), &userpb.RecipientsRequest{Sender: "andy@example.com", Text: "Hello @ghost"})
+			},
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().ExplainRecipients("andy@example.com", "Hello @ghost", "", 50).Return(&entities.RecipientExplanation{
+					RecipientPage: entities.RecipientPage{
+						Recipients: []*entities.Recipient{{User: john, Reasons: []entities.RecipientReason{entities.RecipientReasonFriend, entities.RecipientReasonSubscriber}}},
+						Total:      1,
+					},
+					DroppedMentions: []*entities.DroppedMention{{Handle: "ghost", Reason: entities.DroppedMentionUnknown}},
+				}, nil)
+			},
+			expected: &userpb.RecipientExplanation{
+				Recipients:      []*userpb.Recipient{{Email: "john@example.com", Reasons: []string{"friend", "subscriber"}}},
+				DroppedMentions: []*userpb.DroppedMention{{Handle: "ghost", Reason: "unknown"}},
+				Total:           1,
+			},
+		},
+		{
+			name: "update settings",
+			call: func(client userpb.UserServiceClient) (proto.Message, error) {
+				return client.UpdateSettings(context.Background(), &userpb.Settings{Email: "andy@example.com", MentionPolicy: "friends"})
+			},
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().UpdateSettings("andy@example.com", entities.MentionPolicyFriends).Return(&entities.UserSettings{User: andy, MentionPolicy: entities.MentionPolicyFriends}, nil)
+			},
+			expected: &userpb.Settings{Email: "andy@example.com", MentionPolicy: "friends"},
+		},
+		{
+			name: "list users",
+			call: func(client userpb.UserServiceClient) (proto.Message, error) {
+				return client.GetUsers(context.Background(), &emptypb.Empty{})
+			},
+			setupMock: func(mockController *mocks.MockUserControllerInterface) {
+				mockController.EXPECT().GetUsers().Return([]*entities.User{andy, john}, nil)
+			},
+			expected: &userpb.UserList{Users: []string{"andy@example.com", "john@example.com"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockController := mocks.NewMockUserControllerInterface(ctrl)
+			tt.setupMock(mockController)
+
+			client := newTestClient(t, ctrl, mockController)
+			response, err := tt.call(client)
+
+			assert.NoError(t, err)
+			assert.True(t, proto.Equal(tt.expected, response), "expected %v, got %v", tt.expected, response)
+		})
+	}
+}
+
+func TestUserServer_SubscribeEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	andy := &entities.User{ID: 1, Email: "andy@example.com"}
+	john := &entities.User{ID: 2, Email: "john@example.com"}
+	createdAt := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	events := make(chan *entities.UserEvent, 1)
+	closed := make(chan struct{})
+
+	mockController := mocks.NewMockUserControllerInterface(ctrl)
+	mockController.EXPECT().SubscribeEvents("andy@example.com").Return(&entities.UserEventStream{
+		Events: events,
+		Close:  func() { close(closed) },
+	}, nil)
+
+	client := newTestClient(t, ctrl, mockController)
+
+	stream, err := client.SubscribeEvents(context.Background(), &userpb.EmailRequest{Email: "andy@example.com"})
+	assert.NoError(t, err)
+
+	events <- &entities.UserEvent{Type: entities.UserEventFriendAdded, Actor: john, Target: andy, CreatedAt: createdAt}
+	event, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(&userpb.UserEvent{Type: "friend.added", Actor: "john@example.com", Target: "andy@example.com", CreatedAt: timestamppb.New(createdAt)}, event))
+
+	// The hub closes the channel on shutdown
+	close(events)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("event stream was not closed")
+	}
+}
+
+func TestUserServer_SubscribeEventsValidation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := newTestClient(t, ctrl, mocks.NewMockUserControllerInterface(ctrl))
+
+	stream, err := client.SubscribeEvents(context.Background(), &userpb.EmailRequest{Email: "not-an-email"})
+	assert.NoError(t, err)
+
+	_, err = stream.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,1553 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: user/v1/user.proto
+
+package userpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	UserService_CreateFriendship_FullMethodName          = "/user.v1.UserService/CreateFriendship"
+	UserService_DeleteFriendship_FullMethodName          = "/user.v1.UserService/DeleteFriendship"
+	UserService_SendFriendRequest_FullMethodName         = "/user.v1.UserService/SendFriendRequest"
+	UserService_GetIncomingFriendRequests_FullMethodName = "/user.v1.UserService/GetIncomingFriendRequests"
+	UserService_GetOutgoingFriendRequests_FullMethodName = "/user.v1.UserService/GetOutgoingFriendRequests"
+	UserService_AcceptFriendRequest_FullMethodName       = "/user.v1.UserService/AcceptFriendRequest"
+	UserService_RejectFriendRequest_FullMethodName       = "/user.v1.UserService/RejectFriendRequest"
+	UserService_CancelFriendRequest_FullMethodName       = "/user.v1.UserService/CancelFriendRequest"
+	UserService_GetFriendList_FullMethodName             = "/user.v1.UserService/GetFriendList"
+	UserService_GetCommonFriends_FullMethodName          = "/user.v1.UserService/GetCommonFriends"
+	UserService_GetFriendshipPath_FullMethodName         = "/user.v1.UserService/GetFriendshipPath"
+	UserService_GetFriendSuggestions_FullMethodName      = "/user.v1.UserService/GetFriendSuggestions"
+	UserService_CreateSubscription_FullMethodName        = "/user.v1.UserService/CreateSubscription"
+	UserService_DeleteSubscription_FullMethodName        = "/user.v1.UserService/DeleteSubscription"
+	UserService_GetSubscribers_FullMethodName            = "/user.v1.UserService/GetSubscribers"
+	UserService_GetSubscriptions_FullMethodName          = "/user.v1.UserService/GetSubscriptions"
+	UserService_CountSubscribers_FullMethodName          = "/user.v1.UserService/CountSubscribers"
+	UserService_GetSubscriptionFilter_FullMethodName     = "/user.v1.UserService/GetSubscriptionFilter"
+	UserService_UpdateSubscriptionFilter_FullMethodName  = "/user.v1.UserService/UpdateSubscriptionFilter"
+	UserService_CreateBlock_FullMethodName               = "/user.v1.UserService/CreateBlock"
+	UserService_DeleteBlock_FullMethodName               = "/user.v1.UserService/DeleteBlock"
+	UserService_GetBlockedUsers_FullMethodName           = "/user.v1.UserService/GetBlockedUsers"
+	UserService_GetBlockers_FullMethodName               = "/user.v1.UserService/GetBlockers"
+	UserService_CreateMute_FullMethodName                = "/user.v1.UserService/CreateMute"
+	UserService_DeleteMute_FullMethodName                = "/user.v1.UserService/DeleteMute"
+	UserService_GetMutes_FullMethodName                  = "/user.v1.UserService/GetMutes"
+	UserService_GetRelationship_FullMethodName           = "/user.v1.UserService/GetRelationship"
+	UserService_SubscribeEvents_FullMethodName           = "/user.v1.UserService/SubscribeEvents"
+	UserService_GetRecipients_FullMethodName             = "/user.v1.UserService/GetRecipients"
+	UserService_ExplainRecipients_FullMethodName         = "/user.v1.UserService/ExplainRecipients"
+	UserService_CreateUser_FullMethodName                = "/user.v1.UserService/CreateUser"
+	UserService_GetUser_FullMethodName                   = "/user.v1.UserService/GetUser"
+	UserService_GetUsersByEmails_FullMethodName          = "/user.v1.UserService/GetUsersByEmails"
+	UserService_SetUsername_FullMethodName               = "/user.v1.UserService/SetUsername"
+	UserService_GetSettings_FullMethodName               = "/user.v1.UserService/GetSettings"
+	UserService_UpdateSettings_FullMethodName            = "/user.v1.UserService/UpdateSettings"
+	UserService_GetUsers_FullMethodName                  = "/user.v1.UserService/GetUsers"
+	UserService_DeleteUser_FullMethodName                = "/user.v1.UserService/DeleteUser"
+)
+
+// UserServiceClient is the client API for UserService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// UserService mirrors UserControllerInterface for other backend services.
+// Users are identified by email, as in the HTTP API, and errors carry the
+// status code mapped from the controller's error type
+type UserServiceClient interface {
+	// Deprecated: Do not use.
+	// CreateFriendship is deprecated: friendships need the target's consent, so
+	// it sends a friend request from the first user to the second, as
+	// SendFriendRequest does
+	CreateFriendship(ctx context.Context, in *FriendsRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	DeleteFriendship(ctx context.Context, in *FriendsRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	SendFriendRequest(ctx context.Context, in *UserPairRequest, opts ...grpc.CallOption) (*FriendRequest, error)
+	GetIncomingFriendRequests(ctx context.Context, in *EmailRequest, opts ...grpc.CallOption) (*FriendRequestList, error)
+	GetOutgoingFriendRequests(ctx context.Context, in *EmailRequest, opts ...grpc.CallOption) (*FriendRequestList, error)
+	AcceptFriendRequest(ctx context.Context, in *UserPairRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RejectFriendRequest(ctx context.Context, in *UserPairRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	CancelFriendRequest(ctx context.Context, in *UserPairRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetFriendList(ctx context.Context, in *UserListRequest, opts ...grpc.CallOption) (*UserPage, error)
+	GetCommonFriends(ctx context.Context, in *CommonFriendsRequest, opts ...grpc.CallOption) (*UserPage, error)
+	GetFriendshipPath(ctx context.Context, in *FriendshipPathRequest, opts ...grpc.CallOption) (*FriendshipPath, error)
+	GetFriendSuggestions(ctx context.Context, in *FriendSuggestionsRequest, opts ...grpc.CallOption) (*FriendSuggestionList, error)
+	CreateSubscription(ctx context.Context, in *UserPairRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	DeleteSubscription(ctx context.Context, in *UserPairRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetSubscribers(ctx context.Context, in *UserListRequest, opts ...grpc.CallOption) (*UserPage, error)
+	GetSubscriptions(ctx context.Context, in *UserListRequest, opts ...grpc.CallOption) (*UserPage, error)
+	CountSubscribers(ctx context.Context, in *EmailsRequest, opts ...grpc.CallOption) (*SubscriberCounts, error)
+	GetSubscriptionFilter(ctx context.Context, in *UserPairRequest, opts ...grpc.CallOption) (*SubscriptionFilter, error)
+	UpdateSubscriptionFilter(ctx context.Context, in *SubscriptionFilter, opts ...grpc.CallOption) (*SubscriptionFilter, error)
+	CreateBlock(ctx context.Context, in *UserPairRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	DeleteBlock(ctx context.Context, in *DeleteBlockRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetBlockedUsers(ctx context.Context, in *UserListRequest, opts ...grpc.CallOption) (*UserPage, error)
+	GetBlockers(ctx context.Context, in *UserListRequest, opts ...grpc.CallOption) (*UserPage, error)
+	CreateMute(ctx context.Context, in *CreateMuteRequest, opts ...grpc.CallOption) (*Mute, error)
+	DeleteMute(ctx context.Context, in *UserPairRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetMutes(ctx context.Context, in *EmailRequest, opts ...grpc.CallOption) (*MuteList, error)
+	GetRelationship(ctx context.Context, in *UserPairRequest, opts ...grpc.CallOption) (*Relationship, error)
+	// SubscribeEvents streams the relationship and update events that concern
+	// the user until the client cancels or the server shuts down
+	SubscribeEvents(ctx context.Context, in *EmailRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[UserEvent], error)
+	GetRecipients(ctx context.Context, in *RecipientsRequest, opts ...grpc.CallOption) (*UserPage, error)
+	ExplainRecipients(ctx context.Context, in *RecipientsRequest, opts ...grpc.CallOption) (*RecipientExplanation, error)
+	CreateUser(ctx context.Context, in *EmailRequest, opts ...grpc.CallOption) (*User, error)
+	GetUser(ctx context.Context, in *EmailRequest, opts ...grpc.CallOption) (*User, error)
+	GetUsersByEmails(ctx context.Context, in *EmailsRequest, opts ...grpc.CallOption) (*UserList, error)
+	SetUsername(ctx context.Context, in *SetUsernameRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetSettings(ctx context.Context, in *EmailRequest, opts ...grpc.CallOption) (*Settings, error)
+	UpdateSettings(ctx context.Context, in *Settings, opts ...grpc.CallOption) (*Settings, error)
+	GetUsers(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*UserList, error)
+	DeleteUser(ctx context.Context, in *EmailRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type userServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUserServiceClient(cc grpc.ClientConnInterface) UserServiceClient {
+	return &userServiceClient{cc}
+}
+
+// Deprecated: Do not use.
+func (c *userServiceClient) CreateFriendship(ctx context.Context, in *FriendsRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, UserService_CreateFriendship_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) DeleteFriendship(ctx context.Context, in *FriendsRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, UserService_DeleteFriendship_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) SendFriendRequest(ctx context.Context, in *UserPairRequest, opts ...grpc.CallOption) (*FriendRequest, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FriendRequest)
+	err := c.cc.Invoke(ctx, UserService_SendFriendRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetIncomingFriendRequests(ctx context.Context, in *EmailRequest, opts ...grpc.CallOption) (*FriendRequestList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FriendRequestList)
+	err := c.cc.Invoke(ctx, UserService_GetIncomingFriendRequests_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetOutgoingFriendRequests(ctx context.Context, in *EmailRequest, opts ...grpc.CallOption) (*FriendRequestList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FriendRequestList)
+	err := c.cc.Invoke(ctx, UserService_GetOutgoingFriendRequests_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) AcceptFriendRequest(ctx context.Context, in *UserPairRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, UserService_AcceptFriendRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RejectFriendRequest(ctx context.Context, in *UserPairRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, UserService_RejectFriendRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) CancelFriendRequest(ctx context.Context, in *UserPairRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, UserService_CancelFriendRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetFriendList(ctx context.Context, in *UserListRequest, opts ...grpc.CallOption) (*UserPage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserPage)
+	err := c.cc.Invoke(ctx, UserService_GetFriendList_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetCommonFriends(ctx context.Context, in *CommonFriendsRequest, opts ...grpc.CallOption) (*UserPage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserPage)
+	err := c.cc.Invoke(ctx, UserService_GetCommonFriends_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetFriendshipPath(ctx context.Context, in *FriendshipPathRequest, opts ...grpc.CallOption) (*FriendshipPath, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FriendshipPath)
+	err := c.cc.Invoke(ctx, UserService_GetFriendshipPath_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetFriendSuggestions(ctx context.Context, in *FriendSuggestionsRequest, opts ...grpc.CallOption) (*FriendSuggestionList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FriendSuggestionList)
+	err := c.cc.Invoke(ctx, UserService_GetFriendSuggestions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) CreateSubscription(ctx context.Context, in *UserPairRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, UserService_CreateSubscription_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) DeleteSubscription(ctx context.Context, in *UserPairRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, UserService_DeleteSubscription_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetSubscribers(ctx context.Context, in *UserListRequest, opts ...grpc.CallOption) (*UserPage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserPage)
+	err := c.cc.Invoke(ctx, UserService_GetSubscribers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetSubscriptions(ctx context.Context, in *UserListRequest, opts ...grpc.CallOption) (*UserPage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserPage)
+	err := c.cc.Invoke(ctx, UserService_GetSubscriptions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) CountSubscribers(ctx context.Context, in *EmailsRequest, opts ...grpc.CallOption) (*SubscriberCounts, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubscriberCounts)
+	err := c.cc.Invoke(ctx, UserService_CountSubscribers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetSubscriptionFilter(ctx context.Context, in *UserPairRequest, opts ...grpc.CallOption) (*SubscriptionFilter, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubscriptionFilter)
+	err := c.cc.Invoke(ctx, UserService_GetSubscriptionFilter_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) UpdateSubscriptionFilter(ctx context.Context, in *SubscriptionFilter, opts ...grpc.CallOption) (*SubscriptionFilter, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubscriptionFilter)
+	err := c.cc.Invoke(ctx, UserService_UpdateSubscriptionFilter_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) CreateBlock(ctx context.Context, in *UserPairRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, UserService_CreateBlock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) DeleteBlock(ctx context.Context, in *DeleteBlockRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, UserService_DeleteBlock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetBlockedUsers(ctx context.Context, in *UserListRequest, opts ...grpc.CallOption) (*UserPage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserPage)
+	err := c.cc.Invoke(ctx, UserService_GetBlockedUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetBlockers(ctx context.Context, in *UserListRequest, opts ...grpc.CallOption) (*UserPage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserPage)
+	err := c.cc.Invoke(ctx, UserService_GetBlockers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) CreateMute(ctx context.Context, in *CreateMuteRequest, opts ...grpc.CallOption) (*Mute, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Mute)
+	err := c.cc.Invoke(ctx, UserService_CreateMute_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) DeleteMute(ctx context.Context, in *UserPairRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, UserService_DeleteMute_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetMutes(ctx context.Context, in *EmailRequest, opts ...grpc.CallOption) (*MuteList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MuteList)
+	err := c.cc.Invoke(ctx, UserService_GetMutes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetRelationship(ctx context.Context, in *UserPairRequest, opts ...grpc.CallOption) (*Relationship, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Relationship)
+	err := c.cc.Invoke(ctx, UserService_GetRelationship_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) SubscribeEvents(ctx context.Context, in *EmailRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[UserEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &UserService_ServiceDesc.Streams[0], UserService_SubscribeEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[EmailRequest, UserEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UserService_SubscribeEventsClient = grpc.ServerStreamingClient[UserEvent]
+
+func (c *userServiceClient) GetRecipients(ctx context.Context, in *RecipientsRequest, opts ...grpc.CallOption) (*UserPage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserPage)
+	err := c.cc.Invoke(ctx, UserService_GetRecipients_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ExplainRecipients(ctx context.Context, in *RecipientsRequest, opts ...grpc.CallOption) (*RecipientExplanation, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RecipientExplanation)
+	err := c.cc.Invoke(ctx, UserService_ExplainRecipients_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) CreateUser(ctx context.Context, in *EmailRequest, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, UserService_CreateUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetUser(ctx context.Context, in *EmailRequest, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, UserService_GetUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetUsersByEmails(ctx context.Context, in *EmailsRequest, opts ...grpc.CallOption) (*UserList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserList)
+	err := c.cc.Invoke(ctx, UserService_GetUsersByEmails_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) SetUsername(ctx context.Context, in *SetUsernameRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, UserService_SetUsername_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetSettings(ctx context.Context, in *EmailRequest, opts ...grpc.CallOption) (*Settings, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Settings)
+	err := c.cc.Invoke(ctx, UserService_GetSettings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) UpdateSettings(ctx context.Context, in *Settings, opts ...grpc.CallOption) (*Settings, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Settings)
+	err := c.cc.Invoke(ctx, UserService_UpdateSettings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetUsers(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*UserList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserList)
+	err := c.cc.Invoke(ctx, UserService_GetUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) DeleteUser(ctx context.Context, in *EmailRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, UserService_DeleteUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UserServiceServer is the server API for UserService service.
+// All implementations must embed UnimplementedUserServiceServer
+// for forward compatibility.
+//
+// UserService mirrors UserControllerInterface for other backend services.
+// Users are identified by email, as in the HTTP API, and errors carry the
+// status code mapped from the controller's error type
+type UserServiceServer interface {
+	// Deprecated: Do not use.
+	// CreateFriendship is deprecated: friendships need the target's consent, so
+	// it sends a friend request from the first user to the second, as
+	// SendFriendRequest does
+	CreateFriendship(context.Context, *FriendsRequest) (*emptypb.Empty, error)
+	DeleteFriendship(context.Context, *FriendsRequest) (*emptypb.Empty, error)
+	SendFriendRequest(context.Context, *UserPairRequest) (*FriendRequest, error)
+	GetIncomingFriendRequests(context.Context, *EmailRequest) (*FriendRequestList, error)
+	GetOutgoingFriendRequests(context.Context, *EmailRequest) (*FriendRequestList, error)
+	AcceptFriendRequest(context.Context, *UserPairRequest) (*emptypb.Empty, error)
+	RejectFriendRequest(context.Context, *UserPairRequest) (*emptypb.Empty, error)
+	CancelFriendRequest(context.Context, *UserPairRequest) (*emptypb.Empty, error)
+	GetFriendList(context.Context, *UserListRequest) (*UserPage, error)
+	GetCommonFriends(context.Context, *CommonFriendsRequest) (*UserPage, error)
+	GetFriendshipPath(context.Context, *FriendshipPathRequest) (*FriendshipPath, error)
+	GetFriendSuggestions(context.Context, *FriendSuggestionsRequest) (*FriendSuggestionList, error)
+	CreateSubscription(context.Context, *UserPairRequest) (*emptypb.Empty, error)
+	DeleteSubscription(context.Context, *UserPairRequest) (*emptypb.Empty, error)
+	GetSubscribers(context.Context, *UserListRequest) (*UserPage, error)
+	GetSubscriptions(context.Context, *UserListRequest) (*UserPage, error)
+	CountSubscribers(context.Context, *EmailsRequest) (*SubscriberCounts, error)
+	GetSubscriptionFilter(context.Context, *UserPairRequest) (*SubscriptionFilter, error)
+	UpdateSubscriptionFilter(context.Context, *SubscriptionFilter) (*SubscriptionFilter, error)
+	CreateBlock(context.Context, *UserPairRequest) (*emptypb.Empty, error)
+	DeleteBlock(context.Context, *DeleteBlockRequest) (*emptypb.Empty, error)
+	GetBlockedUsers(context.Context, *UserListRequest) (*UserPage, error)
+	GetBlockers(context.Context, *UserListRequest) (*UserPage, error)
+	CreateMute(context.Context, *CreateMuteRequest) (*Mute, error)
+	DeleteMute(context.Context, *UserPairRequest) (*emptypb.Empty, error)
+	GetMutes(context.Context, *EmailRequest) (*MuteList, error)
+	GetRelationship(context.Context, *UserPairRequest) (*Relationship, error)
+	// SubscribeEvents streams the relationship and update events that concern
+	// the user until the client cancels or the server shuts down
+	SubscribeEvents(*EmailRequest, grpc.ServerStreamingServer[UserEvent]) error
+	GetRecipients(context.Context, *RecipientsRequest) (*UserPage, error)
+	ExplainRecipients(context.Context, *RecipientsRequest) (*RecipientExplanation, error)
+	CreateUser(context.Context, *EmailRequest) (*User, error)
+	GetUser(context.Context, *EmailRequest) (*User, error)
+	GetUsersByEmails(context.Context, *EmailsRequest) (*UserList, error)
+	SetUsername(context.Context, *SetUsernameRequest) (*emptypb.Empty, error)
+	GetSettings(context.Context, *EmailRequest) (*Settings, error)
+	UpdateSettings(context.Context, *Settings) (*Settings, error)
+	GetUsers(context.Context, *emptypb.Empty) (*UserList, error)
+	DeleteUser(context.Context, *EmailRequest) (*emptypb.Empty, error)
+	mustEmbedUnimplementedUserServiceServer()
+}
+
+// UnimplementedUserServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedUserServiceServer struct{}
+
+func (UnimplementedUserServiceServer) CreateFriendship(context.Context, *FriendsRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateFriendship not implemented")
+}
+func (UnimplementedUserServiceServer) DeleteFriendship(context.Context, *FriendsRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteFriendship not implemented")
+}
+func (UnimplementedUserServiceServer) SendFriendRequest(context.Context, *UserPairRequest) (*FriendRequest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendFriendRequest not implemented")
+}
+func (UnimplementedUserServiceServer) GetIncomingFriendRequests(context.Context, *EmailRequest) (*FriendRequestList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIncomingFriendRequests not implemented")
+}
+func (UnimplementedUserServiceServer) GetOutgoingFriendRequests(context.Context, *EmailRequest) (*FriendRequestList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOutgoingFriendRequests not implemented")
+}
+func (UnimplementedUserServiceServer) AcceptFriendRequest(context.Context, *UserPairRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcceptFriendRequest not implemented")
+}
+func (UnimplementedUserServiceServer) RejectFriendRequest(context.Context, *UserPairRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RejectFriendRequest not implemented")
+}
+func (UnimplementedUserServiceServer) CancelFriendRequest(context.Context, *UserPairRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelFriendRequest not implemented")
+}
+func (UnimplementedUserServiceServer) GetFriendList(context.Context, *UserListRequest) (*UserPage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFriendList not implemented")
+}
+func (UnimplementedUserServiceServer) GetCommonFriends(context.Context, *CommonFriendsRequest) (*UserPage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCommonFriends not implemented")
+}
+func (UnimplementedUserServiceServer) GetFriendshipPath(context.Context, *FriendshipPathRequest) (*FriendshipPath, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFriendshipPath not implemented")
+}
+func (UnimplementedUserServiceServer) GetFriendSuggestions(context.Context, *FriendSuggestionsRequest) (*FriendSuggestionList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFriendSuggestions not implemented")
+}
+func (UnimplementedUserServiceServer) CreateSubscription(context.Context, *UserPairRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSubscription not implemented")
+}
+func (UnimplementedUserServiceServer) DeleteSubscription(context.Context, *UserPairRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSubscription not implemented")
+}
+func (UnimplementedUserServiceServer) GetSubscribers(context.Context, *UserListRequest) (*UserPage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSubscribers not implemented")
+}
+func (UnimplementedUserServiceServer) GetSubscriptions(context.Context, *UserListRequest) (*UserPage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSubscriptions not implemented")
+}
+func (UnimplementedUserServiceServer) CountSubscribers(context.Context, *EmailsRequest) (*SubscriberCounts, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CountSubscribers not implemented")
+}
+func (UnimplementedUserServiceServer) GetSubscriptionFilter(context.Context, *UserPairRequest) (*SubscriptionFilter, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSubscriptionFilter not implemented")
+}
+func (UnimplementedUserServiceServer) UpdateSubscriptionFilter(context.Context, *SubscriptionFilter) (*SubscriptionFilter, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateSubscriptionFilter not implemented")
+}
+func (UnimplementedUserServiceServer) CreateBlock(context.Context, *UserPairRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateBlock not implemented")
+}
+func (UnimplementedUserServiceServer) DeleteBlock(context.Context, *DeleteBlockRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteBlock not implemented")
+}
+func (UnimplementedUserServiceServer) GetBlockedUsers(context.Context, *UserListRequest) (*UserPage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlockedUsers not implemented")
+}
+func (UnimplementedUserServiceServer) GetBlockers(context.Context, *UserListRequest) (*UserPage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlockers not implemented")
+}
+func (UnimplementedUserServiceServer) CreateMute(context.Context, *CreateMuteRequest) (*Mute, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateMute not implemented")
+}
+func (UnimplementedUserServiceServer) DeleteMute(context.Context, *UserPairRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteMute not implemented")
+}
+func (UnimplementedUserServiceServer) GetMutes(context.Context, *EmailRequest) (*MuteList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMutes not implemented")
+}
+func (UnimplementedUserServiceServer) GetRelationship(context.Context, *UserPairRequest) (*Relationship, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRelationship not implemented")
+}
+func (UnimplementedUserServiceServer) SubscribeEvents(*EmailRequest, grpc.ServerStreamingServer[UserEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeEvents not implemented")
+}
+func (UnimplementedUserServiceServer) GetRecipients(context.Context, *RecipientsRequest) (*UserPage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRecipients not implemented")
+}
+func (UnimplementedUserServiceServer) ExplainRecipients(context.Context, *RecipientsRequest) (*RecipientExplanation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExplainRecipients not implemented")
+}
+func (UnimplementedUserServiceServer) CreateUser(context.Context, *EmailRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUser not implemented")
+}
+func (UnimplementedUserServiceServer) GetUser(context.Context, *EmailRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUser not implemented")
+}
+func (UnimplementedUserServiceServer) GetUsersByEmails(context.Context, *EmailsRequest) (*UserList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUsersByEmails not implemented")
+}
+func (UnimplementedUserServiceServer) SetUsername(context.Context, *SetUsernameRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetUsername not implemented")
+}
+func (UnimplementedUserServiceServer) GetSettings(context.Context, *EmailRequest) (*Settings, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSettings not implemented")
+}
+func (UnimplementedUserServiceServer) UpdateSettings(context.Context, *Settings) (*Settings, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateSettings not implemented")
+}
+func (UnimplementedUserServiceServer) GetUsers(context.Context, *emptypb.Empty) (*UserList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUsers not implemented")
+}
+func (UnimplementedUserServiceServer) DeleteUser(context.Context, *EmailRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUser not implemented")
+}
+func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
+func (UnimplementedUserServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeUserServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UserServiceServer will
+// result in compilation errors.
+type UnsafeUserServiceServer interface {
+	mustEmbedUnimplementedUserServiceServer()
+}
+
+func RegisterUserServiceServer(s grpc.ServiceRegistrar, srv UserServiceServer) {
+	// If the following call pancis, it indicates UnimplementedUserServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&UserService_ServiceDesc, srv)
+}
+
+func _UserService_CreateFriendship_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FriendsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).CreateFriendship(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_CreateFriendship_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).CreateFriendship(ctx, req.(*FriendsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_DeleteFriendship_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FriendsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).DeleteFriendship(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_DeleteFriendship_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).DeleteFriendship(ctx, req.(*FriendsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_SendFriendRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserPairRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).SendFriendRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_SendFriendRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).SendFriendRequest(ctx, req.(*UserPairRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetIncomingFriendRequests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetIncomingFriendRequests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetIncomingFriendRequests_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetIncomingFriendRequests(ctx, req.(*EmailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetOutgoingFriendRequests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetOutgoingFriendRequests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetOutgoingFriendRequests_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetOutgoingFriendRequests(ctx, req.(*EmailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_AcceptFriendRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserPairRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).AcceptFriendRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_AcceptFriendRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).AcceptFriendRequest(ctx, req.(*UserPairRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_RejectFriendRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserPairRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RejectFriendRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_RejectFriendRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RejectFriendRequest(ctx, req.(*UserPairRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_CancelFriendRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserPairRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).CancelFriendRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_CancelFriendRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).CancelFriendRequest(ctx, req.(*UserPairRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetFriendList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetFriendList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetFriendList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetFriendList(ctx, req.(*UserListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetCommonFriends_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommonFriendsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetCommonFriends(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetCommonFriends_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetCommonFriends(ctx, req.(*CommonFriendsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetFriendshipPath_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FriendshipPathRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetFriendshipPath(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetFriendshipPath_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetFriendshipPath(ctx, req.(*FriendshipPathRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetFriendSuggestions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FriendSuggestionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetFriendSuggestions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetFriendSuggestions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetFriendSuggestions(ctx, req.(*FriendSuggestionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_CreateSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserPairRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).CreateSubscription(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_CreateSubscription_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).CreateSubscription(ctx, req.(*UserPairRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_DeleteSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserPairRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).DeleteSubscription(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_DeleteSubscription_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).DeleteSubscription(ctx, req.(*UserPairRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetSubscribers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetSubscribers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetSubscribers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetSubscribers(ctx, req.(*UserListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetSubscriptions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetSubscriptions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetSubscriptions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetSubscriptions(ctx, req.(*UserListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_CountSubscribers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmailsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).CountSubscribers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_CountSubscribers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).CountSubscribers(ctx, req.(*EmailsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetSubscriptionFilter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserPairRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetSubscriptionFilter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetSubscriptionFilter_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetSubscriptionFilter(ctx, req.(*UserPairRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_UpdateSubscriptionFilter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubscriptionFilter)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).UpdateSubscriptionFilter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_UpdateSubscriptionFilter_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).UpdateSubscriptionFilter(ctx, req.(*SubscriptionFilter))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_CreateBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserPairRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).CreateBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_CreateBlock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).CreateBlock(ctx, req.(*UserPairRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_DeleteBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).DeleteBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_DeleteBlock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).DeleteBlock(ctx, req.(*DeleteBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetBlockedUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetBlockedUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetBlockedUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetBlockedUsers(ctx, req.(*UserListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetBlockers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetBlockers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetBlockers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetBlockers(ctx, req.(*UserListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_CreateMute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateMuteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).CreateMute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_CreateMute_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).CreateMute(ctx, req.(*CreateMuteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_DeleteMute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserPairRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).DeleteMute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_DeleteMute_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).DeleteMute(ctx, req.(*UserPairRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetMutes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetMutes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetMutes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetMutes(ctx, req.(*EmailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetRelationship_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserPairRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetRelationship(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetRelationship_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetRelationship(ctx, req.(*UserPairRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_SubscribeEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EmailRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(UserServiceServer).SubscribeEvents(m, &grpc.GenericServerStream[EmailRequest, UserEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UserService_SubscribeEventsServer = grpc.ServerStreamingServer[UserEvent]
+
+func _UserService_GetRecipients_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecipientsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetRecipients(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetRecipients_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetRecipients(ctx, req.(*RecipientsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ExplainRecipients_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecipientsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ExplainRecipients(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_ExplainRecipients_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ExplainRecipients(ctx, req.(*RecipientsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).CreateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_CreateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).CreateUser(ctx, req.(*EmailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetUser(ctx, req.(*EmailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetUsersByEmails_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmailsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetUsersByEmails(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetUsersByEmails_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetUsersByEmails(ctx, req.(*EmailsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_SetUsername_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetUsernameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).SetUsername(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_SetUsername_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).SetUsername(ctx, req.(*SetUsernameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetSettings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetSettings(ctx, req.(*EmailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_UpdateSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Settings)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).UpdateSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_UpdateSettings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).UpdateSettings(ctx, req.(*Settings))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetUsers(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_DeleteUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).DeleteUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_DeleteUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).DeleteUser(ctx, req.(*EmailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// UserService_ServiceDesc is the grpc.ServiceDesc for UserService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UserService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "user.v1.UserService",
+	HandlerType: (*UserServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateFriendship",
+			Handler:    _UserService_CreateFriendship_Handler,
+		},
+		{
+			MethodName: "DeleteFriendship",
+			Handler:    _UserService_DeleteFriendship_Handler,
+		},
+		{
+			MethodName: "SendFriendRequest",
+			Handler:    _UserService_SendFriendRequest_Handler,
+		},
+		{
+			MethodName: "GetIncomingFriendRequests",
+			Handler:    _UserService_GetIncomingFriendRequests_Handler,
+		},
+		{
+			MethodName: "GetOutgoingFriendRequests",
+			Handler:    _UserService_GetOutgoingFriendRequests_Handler,
+		},
+		{
+			MethodName: "AcceptFriendRequest",
+			Handler:    _UserService_AcceptFriendRequest_Handler,
+		},
+		{
+			MethodName: "RejectFriendRequest",
+			Handler:    _UserService_RejectFriendRequest_Handler,
+		},
+		{
+			MethodName: "CancelFriendRequest",
+			Handler:    _UserService_CancelFriendRequest_Handler,
+		},
+		{
+			MethodName: "GetFriendList",
+			Handler:    _UserService_GetFriendList_Handler,
+		},
+		{
+			MethodName: "GetCommonFriends",
+			Handler:    _UserService_GetCommonFriends_Handler,
+		},
+		{
+			MethodName: "GetFriendshipPath",
+			Handler:    _UserService_GetFriendshipPath_Handler,
+		},
+		{
+			MethodName: "GetFriendSuggestions",
+			Handler:    _UserService_GetFriendSuggestions_Handler,
+		},
+		{
+			MethodName: "CreateSubscription",
+			Handler:    _UserService_CreateSubscription_Handler,
+		},
+		{
+			MethodName: "DeleteSubscription",
+			Handler:    _UserService_DeleteSubscription_Handler,
+		},
+		{
+			MethodName: "GetSubscribers",
+			Handler:    _UserService_GetSubscribers_Handler,
+		},
+		{
+			MethodName: "GetSubscriptions",
+			Handler:    _UserService_GetSubscriptions_Handler,
+		},
+		{
+			MethodName: "CountSubscribers",
+			Handler:    _UserService_CountSubscribers_Handler,
+		},
+		{
+			MethodName: "GetSubscriptionFilter",
+			Handler:    _UserService_GetSubscriptionFilter_Handler,
+		},
+		{
+			MethodName: "UpdateSubscriptionFilter",
+			Handler:    _UserService_UpdateSubscriptionFilter_Handler,
+		},
+		{
+			MethodName: "CreateBlock",
+			Handler:    _UserService_CreateBlock_Handler,
+		},
+		{
+			MethodName: "DeleteBlock",
+			Handler:    _UserService_DeleteBlock_Handler,
+		},
+		{
+			MethodName: "GetBlockedUsers",
+			Handler:    _UserService_GetBlockedUsers_Handler,
+		},
+		{
+			MethodName: "GetBlockers",
+			Handler:    _UserService_GetBlockers_Handler,
+		},
+		{
+			MethodName: "CreateMute",
+			Handler:    _UserService_CreateMute_Handler,
+		},
+		{
+			MethodName: "DeleteMute",
+			Handler:    _UserService_DeleteMute_Handler,
+		},
+		{
+			MethodName: "GetMutes",
+			Handler:    _UserService_GetMutes_Handler,
+		},
+		{
+			MethodName: "GetRelationship",
+			Handler:    _UserService_GetRelationship_Handler,
+		},
+		{
+			MethodName: "GetRecipients",
+			Handler:    _UserService_GetRecipients_Handler,
+		},
+		{
+			MethodName: "ExplainRecipients",
+			Handler:    _UserService_ExplainRecipients_Handler,
+		},
+		{
+			MethodName: "CreateUser",
+			Handler:    _UserService_CreateUser_Handler,
+		},
+		{
+			MethodName: "GetUser",
+			Handler:    _UserService_GetUser_Handler,
+		},
+		{
+			MethodName: "GetUsersByEmails",
+			Handler:    _UserService_GetUsersByEmails_Handler,
+		},
+		{
+			MethodName: "SetUsername",
+			Handler:    _UserService_SetUsername_Handler,
+		},
+		{
+			MethodName: "GetSettings",
+			Handler:    _UserService_GetSettings_Handler,
+		},
+		{
+			MethodName: "UpdateSettings",
+			Handler:    _UserService_UpdateSettings_Handler,
+		},
+		{
+			MethodName: "GetUsers",
+			Handler:    _UserService_GetUsers_Handler,
+		},
+		{
+			MethodName: "DeleteUser",
+			Handler:    _UserService_DeleteUser_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeEvents",
+			Handler:       _UserService_SubscribeEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "user/v1/user.proto",
+}
@@ -0,0 +1,191 @@
+package worker
+
+import (
+	"assignment/internal/config"
+	"assignment/internal/domain/entities"
+	"assignment/internal/domain/interfaces"
+	"assignment/pkg/errors"
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Deliverer sends an outbox event to its destination. Returning an error makes
+// the worker retry the event later
+type Deliverer interface {
+	Deliver(ctx context.Context, event *entities.OutboxEvent) error
+}
+
+// LogDeliverer only logs events, for when no other destination is configured.
+// It logs the event's ID and type but never its payload, which can hold the
+// text of an update and the emails of its recipients
+type LogDeliverer struct{}
+
+func (LogDeliverer) Deliver(ctx context.Context, event *entities.OutboxEvent) error {
+	log.Printf("Outbox event %d (%s) has no consumer, marking it delivered", event.ID, event.EventType)
+	return nil
+}
+
+// EventRouter hands each event to the deliverer registered for its type
+type EventRouter map[string]Deliverer
+
+func (r EventRouter) Deliver(ctx context.Context, event *entities.OutboxEvent) error {
+	deliverer, ok := r[event.EventType]
+	if !ok {
+		return errors.Newf(errors.ErrorTypeInternal, "No deliverer for event type %s", event.EventType)
+	}
+	return deliverer.Deliver(ctx, event)
+}
+
+// OutboxWorker drains the outbox in the background, retrying failed deliveries
+// with exponential backoff and dead-lettering events that keep failing
+type OutboxWorker struct {
+	outboxRepo interfaces.OutboxRepositoryInterface
+	deliverer  Deliverer
+	cfg        config.OutboxConfig
+	now        func() time.Time
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewOutboxWorker(outboxRepo interfaces.OutboxRepositoryInterface, deliverer Deliverer, cfg config.OutboxConfig) *OutboxWorker {
+	return &OutboxWorker{
+		outboxRepo: outboxRepo,
+		deliverer:  deliverer,
+		cfg:        cfg,
+		now:        time.Now,
+	}
+}
+
+// Start runs the worker in a goroutine until Shutdown is called
+func (w *OutboxWorker) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		w.run(ctx)
+	}()
+}
+
+// Shutdown stops the worker and waits for it to return, or for ctx to expire.
+// The delivery in flight is cancelled and the rest of its batch is left
+// claimed, so those events are retried once their lease runs out
+func (w *OutboxWorker) Shutdown(ctx context.Context) error {
+	if w.cancel != nil {
+		w.cancel()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *OutboxWorker) run(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		w.drain(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain processes batches until the outbox has no due events left
+func (w *OutboxWorker) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		processed, err := w.processBatch(ctx)
+		if err != nil {
+			log.Printf("Outbox worker failed to process batch: %v", err)
+			return
+		}
+		if processed < w.cfg.BatchSize {
+			return
+		}
+	}
+}
+
+// processBatch claims one batch of due events and delivers them in order,
+// stopping early once ctx is done. The configuration makes a lease long enough
+// for every delivery of a batch to time out, but an event whose delivery could
+// outlast the lease is left for the next claim rather than delivered while
+// another worker may pick it up too
+func (w *OutboxWorker) processBatch(ctx context.Context) (int, error) {
+	leaseEnd := w.now().Add(w.cfg.LeaseDuration)
+	events, err := w.outboxRepo.ClaimOutboxEvents(w.cfg.BatchSize, w.cfg.LeaseDuration)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, event := range events {
+		if ctx.Err() != nil {
+			break
+		}
+		if w.now().Add(w.cfg.DeliveryTimeout).After(leaseEnd) {
+			log.Printf("Outbox worker lease running out, leaving %d events of the batch for the next claim", len(events)-i)
+			break
+		}
+		w.process(ctx, event)
+	}
+
+	return len(events), nil
+}
+
+func (w *OutboxWorker) process(ctx context.Context, event *entities.OutboxEvent) {
+	deliveryCtx, cancel := context.WithTimeout(ctx, w.cfg.DeliveryTimeout)
+	defer cancel()
+
+	deliveryErr := w.deliverer.Deliver(deliveryCtx, event)
+	if deliveryErr == nil {
+		if err := w.outboxRepo.MarkOutboxEventDelivered(event.ID, event.Attempts); err != nil {
+			log.Printf("Outbox worker failed to mark event %d delivered: %v", event.ID, err)
+		}
+		return
+	}
+
+	// A delivery cut short by shutdown is not the endpoint's fault, leave the
+	// event claimed so it is retried when the lease runs out
+	if ctx.Err() != nil {
+		log.Printf("Outbox event %d delivery interrupted by shutdown: %v", event.ID, deliveryErr)
+		return
+	}
+
+	if event.Attempts >= w.cfg.MaxAttempts {
+		log.Printf("Outbox event %d dead-lettered after %d attempts: %v", event.ID, event.Attempts, deliveryErr)
+		if err := w.outboxRepo.DeadLetterOutboxEvent(event.ID, event.Attempts, deliveryErr.Error()); err != nil {
+			log.Printf("Outbox worker failed to dead-letter event %d: %v", event.ID, err)
+		}
+		return
+	}
+
+	nextAttemptAt := w.now().Add(w.backoff(event.Attempts))
+	if err := w.outboxRepo.RescheduleOutboxEvent(event.ID, event.Attempts, nextAttemptAt, deliveryErr.Error()); err != nil {
+		log.Printf("Outbox worker failed to reschedule event %d: %v", event.ID, err)
+	}
+}
+
+// backoff doubles the base delay for every attempt already made, up to the maximum
+func (w *OutboxWorker) backoff(attempts int) time.Duration {
+	delay := w.cfg.BaseBackoff
+	for i := 1; i < attempts && delay < w.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, w.cfg.MaxBackoff)
+}
@@ -0,0 +1,276 @@
+package worker
+
+import (
+	"assignment/internal/config"
+	"assignment/internal/domain/entities"
+	"assignment/mocks"
+	"assignment/pkg/errors"
+	"bytes"
+	"context"
+	stderrors "errors"
+	"log"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// fakeDeliverer records delivered events and fails while err is set
+type fakeDeliverer struct {
+	mu        sync.Mutex
+	err       error
+	delivered []int
+}
+
+func (d *fakeDeliverer) Deliver(ctx context.Context, event *entities.OutboxEvent) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.err != nil {
+		return d.err
+	}
+	d.delivered = append(d.delivered, event.ID)
+	return nil
+}
+
+func (d *fakeDeliverer) deliveredIDs() []int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return append([]int(nil), d.delivered...)
+}
+
+// blockingDeliverer reports each event it starts and blocks until its context is done
+type blockingDeliverer struct {
+	started chan int
+}
+
+func (d *blockingDeliverer) Deliver(ctx context.Context, event *entities.OutboxEvent) error {
+	d.started <- event.ID
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func testOutboxConfig() config.OutboxConfig {
+	return config.OutboxConfig{
+		PollInterval:    10 * time.Millisecond,
+		BatchSize:       2,
+		MaxAttempts:     3,
+		BaseBackoff:     time.Second,
+		MaxBackoff:      10 * time.Second,
+		LeaseDuration:   time.Minute,
+		DeliveryTimeout: time.Second,
+	}
+}
+
+func TestOutboxWorker_ProcessBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		deliveryErr  error
+		setupMock    func(mockRepo *mocks.MockOutboxRepositoryInterface)
+		wantErr      bool
+		wantCount    int
+		wantDelivery []int
+	}{
+		{
+			name: "delivered events are marked delivered",
+			setupMock: func(mockRepo *mocks.MockOutboxRepositoryInterface) {
+				mockRepo.EXPECT().ClaimOutboxEvents(2, time.Minute).Return([]*entities.OutboxEvent{
+					{ID: 1, EventType: entities.EventUpdatePublished, Attempts: 1},
+					{ID: 2, EventType: entities.EventUpdatePublished, Attempts: 1},
+				}, nil)
+				mockRepo.EXPECT().MarkOutboxEventDelivered(1, 1).Return(nil)
+				mockRepo.EXPECT().MarkOutboxEventDelivered(2, 1).Return(nil)
+			},
+			wantCount:    2,
+			wantDelivery: []int{1, 2},
+		},
+		{
+			name:        "failed event is rescheduled with backoff",
+			deliveryErr: stderrors.New("connection refused"),
+			setupMock: func(mockRepo *mocks.MockOutboxRepositoryInterface) {
+				mockRepo.EXPECT().ClaimOutboxEvents(2, time.Minute).Return([]*entities.OutboxEvent{
+					{ID: 3, EventType: entities.EventUpdatePublished, Attempts: 2},
+				}, nil)
+				mockRepo.EXPECT().RescheduleOutboxEvent(3, 2, now.Add(2*time.Second), "connection refused").Return(nil)
+			},
+			wantCount: 1,
+		},
+		{
+			name:        "event that used all attempts is dead-lettered",
+			deliveryErr: stderrors.New("connection refused"),
+			setupMock: func(mockRepo *mocks.MockOutboxRepositoryInterface) {
+				mockRepo.EXPECT().ClaimOutboxEvents(2, time.Minute).Return([]*entities.OutboxEvent{
+					{ID: 4, EventType: entities.EventUpdatePublished, Attempts: 3},
+				}, nil)
+				mockRepo.EXPECT().DeadLetterOutboxEvent(4, 3, "connection refused").Return(nil)
+			},
+			wantCount: 1,
+		},
+		{
+			name: "claim error is returned",
+			setupMock: func(mockRepo *mocks.MockOutboxRepositoryInterface) {
+				mockRepo.EXPECT().ClaimOutboxEvents(2, time.Minute).Return(nil, errors.New(errors.ErrorTypeDatabase, "Failed to claim outbox events"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockOutboxRepositoryInterface(ctrl)
+			tt.setupMock(mockRepo)
+
+			deliverer := &fakeDeliverer{err: tt.deliveryErr}
+			w := NewOutboxWorker(mockRepo, deliverer, testOutboxConfig())
+			w.now = func() time.Time { return now }
+
+			count, err := w.processBatch(context.Background())
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCount, count)
+			assert.Equal(t, tt.wantDelivery, deliverer.deliveredIDs())
+		})
+	}
+}
+
+func TestOutboxWorker_ProcessBatchStopsBeforeLeaseRunsOut(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// The second event could still be delivering when the lease ends, so it
+	// is left for the next claim
+	mockRepo := mocks.NewMockOutboxRepositoryInterface(ctrl)
+	mockRepo.EXPECT().ClaimOutboxEvents(2, time.Minute).Return([]*entities.OutboxEvent{
+		{ID: 1, Attempts: 1},
+		{ID: 2, Attempts: 1},
+	}, nil)
+	mockRepo.EXPECT().MarkOutboxEventDelivered(1, 1).Return(nil)
+
+	cfg := testOutboxConfig()
+	cfg.DeliveryTimeout = 10 * time.Second
+	deliverer := &fakeDeliverer{}
+	w := NewOutboxWorker(mockRepo, deliverer, cfg)
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	w.now = func() time.Time {
+		current := now
+		now = now.Add(40 * time.Second)
+		return current
+	}
+
+	count, err := w.processBatch(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, []int{1}, deliverer.deliveredIDs())
+}
+
+func TestOutboxWorker_Backoff(t *testing.T) {
+	w := NewOutboxWorker(nil, nil, testOutboxConfig())
+
+	tests := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{attempts: 1, expected: time.Second},
+		{attempts: 2, expected: 2 * time.Second},
+		{attempts: 3, expected: 4 * time.Second},
+		{attempts: 4, expected: 8 * time.Second},
+		{attempts: 5, expected: 10 * time.Second},
+		{attempts: 50, expected: 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, w.backoff(tt.attempts), "attempts %d", tt.attempts)
+	}
+}
+
+func TestOutboxWorker_StartAndShutdown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOutboxRepositoryInterface(ctrl)
+
+	// A full batch makes the worker claim again right away
+	gomock.InOrder(
+		mockRepo.EXPECT().ClaimOutboxEvents(2, time.Minute).Return([]*entities.OutboxEvent{
+			{ID: 1, Attempts: 1},
+			{ID: 2, Attempts: 1},
+		}, nil),
+		mockRepo.EXPECT().ClaimOutboxEvents(2, time.Minute).Return([]*entities.OutboxEvent{
+			{ID: 3, Attempts: 1},
+		}, nil),
+	)
+	mockRepo.EXPECT().ClaimOutboxEvents(2, time.Minute).Return([]*entities.OutboxEvent{}, nil).AnyTimes()
+	mockRepo.EXPECT().MarkOutboxEventDelivered(gomock.Any(), 1).Return(nil).Times(3)
+
+	deliverer := &fakeDeliverer{}
+	w := NewOutboxWorker(mockRepo, deliverer, testOutboxConfig())
+	w.Start()
+
+	assert.Eventually(t, func() bool {
+		return len(deliverer.deliveredIDs()) == 3
+	}, time.Second, 5*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, w.Shutdown(ctx))
+	assert.Equal(t, []int{1, 2, 3}, deliverer.deliveredIDs())
+}
+
+func TestOutboxWorker_ShutdownInterruptsBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Neither event is marked, rescheduled or dead-lettered: the interrupted
+	// one and the one never started are retried once their lease runs out
+	mockRepo := mocks.NewMockOutboxRepositoryInterface(ctrl)
+	mockRepo.EXPECT().ClaimOutboxEvents(2, time.Minute).Return([]*entities.OutboxEvent{
+		{ID: 1, Attempts: 1},
+		{ID: 2, Attempts: 1},
+	}, nil)
+
+	cfg := testOutboxConfig()
+	cfg.DeliveryTimeout = 30 * time.Second
+	deliverer := &blockingDeliverer{started: make(chan int, 2)}
+	w := NewOutboxWorker(mockRepo, deliverer, cfg)
+	w.Start()
+
+	assert.Equal(t, 1, <-deliverer.started)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, w.Shutdown(ctx))
+	assert.Empty(t, deliverer.started)
+}
+
+func TestLogDeliverer_LeavesOutPayload(t *testing.T) {
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+
+	err := LogDeliverer{}.Deliver(context.Background(), &entities.OutboxEvent{
+		ID:        7,
+		EventType: entities.EventUpdatePublished,
+		Payload:   []byte(`{"text":"secret plans","recipients":["lisa@example.com"]}`),
+	})
+
+	assert.NoError(t, err)
+	assert.Contains(t, logged.String(), "Outbox event 7 (update.published)")
+	assert.NotContains(t, logged.String(), "secret plans")
+	assert.NotContains(t, logged.String(), "lisa@example.com")
+}
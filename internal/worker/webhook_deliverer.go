@@ -0,0 +1,198 @@
+package worker
+
+import (
+	"assignment/internal/domain/entities"
+	"assignment/internal/domain/interfaces"
+	"assignment/pkg/errors"
+	"assignment/pkg/validator"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	HeaderWebhookEvent     = "X-Webhook-Event"
+	HeaderWebhookDelivery  = "X-Webhook-Delivery"
+	HeaderWebhookTimestamp = "X-Webhook-Timestamp"
+	HeaderWebhookSignature = "X-Webhook-Signature"
+)
+
+// webhookBody is the JSON document POSTed to a webhook
+type webhookBody struct {
+	Event      string            `json:"event"`
+	DeliveryID int               `json:"delivery_id"`
+	Recipient  string            `json:"recipient"`
+	Update     webhookBodyUpdate `json:"update"`
+}
+
+type webhookBodyUpdate struct {
+	ID        int       `json:"id"`
+	Sender    string    `json:"sender"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewWebhookClient returns the client webhooks are delivered with. Webhook URLs
+// come from users, so it only connects to public addresses, checked after DNS
+// resolution so that no host name can point it at the server's own network,
+// does not follow redirects, which could lead there as well, and gives up
+// after timeout
+func NewWebhookClient(timeout time.Duration) *http.Client {
+	return newWebhookClient(timeout, validator.IsPublicIP)
+}
+
+func newWebhookClient(timeout time.Duration, allowed func(ip netip.Addr) bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !allowed(addrPort.Addr()) {
+				return errors.Newf(errors.ErrorTypeForbidden, "Webhook address %s is not public", addrPort.Addr())
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		// No proxy from the environment, it would be dialed instead of the webhook
+		Transport: &http.Transport{
+			DialContext:           dialer.DialContext,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          100,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+		Timeout: timeout,
+	}
+}
+
+// WebhookDeliverer POSTs webhook.delivery events to the recipient's webhook and
+// logs the outcome of every attempt. Failed attempts are returned as errors so
+// the outbox worker retries them
+type WebhookDeliverer struct {
+	webhookRepo interfaces.WebhookRepositoryInterface
+	client      *http.Client
+	now         func() time.Time
+}
+
+func NewWebhookDeliverer(webhookRepo interfaces.WebhookRepositoryInterface, client *http.Client) *WebhookDeliverer {
+	return &WebhookDeliverer{
+		webhookRepo: webhookRepo,
+		client:      client,
+		now:         time.Now,
+	}
+}
+
+func (d *WebhookDeliverer) Deliver(ctx context.Context, event *entities.OutboxEvent) error {
+	var payload entities.WebhookDeliveryPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return errors.Wrap(err, errors.ErrorTypeInternal, "Failed to decode webhook delivery payload")
+	}
+
+	webhook, err := d.webhookRepo.GetWebhookByID(payload.WebhookID)
+	if err != nil {
+		// The webhook was removed after the update was published
+		if errors.FromError(err).Type == errors.ErrorTypeNotFound {
+			return nil
+		}
+		return err
+	}
+
+	body, err := json.Marshal(webhookBody{
+		Event:      entities.EventUpdatePublished,
+		DeliveryID: event.ID,
+		Recipient:  payload.Recipient,
+		Update: webhookBodyUpdate{
+			ID:        payload.UpdateID,
+			Sender:    payload.Sender,
+			Text:      payload.Text,
+			CreatedAt: payload.CreatedAt,
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, errors.ErrorTypeInternal, "Failed to encode webhook body")
+	}
+
+	started := d.now()
+	statusCode, deliveryErr := d.post(ctx, webhook, event.ID, body)
+
+	delivery := &entities.WebhookDelivery{
+		WebhookID:     webhook.ID,
+		OutboxEventID: event.ID,
+		UpdateID:      payload.UpdateID,
+		Attempt:       event.Attempts,
+		StatusCode:    statusCode,
+		Success:       deliveryErr == nil,
+		Duration:      d.now().Sub(started),
+	}
+	if deliveryErr != nil {
+		delivery.Error = deliveryErr.Error()
+	}
+
+	// A missing log entry must not cause a successful delivery to be repeated
+	if err := d.webhookRepo.RecordWebhookDelivery(delivery); err != nil {
+		log.Printf("Failed to record delivery of event %d to webhook %d: %v", event.ID, webhook.ID, err)
+	}
+
+	return deliveryErr
+}
+
+// post sends the signed body and returns the response status code, which is
+// zero when no response was received
+func (d *WebhookDeliverer) post(ctx context.Context, webhook *entities.Webhook, deliveryID int, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, errors.Wrap(err, errors.ErrorTypeInternal, "Failed to build webhook request")
+	}
+
+	timestamp := d.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderWebhookEvent, entities.EventUpdatePublished)
+	req.Header.Set(HeaderWebhookDelivery, strconv.Itoa(deliveryID))
+	req.Header.Set(HeaderWebhookTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderWebhookSignature, "sha256="+SignWebhookPayload(webhook.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, errors.Wrap(err, errors.ErrorTypeExternal, "Webhook request failed")
+	}
+	defer resp.Body.Close()
+
+	// Drain a bounded amount of the body so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, errors.Newf(errors.ErrorTypeExternal, "Webhook responded with status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// SignWebhookPayload returns the hex HMAC-SHA256 of "<timestamp>.<body>" keyed
+// with the webhook secret. Receivers recompute it to verify a delivery and can
+// reject old timestamps to prevent replays
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,318 @@
+package worker
+
+import (
+	"assignment/internal/domain/entities"
+	"assignment/mocks"
+	"assignment/pkg/errors"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// webhookReceiver is an httptest endpoint that verifies signatures and answers
+// with the queued status codes, then 200
+type webhookReceiver struct {
+	t        *testing.T
+	secret   string
+	mu       sync.Mutex
+	statuses []int
+	bodies   []webhookBody
+}
+
+func (rcv *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	assert.NoError(rcv.t, err)
+
+	timestamp, err := strconv.ParseInt(r.Header.Get(HeaderWebhookTimestamp), 10, 64)
+	assert.NoError(rcv.t, err)
+	assert.Equal(rcv.t, "sha256="+SignWebhookPayload(rcv.secret, timestamp, body), r.Header.Get(HeaderWebhookSignature))
+	assert.Equal(rcv.t, entities.EventUpdatePublished, r.Header.Get(HeaderWebhookEvent))
+	assert.Equal(rcv.t, "application/json", r.Header.Get("Content-Type"))
+
+	var decoded webhookBody
+	assert.NoError(rcv.t, json.Unmarshal(body, &decoded))
+
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+
+	rcv.bodies = append(rcv.bodies, decoded)
+	status := http.StatusOK
+	if len(rcv.statuses) > 0 {
+		status, rcv.statuses = rcv.statuses[0], rcv.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func (rcv *webhookReceiver) received() []webhookBody {
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+
+	return append([]webhookBody(nil), rcv.bodies...)
+}
+
+func webhookEvent(t *testing.T, id, attempts, webhookID int) *entities.OutboxEvent {
+	payload, err := json.Marshal(entities.WebhookDeliveryPayload{
+		WebhookID: webhookID,
+		Recipient: "bob@mail.com",
+		UpdateID:  42,
+		Sender:    "andy@mail.com",
+		Text:      "Hello bob@mail.com",
+		CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("failed to encode payload: %v", err)
+	}
+
+	return &entities.OutboxEvent{ID: id, EventType: entities.EventWebhookDelivery, Payload: payload, Attempts: attempts}
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	signature := SignWebhookPayload("secret", 1700000000, []byte(`{"event":"update.published"}`))
+
+	assert.Len(t, signature, 64)
+	assert.Equal(t, signature, SignWebhookPayload("secret", 1700000000, []byte(`{"event":"update.published"}`)))
+	assert.NotEqual(t, signature, SignWebhookPayload("other", 1700000000, []byte(`{"event":"update.published"}`)))
+	assert.NotEqual(t, signature, SignWebhookPayload("secret", 1700000001, []byte(`{"event":"update.published"}`)))
+}
+
+func TestWebhookDeliverer_Deliver(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name           string
+		statuses       []int
+		setupMock      func(mockRepo *mocks.MockWebhookRepositoryInterface, url string)
+		wantErr        bool
+		wantReceived   int
+		wantStatusCode int
+	}{
+		{
+			name: "signed delivery is logged as successful",
+			setupMock: func(mockRepo *mocks.MockWebhookRepositoryInterface, url string) {
+				mockRepo.EXPECT().GetWebhookByID(7).Return(&entities.Webhook{ID: 7, URL: url, Secret: "s3cr3t"}, nil)
+				mockRepo.EXPECT().RecordWebhookDelivery(gomock.Any()).DoAndReturn(func(delivery *entities.WebhookDelivery) error {
+					assert.Equal(t, 7, delivery.WebhookID)
+					assert.Equal(t, 1, delivery.OutboxEventID)
+					assert.Equal(t, 42, delivery.UpdateID)
+					assert.Equal(t, 1, delivery.Attempt)
+					assert.Equal(t, http.StatusOK, delivery.StatusCode)
+					assert.True(t, delivery.Success)
+					assert.Empty(t, delivery.Error)
+					return nil
+				})
+			},
+			wantReceived: 1,
+		},
+		{
+			name:     "error status is logged and returned",
+			statuses: []int{http.StatusInternalServerError},
+			setupMock: func(mockRepo *mocks.MockWebhookRepositoryInterface, url string) {
+				mockRepo.EXPECT().GetWebhookByID(7).Return(&entities.Webhook{ID: 7, URL: url, Secret: "s3cr3t"}, nil)
+				mockRepo.EXPECT().RecordWebhookDelivery(gomock.Any()).DoAndReturn(func(delivery *entities.WebhookDelivery) error {
+					assert.Equal(t, http.StatusInternalServerError, delivery.StatusCode)
+					assert.False(t, delivery.Success)
+					assert.Equal(t, "EXTERNAL_ERROR: Webhook responded with status 500", delivery.Error)
+					return nil
+				})
+			},
+			wantErr:      true,
+			wantReceived: 1,
+		},
+		{
+			name: "failing to log does not fail the delivery",
+			setupMock: func(mockRepo *mocks.MockWebhookRepositoryInterface, url string) {
+				mockRepo.EXPECT().GetWebhookByID(7).Return(&entities.Webhook{ID: 7, URL: url, Secret: "s3cr3t"}, nil)
+				mockRepo.EXPECT().RecordWebhookDelivery(gomock.Any()).Return(errors.New(errors.ErrorTypeDatabase, "Failed to record webhook delivery"))
+			},
+			wantReceived: 1,
+		},
+		{
+			name: "removed webhook is skipped",
+			setupMock: func(mockRepo *mocks.MockWebhookRepositoryInterface, url string) {
+				mockRepo.EXPECT().GetWebhookByID(7).Return(nil, errors.ErrWebhookNotFound)
+			},
+			wantReceived: 0,
+		},
+		{
+			name: "lookup error is retried",
+			setupMock: func(mockRepo *mocks.MockWebhookRepositoryInterface, url string) {
+				mockRepo.EXPECT().GetWebhookByID(7).Return(nil, errors.New(errors.ErrorTypeDatabase, "Failed to fetch webhook"))
+			},
+			wantErr:      true,
+			wantReceived: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver := &webhookReceiver{t: t, secret: "s3cr3t", statuses: tt.statuses}
+			server := httptest.NewServer(receiver)
+			defer server.Close()
+
+			mockRepo := mocks.NewMockWebhookRepositoryInterface(ctrl)
+			tt.setupMock(mockRepo, server.URL)
+
+			deliverer := NewWebhookDeliverer(mockRepo, server.Client())
+			err := deliverer.Deliver(context.Background(), webhookEvent(t, 1, 1, 7))
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			received := receiver.received()
+			assert.Len(t, received, tt.wantReceived)
+			for _, body := range received {
+				assert.Equal(t, entities.EventUpdatePublished, body.Event)
+				assert.Equal(t, 1, body.DeliveryID)
+				assert.Equal(t, "bob@mail.com", body.Recipient)
+				assert.Equal(t, 42, body.Update.ID)
+				assert.Equal(t, "andy@mail.com", body.Update.Sender)
+				assert.Equal(t, "Hello bob@mail.com", body.Update.Text)
+			}
+		})
+	}
+}
+
+func TestWebhookDeliverer_UnreachableEndpoint(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Close the server straight away so the connection is refused
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	mockRepo := mocks.NewMockWebhookRepositoryInterface(ctrl)
+	mockRepo.EXPECT().GetWebhookByID(7).Return(&entities.Webhook{ID: 7, URL: url, Secret: "s3cr3t"}, nil)
+	mockRepo.EXPECT().RecordWebhookDelivery(gomock.Any()).DoAndReturn(func(delivery *entities.WebhookDelivery) error {
+		assert.Zero(t, delivery.StatusCode)
+		assert.False(t, delivery.Success)
+		assert.Contains(t, delivery.Error, "Webhook request failed")
+		return nil
+	})
+
+	deliverer := NewWebhookDeliverer(mockRepo, http.DefaultClient)
+	assert.Error(t, deliverer.Deliver(context.Background(), webhookEvent(t, 1, 1, 7)))
+}
+
+func TestNewWebhookClient_RejectsNonPublicAddresses(t *testing.T) {
+	// Every address is rejected before the connection is attempted
+	urls := []string{
+		"http://127.0.0.1:8080/hook",
+		"http://localhost:8080/hook",
+		"http://[::1]/hook",
+		"http://10.0.0.1/hook",
+		"http://172.16.5.4/hook",
+		"http://192.168.1.1/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[fe80::1]/hook",
+		"http://0.0.0.0/hook",
+		"http://100.64.0.1/hook",
+		"http://[::ffff:127.0.0.1]/hook",
+	}
+
+	client := NewWebhookClient(time.Second)
+	for _, url := range urls {
+		t.Run(url, func(t *testing.T) {
+			resp, err := client.Post(url, "application/json", nil)
+			if resp != nil {
+				resp.Body.Close()
+			}
+
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), "is not public")
+			}
+		})
+	}
+}
+
+func TestNewWebhookClient_DoesNotFollowRedirects(t *testing.T) {
+	var redirected bool
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected = true
+	}))
+	defer target.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	// The test servers listen on loopback, so every address is allowed here
+	client := newWebhookClient(time.Second, func(ip netip.Addr) bool { return true })
+	resp, err := client.Post(server.URL, "application/json", nil)
+
+	if assert.NoError(t, err) {
+		resp.Body.Close()
+		assert.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+	}
+	assert.False(t, redirected)
+}
+
+func TestOutboxWorker_RetriesWebhookDelivery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	receiver := &webhookReceiver{t: t, secret: "s3cr3t", statuses: []int{http.StatusServiceUnavailable}}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	webhookRepo := mocks.NewMockWebhookRepositoryInterface(ctrl)
+	webhookRepo.EXPECT().GetWebhookByID(7).Return(&entities.Webhook{ID: 7, URL: server.URL, Secret: "s3cr3t"}, nil).Times(2)
+
+	var deliveries []*entities.WebhookDelivery
+	webhookRepo.EXPECT().RecordWebhookDelivery(gomock.Any()).DoAndReturn(func(delivery *entities.WebhookDelivery) error {
+		deliveries = append(deliveries, delivery)
+		return nil
+	}).Times(2)
+
+	// The first attempt is rejected and rescheduled, the second one succeeds
+	outboxRepo := mocks.NewMockOutboxRepositoryInterface(ctrl)
+	gomock.InOrder(
+		outboxRepo.EXPECT().ClaimOutboxEvents(2, time.Minute).Return([]*entities.OutboxEvent{webhookEvent(t, 1, 1, 7)}, nil),
+		outboxRepo.EXPECT().RescheduleOutboxEvent(1, 1, now.Add(time.Second), "EXTERNAL_ERROR: Webhook responded with status 503").Return(nil),
+		outboxRepo.EXPECT().ClaimOutboxEvents(2, time.Minute).Return([]*entities.OutboxEvent{webhookEvent(t, 1, 2, 7)}, nil),
+		outboxRepo.EXPECT().MarkOutboxEventDelivered(1, 2).Return(nil),
+	)
+
+	router := EventRouter{entities.EventWebhookDelivery: NewWebhookDeliverer(webhookRepo, server.Client())}
+	w := NewOutboxWorker(outboxRepo, router, testOutboxConfig())
+	w.now = func() time.Time { return now }
+
+	for range 2 {
+		_, err := w.processBatch(context.Background())
+		assert.NoError(t, err)
+	}
+
+	assert.Len(t, receiver.received(), 2)
+	if assert.Len(t, deliveries, 2) {
+		assert.Equal(t, 1, deliveries[0].Attempt)
+		assert.Equal(t, http.StatusServiceUnavailable, deliveries[0].StatusCode)
+		assert.False(t, deliveries[0].Success)
+		assert.Equal(t, 2, deliveries[1].Attempt)
+		assert.Equal(t, http.StatusOK, deliveries[1].StatusCode)
+		assert.True(t, deliveries[1].Success)
+	}
+}
+
+func TestEventRouter_UnknownEventType(t *testing.T) {
+	router := EventRouter{entities.EventUpdatePublished: LogDeliverer{}}
+
+	assert.NoError(t, router.Deliver(context.Background(), &entities.OutboxEvent{ID: 1, EventType: entities.EventUpdatePublished}))
+	assert.Error(t, router.Deliver(context.Background(), &entities.OutboxEvent{ID: 2, EventType: "unknown"}))
+}
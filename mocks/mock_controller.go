@@ -13,6 +13,7 @@ import (
 	entities "assignment/internal/domain/entities"
 	interfaces "assignment/internal/domain/interfaces"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -41,6 +42,64 @@ func (m *MockUserControllerInterface) EXPECT() *MockUserControllerInterfaceMockR
 	return m.recorder
 }
 
+// AcceptFriendRequest mocks base method.
+func (m *MockUserControllerInterface) AcceptFriendRequest(requestorEmail, targetEmail string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptFriendRequest", requestorEmail, targetEmail)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AcceptFriendRequest indicates an expected call of AcceptFriendRequest.
+func (mr *MockUserControllerInterfaceMockRecorder) AcceptFriendRequest(requestorEmail, targetEmail any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptFriendRequest", reflect.TypeOf((*MockUserControllerInterface)(nil).AcceptFriendRequest), requestorEmail, targetEmail)
+}
+
+// CancelFriendRequest mocks base method.
+func (m *MockUserControllerInterface) CancelFriendRequest(requestorEmail, targetEmail string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelFriendRequest", requestorEmail, targetEmail)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelFriendRequest indicates an expected call of CancelFriendRequest.
+func (mr *MockUserControllerInterfaceMockRecorder) CancelFriendRequest(requestorEmail, targetEmail any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelFriendRequest", reflect.TypeOf((*MockUserControllerInterface)(nil).CancelFriendRequest), requestorEmail, targetEmail)
+}
+
+// CountSubscribers mocks base method.
+func (m *MockUserControllerInterface) CountSubscribers(emails []string) (map[string]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSubscribers", emails)
+	ret0, _ := ret[0].(map[string]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSubscribers indicates an expected call of CountSubscribers.
+func (mr *MockUserControllerInterfaceMockRecorder) CountSubscribers(emails any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSubscribers", reflect.TypeOf((*MockUserControllerInterface)(nil).CountSubscribers), emails)
+}
+
+// CountSubscribersBatch mocks base method.
+func (m *MockUserControllerInterface) CountSubscribersBatch(users []*entities.User) (map[int]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSubscribersBatch", users)
+	ret0, _ := ret[0].(map[int]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSubscribersBatch indicates an expected call of CountSubscribersBatch.
+func (mr *MockUserControllerInterfaceMockRecorder) CountSubscribersBatch(users any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSubscribersBatch", reflect.TypeOf((*MockUserControllerInterface)(nil).CountSubscribersBatch), users)
+}
+
 // CreateBlock mocks base method.
 func (m *MockUserControllerInterface) CreateBlock(requestorEmail, targetEmail string) error {
 	m.ctrl.T.Helper()
@@ -55,18 +114,19 @@ func (mr *MockUserControllerInterfaceMockRecorder) CreateBlock(requestorEmail, t
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBlock", reflect.TypeOf((*MockUserControllerInterface)(nil).CreateBlock), requestorEmail, targetEmail)
 }
 
-// CreateFriendship mocks base method.
-func (m *MockUserControllerInterface) CreateFriendship(user1Email, user2Email string) error {
+// CreateMute mocks base method.
+func (m *MockUserControllerInterface) CreateMute(requestorEmail, targetEmail string, expiresAt *time.Time) (*entities.Mute, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateFriendship", user1Email, user2Email)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "CreateMute", requestorEmail, targetEmail, expiresAt)
+	ret0, _ := ret[0].(*entities.Mute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// CreateFriendship indicates an expected call of CreateFriendship.
-func (mr *MockUserControllerInterfaceMockRecorder) CreateFriendship(user1Email, user2Email any) *gomock.Call {
+// CreateMute indicates an expected call of CreateMute.
+func (mr *MockUserControllerInterfaceMockRecorder) CreateMute(requestorEmail, targetEmail, expiresAt any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFriendship", reflect.TypeOf((*MockUserControllerInterface)(nil).CreateFriendship), user1Email, user2Email)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMute", reflect.TypeOf((*MockUserControllerInterface)(nil).CreateMute), requestorEmail, targetEmail, expiresAt)
 }
 
 // CreateSubscription mocks base method.
@@ -83,49 +143,659 @@ func (mr *MockUserControllerInterfaceMockRecorder) CreateSubscription(requestorE
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSubscription", reflect.TypeOf((*MockUserControllerInterface)(nil).CreateSubscription), requestorEmail, targetEmail)
 }
 
+// CreateUser mocks base method.
+func (m *MockUserControllerInterface) CreateUser(email string) (*entities.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", email)
+	ret0, _ := ret[0].(*entities.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockUserControllerInterfaceMockRecorder) CreateUser(email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUserControllerInterface)(nil).CreateUser), email)
+}
+
+// DeleteBlock mocks base method.
+func (m *MockUserControllerInterface) DeleteBlock(requestorEmail, targetEmail string, restore bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBlock", requestorEmail, targetEmail, restore)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBlock indicates an expected call of DeleteBlock.
+func (mr *MockUserControllerInterfaceMockRecorder) DeleteBlock(requestorEmail, targetEmail, restore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBlock", reflect.TypeOf((*MockUserControllerInterface)(nil).DeleteBlock), requestorEmail, targetEmail, restore)
+}
+
+// DeleteFriendship mocks base method.
+func (m *MockUserControllerInterface) DeleteFriendship(user1Email, user2Email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFriendship", user1Email, user2Email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFriendship indicates an expected call of DeleteFriendship.
+func (mr *MockUserControllerInterfaceMockRecorder) DeleteFriendship(user1Email, user2Email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFriendship", reflect.TypeOf((*MockUserControllerInterface)(nil).DeleteFriendship), user1Email, user2Email)
+}
+
+// DeleteMute mocks base method.
+func (m *MockUserControllerInterface) DeleteMute(requestorEmail, targetEmail string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMute", requestorEmail, targetEmail)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMute indicates an expected call of DeleteMute.
+func (mr *MockUserControllerInterfaceMockRecorder) DeleteMute(requestorEmail, targetEmail any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMute", reflect.TypeOf((*MockUserControllerInterface)(nil).DeleteMute), requestorEmail, targetEmail)
+}
+
+// DeleteSubscription mocks base method.
+func (m *MockUserControllerInterface) DeleteSubscription(requestorEmail, targetEmail string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSubscription", requestorEmail, targetEmail)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSubscription indicates an expected call of DeleteSubscription.
+func (mr *MockUserControllerInterfaceMockRecorder) DeleteSubscription(requestorEmail, targetEmail any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscription", reflect.TypeOf((*MockUserControllerInterface)(nil).DeleteSubscription), requestorEmail, targetEmail)
+}
+
+// DeleteUser mocks base method.
+func (m *MockUserControllerInterface) DeleteUser(email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUser", email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUser indicates an expected call of DeleteUser.
+func (mr *MockUserControllerInterfaceMockRecorder) DeleteUser(email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockUserControllerInterface)(nil).DeleteUser), email)
+}
+
+// ExplainRecipients mocks base method.
+func (m *MockUserControllerInterface) ExplainRecipients(senderEmail, text, cursor string, limit int) (*entities.RecipientExplanation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExplainRecipients", senderEmail, text, cursor, limit)
+	ret0, _ := ret[0].(*entities.RecipientExplanation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExplainRecipients indicates an expected call of ExplainRecipients.
+func (mr *MockUserControllerInterfaceMockRecorder) ExplainRecipients(senderEmail, text, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExplainRecipients", reflect.TypeOf((*MockUserControllerInterface)(nil).ExplainRecipients), senderEmail, text, cursor, limit)
+}
+
+// GetBlockedUsers mocks base method.
+func (m *MockUserControllerInterface) GetBlockedUsers(email, cursor string, limit int) (*entities.UserPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockedUsers", email, cursor, limit)
+	ret0, _ := ret[0].(*entities.UserPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockedUsers indicates an expected call of GetBlockedUsers.
+func (mr *MockUserControllerInterfaceMockRecorder) GetBlockedUsers(email, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockedUsers", reflect.TypeOf((*MockUserControllerInterface)(nil).GetBlockedUsers), email, cursor, limit)
+}
+
+// GetBlockers mocks base method.
+func (m *MockUserControllerInterface) GetBlockers(email, cursor string, limit int) (*entities.UserPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockers", email, cursor, limit)
+	ret0, _ := ret[0].(*entities.UserPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockers indicates an expected call of GetBlockers.
+func (mr *MockUserControllerInterfaceMockRecorder) GetBlockers(email, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockers", reflect.TypeOf((*MockUserControllerInterface)(nil).GetBlockers), email, cursor, limit)
+}
+
+// GetBlockersBatch mocks base method.
+func (m *MockUserControllerInterface) GetBlockersBatch(users []*entities.User, cursor string, limit int) (map[int]*entities.UserPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockersBatch", users, cursor, limit)
+	ret0, _ := ret[0].(map[int]*entities.UserPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockersBatch indicates an expected call of GetBlockersBatch.
+func (mr *MockUserControllerInterfaceMockRecorder) GetBlockersBatch(users, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockersBatch", reflect.TypeOf((*MockUserControllerInterface)(nil).GetBlockersBatch), users, cursor, limit)
+}
+
 // GetCommonFriends mocks base method.
-func (m *MockUserControllerInterface) GetCommonFriends(email1, email2 string) ([]*entities.User, error) {
+func (m *MockUserControllerInterface) GetCommonFriends(emails []string, cursor string, limit int) (*entities.UserPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCommonFriends", email1, email2)
-	ret0, _ := ret[0].([]*entities.User)
+	ret := m.ctrl.Call(m, "GetCommonFriends", emails, cursor, limit)
+	ret0, _ := ret[0].(*entities.UserPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetCommonFriends indicates an expected call of GetCommonFriends.
-func (mr *MockUserControllerInterfaceMockRecorder) GetCommonFriends(email1, email2 any) *gomock.Call {
+func (mr *MockUserControllerInterfaceMockRecorder) GetCommonFriends(emails, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommonFriends", reflect.TypeOf((*MockUserControllerInterface)(nil).GetCommonFriends), emails, cursor, limit)
+}
+
+// GetCommonFriendsBatch mocks base method.
+func (m *MockUserControllerInterface) GetCommonFriendsBatch(users []*entities.User, emails []string, cursor string, limit int) (map[int]*entities.UserPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommonFriendsBatch", users, emails, cursor, limit)
+	ret0, _ := ret[0].(map[int]*entities.UserPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCommonFriendsBatch indicates an expected call of GetCommonFriendsBatch.
+func (mr *MockUserControllerInterfaceMockRecorder) GetCommonFriendsBatch(users, emails, cursor, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommonFriends", reflect.TypeOf((*MockUserControllerInterface)(nil).GetCommonFriends), email1, email2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommonFriendsBatch", reflect.TypeOf((*MockUserControllerInterface)(nil).GetCommonFriendsBatch), users, emails, cursor, limit)
 }
 
 // GetFriendList mocks base method.
-func (m *MockUserControllerInterface) GetFriendList(email string) ([]*entities.User, error) {
+func (m *MockUserControllerInterface) GetFriendList(email, cursor string, limit int) (*entities.UserPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFriendList", email)
-	ret0, _ := ret[0].([]*entities.User)
+	ret := m.ctrl.Call(m, "GetFriendList", email, cursor, limit)
+	ret0, _ := ret[0].(*entities.UserPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFriendList indicates an expected call of GetFriendList.
-func (mr *MockUserControllerInterfaceMockRecorder) GetFriendList(email any) *gomock.Call {
+func (mr *MockUserControllerInterfaceMockRecorder) GetFriendList(email, cursor, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendList", reflect.TypeOf((*MockUserControllerInterface)(nil).GetFriendList), email)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendList", reflect.TypeOf((*MockUserControllerInterface)(nil).GetFriendList), email, cursor, limit)
 }
 
-// GetRecipients mocks base method.
-func (m *MockUserControllerInterface) GetRecipients(senderEmail, text string) ([]*entities.User, error) {
+// GetFriendListsBatch mocks base method.
+func (m *MockUserControllerInterface) GetFriendListsBatch(users []*entities.User, cursor string, limit int) (map[int]*entities.UserPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFriendListsBatch", users, cursor, limit)
+	ret0, _ := ret[0].(map[int]*entities.UserPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFriendListsBatch indicates an expected call of GetFriendListsBatch.
+func (mr *MockUserControllerInterfaceMockRecorder) GetFriendListsBatch(users, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendListsBatch", reflect.TypeOf((*MockUserControllerInterface)(nil).GetFriendListsBatch), users, cursor, limit)
+}
+
+// GetFriendSuggestions mocks base method.
+func (m *MockUserControllerInterface) GetFriendSuggestions(email string, limit int) ([]*entities.FriendSuggestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFriendSuggestions", email, limit)
+	ret0, _ := ret[0].([]*entities.FriendSuggestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFriendSuggestions indicates an expected call of GetFriendSuggestions.
+func (mr *MockUserControllerInterfaceMockRecorder) GetFriendSuggestions(email, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendSuggestions", reflect.TypeOf((*MockUserControllerInterface)(nil).GetFriendSuggestions), email, limit)
+}
+
+// GetFriendshipPath mocks base method.
+func (m *MockUserControllerInterface) GetFriendshipPath(requestorEmail, targetEmail string, maxDepth int) ([]*entities.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRecipients", senderEmail, text)
+	ret := m.ctrl.Call(m, "GetFriendshipPath", requestorEmail, targetEmail, maxDepth)
 	ret0, _ := ret[0].([]*entities.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// GetFriendshipPath indicates an expected call of GetFriendshipPath.
+func (mr *MockUserControllerInterfaceMockRecorder) GetFriendshipPath(requestorEmail, targetEmail, maxDepth any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendshipPath", reflect.TypeOf((*MockUserControllerInterface)(nil).GetFriendshipPath), requestorEmail, targetEmail, maxDepth)
+}
+
+// GetIncomingFriendRequests mocks base method.
+func (m *MockUserControllerInterface) GetIncomingFriendRequests(email string) ([]*entities.FriendRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncomingFriendRequests", email)
+	ret0, _ := ret[0].([]*entities.FriendRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncomingFriendRequests indicates an expected call of GetIncomingFriendRequests.
+func (mr *MockUserControllerInterfaceMockRecorder) GetIncomingFriendRequests(email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncomingFriendRequests", reflect.TypeOf((*MockUserControllerInterface)(nil).GetIncomingFriendRequests), email)
+}
+
+// GetMutes mocks base method.
+func (m *MockUserControllerInterface) GetMutes(email string) ([]*entities.Mute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMutes", email)
+	ret0, _ := ret[0].([]*entities.Mute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMutes indicates an expected call of GetMutes.
+func (mr *MockUserControllerInterfaceMockRecorder) GetMutes(email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMutes", reflect.TypeOf((*MockUserControllerInterface)(nil).GetMutes), email)
+}
+
+// GetOutgoingFriendRequests mocks base method.
+func (m *MockUserControllerInterface) GetOutgoingFriendRequests(email string) ([]*entities.FriendRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutgoingFriendRequests", email)
+	ret0, _ := ret[0].([]*entities.FriendRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOutgoingFriendRequests indicates an expected call of GetOutgoingFriendRequests.
+func (mr *MockUserControllerInterfaceMockRecorder) GetOutgoingFriendRequests(email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutgoingFriendRequests", reflect.TypeOf((*MockUserControllerInterface)(nil).GetOutgoingFriendRequests), email)
+}
+
+// GetRecipients mocks base method.
+func (m *MockUserControllerInterface) GetRecipients(senderEmail, text, cursor string, limit int) (*entities.UserPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecipients", senderEmail, text, cursor, limit)
+	ret0, _ := ret[0].(*entities.UserPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // GetRecipients indicates an expected call of GetRecipients.
-func (mr *MockUserControllerInterfaceMockRecorder) GetRecipients(senderEmail, text any) *gomock.Call {
+func (mr *MockUserControllerInterfaceMockRecorder) GetRecipients(senderEmail, text, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipients", reflect.TypeOf((*MockUserControllerInterface)(nil).GetRecipients), senderEmail, text, cursor, limit)
+}
+
+// GetRelationship mocks base method.
+func (m *MockUserControllerInterface) GetRelationship(emailA, emailB string) (*entities.Relationship, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRelationship", emailA, emailB)
+	ret0, _ := ret[0].(*entities.Relationship)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRelationship indicates an expected call of GetRelationship.
+func (mr *MockUserControllerInterfaceMockRecorder) GetRelationship(emailA, emailB any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRelationship", reflect.TypeOf((*MockUserControllerInterface)(nil).GetRelationship), emailA, emailB)
+}
+
+// GetSettings mocks base method.
+func (m *MockUserControllerInterface) GetSettings(email string) (*entities.UserSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSettings", email)
+	ret0, _ := ret[0].(*entities.UserSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSettings indicates an expected call of GetSettings.
+func (mr *MockUserControllerInterfaceMockRecorder) GetSettings(email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSettings", reflect.TypeOf((*MockUserControllerInterface)(nil).GetSettings), email)
+}
+
+// GetSubscribers mocks base method.
+func (m *MockUserControllerInterface) GetSubscribers(email, cursor string, limit int) (*entities.UserPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscribers", email, cursor, limit)
+	ret0, _ := ret[0].(*entities.UserPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscribers indicates an expected call of GetSubscribers.
+func (mr *MockUserControllerInterfaceMockRecorder) GetSubscribers(email, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscribers", reflect.TypeOf((*MockUserControllerInterface)(nil).GetSubscribers), email, cursor, limit)
+}
+
+// GetSubscribersBatch mocks base method.
+func (m *MockUserControllerInterface) GetSubscribersBatch(users []*entities.User, cursor string, limit int) (map[int]*entities.UserPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscribersBatch", users, cursor, limit)
+	ret0, _ := ret[0].(map[int]*entities.UserPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscribersBatch indicates an expected call of GetSubscribersBatch.
+func (mr *MockUserControllerInterfaceMockRecorder) GetSubscribersBatch(users, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscribersBatch", reflect.TypeOf((*MockUserControllerInterface)(nil).GetSubscribersBatch), users, cursor, limit)
+}
+
+// GetSubscriptionFilter mocks base method.
+func (m *MockUserControllerInterface) GetSubscriptionFilter(requestorEmail, targetEmail string) (*entities.SubscriptionFilter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscriptionFilter", requestorEmail, targetEmail)
+	ret0, _ := ret[0].(*entities.SubscriptionFilter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscriptionFilter indicates an expected call of GetSubscriptionFilter.
+func (mr *MockUserControllerInterfaceMockRecorder) GetSubscriptionFilter(requestorEmail, targetEmail any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscriptionFilter", reflect.TypeOf((*MockUserControllerInterface)(nil).GetSubscriptionFilter), requestorEmail, targetEmail)
+}
+
+// GetSubscriptions mocks base method.
+func (m *MockUserControllerInterface) GetSubscriptions(email, cursor string, limit int) (*entities.UserPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscriptions", email, cursor, limit)
+	ret0, _ := ret[0].(*entities.UserPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscriptions indicates an expected call of GetSubscriptions.
+func (mr *MockUserControllerInterfaceMockRecorder) GetSubscriptions(email, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscriptions", reflect.TypeOf((*MockUserControllerInterface)(nil).GetSubscriptions), email, cursor, limit)
+}
+
+// GetUser mocks base method.
+func (m *MockUserControllerInterface) GetUser(email string) (*entities.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", email)
+	ret0, _ := ret[0].(*entities.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockUserControllerInterfaceMockRecorder) GetUser(email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockUserControllerInterface)(nil).GetUser), email)
+}
+
+// GetUsers mocks base method.
+func (m *MockUserControllerInterface) GetUsers() ([]*entities.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsers")
+	ret0, _ := ret[0].([]*entities.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsers indicates an expected call of GetUsers.
+func (mr *MockUserControllerInterfaceMockRecorder) GetUsers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*MockUserControllerInterface)(nil).GetUsers))
+}
+
+// GetUsersByEmails mocks base method.
+func (m *MockUserControllerInterface) GetUsersByEmails(emails []string) ([]*entities.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsersByEmails", emails)
+	ret0, _ := ret[0].([]*entities.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsersByEmails indicates an expected call of GetUsersByEmails.
+func (mr *MockUserControllerInterfaceMockRecorder) GetUsersByEmails(emails any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersByEmails", reflect.TypeOf((*MockUserControllerInterface)(nil).GetUsersByEmails), emails)
+}
+
+// RejectFriendRequest mocks base method.
+func (m *MockUserControllerInterface) RejectFriendRequest(requestorEmail, targetEmail string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectFriendRequest", requestorEmail, targetEmail)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RejectFriendRequest indicates an expected call of RejectFriendRequest.
+func (mr *MockUserControllerInterfaceMockRecorder) RejectFriendRequest(requestorEmail, targetEmail any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectFriendRequest", reflect.TypeOf((*MockUserControllerInterface)(nil).RejectFriendRequest), requestorEmail, targetEmail)
+}
+
+// SendFriendRequest mocks base method.
+func (m *MockUserControllerInterface) SendFriendRequest(requestorEmail, targetEmail string) (*entities.FriendRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendFriendRequest", requestorEmail, targetEmail)
+	ret0, _ := ret[0].(*entities.FriendRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendFriendRequest indicates an expected call of SendFriendRequest.
+func (mr *MockUserControllerInterfaceMockRecorder) SendFriendRequest(requestorEmail, targetEmail any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendFriendRequest", reflect.TypeOf((*MockUserControllerInterface)(nil).SendFriendRequest), requestorEmail, targetEmail)
+}
+
+// SetUsername mocks base method.
+func (m *MockUserControllerInterface) SetUsername(email, username string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUsername", email, username)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUsername indicates an expected call of SetUsername.
+func (mr *MockUserControllerInterfaceMockRecorder) SetUsername(email, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUsername", reflect.TypeOf((*MockUserControllerInterface)(nil).SetUsername), email, username)
+}
+
+// SubscribeEvents mocks base method.
+func (m *MockUserControllerInterface) SubscribeEvents(email string) (*entities.UserEventStream, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeEvents", email)
+	ret0, _ := ret[0].(*entities.UserEventStream)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeEvents indicates an expected call of SubscribeEvents.
+func (mr *MockUserControllerInterfaceMockRecorder) SubscribeEvents(email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeEvents", reflect.TypeOf((*MockUserControllerInterface)(nil).SubscribeEvents), email)
+}
+
+// UpdateSettings mocks base method.
+func (m *MockUserControllerInterface) UpdateSettings(email string, mentionPolicy entities.MentionPolicy) (*entities.UserSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSettings", email, mentionPolicy)
+	ret0, _ := ret[0].(*entities.UserSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSettings indicates an expected call of UpdateSettings.
+func (mr *MockUserControllerInterfaceMockRecorder) UpdateSettings(email, mentionPolicy any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipients", reflect.TypeOf((*MockUserControllerInterface)(nil).GetRecipients), senderEmail, text)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSettings", reflect.TypeOf((*MockUserControllerInterface)(nil).UpdateSettings), email, mentionPolicy)
+}
+
+// UpdateSubscriptionFilter mocks base method.
+func (m *MockUserControllerInterface) UpdateSubscriptionFilter(requestorEmail, targetEmail string, filter *entities.SubscriptionFilter) (*entities.SubscriptionFilter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSubscriptionFilter", requestorEmail, targetEmail, filter)
+	ret0, _ := ret[0].(*entities.SubscriptionFilter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSubscriptionFilter indicates an expected call of UpdateSubscriptionFilter.
+func (mr *MockUserControllerInterfaceMockRecorder) UpdateSubscriptionFilter(requestorEmail, targetEmail, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSubscriptionFilter", reflect.TypeOf((*MockUserControllerInterface)(nil).UpdateSubscriptionFilter), requestorEmail, targetEmail, filter)
+}
+
+// MockUpdateControllerInterface is a mock of UpdateControllerInterface interface.
+type MockUpdateControllerInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockUpdateControllerInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockUpdateControllerInterfaceMockRecorder is the mock recorder for MockUpdateControllerInterface.
+type MockUpdateControllerInterfaceMockRecorder struct {
+	mock *MockUpdateControllerInterface
+}
+
+// NewMockUpdateControllerInterface creates a new mock instance.
+func NewMockUpdateControllerInterface(ctrl *gomock.Controller) *MockUpdateControllerInterface {
+	mock := &MockUpdateControllerInterface{ctrl: ctrl}
+	mock.recorder = &MockUpdateControllerInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUpdateControllerInterface) EXPECT() *MockUpdateControllerInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GetTimeline mocks base method.
+func (m *MockUpdateControllerInterface) GetTimeline(email string, limit, offset int) (*entities.TimelinePage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTimeline", email, limit, offset)
+	ret0, _ := ret[0].(*entities.TimelinePage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTimeline indicates an expected call of GetTimeline.
+func (mr *MockUpdateControllerInterfaceMockRecorder) GetTimeline(email, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTimeline", reflect.TypeOf((*MockUpdateControllerInterface)(nil).GetTimeline), email, limit, offset)
+}
+
+// PublishUpdate mocks base method.
+func (m *MockUpdateControllerInterface) PublishUpdate(senderEmail, text string) (*entities.Update, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishUpdate", senderEmail, text)
+	ret0, _ := ret[0].(*entities.Update)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishUpdate indicates an expected call of PublishUpdate.
+func (mr *MockUpdateControllerInterfaceMockRecorder) PublishUpdate(senderEmail, text any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishUpdate", reflect.TypeOf((*MockUpdateControllerInterface)(nil).PublishUpdate), senderEmail, text)
+}
+
+// SubscribeUpdates mocks base method.
+func (m *MockUpdateControllerInterface) SubscribeUpdates(email string, lastEventID, backlogPageSize int) (*entities.UpdateStream, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeUpdates", email, lastEventID, backlogPageSize)
+	ret0, _ := ret[0].(*entities.UpdateStream)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeUpdates indicates an expected call of SubscribeUpdates.
+func (mr *MockUpdateControllerInterfaceMockRecorder) SubscribeUpdates(email, lastEventID, backlogPageSize any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeUpdates", reflect.TypeOf((*MockUpdateControllerInterface)(nil).SubscribeUpdates), email, lastEventID, backlogPageSize)
+}
+
+// MockWebhookControllerInterface is a mock of WebhookControllerInterface interface.
+type MockWebhookControllerInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookControllerInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockWebhookControllerInterfaceMockRecorder is the mock recorder for MockWebhookControllerInterface.
+type MockWebhookControllerInterfaceMockRecorder struct {
+	mock *MockWebhookControllerInterface
+}
+
+// NewMockWebhookControllerInterface creates a new mock instance.
+func NewMockWebhookControllerInterface(ctrl *gomock.Controller) *MockWebhookControllerInterface {
+	mock := &MockWebhookControllerInterface{ctrl: ctrl}
+	mock.recorder = &MockWebhookControllerInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookControllerInterface) EXPECT() *MockWebhookControllerInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GetWebhookDeliveries mocks base method.
+func (m *MockWebhookControllerInterface) GetWebhookDeliveries(email string, limit int) ([]*entities.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeliveries", email, limit)
+	ret0, _ := ret[0].([]*entities.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDeliveries indicates an expected call of GetWebhookDeliveries.
+func (mr *MockWebhookControllerInterfaceMockRecorder) GetWebhookDeliveries(email, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveries", reflect.TypeOf((*MockWebhookControllerInterface)(nil).GetWebhookDeliveries), email, limit)
+}
+
+// RegisterWebhook mocks base method.
+func (m *MockWebhookControllerInterface) RegisterWebhook(email, url string) (*entities.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterWebhook", email, url)
+	ret0, _ := ret[0].(*entities.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterWebhook indicates an expected call of RegisterWebhook.
+func (mr *MockWebhookControllerInterfaceMockRecorder) RegisterWebhook(email, url any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterWebhook", reflect.TypeOf((*MockWebhookControllerInterface)(nil).RegisterWebhook), email, url)
+}
+
+// RemoveWebhook mocks base method.
+func (m *MockWebhookControllerInterface) RemoveWebhook(email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveWebhook", email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveWebhook indicates an expected call of RemoveWebhook.
+func (mr *MockWebhookControllerInterfaceMockRecorder) RemoveWebhook(email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveWebhook", reflect.TypeOf((*MockWebhookControllerInterface)(nil).RemoveWebhook), email)
 }
 
 // MockControllers is a mock of Controllers interface.
@@ -152,6 +822,20 @@ func (m *MockControllers) EXPECT() *MockControllersMockRecorder {
 	return m.recorder
 }
 
+// UpdateController mocks base method.
+func (m *MockControllers) UpdateController() interfaces.UpdateControllerInterface {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateController")
+	ret0, _ := ret[0].(interfaces.UpdateControllerInterface)
+	return ret0
+}
+
+// UpdateController indicates an expected call of UpdateController.
+func (mr *MockControllersMockRecorder) UpdateController() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateController", reflect.TypeOf((*MockControllers)(nil).UpdateController))
+}
+
 // UserController mocks base method.
 func (m *MockControllers) UserController() interfaces.UserControllerInterface {
 	m.ctrl.T.Helper()
@@ -165,3 +849,17 @@ func (mr *MockControllersMockRecorder) UserController() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserController", reflect.TypeOf((*MockControllers)(nil).UserController))
 }
+
+// WebhookController mocks base method.
+func (m *MockControllers) WebhookController() interfaces.WebhookControllerInterface {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WebhookController")
+	ret0, _ := ret[0].(interfaces.WebhookControllerInterface)
+	return ret0
+}
+
+// WebhookController indicates an expected call of WebhookController.
+func (mr *MockControllersMockRecorder) WebhookController() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WebhookController", reflect.TypeOf((*MockControllers)(nil).WebhookController))
+}
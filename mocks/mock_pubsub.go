@@ -0,0 +1,119 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/interfaces/pubsub.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/domain/interfaces/pubsub.go -destination=mocks/mock_pubsub.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entities "assignment/internal/domain/entities"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUpdateHubInterface is a mock of UpdateHubInterface interface.
+type MockUpdateHubInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockUpdateHubInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockUpdateHubInterfaceMockRecorder is the mock recorder for MockUpdateHubInterface.
+type MockUpdateHubInterfaceMockRecorder struct {
+	mock *MockUpdateHubInterface
+}
+
+// NewMockUpdateHubInterface creates a new mock instance.
+func NewMockUpdateHubInterface(ctrl *gomock.Controller) *MockUpdateHubInterface {
+	mock := &MockUpdateHubInterface{ctrl: ctrl}
+	mock.recorder = &MockUpdateHubInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUpdateHubInterface) EXPECT() *MockUpdateHubInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockUpdateHubInterface) Publish(recipientIDs []int, update *entities.Update) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Publish", recipientIDs, update)
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockUpdateHubInterfaceMockRecorder) Publish(recipientIDs, update any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockUpdateHubInterface)(nil).Publish), recipientIDs, update)
+}
+
+// Subscribe mocks base method.
+func (m *MockUpdateHubInterface) Subscribe(userID int) (<-chan *entities.Update, func()) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", userID)
+	ret0, _ := ret[0].(<-chan *entities.Update)
+	ret1, _ := ret[1].(func())
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockUpdateHubInterfaceMockRecorder) Subscribe(userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockUpdateHubInterface)(nil).Subscribe), userID)
+}
+
+// MockUserEventHubInterface is a mock of UserEventHubInterface interface.
+type MockUserEventHubInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserEventHubInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockUserEventHubInterfaceMockRecorder is the mock recorder for MockUserEventHubInterface.
+type MockUserEventHubInterfaceMockRecorder struct {
+	mock *MockUserEventHubInterface
+}
+
+// NewMockUserEventHubInterface creates a new mock instance.
+func NewMockUserEventHubInterface(ctrl *gomock.Controller) *MockUserEventHubInterface {
+	mock := &MockUserEventHubInterface{ctrl: ctrl}
+	mock.recorder = &MockUserEventHubInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserEventHubInterface) EXPECT() *MockUserEventHubInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockUserEventHubInterface) Publish(userIDs []int, event *entities.UserEvent) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Publish", userIDs, event)
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockUserEventHubInterfaceMockRecorder) Publish(userIDs, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockUserEventHubInterface)(nil).Publish), userIDs, event)
+}
+
+// Subscribe mocks base method.
+func (m *MockUserEventHubInterface) Subscribe(userID int) (<-chan *entities.UserEvent, func()) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", userID)
+	ret0, _ := ret[0].(<-chan *entities.UserEvent)
+	ret1, _ := ret[1].(func())
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockUserEventHubInterfaceMockRecorder) Subscribe(userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockUserEventHubInterface)(nil).Subscribe), userID)
+}
@@ -13,6 +13,7 @@ import (
 	entities "assignment/internal/domain/entities"
 	interfaces "assignment/internal/domain/interfaces"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -41,6 +42,20 @@ func (m *MockUserRepositoryInterface) EXPECT() *MockUserRepositoryInterfaceMockR
 	return m.recorder
 }
 
+// AcceptFriendRequestTx mocks base method.
+func (m *MockUserRepositoryInterface) AcceptFriendRequestTx(requester, addressee *entities.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptFriendRequestTx", requester, addressee)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AcceptFriendRequestTx indicates an expected call of AcceptFriendRequestTx.
+func (mr *MockUserRepositoryInterfaceMockRecorder) AcceptFriendRequestTx(requester, addressee any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptFriendRequestTx", reflect.TypeOf((*MockUserRepositoryInterface)(nil).AcceptFriendRequestTx), requester, addressee)
+}
+
 // CheckBidirectionalBlock mocks base method.
 func (m *MockUserRepositoryInterface) CheckBidirectionalBlock(user1ID, user2ID int) (bool, error) {
 	m.ctrl.T.Helper()
@@ -86,6 +101,36 @@ func (mr *MockUserRepositoryInterfaceMockRecorder) CheckBlockExists(requestorID,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckBlockExists", reflect.TypeOf((*MockUserRepositoryInterface)(nil).CheckBlockExists), requestorID, targetID)
 }
 
+// CheckFriendshipExists mocks base method.
+func (m *MockUserRepositoryInterface) CheckFriendshipExists(user1ID, user2ID int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckFriendshipExists", user1ID, user2ID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckFriendshipExists indicates an expected call of CheckFriendshipExists.
+func (mr *MockUserRepositoryInterfaceMockRecorder) CheckFriendshipExists(user1ID, user2ID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckFriendshipExists", reflect.TypeOf((*MockUserRepositoryInterface)(nil).CheckFriendshipExists), user1ID, user2ID)
+}
+
+// CountSubscribersBatch mocks base method.
+func (m *MockUserRepositoryInterface) CountSubscribersBatch(userIDs []int) (map[int]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSubscribersBatch", userIDs)
+	ret0, _ := ret[0].(map[int]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSubscribersBatch indicates an expected call of CountSubscribersBatch.
+func (mr *MockUserRepositoryInterfaceMockRecorder) CountSubscribersBatch(userIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSubscribersBatch", reflect.TypeOf((*MockUserRepositoryInterface)(nil).CountSubscribersBatch), userIDs)
+}
+
 // CreateBlockTx mocks base method.
 func (m *MockUserRepositoryInterface) CreateBlockTx(requestor, target *entities.User) error {
 	m.ctrl.T.Helper()
@@ -100,18 +145,19 @@ func (mr *MockUserRepositoryInterfaceMockRecorder) CreateBlockTx(requestor, targ
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBlockTx", reflect.TypeOf((*MockUserRepositoryInterface)(nil).CreateBlockTx), requestor, target)
 }
 
-// CreateFriendship mocks base method.
-func (m *MockUserRepositoryInterface) CreateFriendship(user1, user2 *entities.User) error {
+// CreateFriendRequest mocks base method.
+func (m *MockUserRepositoryInterface) CreateFriendRequest(requester, addressee *entities.User) (*entities.FriendRequest, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateFriendship", user1, user2)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "CreateFriendRequest", requester, addressee)
+	ret0, _ := ret[0].(*entities.FriendRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// CreateFriendship indicates an expected call of CreateFriendship.
-func (mr *MockUserRepositoryInterfaceMockRecorder) CreateFriendship(user1, user2 any) *gomock.Call {
+// CreateFriendRequest indicates an expected call of CreateFriendRequest.
+func (mr *MockUserRepositoryInterfaceMockRecorder) CreateFriendRequest(requester, addressee any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFriendship", reflect.TypeOf((*MockUserRepositoryInterface)(nil).CreateFriendship), user1, user2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFriendRequest", reflect.TypeOf((*MockUserRepositoryInterface)(nil).CreateFriendRequest), requester, addressee)
 }
 
 // CreateSubscription mocks base method.
@@ -128,49 +174,404 @@ func (mr *MockUserRepositoryInterfaceMockRecorder) CreateSubscription(requestor,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSubscription", reflect.TypeOf((*MockUserRepositoryInterface)(nil).CreateSubscription), requestor, target)
 }
 
-// GetCommonFriends mocks base method.
-func (m *MockUserRepositoryInterface) GetCommonFriends(user1, user2 *entities.User) ([]*entities.User, error) {
+// CreateUser mocks base method.
+func (m *MockUserRepositoryInterface) CreateUser(email string) (*entities.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", email)
+	ret0, _ := ret[0].(*entities.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockUserRepositoryInterfaceMockRecorder) CreateUser(email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUserRepositoryInterface)(nil).CreateUser), email)
+}
+
+// DeleteBlockTx mocks base method.
+func (m *MockUserRepositoryInterface) DeleteBlockTx(requestor, target *entities.User, restore bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBlockTx", requestor, target, restore)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBlockTx indicates an expected call of DeleteBlockTx.
+func (mr *MockUserRepositoryInterfaceMockRecorder) DeleteBlockTx(requestor, target, restore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBlockTx", reflect.TypeOf((*MockUserRepositoryInterface)(nil).DeleteBlockTx), requestor, target, restore)
+}
+
+// DeleteFriendship mocks base method.
+func (m *MockUserRepositoryInterface) DeleteFriendship(user1, user2 *entities.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFriendship", user1, user2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFriendship indicates an expected call of DeleteFriendship.
+func (mr *MockUserRepositoryInterfaceMockRecorder) DeleteFriendship(user1, user2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFriendship", reflect.TypeOf((*MockUserRepositoryInterface)(nil).DeleteFriendship), user1, user2)
+}
+
+// DeleteMute mocks base method.
+func (m *MockUserRepositoryInterface) DeleteMute(muter, muted *entities.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMute", muter, muted)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMute indicates an expected call of DeleteMute.
+func (mr *MockUserRepositoryInterfaceMockRecorder) DeleteMute(muter, muted any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMute", reflect.TypeOf((*MockUserRepositoryInterface)(nil).DeleteMute), muter, muted)
+}
+
+// DeleteSubscription mocks base method.
+func (m *MockUserRepositoryInterface) DeleteSubscription(requestor, target *entities.User) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCommonFriends", user1, user2)
+	ret := m.ctrl.Call(m, "DeleteSubscription", requestor, target)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSubscription indicates an expected call of DeleteSubscription.
+func (mr *MockUserRepositoryInterfaceMockRecorder) DeleteSubscription(requestor, target any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscription", reflect.TypeOf((*MockUserRepositoryInterface)(nil).DeleteSubscription), requestor, target)
+}
+
+// DeleteUser mocks base method.
+func (m *MockUserRepositoryInterface) DeleteUser(user *entities.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUser", user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUser indicates an expected call of DeleteUser.
+func (mr *MockUserRepositoryInterfaceMockRecorder) DeleteUser(user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockUserRepositoryInterface)(nil).DeleteUser), user)
+}
+
+// GetAllUsers mocks base method.
+func (m *MockUserRepositoryInterface) GetAllUsers() ([]*entities.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllUsers")
 	ret0, _ := ret[0].([]*entities.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// GetAllUsers indicates an expected call of GetAllUsers.
+func (mr *MockUserRepositoryInterfaceMockRecorder) GetAllUsers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllUsers", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetAllUsers))
+}
+
+// GetBlockedUsers mocks base method.
+func (m *MockUserRepositoryInterface) GetBlockedUsers(blocker *entities.User, page entities.PageRequest) (*entities.UserPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockedUsers", blocker, page)
+	ret0, _ := ret[0].(*entities.UserPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockedUsers indicates an expected call of GetBlockedUsers.
+func (mr *MockUserRepositoryInterfaceMockRecorder) GetBlockedUsers(blocker, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockedUsers", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetBlockedUsers), blocker, page)
+}
+
+// GetBlockers mocks base method.
+func (m *MockUserRepositoryInterface) GetBlockers(blocked *entities.User, page entities.PageRequest) (*entities.UserPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockers", blocked, page)
+	ret0, _ := ret[0].(*entities.UserPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockers indicates an expected call of GetBlockers.
+func (mr *MockUserRepositoryInterfaceMockRecorder) GetBlockers(blocked, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockers", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetBlockers), blocked, page)
+}
+
+// GetBlockersBatch mocks base method.
+func (m *MockUserRepositoryInterface) GetBlockersBatch(userIDs []int, page entities.PageRequest) (map[int]*entities.UserPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockersBatch", userIDs, page)
+	ret0, _ := ret[0].(map[int]*entities.UserPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockersBatch indicates an expected call of GetBlockersBatch.
+func (mr *MockUserRepositoryInterfaceMockRecorder) GetBlockersBatch(userIDs, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockersBatch", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetBlockersBatch), userIDs, page)
+}
+
+// GetCommonFriends mocks base method.
+func (m *MockUserRepositoryInterface) GetCommonFriends(users []*entities.User, page entities.PageRequest) (*entities.UserPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommonFriends", users, page)
+	ret0, _ := ret[0].(*entities.UserPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // GetCommonFriends indicates an expected call of GetCommonFriends.
-func (mr *MockUserRepositoryInterfaceMockRecorder) GetCommonFriends(user1, user2 any) *gomock.Call {
+func (mr *MockUserRepositoryInterfaceMockRecorder) GetCommonFriends(users, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommonFriends", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetCommonFriends), users, page)
+}
+
+// GetCommonFriendsBatch mocks base method.
+func (m *MockUserRepositoryInterface) GetCommonFriendsBatch(userIDs []int, others []*entities.User, page entities.PageRequest) (map[int]*entities.UserPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommonFriendsBatch", userIDs, others, page)
+	ret0, _ := ret[0].(map[int]*entities.UserPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCommonFriendsBatch indicates an expected call of GetCommonFriendsBatch.
+func (mr *MockUserRepositoryInterfaceMockRecorder) GetCommonFriendsBatch(userIDs, others, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommonFriendsBatch", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetCommonFriendsBatch), userIDs, others, page)
+}
+
+// GetDroppedMentions mocks base method.
+func (m *MockUserRepositoryInterface) GetDroppedMentions(sender *entities.User, mentionedEmails, keywords []string) ([]*entities.DroppedMention, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDroppedMentions", sender, mentionedEmails, keywords)
+	ret0, _ := ret[0].([]*entities.DroppedMention)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDroppedMentions indicates an expected call of GetDroppedMentions.
+func (mr *MockUserRepositoryInterfaceMockRecorder) GetDroppedMentions(sender, mentionedEmails, keywords any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDroppedMentions", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetDroppedMentions), sender, mentionedEmails, keywords)
+}
+
+// GetEmailsByUsernames mocks base method.
+func (m *MockUserRepositoryInterface) GetEmailsByUsernames(usernames []string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEmailsByUsernames", usernames)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEmailsByUsernames indicates an expected call of GetEmailsByUsernames.
+func (mr *MockUserRepositoryInterfaceMockRecorder) GetEmailsByUsernames(usernames any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommonFriends", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetCommonFriends), user1, user2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEmailsByUsernames", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetEmailsByUsernames), usernames)
 }
 
 // GetFriendList mocks base method.
-func (m *MockUserRepositoryInterface) GetFriendList(user *entities.User) ([]*entities.User, error) {
+func (m *MockUserRepositoryInterface) GetFriendList(user *entities.User, page entities.PageRequest) (*entities.UserPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFriendList", user)
-	ret0, _ := ret[0].([]*entities.User)
+	ret := m.ctrl.Call(m, "GetFriendList", user, page)
+	ret0, _ := ret[0].(*entities.UserPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFriendList indicates an expected call of GetFriendList.
-func (mr *MockUserRepositoryInterfaceMockRecorder) GetFriendList(user any) *gomock.Call {
+func (mr *MockUserRepositoryInterfaceMockRecorder) GetFriendList(user, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendList", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetFriendList), user, page)
+}
+
+// GetFriendListsBatch mocks base method.
+func (m *MockUserRepositoryInterface) GetFriendListsBatch(userIDs []int, page entities.PageRequest) (map[int]*entities.UserPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFriendListsBatch", userIDs, page)
+	ret0, _ := ret[0].(map[int]*entities.UserPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFriendListsBatch indicates an expected call of GetFriendListsBatch.
+func (mr *MockUserRepositoryInterfaceMockRecorder) GetFriendListsBatch(userIDs, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendListsBatch", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetFriendListsBatch), userIDs, page)
+}
+
+// GetFriendSuggestions mocks base method.
+func (m *MockUserRepositoryInterface) GetFriendSuggestions(user *entities.User, limit int) ([]*entities.FriendSuggestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFriendSuggestions", user, limit)
+	ret0, _ := ret[0].([]*entities.FriendSuggestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFriendSuggestions indicates an expected call of GetFriendSuggestions.
+func (mr *MockUserRepositoryInterfaceMockRecorder) GetFriendSuggestions(user, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendSuggestions", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetFriendSuggestions), user, limit)
+}
+
+// GetIncomingFriendRequests mocks base method.
+func (m *MockUserRepositoryInterface) GetIncomingFriendRequests(user *entities.User) ([]*entities.FriendRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncomingFriendRequests", user)
+	ret0, _ := ret[0].([]*entities.FriendRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncomingFriendRequests indicates an expected call of GetIncomingFriendRequests.
+func (mr *MockUserRepositoryInterfaceMockRecorder) GetIncomingFriendRequests(user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncomingFriendRequests", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetIncomingFriendRequests), user)
+}
+
+// GetMutes mocks base method.
+func (m *MockUserRepositoryInterface) GetMutes(muter *entities.User) ([]*entities.Mute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMutes", muter)
+	ret0, _ := ret[0].([]*entities.Mute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMutes indicates an expected call of GetMutes.
+func (mr *MockUserRepositoryInterfaceMockRecorder) GetMutes(muter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMutes", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetMutes), muter)
+}
+
+// GetOutgoingFriendRequests mocks base method.
+func (m *MockUserRepositoryInterface) GetOutgoingFriendRequests(user *entities.User) ([]*entities.FriendRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutgoingFriendRequests", user)
+	ret0, _ := ret[0].([]*entities.FriendRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOutgoingFriendRequests indicates an expected call of GetOutgoingFriendRequests.
+func (mr *MockUserRepositoryInterfaceMockRecorder) GetOutgoingFriendRequests(user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutgoingFriendRequests", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetOutgoingFriendRequests), user)
+}
+
+// GetRecipients mocks base method.
+func (m *MockUserRepositoryInterface) GetRecipients(sender *entities.User, mentionedEmails, keywords []string, page entities.PageRequest) (*entities.RecipientPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecipients", sender, mentionedEmails, keywords, page)
+	ret0, _ := ret[0].(*entities.RecipientPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecipients indicates an expected call of GetRecipients.
+func (mr *MockUserRepositoryInterfaceMockRecorder) GetRecipients(sender, mentionedEmails, keywords, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipients", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetRecipients), sender, mentionedEmails, keywords, page)
+}
+
+// GetRelationship mocks base method.
+func (m *MockUserRepositoryInterface) GetRelationship(userA, userB *entities.User) (*entities.Relationship, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRelationship", userA, userB)
+	ret0, _ := ret[0].(*entities.Relationship)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRelationship indicates an expected call of GetRelationship.
+func (mr *MockUserRepositoryInterfaceMockRecorder) GetRelationship(userA, userB any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRelationship", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetRelationship), userA, userB)
+}
+
+// GetSubscribersBatch mocks base method.
+func (m *MockUserRepositoryInterface) GetSubscribersBatch(userIDs []int, page entities.PageRequest) (map[int]*entities.UserPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscribersBatch", userIDs, page)
+	ret0, _ := ret[0].(map[int]*entities.UserPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscribersBatch indicates an expected call of GetSubscribersBatch.
+func (mr *MockUserRepositoryInterfaceMockRecorder) GetSubscribersBatch(userIDs, page any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendList", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetFriendList), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscribersBatch", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetSubscribersBatch), userIDs, page)
 }
 
 // GetSubscribersByUserID mocks base method.
-func (m *MockUserRepositoryInterface) GetSubscribersByUserID(userID int) ([]*entities.User, error) {
+func (m *MockUserRepositoryInterface) GetSubscribersByUserID(userID int, page entities.PageRequest) (*entities.UserPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSubscribersByUserID", userID)
-	ret0, _ := ret[0].([]*entities.User)
+	ret := m.ctrl.Call(m, "GetSubscribersByUserID", userID, page)
+	ret0, _ := ret[0].(*entities.UserPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetSubscribersByUserID indicates an expected call of GetSubscribersByUserID.
-func (mr *MockUserRepositoryInterfaceMockRecorder) GetSubscribersByUserID(userID any) *gomock.Call {
+func (mr *MockUserRepositoryInterfaceMockRecorder) GetSubscribersByUserID(userID, page any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscribersByUserID", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetSubscribersByUserID), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscribersByUserID", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetSubscribersByUserID), userID, page)
+}
+
+// GetSubscriptionFilter mocks base method.
+func (m *MockUserRepositoryInterface) GetSubscriptionFilter(subscriber, target *entities.User) (*entities.SubscriptionFilter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscriptionFilter", subscriber, target)
+	ret0, _ := ret[0].(*entities.SubscriptionFilter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscriptionFilter indicates an expected call of GetSubscriptionFilter.
+func (mr *MockUserRepositoryInterfaceMockRecorder) GetSubscriptionFilter(subscriber, target any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscriptionFilter", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetSubscriptionFilter), subscriber, target)
+}
+
+// GetSubscriptionsByUserID mocks base method.
+func (m *MockUserRepositoryInterface) GetSubscriptionsByUserID(userID int, page entities.PageRequest) (*entities.UserPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscriptionsByUserID", userID, page)
+	ret0, _ := ret[0].(*entities.UserPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscriptionsByUserID indicates an expected call of GetSubscriptionsByUserID.
+func (mr *MockUserRepositoryInterfaceMockRecorder) GetSubscriptionsByUserID(userID, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscriptionsByUserID", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetSubscriptionsByUserID), userID, page)
+}
+
+// GetUnblockedFriendsBatch mocks base method.
+func (m *MockUserRepositoryInterface) GetUnblockedFriendsBatch(userIDs []int) (map[int][]*entities.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnblockedFriendsBatch", userIDs)
+	ret0, _ := ret[0].(map[int][]*entities.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnblockedFriendsBatch indicates an expected call of GetUnblockedFriendsBatch.
+func (mr *MockUserRepositoryInterfaceMockRecorder) GetUnblockedFriendsBatch(userIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnblockedFriendsBatch", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetUnblockedFriendsBatch), userIDs)
 }
 
 // GetUserByEmail mocks base method.
@@ -188,6 +589,21 @@ func (mr *MockUserRepositoryInterfaceMockRecorder) GetUserByEmail(email any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetUserByEmail), email)
 }
 
+// GetUserSettings mocks base method.
+func (m *MockUserRepositoryInterface) GetUserSettings(user *entities.User) (*entities.UserSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserSettings", user)
+	ret0, _ := ret[0].(*entities.UserSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserSettings indicates an expected call of GetUserSettings.
+func (mr *MockUserRepositoryInterfaceMockRecorder) GetUserSettings(user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSettings", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetUserSettings), user)
+}
+
 // GetUsersByEmails mocks base method.
 func (m *MockUserRepositoryInterface) GetUsersByEmails(emails []string) ([]*entities.User, error) {
 	m.ctrl.T.Helper()
@@ -203,19 +619,337 @@ func (mr *MockUserRepositoryInterfaceMockRecorder) GetUsersByEmails(emails any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersByEmails", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetUsersByEmails), emails)
 }
 
-// UserExists mocks base method.
-func (m *MockUserRepositoryInterface) UserExists(email string) (*entities.User, error) {
+// SetUsername mocks base method.
+func (m *MockUserRepositoryInterface) SetUsername(user *entities.User, username string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UserExists", email)
-	ret0, _ := ret[0].(*entities.User)
+	ret := m.ctrl.Call(m, "SetUsername", user, username)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUsername indicates an expected call of SetUsername.
+func (mr *MockUserRepositoryInterfaceMockRecorder) SetUsername(user, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUsername", reflect.TypeOf((*MockUserRepositoryInterface)(nil).SetUsername), user, username)
+}
+
+// UpdateFriendRequestStatus mocks base method.
+func (m *MockUserRepositoryInterface) UpdateFriendRequestStatus(requester, addressee *entities.User, status entities.FriendRequestStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFriendRequestStatus", requester, addressee, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateFriendRequestStatus indicates an expected call of UpdateFriendRequestStatus.
+func (mr *MockUserRepositoryInterfaceMockRecorder) UpdateFriendRequestStatus(requester, addressee, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFriendRequestStatus", reflect.TypeOf((*MockUserRepositoryInterface)(nil).UpdateFriendRequestStatus), requester, addressee, status)
+}
+
+// UpdateSubscriptionFilter mocks base method.
+func (m *MockUserRepositoryInterface) UpdateSubscriptionFilter(subscriber, target *entities.User, filter *entities.SubscriptionFilter) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSubscriptionFilter", subscriber, target, filter)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSubscriptionFilter indicates an expected call of UpdateSubscriptionFilter.
+func (mr *MockUserRepositoryInterfaceMockRecorder) UpdateSubscriptionFilter(subscriber, target, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSubscriptionFilter", reflect.TypeOf((*MockUserRepositoryInterface)(nil).UpdateSubscriptionFilter), subscriber, target, filter)
+}
+
+// UpdateUserSettings mocks base method.
+func (m *MockUserRepositoryInterface) UpdateUserSettings(settings *entities.UserSettings) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserSettings", settings)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserSettings indicates an expected call of UpdateUserSettings.
+func (mr *MockUserRepositoryInterfaceMockRecorder) UpdateUserSettings(settings any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserSettings", reflect.TypeOf((*MockUserRepositoryInterface)(nil).UpdateUserSettings), settings)
+}
+
+// UpsertMute mocks base method.
+func (m *MockUserRepositoryInterface) UpsertMute(muter, muted *entities.User, expiresAt *time.Time) (*entities.Mute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertMute", muter, muted, expiresAt)
+	ret0, _ := ret[0].(*entities.Mute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertMute indicates an expected call of UpsertMute.
+func (mr *MockUserRepositoryInterfaceMockRecorder) UpsertMute(muter, muted, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertMute", reflect.TypeOf((*MockUserRepositoryInterface)(nil).UpsertMute), muter, muted, expiresAt)
+}
+
+// MockUpdateRepositoryInterface is a mock of UpdateRepositoryInterface interface.
+type MockUpdateRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockUpdateRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockUpdateRepositoryInterfaceMockRecorder is the mock recorder for MockUpdateRepositoryInterface.
+type MockUpdateRepositoryInterfaceMockRecorder struct {
+	mock *MockUpdateRepositoryInterface
+}
+
+// NewMockUpdateRepositoryInterface creates a new mock instance.
+func NewMockUpdateRepositoryInterface(ctrl *gomock.Controller) *MockUpdateRepositoryInterface {
+	mock := &MockUpdateRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockUpdateRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUpdateRepositoryInterface) EXPECT() *MockUpdateRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateUpdateTx mocks base method.
+func (m *MockUpdateRepositoryInterface) CreateUpdateTx(sender *entities.User, text string, recipients []*entities.User) (*entities.Update, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUpdateTx", sender, text, recipients)
+	ret0, _ := ret[0].(*entities.Update)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUpdateTx indicates an expected call of CreateUpdateTx.
+func (mr *MockUpdateRepositoryInterfaceMockRecorder) CreateUpdateTx(sender, text, recipients any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUpdateTx", reflect.TypeOf((*MockUpdateRepositoryInterface)(nil).CreateUpdateTx), sender, text, recipients)
+}
+
+// GetInboxSince mocks base method.
+func (m *MockUpdateRepositoryInterface) GetInboxSince(user *entities.User, afterUpdateID, limit int) ([]*entities.Update, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInboxSince", user, afterUpdateID, limit)
+	ret0, _ := ret[0].([]*entities.Update)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInboxSince indicates an expected call of GetInboxSince.
+func (mr *MockUpdateRepositoryInterfaceMockRecorder) GetInboxSince(user, afterUpdateID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInboxSince", reflect.TypeOf((*MockUpdateRepositoryInterface)(nil).GetInboxSince), user, afterUpdateID, limit)
+}
+
+// GetTimeline mocks base method.
+func (m *MockUpdateRepositoryInterface) GetTimeline(user *entities.User, limit, offset int) ([]*entities.Update, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTimeline", user, limit, offset)
+	ret0, _ := ret[0].([]*entities.Update)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UserExists indicates an expected call of UserExists.
-func (mr *MockUserRepositoryInterfaceMockRecorder) UserExists(email any) *gomock.Call {
+// GetTimeline indicates an expected call of GetTimeline.
+func (mr *MockUpdateRepositoryInterfaceMockRecorder) GetTimeline(user, limit, offset any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserExists", reflect.TypeOf((*MockUserRepositoryInterface)(nil).UserExists), email)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTimeline", reflect.TypeOf((*MockUpdateRepositoryInterface)(nil).GetTimeline), user, limit, offset)
+}
+
+// MockOutboxRepositoryInterface is a mock of OutboxRepositoryInterface interface.
+type MockOutboxRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockOutboxRepositoryInterfaceMockRecorder is the mock recorder for MockOutboxRepositoryInterface.
+type MockOutboxRepositoryInterfaceMockRecorder struct {
+	mock *MockOutboxRepositoryInterface
+}
+
+// NewMockOutboxRepositoryInterface creates a new mock instance.
+func NewMockOutboxRepositoryInterface(ctrl *gomock.Controller) *MockOutboxRepositoryInterface {
+	mock := &MockOutboxRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockOutboxRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxRepositoryInterface) EXPECT() *MockOutboxRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ClaimOutboxEvents mocks base method.
+func (m *MockOutboxRepositoryInterface) ClaimOutboxEvents(limit int, lease time.Duration) ([]*entities.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimOutboxEvents", limit, lease)
+	ret0, _ := ret[0].([]*entities.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimOutboxEvents indicates an expected call of ClaimOutboxEvents.
+func (mr *MockOutboxRepositoryInterfaceMockRecorder) ClaimOutboxEvents(limit, lease any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimOutboxEvents", reflect.TypeOf((*MockOutboxRepositoryInterface)(nil).ClaimOutboxEvents), limit, lease)
+}
+
+// DeadLetterOutboxEvent mocks base method.
+func (m *MockOutboxRepositoryInterface) DeadLetterOutboxEvent(id, attempts int, lastError string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeadLetterOutboxEvent", id, attempts, lastError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeadLetterOutboxEvent indicates an expected call of DeadLetterOutboxEvent.
+func (mr *MockOutboxRepositoryInterfaceMockRecorder) DeadLetterOutboxEvent(id, attempts, lastError any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeadLetterOutboxEvent", reflect.TypeOf((*MockOutboxRepositoryInterface)(nil).DeadLetterOutboxEvent), id, attempts, lastError)
+}
+
+// MarkOutboxEventDelivered mocks base method.
+func (m *MockOutboxRepositoryInterface) MarkOutboxEventDelivered(id, attempts int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkOutboxEventDelivered", id, attempts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkOutboxEventDelivered indicates an expected call of MarkOutboxEventDelivered.
+func (mr *MockOutboxRepositoryInterfaceMockRecorder) MarkOutboxEventDelivered(id, attempts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOutboxEventDelivered", reflect.TypeOf((*MockOutboxRepositoryInterface)(nil).MarkOutboxEventDelivered), id, attempts)
+}
+
+// RescheduleOutboxEvent mocks base method.
+func (m *MockOutboxRepositoryInterface) RescheduleOutboxEvent(id, attempts int, nextAttemptAt time.Time, lastError string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RescheduleOutboxEvent", id, attempts, nextAttemptAt, lastError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RescheduleOutboxEvent indicates an expected call of RescheduleOutboxEvent.
+func (mr *MockOutboxRepositoryInterfaceMockRecorder) RescheduleOutboxEvent(id, attempts, nextAttemptAt, lastError any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RescheduleOutboxEvent", reflect.TypeOf((*MockOutboxRepositoryInterface)(nil).RescheduleOutboxEvent), id, attempts, nextAttemptAt, lastError)
+}
+
+// MockWebhookRepositoryInterface is a mock of WebhookRepositoryInterface interface.
+type MockWebhookRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockWebhookRepositoryInterfaceMockRecorder is the mock recorder for MockWebhookRepositoryInterface.
+type MockWebhookRepositoryInterfaceMockRecorder struct {
+	mock *MockWebhookRepositoryInterface
+}
+
+// NewMockWebhookRepositoryInterface creates a new mock instance.
+func NewMockWebhookRepositoryInterface(ctrl *gomock.Controller) *MockWebhookRepositoryInterface {
+	mock := &MockWebhookRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockWebhookRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookRepositoryInterface) EXPECT() *MockWebhookRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockWebhookRepositoryInterface) DeleteWebhook(user *entities.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockWebhookRepositoryInterfaceMockRecorder) DeleteWebhook(user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockWebhookRepositoryInterface)(nil).DeleteWebhook), user)
+}
+
+// GetWebhookByID mocks base method.
+func (m *MockWebhookRepositoryInterface) GetWebhookByID(id int) (*entities.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookByID", id)
+	ret0, _ := ret[0].(*entities.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookByID indicates an expected call of GetWebhookByID.
+func (mr *MockWebhookRepositoryInterfaceMockRecorder) GetWebhookByID(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookByID", reflect.TypeOf((*MockWebhookRepositoryInterface)(nil).GetWebhookByID), id)
+}
+
+// GetWebhookByUser mocks base method.
+func (m *MockWebhookRepositoryInterface) GetWebhookByUser(user *entities.User) (*entities.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookByUser", user)
+	ret0, _ := ret[0].(*entities.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookByUser indicates an expected call of GetWebhookByUser.
+func (mr *MockWebhookRepositoryInterfaceMockRecorder) GetWebhookByUser(user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookByUser", reflect.TypeOf((*MockWebhookRepositoryInterface)(nil).GetWebhookByUser), user)
+}
+
+// GetWebhookDeliveries mocks base method.
+func (m *MockWebhookRepositoryInterface) GetWebhookDeliveries(webhook *entities.Webhook, limit int) ([]*entities.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeliveries", webhook, limit)
+	ret0, _ := ret[0].([]*entities.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDeliveries indicates an expected call of GetWebhookDeliveries.
+func (mr *MockWebhookRepositoryInterfaceMockRecorder) GetWebhookDeliveries(webhook, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveries", reflect.TypeOf((*MockWebhookRepositoryInterface)(nil).GetWebhookDeliveries), webhook, limit)
+}
+
+// RecordWebhookDelivery mocks base method.
+func (m *MockWebhookRepositoryInterface) RecordWebhookDelivery(delivery *entities.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordWebhookDelivery", delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordWebhookDelivery indicates an expected call of RecordWebhookDelivery.
+func (mr *MockWebhookRepositoryInterfaceMockRecorder) RecordWebhookDelivery(delivery any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordWebhookDelivery", reflect.TypeOf((*MockWebhookRepositoryInterface)(nil).RecordWebhookDelivery), delivery)
+}
+
+// UpsertWebhook mocks base method.
+func (m *MockWebhookRepositoryInterface) UpsertWebhook(user *entities.User, url, secret string) (*entities.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWebhook", user, url, secret)
+	ret0, _ := ret[0].(*entities.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertWebhook indicates an expected call of UpsertWebhook.
+func (mr *MockWebhookRepositoryInterfaceMockRecorder) UpsertWebhook(user, url, secret any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWebhook", reflect.TypeOf((*MockWebhookRepositoryInterface)(nil).UpsertWebhook), user, url, secret)
 }
 
 // MockRepositories is a mock of Repositories interface.
@@ -242,6 +976,34 @@ func (m *MockRepositories) EXPECT() *MockRepositoriesMockRecorder {
 	return m.recorder
 }
 
+// OutboxRepository mocks base method.
+func (m *MockRepositories) OutboxRepository() interfaces.OutboxRepositoryInterface {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OutboxRepository")
+	ret0, _ := ret[0].(interfaces.OutboxRepositoryInterface)
+	return ret0
+}
+
+// OutboxRepository indicates an expected call of OutboxRepository.
+func (mr *MockRepositoriesMockRecorder) OutboxRepository() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OutboxRepository", reflect.TypeOf((*MockRepositories)(nil).OutboxRepository))
+}
+
+// UpdateRepository mocks base method.
+func (m *MockRepositories) UpdateRepository() interfaces.UpdateRepositoryInterface {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRepository")
+	ret0, _ := ret[0].(interfaces.UpdateRepositoryInterface)
+	return ret0
+}
+
+// UpdateRepository indicates an expected call of UpdateRepository.
+func (mr *MockRepositoriesMockRecorder) UpdateRepository() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRepository", reflect.TypeOf((*MockRepositories)(nil).UpdateRepository))
+}
+
 // UserRepository mocks base method.
 func (m *MockRepositories) UserRepository() interfaces.UserRepositoryInterface {
 	m.ctrl.T.Helper()
@@ -255,3 +1017,17 @@ func (mr *MockRepositoriesMockRecorder) UserRepository() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserRepository", reflect.TypeOf((*MockRepositories)(nil).UserRepository))
 }
+
+// WebhookRepository mocks base method.
+func (m *MockRepositories) WebhookRepository() interfaces.WebhookRepositoryInterface {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WebhookRepository")
+	ret0, _ := ret[0].(interfaces.WebhookRepositoryInterface)
+	return ret0
+}
+
+// WebhookRepository indicates an expected call of WebhookRepository.
+func (mr *MockRepositoriesMockRecorder) WebhookRepository() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WebhookRepository", reflect.TypeOf((*MockRepositories)(nil).WebhookRepository))
+}
@@ -92,6 +92,26 @@ func Newf(errorType ErrorType, format string, args ...interface{}) *AppError {
 	}
 }
 
+// NewValidationError creates a validation AppError from the errors of the
+// validator package, listed in its details
+func NewValidationError(validationErrors map[string]string) *AppError {
+	appErr := New(ErrorTypeValidation, "Validation failed")
+
+	// Convert validation errors to a details string
+	if len(validationErrors) > 0 {
+		var details string
+		for field, msg := range validationErrors {
+			if details != "" {
+				details += "; "
+			}
+			details += field + ": " + msg
+		}
+		appErr.WithDetails(details)
+	}
+
+	return appErr
+}
+
 // Wrap wraps an existing error with additional context
 func Wrap(err error, errorType ErrorType, message string) *AppError {
 	return &AppError{
@@ -234,6 +254,10 @@ func getConstraintMessage(constraint string) string {
 	switch {
 	case strings.Contains(constraint, "email"):
 		return "Email address already exists"
+	case strings.Contains(constraint, "username"):
+		return "Username is already taken"
+	case strings.Contains(constraint, "friend_request"):
+		return "Friend request already pending"
 	case strings.Contains(constraint, "friend"):
 		return "Friendship already exists"
 	case strings.Contains(constraint, "block"):
@@ -262,8 +286,22 @@ var (
 	ErrCannotBlockSelf               = New(ErrorTypeBusiness, "Cannot block yourself")
 	ErrCannotSubscribeSelf           = New(ErrorTypeBusiness, "Cannot subscribe to yourself")
 	ErrCannotGetCommonFriendsWithSelf = New(ErrorTypeBusiness, "Cannot get common friends with yourself")
+	ErrCannotUnfriendSelf            = New(ErrorTypeBusiness, "Cannot remove yourself as a friend")
+	ErrCannotGetRelationshipWithSelf = New(ErrorTypeBusiness, "Cannot get relationship with yourself")
+	ErrCannotGetPathToSelf           = New(ErrorTypeBusiness, "Cannot get friendship path to yourself")
 	ErrAlreadyFriends                = New(ErrorTypeConflict, "Users are already friends")
 	ErrAlreadyBlocked                = New(ErrorTypeConflict, "User is already blocked")
 	ErrAlreadySubscribed             = New(ErrorTypeConflict, "Already subscribed to user")
 	ErrUserBlocked                   = New(ErrorTypeForbidden, "Cannot perform action on blocked user")
+	ErrFriendshipNotFound            = New(ErrorTypeNotFound, "Friendship not found")
+	ErrSubscriptionNotFound          = New(ErrorTypeNotFound, "Subscription not found")
+	ErrBlockNotFound                 = New(ErrorTypeNotFound, "Block not found")
+	ErrMuteNotFound                  = New(ErrorTypeNotFound, "Mute not found")
+	ErrMuteExpiryInPast              = New(ErrorTypeValidation, "Mute expiry must be in the future")
+	ErrFriendRequestNotFound         = New(ErrorTypeNotFound, "Pending friend request not found")
+	ErrFriendshipPathNotFound        = New(ErrorTypeNotFound, "No friendship path found within max depth")
+	ErrWebhookNotFound               = New(ErrorTypeNotFound, "Webhook not found")
+	ErrInvalidCursor                 = New(ErrorTypeValidation, "Invalid cursor")
+	ErrOutboxClaimLost               = New(ErrorTypeConflict, "Outbox event is no longer claimed")
+	ErrNotAuthenticated              = New(ErrorTypeUnauthorized, "Request is not authenticated")
 )
\ No newline at end of file
@@ -0,0 +1,42 @@
+package errors
+
+import (
+	"context"
+	"errors"
+)
+
+// GraphQLError is an AppError as reported in the errors of a GraphQL response,
+// which are sent with a 200 status. The error type goes in the extensions for
+// clients to tell errors apart
+type GraphQLError struct {
+	*AppError
+}
+
+// Error returns the message of the error, followed by its details if any
+func (e *GraphQLError) Error() string {
+	if e.Details != "" {
+		return e.Message + ": " + e.Details
+	}
+	return e.Message
+}
+
+// Extensions returns the extensions of the GraphQL error
+func (e *GraphQLError) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"type": e.Type,
+	}
+}
+
+// ToGraphQLError converts an error returned by a resolver to a GraphQLError,
+// as ToGRPCStatus does for the gRPC API. Context errors are kept as they are
+func ToGraphQLError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	return &GraphQLError{AppError: FromError(err)}
+}
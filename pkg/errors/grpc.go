@@ -0,0 +1,59 @@
+package errors
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetGRPCCode returns the gRPC status code for the error, the counterpart of
+// GetStatusCode for the gRPC API
+func (e *AppError) GetGRPCCode() codes.Code {
+	switch e.Type {
+	case ErrorTypeValidation:
+		return codes.InvalidArgument
+	case ErrorTypeBusiness:
+		return codes.FailedPrecondition
+	case ErrorTypeNotFound:
+		return codes.NotFound
+	case ErrorTypeConflict:
+		return codes.AlreadyExists
+	case ErrorTypeUnauthorized:
+		return codes.Unauthenticated
+	case ErrorTypeForbidden:
+		return codes.PermissionDenied
+	case ErrorTypeExternal:
+		return codes.Unavailable
+	case ErrorTypeInternal, ErrorTypeDatabase:
+		return codes.Internal
+	default:
+		return codes.Internal
+	}
+}
+
+// ToGRPCStatus converts an error to a gRPC status error, as HandleError does
+// for HTTP responses. Errors that already carry a status, and context errors,
+// keep their own code
+func ToGRPCStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	appErr := FromError(err)
+
+	message := appErr.Message
+	if appErr.Details != "" {
+		message += ": " + appErr.Details
+	}
+
+	return status.Error(appErr.GetGRPCCode(), message)
+}
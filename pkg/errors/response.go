@@ -63,19 +63,5 @@ func SendConflict(c *gin.Context, message string) {
 
 // HandleValidationErrors handles validation errors from the validator package
 func HandleValidationErrors(c *gin.Context, validationErrors map[string]string) {
-	appErr := New(ErrorTypeValidation, "Validation failed")
-
-	// Convert validation errors to a details string
-	if len(validationErrors) > 0 {
-		var details string
-		for field, msg := range validationErrors {
-			if details != "" {
-				details += "; "
-			}
-			details += field + ": " + msg
-		}
-		appErr.WithDetails(details)
-	}
-
-	HandleError(c, appErr)
+	HandleError(c, NewValidationError(validationErrors))
 }
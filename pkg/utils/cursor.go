@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// EncodeCursor turns the sort key of the last item on a page into the opaque
+// cursor clients send back for the next page
+func EncodeCursor(key string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(key))
+}
+
+// DecodeCursor returns the sort key inside a cursor made by EncodeCursor
+func DecodeCursor(cursor string) (string, error) {
+	key, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(key) == 0 {
+		return "", errInvalidCursor
+	}
+	return string(key), nil
+}
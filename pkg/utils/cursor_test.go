@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor(t *testing.T) {
+	for _, key := range []string{"andy@mail.com", "o'brien+tag@mail.com", "ünïcode@mail.com"} {
+		cursor := EncodeCursor(key)
+		assert.NotContains(t, cursor, "@")
+
+		decoded, err := DecodeCursor(cursor)
+		assert.NoError(t, err)
+		assert.Equal(t, key, decoded)
+	}
+
+	for _, cursor := range []string{"", "not a cursor", "YW5keQ=="} {
+		_, err := DecodeCursor(cursor)
+		assert.Error(t, err, cursor)
+	}
+}
@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ExtractKeywords returns the distinct lowercase words and hashtags in the text,
+// in order of first appearance. A hashtag is returned both with and without its
+// '#', so a plain keyword filter matches either form while a '#' filter only
+// matches the hashtag
+func ExtractKeywords(text string) []string {
+	var keywords []string
+	seen := make(map[string]bool)
+	add := func(keyword string) {
+		if !seen[keyword] {
+			seen[keyword] = true
+			keywords = append(keywords, keyword)
+		}
+	}
+
+	for _, field := range strings.FieldsFunc(strings.ToLower(text), isKeywordSeparator) {
+		// "go#rust" is the word go followed by the hashtag #rust
+		parts := strings.Split(field, "#")
+		if parts[0] != "" {
+			add(parts[0])
+		}
+		for _, tag := range parts[1:] {
+			if tag != "" {
+				add("#" + tag)
+				add(tag)
+			}
+		}
+	}
+
+	return keywords
+}
+
+func isKeywordSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '_' && r != '#'
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractKeywords(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected []string
+	}{
+		{
+			name:     "empty text",
+			text:     "",
+			expected: nil,
+		},
+		{
+			name:     "words are lowercased and deduplicated",
+			text:     "Go is fun, go!",
+			expected: []string{"go", "is", "fun"},
+		},
+		{
+			name:     "hashtags are returned with and without the hash",
+			text:     "Shipping #Golang today",
+			expected: []string{"shipping", "#golang", "golang", "today"},
+		},
+		{
+			name:     "adjacent hashtags",
+			text:     "#go#rust c#",
+			expected: []string{"#go", "go", "#rust", "rust", "c"},
+		},
+		{
+			name:     "emails and punctuation split words",
+			text:     "ping bob@mail.com (re: release_notes)",
+			expected: []string{"ping", "bob", "mail", "com", "re", "release_notes"},
+		},
+		{
+			name:     "unicode letters",
+			text:     "Café #Über",
+			expected: []string{"café", "#über", "über"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ExtractKeywords(tt.text))
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"assignment/pkg/validator"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+const mailtoPrefix = "mailto:"
+
+// mentionTLDRX requires mentioned emails to end in an alphabetic top level
+// domain, so that text like "me@home" is not taken for an address
+var mentionTLDRX = regexp.MustCompile(`\.[a-z]{2,}$`)
+
+// Mentions holds the distinct emails and @handles mentioned in a text,
+// lowercased and in order of first appearance
+type Mentions struct {
+	Emails  []string
+	Handles []string
+}
+
+// ParseMentions extracts the emails and @handles mentioned in the text.
+// Surrounding punctuation and mailto: prefixes are stripped, "@alice@mail.com"
+// counts as an email and duplicates are dropped regardless of case
+func ParseMentions(text string) Mentions {
+	var mentions Mentions
+	seenEmails := make(map[string]bool)
+	seenHandles := make(map[string]bool)
+
+	for _, token := range strings.FieldsFunc(text, isMentionSeparator) {
+		token = strings.ToLower(strings.TrimFunc(token, isMentionPunct))
+
+		if rest, ok := strings.CutPrefix(token, mailtoPrefix); ok {
+			// Drop any ?subject=... query from the mailto link
+			rest, _, _ = strings.Cut(rest, "?")
+			token = strings.TrimFunc(rest, isMentionPunct)
+		}
+
+		if rest, ok := strings.CutPrefix(token, "@"); ok {
+			rest = strings.TrimFunc(rest, isMentionPunct)
+			if !strings.Contains(rest, "@") {
+				if validator.Matches(rest, validator.UsernameRX) && !seenHandles[rest] {
+					seenHandles[rest] = true
+					mentions.Handles = append(mentions.Handles, rest)
+				}
+				continue
+			}
+			token = rest
+		}
+
+		if isMentionEmail(token) && !seenEmails[token] {
+			seenEmails[token] = true
+			mentions.Emails = append(mentions.Emails, token)
+		}
+	}
+
+	return mentions
+}
+
+func isMentionEmail(token string) bool {
+	return validator.Matches(token, validator.EmailRX) && mentionTLDRX.MatchString(token)
+}
+
+func isMentionSeparator(r rune) bool {
+	return unicode.IsSpace(r) || r == ',' || r == ';'
+}
+
+// isMentionPunct reports whether r may be trimmed from either end of a mention.
+// '@' marks a handle and '_' is a valid username character, so both are kept
+func isMentionPunct(r rune) bool {
+	if r == '@' || r == '_' {
+		return false
+	}
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
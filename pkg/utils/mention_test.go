@@ -0,0 +1,147 @@
+package utils
+
+import (
+	"assignment/pkg/validator"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		name            string
+		text            string
+		expectedEmails  []string
+		expectedHandles []string
+	}{
+		{
+			name: "no mentions",
+			text: "Hello everyone!",
+		},
+		{
+			name:           "plain email",
+			text:           "Hi bob@example.com how are you?",
+			expectedEmails: []string{"bob@example.com"},
+		},
+		{
+			name:           "email prefixed with @",
+			text:           "Hello @mentioned@example.com",
+			expectedEmails: []string{"mentioned@example.com"},
+		},
+		{
+			name:           "trailing sentence punctuation",
+			text:           "Say hi to bob@example.com. Then alice@example.com!",
+			expectedEmails: []string{"bob@example.com", "alice@example.com"},
+		},
+		{
+			name:           "surrounding brackets and quotes",
+			text:           `(bob@example.com) <alice@example.com> "jack@example.com"`,
+			expectedEmails: []string{"bob@example.com", "alice@example.com", "jack@example.com"},
+		},
+		{
+			name:           "comma separated list",
+			text:           "cc bob@example.com,alice@example.com;jack@example.com",
+			expectedEmails: []string{"bob@example.com", "alice@example.com", "jack@example.com"},
+		},
+		{
+			name:           "mailto links",
+			text:           "Write to mailto:Bob@Example.com or <MAILTO:alice@example.com?subject=hi>",
+			expectedEmails: []string{"bob@example.com", "alice@example.com"},
+		},
+		{
+			name:           "case insensitive duplicates",
+			text:           "Bob@Example.com bob@example.com BOB@EXAMPLE.COM",
+			expectedEmails: []string{"bob@example.com"},
+		},
+		{
+			name:           "address without top level domain",
+			text:           "ping me@localhost or bob@example.c",
+			expectedEmails: nil,
+		},
+		{
+			name:            "handles",
+			text:            "Thanks @Alice and @bob_smith!",
+			expectedHandles: []string{"alice", "bob_smith"},
+		},
+		{
+			name:            "duplicate handles",
+			text:            "@bob @Bob (@BOB)",
+			expectedHandles: []string{"bob"},
+		},
+		{
+			name: "invalid handles",
+			text: "@ @a @this_handle_is_far_too_long_to_be_valid @bob-smith",
+		},
+		{
+			name:            "emails and handles together",
+			text:            "@alice meet bob@example.com, cc @jack",
+			expectedEmails:  []string{"bob@example.com"},
+			expectedHandles: []string{"alice", "jack"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mentions := ParseMentions(tt.text)
+
+			assert.Equal(t, tt.expectedEmails, mentions.Emails)
+			assert.Equal(t, tt.expectedHandles, mentions.Handles)
+		})
+	}
+}
+
+func FuzzParseMentions(f *testing.F) {
+	seeds := []string{
+		"",
+		"Hello @mentioned@example.com how are you?",
+		"Say hi to bob@example.com. Then alice@example.com!",
+		"<MAILTO:alice@example.com?subject=hi>",
+		"@Alice @bob_smith, @bob_smith",
+		"@@bob @!alice@example.com mailto:@jack",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, text string) {
+		mentions := ParseMentions(text)
+
+		seen := make(map[string]bool)
+		for _, email := range mentions.Emails {
+			if email != strings.ToLower(email) {
+				t.Errorf("email %q is not lowercase", email)
+			}
+			if !validator.Matches(email, validator.EmailRX) {
+				t.Errorf("email %q is not a valid address", email)
+			}
+			if seen[email] {
+				t.Errorf("email %q returned twice", email)
+			}
+			seen[email] = true
+		}
+
+		seen = make(map[string]bool)
+		for _, handle := range mentions.Handles {
+			if handle != strings.ToLower(handle) {
+				t.Errorf("handle %q is not lowercase", handle)
+			}
+			if !validator.Matches(handle, validator.UsernameRX) {
+				t.Errorf("handle %q is not a valid username", handle)
+			}
+			if seen[handle] {
+				t.Errorf("handle %q returned twice", handle)
+			}
+			seen[handle] = true
+		}
+
+		// Parsing the parsed mentions again must give back the same mentions
+		tokens := append([]string{}, mentions.Emails...)
+		for _, handle := range mentions.Handles {
+			tokens = append(tokens, "@"+handle)
+		}
+		reparsed := ParseMentions(strings.Join(tokens, " "))
+		assert.Equal(t, mentions.Emails, reparsed.Emails)
+		assert.Equal(t, mentions.Handles, reparsed.Handles)
+	})
+}
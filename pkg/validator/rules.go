@@ -0,0 +1,94 @@
+package validator
+
+// Limits on the requests every API takes, HTTP and gRPC alike.
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 100
+
+	MinCommonFriendsUsers = 2
+	MaxCommonFriendsUsers = 20
+
+	DefaultPathMaxDepth = 6
+	MaxPathMaxDepth     = 10
+
+	DefaultSuggestionLimit = 10
+	MaxSuggestionLimit     = 50
+
+	MaxSubscriptionKeywords = 20
+)
+
+// ValidateEmailPair checks a list holding the two users of a friendship.
+func ValidateEmailPair(v *Validator, emails []string) {
+	v.Check(len(emails) == 2, "emails count", "exactly 2 emails required")
+
+	for _, email := range emails {
+		v.Check(len(email) > 0, "email", "email cannot be empty")
+		ValidateEmail(v, email)
+	}
+}
+
+// ValidateCommonFriendsEmails checks the users whose common friends are listed.
+func ValidateCommonFriendsEmails(v *Validator, emails []string) {
+	v.Check(len(emails) >= MinCommonFriendsUsers && len(emails) <= MaxCommonFriendsUsers, "emails count", "between 2 and 20 emails required")
+
+	for _, email := range emails {
+		v.Check(len(email) > 0, "email", "email cannot be empty")
+		ValidateEmail(v, email)
+	}
+}
+
+// ValidateUserPair checks a requestor acting on a target, which must be another user.
+// sameMessage is the error reported when they are the same.
+func ValidateUserPair(v *Validator, requestor, target, sameMessage string) {
+	v.Check(len(requestor) > 0, "requestor", "requestor email cannot be empty")
+	v.Check(len(target) > 0, "target", "target email cannot be empty")
+	ValidateEmail(v, requestor)
+	ValidateEmail(v, target)
+	v.Check(requestor != target, "emails", sameMessage)
+}
+
+// ValidatePageLimit checks the size of a page of a paginated list, zero meaning the default.
+func ValidatePageLimit(v *Validator, limit int) {
+	v.Check(limit >= 0, "limit", "must not be negative")
+	v.Check(limit <= MaxPageLimit, "limit", "must not exceed 100")
+}
+
+// PageLimit returns the requested page size, or DefaultPageLimit when none was given.
+func PageLimit(limit int) int {
+	if limit == 0 {
+		return DefaultPageLimit
+	}
+	return limit
+}
+
+// ValidatePathMaxDepth checks how many hops a friendship path may take, zero meaning the default.
+func ValidatePathMaxDepth(v *Validator, maxDepth int) {
+	v.Check(maxDepth >= 0, "max_depth", "must not be negative")
+	v.Check(maxDepth <= MaxPathMaxDepth, "max_depth", "must not exceed 10")
+}
+
+// ValidateSuggestionLimit checks how many friend suggestions are asked for, zero meaning the default.
+func ValidateSuggestionLimit(v *Validator, limit int) {
+	v.Check(limit >= 0, "limit", "must not be negative")
+	v.Check(limit <= MaxSuggestionLimit, "limit", "must not exceed 50")
+}
+
+// ValidateKeywords checks the include or exclude keywords of a subscription filter.
+func ValidateKeywords(v *Validator, key string, keywords []string) {
+	v.Check(len(keywords) <= MaxSubscriptionKeywords, key, "must not contain more than 20 keywords")
+	for _, keyword := range keywords {
+		v.Check(Matches(keyword, KeywordRX), key, "keywords must be a single word or #hashtag of at most 50 characters")
+	}
+}
+
+// ValidateUpdateText checks the sender and text of an update, published or previewed.
+func ValidateUpdateText(v *Validator, sender, text string) {
+	v.Check(len(sender) > 0, "sender", "sender email cannot be empty")
+	ValidateEmail(v, sender)
+	v.Check(len(text) > 0, "text", "text cannot be empty")
+}
+
+// ValidateMentionPolicy checks who a user lets mention them.
+func ValidateMentionPolicy(v *Validator, policy string) {
+	v.Check(In(policy, "everyone", "friends", "nobody"), "mention_policy", "must be one of everyone, friends or nobody")
+}
@@ -1,11 +1,31 @@
 package validator
 
-import "regexp"
+import (
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strings"
+)
 
 var (
 	// EmailRX is a regex for sanity checking the format of email addresses.
 	// The regex pattern used is taken from  https://html.spec.whatwg.org/#valid-e-mail-address.
 	EmailRX = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+\\/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
+
+	// UsernameRX is a regex for usernames, which are mentioned in updates as @username.
+	UsernameRX = regexp.MustCompile("^[a-zA-Z0-9_]{3,32}$")
+
+	// KeywordRX is a regex for subscription filter keywords: a single word, or a hashtag when prefixed with '#'.
+	KeywordRX = regexp.MustCompile(`^#?[\p{L}\p{N}_]{1,50}$`)
+
+	// nonPublicPrefixes are the special-purpose ranges that netip has no predicate for:
+	// "this network", carrier-grade NAT, benchmarking and the reserved 240/4.
+	nonPublicPrefixes = []netip.Prefix{
+		netip.MustParsePrefix("0.0.0.0/8"),
+		netip.MustParsePrefix("100.64.0.0/10"),
+		netip.MustParsePrefix("198.18.0.0/15"),
+		netip.MustParsePrefix("240.0.0.0/4"),
+	}
 )
 
 // Validator struct type contains a map of validation errors.
@@ -53,6 +73,49 @@ func Matches(value string, rx *regexp.Regexp) bool {
 	return rx.MatchString(value)
 }
 
+// IsHTTPURL returns true if a string value is an absolute http or https URL.
+func IsHTTPURL(value string) bool {
+	u, err := url.Parse(value)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// IsPublicIP returns true if an IP address is reachable on the public internet, that is
+// not loopback, link-local, private, multicast, unspecified or otherwise reserved.
+func IsPublicIP(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsValid() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsPrivate() || ip.IsUnspecified() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsPublicURLHost returns false if a URL's host is localhost or an IP address that is not
+// public. Host names are not resolved, so those pointing at private addresses pass.
+func IsPublicURLHost(value string) bool {
+	u, err := url.Parse(value)
+	if err != nil {
+		return false
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if ip, err := netip.ParseAddr(host); err == nil {
+		return IsPublicIP(ip)
+	}
+	return true
+}
+
 // Unique returns true if all string values in a slice are unique.
 func Unique(values []string) bool {
 	uniqueValues := make(map[string]bool)
@@ -68,3 +131,8 @@ func ValidateEmail(v *Validator, email string) {
 	v.Check(email != "", "email", "must be provided")
 	v.Check(Matches(email, EmailRX), "email", "must be valid email address")
 }
+
+func ValidateUsername(v *Validator, username string) {
+	v.Check(username != "", "username", "must be provided")
+	v.Check(Matches(username, UsernameRX), "username", "must be 3-32 letters, digits or underscores")
+}